@@ -0,0 +1,97 @@
+// Package claude is the agent driver for Claude Code.
+package claude
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/agentdriver"
+	claudesettings "github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// Name is the preset this driver registers under.
+const Name = "claude"
+
+// busyMarker is shown in the status line while Claude works on a prompt.
+// Its input box stays on screen throughout, so the prompt prefix alone
+// doesn't mean idle.
+const busyMarker = "esc to interrupt"
+
+func init() {
+	agentdriver.Register(Name, New)
+}
+
+// Driver drives Claude Code.
+type Driver struct {
+	*agentdriver.Generic
+}
+
+// New returns the Claude driver for rc.
+func New(rc *config.RuntimeConfig) agentdriver.AgentDriver {
+	g := agentdriver.NewGeneric(Name, rc)
+	g.BusyMarkers = []string{busyMarker}
+	return &Driver{Generic: g}
+}
+
+// Setup installs Claude's settings.json with the Gas Town hooks for role.
+func (d *Driver) Setup(workDir, role string) error {
+	dir, file := ".claude", "settings.json"
+	if hooks := d.Config().Hooks; hooks != nil {
+		if hooks.Dir != "" {
+			dir = hooks.Dir
+		}
+		if hooks.SettingsFile != "" {
+			file = hooks.SettingsFile
+		}
+	}
+	return claudesettings.EnsureSettingsForRoleAt(workDir, role, dir, file)
+}
+
+// AfterStart accepts the bypass permissions warning that
+// --dangerously-skip-permissions shows on first start.
+func (d *Driver) AfterStart(t *tmux.Tmux, session string) error {
+	return t.AcceptBypassPermissionsWarning(session)
+}
+
+// Transcript returns Claude's project dir for workDir under the account's
+// config dir (default ~/.claude).
+func (d *Driver) Transcript(workDir, configDir string) transcript.Source {
+	if d.TranscriptDir() != "" {
+		return d.Generic.Transcript(workDir, configDir)
+	}
+	base := configDir
+	if base == "" {
+		base = "~/.claude"
+	}
+	return transcript.Source{
+		Provider:  Name,
+		Dirs:      ProjectDirs(base, workDir),
+		ConfigDir: configDir,
+	}
+}
+
+var nonAlnum = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// ProjectDirs returns the directories Claude may keep workDir's transcripts
+// in. Claude names project dirs after the path with separators (and, in
+// newer versions, any other punctuation) turned into dashes.
+func ProjectDirs(configDir, workDir string) []string {
+	return []string{
+		filepath.Join(configDir, "projects", strings.ReplaceAll(workDir, "/", "-")),
+		filepath.Join(configDir, "projects", nonAlnum.ReplaceAllString(workDir, "-")),
+	}
+}
+
+// ParseCost sums the token usage of a transcript's assistant messages and
+// prices it.
+func (d *Driver) ParseCost(transcriptPath string) (float64, error) {
+	usage, err := parseUsage(transcriptPath)
+	if err != nil {
+		return 0, err
+	}
+	return usage.cost(), nil
+}
@@ -0,0 +1,84 @@
+package claude
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+func writeFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := "/home/gt/gastown/polecats/Toast.v2/gastown"
+	now := time.Now()
+	// Newer Claude versions also dash out the dot
+	project := filepath.Join(configDir, "projects", "-home-gt-gastown-polecats-Toast-v2-gastown")
+	writeFile(t, filepath.Join(project, "old.jsonl"), "{}\n", now.Add(-time.Hour))
+	writeFile(t, filepath.Join(project, "new.jsonl"), "{}\n", now)
+	writeFile(t, filepath.Join(project, "sub", "newer.jsonl"), "{}\n", now.Add(time.Minute))
+
+	d := New(config.DefaultRuntimeConfig())
+	got, err := transcript.Locate(d.Transcript(workDir, configDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(got) != "new.jsonl" {
+		t.Errorf("Locate = %s, want new.jsonl (newest, not in a subdir)", got)
+	}
+
+	if src := d.Transcript(workDir, ""); src.Dirs[0] != "~/.claude/projects/-home-gt-gastown-polecats-Toast.v2-gastown" {
+		t.Errorf("default config dir: Dirs = %v", src.Dirs)
+	}
+
+	rc := config.DefaultRuntimeConfig()
+	rc.Session.TranscriptDir = "/var/transcripts"
+	if src := New(rc).Transcript(workDir, configDir); len(src.Dirs) != 1 || src.Dirs[0] != "/var/transcripts" {
+		t.Errorf("transcript_dir not honored: Dirs = %v", src.Dirs)
+	}
+}
+
+func TestParseCost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	content := `{"type":"user","message":{"role":"user","content":"hi"}}
+{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000,"output_tokens":100000}}}
+not json
+{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"cache_read_input_tokens":1000000,"cache_creation_input_tokens":1000000}}}
+`
+	writeFile(t, path, content, time.Now())
+
+	cost, err := New(config.DefaultRuntimeConfig()).ParseCost(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3.00 input + 1.50 output + 0.30 cache read + 3.75 cache create
+	if want := 8.55; math.Abs(cost-want) > 1e-9 {
+		t.Errorf("ParseCost = %v, want %v", cost, want)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	workDir := t.TempDir()
+	if err := New(config.DefaultRuntimeConfig()).Setup(workDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, ".claude", "settings.json")); err != nil {
+		t.Errorf("settings not installed: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// transcriptMessage is a line of a Claude Code transcript file.
+type transcriptMessage struct {
+	Type    string       `json:"type"`
+	Message *messageBody `json:"message,omitempty"`
+}
+
+// messageBody is the message itself, carrying the model and usage.
+type messageBody struct {
+	Model string           `json:"model"`
+	Usage *transcriptUsage `json:"usage,omitempty"`
+}
+
+// transcriptUsage is the token usage of one assistant message.
+type transcriptUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+}
+
+// tokenUsage aggregates token usage across a session.
+type tokenUsage struct {
+	Model string
+	transcriptUsage
+}
+
+// Model pricing per million tokens (as of Jan 2025).
+// See: https://www.anthropic.com/pricing
+var modelPricing = map[string]struct {
+	InputPerMillion       float64
+	OutputPerMillion      float64
+	CacheReadPerMillion   float64 // 90% discount on input price
+	CacheCreatePerMillion float64 // 25% premium on input price
+}{
+	// Claude Opus 4.5
+	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
+	// Claude Sonnet 4
+	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
+	// Claude Haiku 3.5
+	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
+	// Fallback for unknown models (use Sonnet pricing)
+	"default": {3.0, 15.0, 0.3, 3.75},
+}
+
+// parseUsage reads a transcript file and sums token usage from assistant messages.
+func parseUsage(transcriptPath string) (*tokenUsage, error) {
+	file, err := os.Open(transcriptPath) //nolint:gosec // G304: transcript path located by gt
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	usage := &tokenUsage{}
+	scanner := bufio.NewScanner(file)
+	// Increase buffer for potentially large JSON lines
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg transcriptMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue // Skip malformed lines
+		}
+
+		// Only process assistant messages with usage info
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+
+		// Capture the model (use first one found, they should all be the same)
+		if usage.Model == "" && msg.Message.Model != "" {
+			usage.Model = msg.Message.Model
+		}
+
+		u := msg.Message.Usage
+		usage.InputTokens += u.InputTokens
+		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
+		usage.CacheReadInputTokens += u.CacheReadInputTokens
+		usage.OutputTokens += u.OutputTokens
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// cost converts token usage to USD based on model pricing.
+func (u *tokenUsage) cost() float64 {
+	pricing, ok := modelPricing[u.Model]
+	if !ok {
+		pricing = modelPricing["default"]
+	}
+
+	// Prices are per million tokens
+	inputCost := float64(u.InputTokens) / 1_000_000 * pricing.InputPerMillion
+	cacheReadCost := float64(u.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
+	cacheCreateCost := float64(u.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
+	outputCost := float64(u.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
+
+	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+}
@@ -0,0 +1,73 @@
+// Package codex is the agent driver for OpenAI Codex.
+package codex
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/agentdriver"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// Name is the preset this driver registers under.
+const Name = "codex"
+
+func init() {
+	agentdriver.Register(Name, New)
+}
+
+// Driver drives Codex.
+type Driver struct {
+	*agentdriver.Generic
+}
+
+// New returns the Codex driver for rc.
+func New(rc *config.RuntimeConfig) agentdriver.AgentDriver {
+	g := agentdriver.NewGeneric(Name, rc)
+	g.BusyMarkers = []string{"esc to interrupt"}
+	return &Driver{Generic: g}
+}
+
+// Transcript returns Codex's sessions dir under configDir, $CODEX_HOME or
+// ~/.codex. Codex keeps every session under sessions/YYYY/MM/DD and records
+// the cwd in each rollout's first line, so only rollouts for workDir match.
+func (d *Driver) Transcript(workDir, configDir string) transcript.Source {
+	if d.TranscriptDir() != "" {
+		return d.Generic.Transcript(workDir, configDir)
+	}
+	base := configDir
+	if base == "" {
+		base = os.Getenv("CODEX_HOME")
+	}
+	if base == "" {
+		base = "~/.codex"
+	}
+	cwd, _ := json.Marshal(workDir)
+	return transcript.Source{
+		Provider:  Name,
+		Dirs:      []string{filepath.Join(base, "sessions")},
+		Recursive: true,
+		Match: func(path string) bool {
+			line, err := firstLine(path)
+			return err == nil && strings.Contains(line, `"cwd":`+string(cwd))
+		},
+		ConfigDir: configDir,
+	}
+}
+
+func firstLine(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from a directory walk
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return line, nil
+}
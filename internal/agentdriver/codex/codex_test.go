@@ -0,0 +1,50 @@
+package codex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+func TestTranscript(t *testing.T) {
+	codexHome := t.TempDir()
+	now := time.Now()
+	day := filepath.Join(codexHome, "sessions", "2026", "03", "01")
+	for name, content := range map[string]string{
+		"rollout-mine.jsonl":  `{"type":"session_meta","payload":{"cwd":"/town/gastown/polecats/Nux/gastown"}}`,
+		"rollout-other.jsonl": `{"type":"session_meta","payload":{"cwd":"/town/gastown/polecats/Toast/gastown"}}`,
+	} {
+		path := filepath.Join(day, name)
+		if err := os.MkdirAll(day, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now
+		if name == "rollout-mine.jsonl" {
+			mtime = now.Add(-time.Minute)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := New(config.LookupAgentConfig("codex"))
+	got, err := transcript.Locate(d.Transcript("/town/gastown/polecats/Nux/gastown", codexHome))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(got) != "rollout-mine.jsonl" {
+		t.Errorf("Locate = %s, want the rollout for this cwd", got)
+	}
+
+	t.Setenv("CODEX_HOME", "/srv/codex")
+	if src := d.Transcript("/w", ""); src.Dirs[0] != "/srv/codex/sessions" {
+		t.Errorf("CODEX_HOME not honored: Dirs = %v", src.Dirs)
+	}
+}
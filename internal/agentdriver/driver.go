@@ -0,0 +1,185 @@
+// Package agentdriver defines how Gas Town drives an agent runtime (Claude,
+// Codex, Gemini, ...) inside a tmux session.
+//
+// A runtime that needs more than its config describes is one package under
+// internal/agentdriver that registers an AgentDriver in its init function.
+// Runtimes without their own package get the Generic driver, which works
+// from the runtime's RuntimeConfig alone.
+// internal/runtime imports every driver package, so looking a driver up
+// through it always sees the full set.
+package agentdriver
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// Status is what an agent in a session is doing.
+type Status string
+
+const (
+	StatusStopped Status = "stopped" // Runtime process not running
+	StatusIdle    Status = "idle"    // Waiting at its prompt
+	StatusBusy    Status = "busy"    // Working on a prompt
+	StatusUnknown Status = "unknown" // Running, but the driver can't tell
+)
+
+// ErrNoCost is returned by ParseCost for runtimes whose transcripts don't
+// record token usage.
+var ErrNoCost = errors.New("runtime transcripts carry no cost data")
+
+// AgentDriver is everything Gas Town needs to know about one agent runtime.
+type AgentDriver interface {
+	// Name is the runtime's preset name (claude, codex, ...).
+	Name() string
+
+	// Setup installs the runtime's hooks or plugins for role in workDir.
+	Setup(workDir, role string) error
+
+	// StartCommand returns the command line that starts the runtime with
+	// an initial prompt ("" for none).
+	StartCommand(prompt string) string
+
+	// ResumeCommand returns the command line that resumes a session, or ""
+	// if the runtime can't resume.
+	ResumeCommand(sessionID string) string
+
+	// AfterStart handles anything the runtime shows once started that
+	// would block a prompt (e.g. a permissions dialog).
+	AfterStart(t *tmux.Tmux, session string) error
+
+	// WaitReady blocks until the runtime is ready for a prompt.
+	WaitReady(t *tmux.Tmux, session string, timeout time.Duration) error
+
+	// SendPrompt types prompt into the runtime and submits it.
+	SendPrompt(t *tmux.Tmux, session, prompt string) error
+
+	// Status reports whether the runtime is idle at its prompt or busy.
+	Status(t *tmux.Tmux, session string) Status
+
+	// Interrupt stops the runtime's current turn, leaving it running.
+	Interrupt(t *tmux.Tmux, session string) error
+
+	// Transcript says where the runtime writes the transcript of a session
+	// that ran in workDir under configDir (the account dir, "" for the
+	// default).
+	Transcript(workDir, configDir string) transcript.Source
+
+	// ParseCost returns the USD cost recorded in a transcript, or ErrNoCost.
+	ParseCost(transcriptPath string) (float64, error)
+}
+
+// Factory builds a driver for a resolved runtime config.
+type Factory func(rc *config.RuntimeConfig) AgentDriver
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a driver available under a preset name. It is called from
+// the init function of each driver package.
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = f
+}
+
+// Registered returns the names of the registered drivers, sorted.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the driver registered under name, built for rc.
+func Lookup(name string, rc *config.RuntimeConfig) (AgentDriver, bool) {
+	registryMu.RLock()
+	f, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return f(rc), true
+}
+
+func registered(name string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, ok := registry[name]
+	return ok
+}
+
+// For returns the driver for a resolved runtime config, falling back to the
+// Generic driver for runtimes without a package of their own.
+func For(rc *config.RuntimeConfig) AgentDriver {
+	if rc == nil {
+		rc = config.DefaultRuntimeConfig()
+	}
+	name := nameFor(rc)
+	if d, ok := Lookup(name, rc); ok {
+		return d
+	}
+	return NewGeneric(name, rc)
+}
+
+// ForAgent returns the driver for an agent preset name, as recorded in
+// GT_AGENT. An empty or unknown name is the default agent.
+func ForAgent(name string) AgentDriver {
+	if name == "" {
+		name = string(config.DefaultAgentPreset())
+	}
+	rc := config.LookupAgentConfig(name)
+	if d, ok := Lookup(name, rc); ok {
+		return d
+	}
+	return For(rc)
+}
+
+// ForSession returns the driver for the agent running in a tmux session,
+// going by the session's GT_AGENT.
+func ForSession(t *tmux.Tmux, session string) AgentDriver {
+	agent, _ := t.GetEnvironment(session, "GT_AGENT")
+	return ForAgent(agent)
+}
+
+// nameFor picks the driver name for rc. Configs resolved from a preset keep
+// the default "claude" provider, so a command that belongs to a registered
+// preset, or failing that a built-in one, says more than the provider does.
+func nameFor(rc *config.RuntimeConfig) string {
+	if rc.Command != "" {
+		cmd := filepath.Base(rc.Command)
+		builtin := ""
+		for _, name := range config.ListAgentPresets() {
+			info := config.GetAgentPresetByName(name)
+			if info == nil || filepath.Base(info.Command) != cmd {
+				continue
+			}
+			if registered(name) {
+				return name
+			}
+			if config.IsBuiltinPreset(name) {
+				builtin = name
+			}
+		}
+		if builtin != "" {
+			return builtin
+		}
+	}
+	if rc.Provider == "" {
+		return string(config.DefaultAgentPreset())
+	}
+	return rc.Provider
+}
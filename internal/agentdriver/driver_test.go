@@ -0,0 +1,91 @@
+package agentdriver
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestForFallsBackToGeneric(t *testing.T) {
+	rc := &config.RuntimeConfig{Provider: "mystery", Command: "/opt/bin/mystery"}
+	d := For(rc)
+	if _, ok := d.(*Generic); !ok || d.Name() != "mystery" {
+		t.Errorf("For(unregistered) = %T %q, want Generic named mystery", d, d.Name())
+	}
+}
+
+func TestForPrefersPresetCommand(t *testing.T) {
+	Register("codex", func(rc *config.RuntimeConfig) AgentDriver { return NewGeneric("codex", rc) })
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "codex")
+		registryMu.Unlock()
+	}()
+
+	// Presets resolve with the default provider; the command gives them away
+	rc := &config.RuntimeConfig{Provider: "claude", Command: "codex"}
+	if got := For(rc).Name(); got != "codex" {
+		t.Errorf("For(codex command) = %q, want codex", got)
+	}
+	rc = &config.RuntimeConfig{Provider: "codex", Command: "/usr/local/bin/codex-wrapper"}
+	if got := For(rc).Name(); got != "codex" {
+		t.Errorf("For(codex provider) = %q, want codex", got)
+	}
+	if got := ForAgent("codex").Name(); got != "codex" {
+		t.Errorf("ForAgent(codex) = %q", got)
+	}
+}
+
+func TestStatusFromPane(t *testing.T) {
+	rc := &config.RuntimeConfig{Tmux: &config.RuntimeTmuxConfig{ReadyPromptPrefix: "❯ "}}
+	g := NewGeneric("claude", rc)
+	g.BusyMarkers = []string{"esc to interrupt"}
+
+	tests := []struct {
+		name  string
+		lines []string
+		want  Status
+	}{
+		{"prompt", []string{"done.", "", "❯ "}, StatusIdle},
+		{"prompt with draft", []string{"  ❯ fix the tests"}, StatusIdle},
+		{"busy marker wins", []string{"✻ Thinking… (esc to interrupt)", "❯ "}, StatusBusy},
+		{"no prompt", []string{"running tests..."}, StatusBusy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.statusFromPane(tt.lines); got != tt.want {
+				t.Errorf("statusFromPane = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	noPrefix := NewGeneric("amp", &config.RuntimeConfig{})
+	if got := noPrefix.statusFromPane([]string{"> "}); got != StatusUnknown {
+		t.Errorf("statusFromPane without a prompt prefix = %s, want unknown", got)
+	}
+}
+
+func TestGenericTranscript(t *testing.T) {
+	g := NewGeneric("gemini", &config.RuntimeConfig{})
+	if src := g.Transcript("/w", ""); len(src.Dirs) != 0 {
+		t.Errorf("Transcript without transcript_dir = %+v, want no dirs", src)
+	}
+
+	g = NewGeneric("gemini", &config.RuntimeConfig{Session: &config.RuntimeSessionConfig{TranscriptDir: "~/.gemini/logs"}})
+	src := g.Transcript("/w", "")
+	if len(src.Dirs) != 1 || src.Dirs[0] != "~/.gemini/logs" || !src.Recursive || src.Provider != "gemini" {
+		t.Errorf("Transcript = %+v", src)
+	}
+	if _, err := g.ParseCost("/nope"); err != ErrNoCost {
+		t.Errorf("ParseCost = %v, want ErrNoCost", err)
+	}
+}
+
+func TestForBuiltinPresetWithoutPackage(t *testing.T) {
+	// cursor has no driver package; its command still names the preset
+	rc := &config.RuntimeConfig{Provider: "claude", Command: "cursor-agent"}
+	d := For(rc)
+	if _, ok := d.(*Generic); !ok || d.Name() != "cursor" {
+		t.Errorf("For(cursor-agent command) = %T %q, want Generic named cursor", d, d.Name())
+	}
+}
@@ -0,0 +1,42 @@
+// Package gemini is the agent driver for Gemini CLI.
+package gemini
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/agentdriver"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Name is the preset this driver registers under.
+const Name = "gemini"
+
+// busyMarker is shown while Gemini works on a prompt.
+const busyMarker = "esc to cancel"
+
+func init() {
+	agentdriver.Register(Name, New)
+}
+
+// Driver drives Gemini CLI.
+type Driver struct {
+	*agentdriver.Generic
+}
+
+// New returns the Gemini driver for rc.
+func New(rc *config.RuntimeConfig) agentdriver.AgentDriver {
+	g := agentdriver.NewGeneric(Name, rc)
+	g.BusyMarkers = []string{busyMarker}
+	return &Driver{Generic: g}
+}
+
+// StartCommand passes the prompt with --prompt-interactive. Gemini runs a
+// positional prompt one-shot and exits, which would end the session.
+func (d *Driver) StartCommand(prompt string) string {
+	base := d.Config().BuildCommand()
+	full := d.Config().BuildCommandWithPrompt(prompt)
+	if full == base {
+		return base
+	}
+	return base + " --prompt-interactive" + strings.TrimPrefix(full, base)
+}
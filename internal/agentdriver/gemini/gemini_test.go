@@ -0,0 +1,18 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestStartCommandKeepsSessionInteractive(t *testing.T) {
+	d := New(&config.RuntimeConfig{Command: "gemini", Args: []string{"--approval-mode", "yolo"}})
+
+	if got, want := d.StartCommand(""), "gemini --approval-mode yolo"; got != want {
+		t.Errorf("StartCommand(\"\") = %q, want %q", got, want)
+	}
+	if got, want := d.StartCommand("run gt prime"), `gemini --approval-mode yolo --prompt-interactive "run gt prime"`; got != want {
+		t.Errorf("StartCommand(prompt) = %q, want %q", got, want)
+	}
+}
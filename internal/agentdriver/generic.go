@@ -0,0 +1,140 @@
+package agentdriver
+
+import (
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// statusLines is how much of the pane Status looks at.
+const statusLines = 15
+
+// Generic drives any runtime from its RuntimeConfig: it starts and resumes
+// it with the configured command, waits for the configured prompt prefix,
+// and finds transcripts in session.transcript_dir. Driver packages embed it
+// and override what their runtime does differently.
+type Generic struct {
+	name string
+	rc   *config.RuntimeConfig
+
+	// BusyMarkers are pane text the runtime shows only while working on a
+	// prompt (e.g. "esc to interrupt"). Without any, Status calls the
+	// runtime idle when its prompt prefix is on screen.
+	BusyMarkers []string
+}
+
+// NewGeneric returns the Generic driver for a runtime.
+func NewGeneric(name string, rc *config.RuntimeConfig) *Generic {
+	if rc == nil {
+		rc = config.DefaultRuntimeConfig()
+	}
+	return &Generic{name: name, rc: rc}
+}
+
+// Name returns the runtime's preset name.
+func (g *Generic) Name() string { return g.name }
+
+// Config returns the runtime config the driver was built for.
+func (g *Generic) Config() *config.RuntimeConfig { return g.rc }
+
+// Setup does nothing: the generic runtime has no hooks to install.
+func (g *Generic) Setup(workDir, role string) error { return nil }
+
+// StartCommand builds the configured command, passing the prompt if the
+// runtime takes one on the command line.
+func (g *Generic) StartCommand(prompt string) string {
+	return g.rc.BuildCommandWithPrompt(prompt)
+}
+
+// ResumeCommand uses the preset's resume flag or subcommand.
+func (g *Generic) ResumeCommand(sessionID string) string {
+	return config.BuildResumeCommand(g.name, sessionID)
+}
+
+// AfterStart does nothing.
+func (g *Generic) AfterStart(t *tmux.Tmux, session string) error { return nil }
+
+// WaitReady waits for the configured prompt prefix, or the configured
+// delay if there is none.
+func (g *Generic) WaitReady(t *tmux.Tmux, session string, timeout time.Duration) error {
+	return t.WaitForRuntimeReady(session, g.rc, timeout)
+}
+
+// SendPrompt nudges the prompt into the session.
+func (g *Generic) SendPrompt(t *tmux.Tmux, session, prompt string) error {
+	return t.NudgeSession(session, prompt)
+}
+
+// Status checks the runtime process, then the bottom of the pane for busy
+// markers and the prompt prefix.
+func (g *Generic) Status(t *tmux.Tmux, session string) Status {
+	if !t.IsRuntimeRunning(session, g.processNames()) {
+		return StatusStopped
+	}
+	lines, err := t.CapturePaneLines(session, statusLines)
+	if err != nil {
+		return StatusUnknown
+	}
+	return g.statusFromPane(lines)
+}
+
+func (g *Generic) statusFromPane(lines []string) Status {
+	for _, line := range lines {
+		for _, marker := range g.BusyMarkers {
+			if strings.Contains(line, marker) {
+				return StatusBusy
+			}
+		}
+	}
+	prefix := ""
+	if g.rc.Tmux != nil {
+		prefix = strings.TrimSpace(g.rc.Tmux.ReadyPromptPrefix)
+	}
+	if prefix == "" {
+		return StatusUnknown
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), prefix) {
+			return StatusIdle
+		}
+	}
+	return StatusBusy
+}
+
+func (g *Generic) processNames() []string {
+	if g.rc.Tmux != nil && len(g.rc.Tmux.ProcessNames) > 0 {
+		return g.rc.Tmux.ProcessNames
+	}
+	return config.GetProcessNames(g.name)
+}
+
+// Interrupt presses Escape, which cancels the current turn in most runtimes.
+func (g *Generic) Interrupt(t *tmux.Tmux, session string) error {
+	return t.SendKeysRaw(session, "Escape")
+}
+
+// Transcript looks in session.transcript_dir, if set.
+func (g *Generic) Transcript(workDir, configDir string) transcript.Source {
+	src := transcript.Source{Provider: g.name, ConfigDir: configDir, Recursive: true}
+	if dir := g.TranscriptDir(); dir != "" {
+		src.Dirs = []string{dir}
+	}
+	return src
+}
+
+// TranscriptDir returns the configured session.transcript_dir, which
+// overrides where any driver looks for transcripts.
+func (g *Generic) TranscriptDir() string {
+	if g.rc.Session == nil {
+		return ""
+	}
+	return g.rc.Session.TranscriptDir
+}
+
+// ParseCost returns ErrNoCost.
+func (g *Generic) ParseCost(transcriptPath string) (float64, error) {
+	return 0, ErrNoCost
+}
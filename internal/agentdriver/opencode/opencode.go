@@ -0,0 +1,39 @@
+// Package opencode is the agent driver for OpenCode.
+package opencode
+
+import (
+	"github.com/steveyegge/gastown/internal/agentdriver"
+	"github.com/steveyegge/gastown/internal/config"
+	opencodeplugin "github.com/steveyegge/gastown/internal/opencode"
+)
+
+// Name is the preset this driver registers under.
+const Name = "opencode"
+
+func init() {
+	agentdriver.Register(Name, New)
+}
+
+// Driver drives OpenCode.
+type Driver struct {
+	*agentdriver.Generic
+}
+
+// New returns the OpenCode driver for rc.
+func New(rc *config.RuntimeConfig) agentdriver.AgentDriver {
+	return &Driver{Generic: agentdriver.NewGeneric(Name, rc)}
+}
+
+// Setup installs the Gas Town plugin, which runs gt prime on session start.
+func (d *Driver) Setup(workDir, role string) error {
+	dir, file := ".opencode/plugin", "gastown.js"
+	if hooks := d.Config().Hooks; hooks != nil {
+		if hooks.Dir != "" {
+			dir = hooks.Dir
+		}
+		if hooks.SettingsFile != "" {
+			file = hooks.SettingsFile
+		}
+	}
+	return opencodeplugin.EnsurePluginAt(workDir, dir, file)
+}
@@ -0,0 +1,368 @@
+// Package auditlog provides the tamper-evident compliance audit log.
+//
+// Audit entries are appended to ~/gt/.audit.jsonl, separate from the events
+// log. Each entry records the hash of the previous entry, so editing,
+// reordering or removing any line breaks the chain, and each entry is signed
+// with the town's ed25519 key. The key is kept outside the town in the user's
+// config directory (~/.config/gastown/audit/), so an agent that can write the
+// town can't re-sign a rewritten log. The file is append-only: KRC never
+// prunes it and nothing in gt rewrites it.
+//
+// gt audit verify walks the chain and checks every hash and signature.
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// LogFile is the name of the audit log in the town root.
+const LogFile = ".audit.jsonl"
+
+// LegacyKeyFile is where the town signing key used to be kept, relative to
+// the town root. A key found there is moved to KeyPath on first use.
+const LegacyKeyFile = "mayor/audit.key"
+
+// Entry is one record in the audit log.
+type Entry struct {
+	Seq       int64           `json:"seq"`
+	Timestamp string          `json:"ts"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	KeyID     string          `json:"key_id"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+	Signature string          `json:"sig"`
+}
+
+// digest computes the entry hash over every field except Hash and Signature.
+func (e *Entry) digest() string {
+	signed := struct {
+		Seq       int64           `json:"seq"`
+		Timestamp string          `json:"ts"`
+		Type      string          `json:"type"`
+		Actor     string          `json:"actor"`
+		Payload   json.RawMessage `json:"payload,omitempty"`
+		KeyID     string          `json:"key_id"`
+		PrevHash  string          `json:"prev_hash"`
+	}{e.Seq, e.Timestamp, e.Type, e.Actor, e.Payload, e.KeyID, e.PrevHash}
+
+	data, _ := json.Marshal(signed)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Path returns the audit log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, LogFile)
+}
+
+// KeyPath returns the signing key path for a town: one key per town in the
+// user's config directory, named after a fingerprint of the town's path.
+func KeyPath(townRoot string) string {
+	if abs, err := filepath.Abs(townRoot); err == nil {
+		townRoot = abs
+	}
+	sum := sha256.Sum256([]byte(townRoot))
+	return filepath.Join(state.ConfigDir(), "audit", hex.EncodeToString(sum[:8])+".key")
+}
+
+// KeyID returns a short fingerprint identifying a public key.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadKey reads the town signing key. Returns nil, nil if none exists.
+func LoadKey(townRoot string) (ed25519.PrivateKey, error) {
+	if err := migrateLegacyKey(townRoot); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(KeyPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading audit key: %w", err)
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid audit key in %s", KeyPath(townRoot))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// migrateLegacyKey moves a key kept in the town (LegacyKeyFile) to KeyPath,
+// so logs signed with it keep verifying.
+func migrateLegacyKey(townRoot string) error {
+	legacy := filepath.Join(townRoot, LegacyKeyFile)
+	data, err := os.ReadFile(legacy) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading audit key: %w", err)
+	}
+	path := KeyPath(townRoot)
+	current, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted town root
+	switch {
+	case os.IsNotExist(err):
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return fmt.Errorf("creating key directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("moving audit key: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("reading audit key: %w", err)
+	case !bytes.Equal(bytes.TrimSpace(current), bytes.TrimSpace(data)):
+		return fmt.Errorf("audit keys %s and %s differ; keep the one the log was signed with", legacy, path)
+	}
+	if err := os.Remove(legacy); err != nil {
+		return fmt.Errorf("removing audit key from town: %w", err)
+	}
+	return nil
+}
+
+// LoadOrCreateKey reads the town signing key, generating one on first use.
+func LoadOrCreateKey(townRoot string) (ed25519.PrivateKey, error) {
+	key, err := LoadKey(townRoot)
+	if err != nil || key != nil {
+		return key, err
+	}
+
+	_, key, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating audit key: %w", err)
+	}
+	path := KeyPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	// O_EXCL so two processes racing to create the key don't clobber each other
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsExist(err) {
+			return LoadKey(townRoot)
+		}
+		return nil, fmt.Errorf("writing audit key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(hex.EncodeToString(key.Seed()) + "\n"); err != nil {
+		return nil, fmt.Errorf("writing audit key: %w", err)
+	}
+	return key, nil
+}
+
+// Record appends an entry to the audit log of the town containing the
+// current directory. Outside a Gas Town workspace it does nothing.
+func Record(eventType, actor string, payload map[string]interface{}) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	_, err = Append(townRoot, eventType, actor, payload)
+	return err
+}
+
+// Append adds a signed entry to the town's audit log, chained to the
+// previous entry. Appends are serialized across processes with a file lock.
+func Append(townRoot, eventType, actor string, payload map[string]interface{}) (*Entry, error) {
+	key, err := LoadOrCreateKey(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if len(payload) > 0 {
+		if raw, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("marshaling payload: %w", err)
+		}
+	}
+
+	path := Path(townRoot)
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking audit log: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	last, err := lastEntry(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Seq:       1,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Type:      eventType,
+		Actor:     actor,
+		Payload:   raw,
+		KeyID:     KeyID(key.Public().(ed25519.PublicKey)),
+	}
+	if last != nil {
+		entry.Seq = last.Seq + 1
+		entry.PrevHash = last.Hash
+	}
+	entry.Hash = entry.digest()
+	entry.Signature = hex.EncodeToString(ed25519.Sign(key, []byte(entry.Hash)))
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return nil, fmt.Errorf("writing audit entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return nil, fmt.Errorf("syncing audit log: %w", err)
+	}
+	return entry, nil
+}
+
+// lastEntry returns the final entry in the log, or nil if the log is empty.
+// Only the tail of the file is read.
+func lastEntry(path string) (*Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	const tailSize = 64 * 1024
+	offset := info.Size() - tailSize
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	tail, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	lines := bytes.Split(bytes.TrimRight(tail, "\n"), []byte("\n"))
+	line := lines[len(lines)-1]
+	if len(line) == 0 {
+		return nil, nil
+	}
+	var e Entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		// Refuse to extend a chain whose tail we can't read
+		return nil, fmt.Errorf("audit log tail is corrupt: %w", err)
+	}
+	return &e, nil
+}
+
+// Break describes the first point where the chain fails verification.
+type Break struct {
+	Line   int    `json:"line"`
+	Seq    int64  `json:"seq,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// VerifyResult is the outcome of verifying an audit log.
+type VerifyResult struct {
+	Entries int    `json:"entries"`
+	KeyID   string `json:"key_id"`
+	Break   *Break `json:"break,omitempty"`
+}
+
+// OK reports whether the whole chain verified.
+func (r *VerifyResult) OK() bool {
+	return r.Break == nil
+}
+
+// Verify checks every entry in the town's audit log: sequence numbers are
+// contiguous, each entry links to the previous entry's hash, each hash
+// matches the entry contents, and each signature is valid for pub.
+func Verify(townRoot string, pub ed25519.PublicKey) (*VerifyResult, error) {
+	result := &VerifyResult{KeyID: KeyID(pub)}
+
+	f, err := os.Open(Path(townRoot)) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var prev *Entry
+	line := 0
+	fail := func(seq int64, format string, args ...interface{}) (*VerifyResult, error) {
+		result.Break = &Break{Line: line, Seq: seq, Reason: fmt.Sprintf(format, args...)}
+		return result, nil
+	}
+
+	for scanner.Scan() {
+		line++
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fail(0, "unparseable entry: %v", err)
+		}
+
+		wantSeq, wantPrev := int64(1), ""
+		if prev != nil {
+			wantSeq, wantPrev = prev.Seq+1, prev.Hash
+		}
+		if e.Seq != wantSeq {
+			return fail(e.Seq, "sequence %d, expected %d (entry missing or reordered)", e.Seq, wantSeq)
+		}
+		if e.PrevHash != wantPrev {
+			return fail(e.Seq, "previous hash does not match entry %d", wantSeq-1)
+		}
+		if e.digest() != e.Hash {
+			return fail(e.Seq, "hash mismatch (entry modified)")
+		}
+		if e.KeyID != result.KeyID {
+			return fail(e.Seq, "signed with key %s, expected %s", e.KeyID, result.KeyID)
+		}
+		sig, err := hex.DecodeString(e.Signature)
+		if err != nil || !ed25519.Verify(pub, []byte(e.Hash), sig) {
+			return fail(e.Seq, "invalid signature")
+		}
+
+		result.Entries++
+		prev = &e
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return result, nil
+}
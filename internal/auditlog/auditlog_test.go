@@ -0,0 +1,184 @@
+package auditlog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTown returns an empty town root, with the user's config directory (where
+// the town key is kept) redirected to a temp dir.
+func newTown(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	return t.TempDir()
+}
+
+func appendEntries(t *testing.T, townRoot string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := Append(townRoot, "sling", "mayor", map[string]interface{}{"bead": "gt-1", "n": i}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+}
+
+func publicKey(t *testing.T, townRoot string) ed25519.PublicKey {
+	t.Helper()
+	key, err := LoadKey(townRoot)
+	if err != nil || key == nil {
+		t.Fatalf("LoadKey() = %v, %v", key, err)
+	}
+	return key.Public().(ed25519.PublicKey)
+}
+
+func TestAppendAndVerify(t *testing.T) {
+	townRoot := newTown(t)
+	appendEntries(t, townRoot, 3)
+
+	info, err := os.Stat(KeyPath(townRoot))
+	if err != nil {
+		t.Fatalf("key not created: %v", err)
+	}
+	if strings.HasPrefix(KeyPath(townRoot), townRoot) {
+		t.Errorf("key %s is inside the town", KeyPath(townRoot))
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("key mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	result, err := Verify(townRoot, publicKey(t, townRoot))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !result.OK() || result.Entries != 3 {
+		t.Errorf("Verify() = %+v, want 3 intact entries", result)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		reason string
+	}{
+		{"modified payload", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"gt-1"`, `"gt-2"`, 1)
+			return lines
+		}, "hash mismatch"},
+		{"removed entry", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}, "sequence"},
+		{"reordered entries", func(lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, "sequence"},
+		{"garbage line", func(lines []string) []string {
+			lines[2] = "not json"
+			return lines
+		}, "unparseable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := newTown(t)
+			appendEntries(t, townRoot, 3)
+
+			data, err := os.ReadFile(Path(townRoot))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(strings.Split(strings.TrimSpace(string(data)), "\n"))
+			if err := os.WriteFile(Path(townRoot), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Verify(townRoot, publicKey(t, townRoot))
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if result.OK() {
+				t.Fatal("Verify() passed a tampered log")
+			}
+			if !strings.Contains(result.Break.Reason, tt.reason) {
+				t.Errorf("Break.Reason = %q, want it to mention %q", result.Break.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestVerifyWrongKey(t *testing.T) {
+	townRoot := newTown(t)
+	appendEntries(t, townRoot, 1)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Verify(townRoot, other)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if result.OK() {
+		t.Error("Verify() passed with a different key")
+	}
+}
+
+func TestAppendRefusesCorruptTail(t *testing.T) {
+	townRoot := newTown(t)
+	appendEntries(t, townRoot, 1)
+
+	f, err := os.OpenFile(Path(townRoot), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{truncated\n")
+	f.Close()
+
+	if _, err := Append(townRoot, "kill", "mayor", nil); err == nil {
+		t.Error("Append() should refuse to extend a corrupt chain")
+	}
+}
+
+func TestVerifyEmpty(t *testing.T) {
+	townRoot := newTown(t)
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	result, err := Verify(townRoot, pub)
+	if err != nil || !result.OK() || result.Entries != 0 {
+		t.Errorf("Verify() on missing log = %+v, %v", result, err)
+	}
+}
+
+func TestLegacyKeyMigrated(t *testing.T) {
+	townRoot := newTown(t)
+	appendEntries(t, townRoot, 2)
+	pub := publicKey(t, townRoot)
+
+	// Simulate a town whose key still lives in mayor/audit.key
+	data, err := os.ReadFile(KeyPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(KeyPath(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(townRoot, LegacyKeyFile)
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	appendEntries(t, townRoot, 1)
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy key still in the town: %v", err)
+	}
+	result, err := Verify(townRoot, pub)
+	if err != nil || !result.OK() || result.Entries != 3 {
+		t.Errorf("Verify() after migration = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,245 @@
+// Package autoscale decides when a rig needs more polecats. Decide is pure
+// policy: callers measure ready work, polecat counts and merge queue depth
+// (gt autoscale), act on the decision, and log it. The state kept per rig is
+// its last scale-up time, for cooldowns, its last decision, so that only
+// changes are logged, and the beads whose slings failed, so that they are
+// backed off instead of retried every run.
+package autoscale
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Action is the outcome of one autoscaling decision.
+type Action string
+
+const (
+	// ActionScaleUp spawns Decision.Spawn polecats.
+	ActionScaleUp Action = "scale_up"
+	// ActionIdle holds: no ready work is waiting.
+	ActionIdle Action = "idle"
+	// ActionAtMax holds: the rig is at its polecat limit.
+	ActionAtMax Action = "at_max"
+	// ActionBackPressure holds: the merge queue is saturated.
+	ActionBackPressure Action = "back_pressure"
+	// ActionCooldown holds: the rig scaled up too recently.
+	ActionCooldown Action = "cooldown"
+	// ActionBlocked holds: the rig is parked, docked, has queued slings, or its
+	// host is saturated.
+	ActionBlocked Action = "blocked"
+)
+
+// State is what the autoscaler knows about a rig at decision time.
+type State struct {
+	Rig         string    `json:"rig"`
+	Ready       int       `json:"ready"`        // ready beads waiting for a polecat
+	BackedOff   int       `json:"backed_off"`   // ready beads skipped after failed slings
+	Polecats    int       `json:"polecats"`     // polecats occupying a slot
+	MaxPolecats int       `json:"max_polecats"` // rig max_polecats, <= 0 means unlimited
+	MergeQueue  int       `json:"merge_queue"`  // open merge requests
+	LastScaleUp time.Time `json:"last_scale_up,omitempty"`
+
+	// Blocked explains why the rig takes no new polecats (e.g. "parked").
+	Blocked string `json:"blocked,omitempty"`
+}
+
+// Decision is the autoscaler's verdict for one rig.
+type Decision struct {
+	Rig    string `json:"rig"`
+	Action Action `json:"action"`
+	Spawn  int    `json:"spawn"`
+	Limit  int    `json:"limit"` // effective polecat limit, 0 = unlimited
+	Reason string `json:"reason"`
+}
+
+// Limit returns the effective polecat limit: the lower of the autoscale
+// max_polecats and the rig's max_polecats, or 0 when neither is set.
+func Limit(cfg *config.AutoscaleConfig, rigMax int) int {
+	limit := rigMax
+	if limit < 0 {
+		limit = 0
+	}
+	if cfg != nil && cfg.MaxPolecats > 0 && (limit == 0 || cfg.MaxPolecats < limit) {
+		limit = cfg.MaxPolecats
+	}
+	return limit
+}
+
+// Decide returns how many polecats to spawn for a rig.
+//
+// The rig scales up by one polecat per ReadyPerPolecat waiting ready beads,
+// at most MaxStep at a time and never past the limit. It holds while the
+// merge queue has MaxMergeQueue or more open requests (more polecats would
+// only grow the backlog) and for Cooldown after each scale-up. Below
+// MinPolecats the cooldown and step size are ignored, but back-pressure is not.
+func Decide(cfg *config.AutoscaleConfig, s State, now time.Time) Decision {
+	if cfg == nil {
+		cfg = config.DefaultAutoscaleConfig()
+	}
+	limit := Limit(cfg, s.MaxPolecats)
+	maxMQ := cfg.MaxMergeQueueOrDefault()
+	d := Decision{Rig: s.Rig, Limit: limit}
+
+	limitStr := "unlimited"
+	if limit > 0 {
+		limitStr = fmt.Sprintf("%d", limit)
+	}
+	summary := fmt.Sprintf("%d ready, %d/%s polecats, merge queue %d/%d",
+		s.Ready, s.Polecats, limitStr, s.MergeQueue, maxMQ)
+
+	hold := func(a Action, why string) Decision {
+		d.Action = a
+		d.Reason = why + "; " + summary
+		return d
+	}
+
+	switch {
+	case s.Blocked != "":
+		return hold(ActionBlocked, s.Blocked)
+	case s.Ready == 0:
+		return hold(ActionIdle, "no ready work")
+	case limit > 0 && s.Polecats >= limit:
+		return hold(ActionAtMax, "at polecat limit")
+	case s.MergeQueue >= maxMQ:
+		return hold(ActionBackPressure, "refinery saturated")
+	}
+
+	deficit := cfg.MinPolecats - s.Polecats
+	if deficit <= 0 && !s.LastScaleUp.IsZero() {
+		cooldown := cfg.CooldownDuration()
+		if since := now.Sub(s.LastScaleUp); since < cooldown {
+			return hold(ActionCooldown, fmt.Sprintf("cooling down (%s left)", (cooldown-since).Round(time.Second)))
+		}
+	}
+
+	perPolecat := cfg.ReadyPerPolecatOrDefault()
+	spawn := (s.Ready + perPolecat - 1) / perPolecat
+	if step := cfg.MaxStepOrDefault(); spawn > step {
+		spawn = step
+	}
+	if spawn < deficit {
+		spawn = deficit
+	}
+	if spawn > s.Ready {
+		spawn = s.Ready
+	}
+	if limit > 0 && spawn > limit-s.Polecats {
+		spawn = limit - s.Polecats
+	}
+
+	d.Action = ActionScaleUp
+	d.Spawn = spawn
+	d.Reason = summary
+	if deficit > 0 {
+		d.Reason = fmt.Sprintf("below min_polecats %d; %s", cfg.MinPolecats, summary)
+	}
+	return d
+}
+
+// stateFile is where a rig's autoscaler state is kept, relative to the rig.
+const stateFile = ".runtime/autoscale.json"
+
+// Sling failure backoff: a bead whose sling failed is skipped for
+// failureBackoff, doubling with each further failure up to maxFailureBackoff.
+const (
+	failureBackoff    = 5 * time.Minute
+	maxFailureBackoff = 4 * time.Hour
+)
+
+type persisted struct {
+	LastScaleUp   time.Time                `json:"last_scale_up"`
+	SlingFailures map[string]*slingFailure `json:"sling_failures,omitempty"`
+}
+
+type slingFailure struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// load reads the rig's state; a missing or unreadable file is empty state.
+func load(rigPath string) *persisted {
+	var p persisted
+	data, err := os.ReadFile(filepath.Join(rigPath, stateFile)) //nolint:gosec // G304: path is constructed internally
+	if err == nil {
+		_ = json.Unmarshal(data, &p)
+	}
+	return &p
+}
+
+// update applies fn to the rig's state and saves it.
+func update(rigPath string, fn func(p *persisted)) error {
+	p := load(rigPath)
+	fn(p)
+	path := filepath.Join(rigPath, stateFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, p)
+}
+
+// LastScaleUp returns when the rig at rigPath last scaled up, or the zero
+// time if it never has.
+func LastScaleUp(rigPath string) time.Time {
+	return load(rigPath).LastScaleUp
+}
+
+// RecordScaleUp stores t as the rig's last scale-up time, starting its cooldown.
+func RecordScaleUp(rigPath string, t time.Time) error {
+	return update(rigPath, func(p *persisted) { p.LastScaleUp = t.UTC() })
+}
+
+// RecordSlingFailure notes that slinging bead failed at t, extending its backoff.
+func RecordSlingFailure(rigPath, bead string, t time.Time) error {
+	return update(rigPath, func(p *persisted) {
+		if p.SlingFailures == nil {
+			p.SlingFailures = make(map[string]*slingFailure)
+		}
+		f := p.SlingFailures[bead]
+		if f == nil {
+			f = &slingFailure{}
+			p.SlingFailures[bead] = f
+		}
+		f.Count++
+		f.Last = t.UTC()
+	})
+}
+
+// ClearSlingFailure forgets the failures of a bead that was slung.
+func ClearSlingFailure(rigPath, bead string) error {
+	if load(rigPath).SlingFailures[bead] == nil {
+		return nil
+	}
+	return update(rigPath, func(p *persisted) { delete(p.SlingFailures, bead) })
+}
+
+// BackedOff returns the beads whose last sling failed too recently to retry
+// at now.
+func BackedOff(rigPath string, now time.Time) map[string]bool {
+	backedOff := make(map[string]bool)
+	for bead, f := range load(rigPath).SlingFailures {
+		if now.Before(f.Last.Add(FailureBackoff(f.Count))) {
+			backedOff[bead] = true
+		}
+	}
+	return backedOff
+}
+
+// FailureBackoff returns how long a bead is skipped after its count-th
+// consecutive sling failure.
+func FailureBackoff(count int) time.Duration {
+	backoff := failureBackoff
+	for i := 1; i < count && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFailureBackoff {
+		backoff = maxFailureBackoff
+	}
+	return backoff
+}
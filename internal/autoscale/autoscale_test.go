@@ -0,0 +1,119 @@
+package autoscale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		cfgMax, rigMax, want int
+	}{
+		{0, 0, 0},
+		{0, 8, 8},
+		{5, 0, 5},
+		{5, 8, 5},
+		{10, 8, 8},
+	}
+	for _, tt := range tests {
+		cfg := &config.AutoscaleConfig{MaxPolecats: tt.cfgMax}
+		if got := Limit(cfg, tt.rigMax); got != tt.want {
+			t.Errorf("Limit(%d, %d) = %d, want %d", tt.cfgMax, tt.rigMax, got, tt.want)
+		}
+	}
+}
+
+func TestDecide(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Minute)
+	cfg := config.DefaultAutoscaleConfig()
+	cfg.Enabled = true
+	cfg.MinPolecats = 2
+
+	tests := []struct {
+		name   string
+		cfg    *config.AutoscaleConfig
+		state  State
+		action Action
+		spawn  int
+	}{
+		{"blocked", cfg, State{Ready: 5, Blocked: "parked"}, ActionBlocked, 0},
+		{"no work", cfg, State{Ready: 0, Polecats: 0}, ActionIdle, 0},
+		{"at max", cfg, State{Ready: 5, Polecats: 4, MaxPolecats: 4}, ActionAtMax, 0},
+		{"back-pressure", cfg, State{Ready: 5, Polecats: 0, MergeQueue: 5}, ActionBackPressure, 0},
+		{"scale up by max step", cfg, State{Ready: 5, Polecats: 3}, ActionScaleUp, 2},
+		{"capped by limit", cfg, State{Ready: 5, Polecats: 3, MaxPolecats: 4}, ActionScaleUp, 1},
+		{"capped by ready", cfg, State{Ready: 1, Polecats: 3}, ActionScaleUp, 1},
+		{"cooldown", cfg, State{Ready: 5, Polecats: 3, LastScaleUp: recent}, ActionCooldown, 0},
+		{"cooldown elapsed", cfg, State{Ready: 5, Polecats: 3, LastScaleUp: now.Add(-time.Hour)}, ActionScaleUp, 2},
+		{"min floor ignores cooldown", cfg, State{Ready: 5, Polecats: 1, LastScaleUp: recent}, ActionScaleUp, 2},
+		{"min floor still honors back-pressure", cfg, State{Ready: 5, Polecats: 0, MergeQueue: 9}, ActionBackPressure, 0},
+		{"ready per polecat", &config.AutoscaleConfig{ReadyPerPolecat: 3, MaxStep: 10}, State{Ready: 7}, ActionScaleUp, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Decide(tt.cfg, tt.state, now)
+			if d.Action != tt.action || d.Spawn != tt.spawn {
+				t.Errorf("Decide() = %s +%d (%s), want %s +%d", d.Action, d.Spawn, d.Reason, tt.action, tt.spawn)
+			}
+		})
+	}
+}
+
+func TestDecideMinFloorExceedsStep(t *testing.T) {
+	cfg := &config.AutoscaleConfig{MinPolecats: 4, MaxStep: 1}
+	d := Decide(cfg, State{Ready: 10, Polecats: 0}, time.Now())
+	if d.Action != ActionScaleUp || d.Spawn != 4 {
+		t.Errorf("Decide() = %s +%d, want scale_up +4 to reach min_polecats", d.Action, d.Spawn)
+	}
+}
+
+func TestRecordScaleUp(t *testing.T) {
+	rigPath := t.TempDir()
+	if got := LastScaleUp(rigPath); !got.IsZero() {
+		t.Fatalf("LastScaleUp() before any scale-up = %v", got)
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := RecordScaleUp(rigPath, at); err != nil {
+		t.Fatal(err)
+	}
+	if got := LastScaleUp(rigPath); !got.Equal(at) {
+		t.Errorf("LastScaleUp() = %v, want %v", got, at)
+	}
+}
+
+func TestSlingFailureBackoff(t *testing.T) {
+	rigPath := t.TempDir()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := RecordScaleUp(rigPath, at); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := RecordSlingFailure(rigPath, "gt-abc", at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two failures back off for twice the base interval
+	if !BackedOff(rigPath, at.Add(9*time.Minute))["gt-abc"] {
+		t.Error("gt-abc not backed off 9m after its second failure")
+	}
+	if BackedOff(rigPath, at.Add(11*time.Minute))["gt-abc"] {
+		t.Error("gt-abc still backed off 11m after its second failure")
+	}
+	if got := LastScaleUp(rigPath); !got.Equal(at) {
+		t.Errorf("LastScaleUp() = %v after recording failures, want %v", got, at)
+	}
+
+	if err := ClearSlingFailure(rigPath, "gt-abc"); err != nil {
+		t.Fatal(err)
+	}
+	if BackedOff(rigPath, at)["gt-abc"] {
+		t.Error("gt-abc backed off after its failures were cleared")
+	}
+	if got := FailureBackoff(100); got != 4*time.Hour {
+		t.Errorf("FailureBackoff(100) = %v, want the 4h cap", got)
+	}
+}
@@ -933,6 +933,43 @@ Some existing prose content.`,
 	}
 }
 
+func TestMoleculeFormulaFieldsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Work on gt-abc.\n\nattached_molecule: gt-wisp-1"}
+	fields := &MoleculeFormulaFields{
+		Formula: "mol-polecat-work",
+		Steps: map[string]string{
+			"gt-wisp-1.2": "implement",
+			"gt-wisp-1.1": "load-context",
+		},
+	}
+
+	desc := SetMoleculeFormulaFields(issue, fields)
+	want := `Work on gt-abc.
+
+attached_molecule: gt-wisp-1
+
+formula: mol-polecat-work
+formula_step: gt-wisp-1.1=load-context
+formula_step: gt-wisp-1.2=implement`
+	if desc != want {
+		t.Errorf("SetMoleculeFormulaFields() =\n%s\nwant:\n%s", desc, want)
+	}
+
+	// Setting again replaces rather than appends
+	issue.Description = desc
+	if again := SetMoleculeFormulaFields(issue, fields); again != want {
+		t.Errorf("SetMoleculeFormulaFields() twice =\n%s", again)
+	}
+
+	got := ParseMoleculeFormulaFields(&Issue{Description: desc})
+	if got == nil || got.Formula != "mol-polecat-work" || got.Steps["gt-wisp-1.2"] != "implement" || len(got.Steps) != 2 {
+		t.Errorf("ParseMoleculeFormulaFields() = %+v", got)
+	}
+	if ParseMoleculeFormulaFields(&Issue{Description: "just prose"}) != nil {
+		t.Error("ParseMoleculeFormulaFields() of prose should be nil")
+	}
+}
+
 // TestAttachmentFieldsRoundTrip tests that parse/format round-trips correctly.
 func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	original := &AttachmentFields{
@@ -3,6 +3,7 @@ package beads
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -521,6 +522,116 @@ func FormatSynthesisFields(fields *SynthesisFields) string {
 	return strings.Join(lines, "\n")
 }
 
+// MoleculeFormulaFields records which formula a molecule was poured from and
+// which formula step each of its step beads is. Step timing is keyed on these
+// rather than on rendered titles, which vary with the molecule's vars.
+type MoleculeFormulaFields struct {
+	Formula string            // Formula name (e.g., "mol-polecat-work")
+	Steps   map[string]string // Step bead ID -> formula step ID
+}
+
+// ParseMoleculeFormulaFields extracts formula fields from a molecule root's
+// description. Step mappings are "formula_step: <bead>=<step>" lines.
+// Returns nil if no fields are found.
+func ParseMoleculeFormulaFields(issue *Issue) *MoleculeFormulaFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &MoleculeFormulaFields{}
+	hasFields := false
+
+	for _, line := range strings.Split(issue.Description, "\n") {
+		line = strings.TrimSpace(line)
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+
+		key := strings.TrimSpace(line[:colonIdx])
+		value := strings.TrimSpace(line[colonIdx+1:])
+		if value == "" {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "formula":
+			fields.Formula = value
+			hasFields = true
+		case "formula_step", "formula-step":
+			bead, step, ok := strings.Cut(value, "=")
+			if !ok || bead == "" || step == "" {
+				continue
+			}
+			if fields.Steps == nil {
+				fields.Steps = make(map[string]string)
+			}
+			fields.Steps[strings.TrimSpace(bead)] = strings.TrimSpace(step)
+			hasFields = true
+		}
+	}
+
+	if !hasFields {
+		return nil
+	}
+	return fields
+}
+
+// FormatMoleculeFormulaFields formats MoleculeFormulaFields as a string for
+// an issue description. Step mappings are sorted by bead ID.
+func FormatMoleculeFormulaFields(fields *MoleculeFormulaFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if fields.Formula != "" {
+		lines = append(lines, "formula: "+fields.Formula)
+	}
+	beadIDs := make([]string, 0, len(fields.Steps))
+	for id := range fields.Steps {
+		beadIDs = append(beadIDs, id)
+	}
+	sort.Strings(beadIDs)
+	for _, id := range beadIDs {
+		lines = append(lines, fmt.Sprintf("formula_step: %s=%s", id, fields.Steps[id]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetMoleculeFormulaFields updates a molecule root's description with the
+// given formula fields. Existing formula field lines are replaced; other
+// content is preserved. Returns the new description string.
+func SetMoleculeFormulaFields(issue *Issue, fields *MoleculeFormulaFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if colonIdx := strings.Index(trimmed, ":"); colonIdx != -1 {
+				switch strings.ToLower(strings.TrimSpace(trimmed[:colonIdx])) {
+				case "formula", "formula_step", "formula-step":
+					continue // Replaced below
+				}
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatMoleculeFormulaFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
 // RoleConfig holds structured lifecycle configuration for role beads.
 // These fields are stored as "key: value" lines in the role bead description.
 // This enables agents to self-register their lifecycle configuration,
@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"fmt"
+	"time"
+)
+
+// MemoryStore is an in-memory bead store with the operations pouring a
+// molecule needs. Formulas can be cooked into it and inspected without a bd
+// database, e.g. by golden tests.
+type MemoryStore struct {
+	prefix string
+	issues map[string]*Issue
+	order  []string // IDs in creation order
+}
+
+// NewMemoryStore creates an empty store whose generated IDs use prefix.
+func NewMemoryStore(prefix string) *MemoryStore {
+	return &MemoryStore{prefix: prefix, issues: make(map[string]*Issue)}
+}
+
+// Create creates an issue with the next generated ID (<prefix>-1, ...).
+func (s *MemoryStore) Create(opts CreateOptions) (*Issue, error) {
+	return s.CreateWithID(fmt.Sprintf("%s-%d", s.prefix, len(s.order)+1), opts)
+}
+
+// CreateWithID creates an issue with a specific ID.
+func (s *MemoryStore) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
+	if _, ok := s.issues[id]; ok {
+		return nil, fmt.Errorf("issue %s already exists", id)
+	}
+	if opts.Parent != "" {
+		if _, ok := s.issues[opts.Parent]; !ok {
+			return nil, fmt.Errorf("parent %s: %w", opts.Parent, ErrNotFound)
+		}
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	issue := &Issue{
+		ID:          id,
+		Title:       opts.Title,
+		Description: opts.Description,
+		Status:      "open",
+		Priority:    opts.Priority,
+		Type:        opts.Type,
+		CreatedAt:   now,
+		CreatedBy:   opts.Actor,
+		UpdatedAt:   now,
+		Parent:      opts.Parent,
+	}
+	s.issues[id] = issue
+	s.order = append(s.order, id)
+	if opts.Parent != "" {
+		parent := s.issues[opts.Parent]
+		parent.Children = append(parent.Children, id)
+	}
+	return issue, nil
+}
+
+// AddDependency records that issue depends on dependsOn.
+func (s *MemoryStore) AddDependency(issue, dependsOn string) error {
+	from, ok := s.issues[issue]
+	if !ok {
+		return fmt.Errorf("%s: %w", issue, ErrNotFound)
+	}
+	to, ok := s.issues[dependsOn]
+	if !ok {
+		return fmt.Errorf("%s: %w", dependsOn, ErrNotFound)
+	}
+	from.DependsOn = append(from.DependsOn, dependsOn)
+	to.Blocks = append(to.Blocks, issue)
+	return nil
+}
+
+// Show returns an issue by ID. The issue is the store's own; changes to it
+// are kept.
+func (s *MemoryStore) Show(id string) (*Issue, error) {
+	issue, ok := s.issues[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+	return issue, nil
+}
+
+// Children returns the issues whose parent is parentID, in creation order.
+func (s *MemoryStore) Children(parentID string) []*Issue {
+	var children []*Issue
+	for _, id := range s.order {
+		if issue := s.issues[id]; issue.Parent == parentID {
+			children = append(children, issue)
+		}
+	}
+	return children
+}
@@ -0,0 +1,47 @@
+package beads
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore("gt")
+	root, err := s.Create(CreateOptions{Title: "mol", Type: "molecule"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ID != "gt-1" || root.Status != "open" {
+		t.Errorf("root = %s (%s), want gt-1 open", root.ID, root.Status)
+	}
+	a, _ := s.CreateWithID("gt-1.a", CreateOptions{Title: "A", Parent: root.ID})
+	b, _ := s.CreateWithID("gt-1.b", CreateOptions{Title: "B", Parent: root.ID})
+	if _, err := s.CreateWithID("gt-1.a", CreateOptions{}); err == nil {
+		t.Error("duplicate ID: want error")
+	}
+	if _, err := s.Create(CreateOptions{Parent: "gt-9"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown parent: err = %v, want ErrNotFound", err)
+	}
+
+	if err := s.AddDependency(b.ID, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddDependency(b.ID, "gt-1.z"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown dependency: err = %v, want ErrNotFound", err)
+	}
+	if !reflect.DeepEqual(b.DependsOn, []string{a.ID}) || !reflect.DeepEqual(a.Blocks, []string{b.ID}) {
+		t.Errorf("b.DependsOn = %v, a.Blocks = %v", b.DependsOn, a.Blocks)
+	}
+	if !reflect.DeepEqual(root.Children, []string{a.ID, b.ID}) {
+		t.Errorf("root.Children = %v", root.Children)
+	}
+
+	children := s.Children(root.ID)
+	if len(children) != 2 || children[0] != a || children[1] != b {
+		t.Errorf("Children() = %v, want [a b] in creation order", children)
+	}
+	if got, err := s.Show(b.ID); err != nil || got != b {
+		t.Errorf("Show(%s) = %v, %v", b.ID, got, err)
+	}
+}
@@ -0,0 +1,155 @@
+// Package cgroup runs agent sessions in their own cgroup with memory, CPU and
+// pids limits. Each session's startup command is wrapped in a transient
+// systemd user scope named after the tmux session, so everything the agent
+// spawns is confined with it, and the scope records why it died.
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// UnitName returns the systemd scope unit for a tmux session.
+func UnitName(session string) string {
+	var b strings.Builder
+	for _, c := range session {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == ':':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String() + ".scope"
+}
+
+// Enabled reports whether cfg asks for isolation and the host supports it.
+func Enabled(cfg *config.CgroupConfig) bool {
+	return cfg != nil && cfg.Enabled && Available()
+}
+
+// Wrap returns command wrapped to run in the session's scope with cfg's
+// limits, or command unchanged when isolation is off or unsupported. Any
+// scope left over from an earlier session of the same name is cleared first.
+func Wrap(session, command string, cfg *config.CgroupConfig) string {
+	if !Enabled(cfg) {
+		return command
+	}
+	Reset(session)
+	return wrapCommand(session, command, cfg)
+}
+
+// wrapCommand builds the systemd-run invocation for a session.
+func wrapCommand(session, command string, cfg *config.CgroupConfig) string {
+	args := []string{"systemd-run", "--user", "--scope", "--quiet", "--unit=" + UnitName(session)}
+	if cfg.MemoryMax != "" {
+		// Without swap the memory limit is a real limit: the OOM killer
+		// fires instead of the session thrashing in swap.
+		args = append(args, "-p", config.ShellQuote("MemoryMax="+cfg.MemoryMax), "-p", "MemorySwapMax=0")
+	}
+	if cfg.CPUQuota != "" {
+		args = append(args, "-p", config.ShellQuote("CPUQuota="+cfg.CPUQuota))
+	}
+	if cfg.TasksMax > 0 {
+		args = append(args, "-p", fmt.Sprintf("TasksMax=%d", cfg.TasksMax))
+	}
+	args = append(args, "--", "sh", "-c", config.ShellQuote(command))
+	return strings.Join(args, " ")
+}
+
+// Release clears a stopped session's scope, killing anything it left
+// behind, when cfg enables isolation.
+func Release(session string, cfg *config.CgroupConfig) {
+	if cfg != nil && cfg.Enabled {
+		Reset(session)
+	}
+}
+
+// Reset stops a session's scope, killing anything still running in it, and
+// forgets its failed state. Errors are ignored: usually there is no scope.
+func Reset(session string) {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return
+	}
+	unit := UnitName(session)
+	_ = exec.Command("systemctl", "--user", "stop", unit).Run()
+	_ = exec.Command("systemctl", "--user", "reset-failed", unit).Run()
+}
+
+// DeathReason explains why a session's scope died when a limit was the
+// cause: the OOM killer or the pids limit. It returns "" when the session
+// was not isolated or no limit was hit. Call it before the session is
+// restarted, which resets the scope.
+func DeathReason(session string) string {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return ""
+	}
+	out, err := exec.Command("systemctl", "--user", "show", UnitName(session),
+		"-p", "Result", "-p", "ControlGroup").Output()
+	if err != nil {
+		return ""
+	}
+	props := parseProperties(string(out))
+
+	// The cgroup outlives the pane while stragglers still run in it
+	var memEvents, pidsEvents map[string]int64
+	if cg := props["ControlGroup"]; cg != "" {
+		dir := filepath.Join("/sys/fs/cgroup", cg)
+		memEvents = readEvents(filepath.Join(dir, "memory.events"))
+		pidsEvents = readEvents(filepath.Join(dir, "pids.events"))
+	}
+	return deathReason(props["Result"], memEvents, pidsEvents)
+}
+
+// deathReason maps a scope's result and cgroup event counters to a reason.
+func deathReason(result string, memEvents, pidsEvents map[string]int64) string {
+	switch {
+	case result == "oom-kill" || memEvents["oom_kill"] > 0:
+		return "killed by OOM: cgroup memory limit reached"
+	case pidsEvents["max"] > 0:
+		return "cgroup pids limit reached"
+	}
+	return ""
+}
+
+// parseProperties parses systemctl show's KEY=value lines.
+func parseProperties(out string) map[string]string {
+	props := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// readEvents reads a cgroup *.events file ("key count" lines). A missing
+// file yields nil.
+func readEvents(path string) map[string]int64 {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under /sys/fs/cgroup
+	if err != nil {
+		return nil
+	}
+	return parseEvents(string(data))
+}
+
+func parseEvents(data string) map[string]int64 {
+	events := make(map[string]int64)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			events[fields[0]] = n
+		}
+	}
+	return events
+}
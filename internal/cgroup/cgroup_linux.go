@@ -0,0 +1,18 @@
+package cgroup
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Available reports whether sessions can be isolated: the unified (v2)
+// cgroup hierarchy is mounted and a systemd user manager is reachable.
+func Available() bool {
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err != nil {
+		return false
+	}
+	if _, err := exec.LookPath("systemd-run"); err != nil {
+		return false
+	}
+	return exec.Command("systemctl", "--user", "show-environment").Run() == nil
+}
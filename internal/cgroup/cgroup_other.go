@@ -0,0 +1,8 @@
+//go:build !linux
+
+package cgroup
+
+// Available reports whether sessions can be isolated. cgroups are Linux-only.
+func Available() bool {
+	return false
+}
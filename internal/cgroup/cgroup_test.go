@@ -0,0 +1,84 @@
+package cgroup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestUnitName(t *testing.T) {
+	tests := map[string]string{
+		"gt-greenplace-Toast": "gt-greenplace-Toast.scope",
+		"hq-dog-alpha":        "hq-dog-alpha.scope",
+		"gt-rig/odd name":     "gt-rig_odd_name.scope",
+	}
+	for session, want := range tests {
+		if got := UnitName(session); got != want {
+			t.Errorf("UnitName(%q) = %q, want %q", session, got, want)
+		}
+	}
+}
+
+func TestWrapCommand(t *testing.T) {
+	cfg := &config.CgroupConfig{Enabled: true, MemoryMax: "4G", CPUQuota: "200%", TasksMax: 512}
+	got := wrapCommand("gt-rig-Toast", "export GT_ROLE=polecat && claude 'hi there'", cfg)
+
+	for _, want := range []string{
+		"systemd-run --user --scope --quiet --unit=gt-rig-Toast.scope",
+		"-p MemoryMax=4G -p MemorySwapMax=0",
+		"-p CPUQuota=200%",
+		"-p TasksMax=512",
+		`-- sh -c 'export GT_ROLE=polecat && claude '\''hi there'\'''`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("wrapCommand() = %q, missing %q", got, want)
+		}
+	}
+
+	bare := wrapCommand("s", "claude", &config.CgroupConfig{Enabled: true})
+	if strings.Contains(bare, " -p ") {
+		t.Errorf("wrapCommand() with no limits = %q, want no properties", bare)
+	}
+}
+
+func TestWrapDisabled(t *testing.T) {
+	for _, cfg := range []*config.CgroupConfig{nil, {MemoryMax: "1G"}} {
+		if got := Wrap("s", "claude", cfg); got != "claude" {
+			t.Errorf("Wrap(%+v) = %q, want command unchanged", cfg, got)
+		}
+	}
+}
+
+func TestDeathReason(t *testing.T) {
+	mem := parseEvents("low 0\nhigh 0\nmax 12\noom 1\noom_kill 1\n")
+	pids := parseEvents("max 3\n")
+
+	tests := []struct {
+		name   string
+		result string
+		mem    map[string]int64
+		pids   map[string]int64
+		want   string
+	}{
+		{"scope result", "oom-kill", nil, nil, "killed by OOM: cgroup memory limit reached"},
+		{"oom counter", "success", mem, nil, "killed by OOM: cgroup memory limit reached"},
+		{"pids limit", "success", nil, pids, "cgroup pids limit reached"},
+		{"clean exit", "success", parseEvents("oom_kill 0\n"), parseEvents("max 0\n"), ""},
+		{"not isolated", "", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deathReason(tt.result, tt.mem, tt.pids); got != tt.want {
+				t.Errorf("deathReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	props := parseProperties("Result=oom-kill\nControlGroup=/user.slice/app.slice/gt-x.scope\n")
+	if props["Result"] != "oom-kill" || props["ControlGroup"] != "/user.slice/app.slice/gt-x.scope" {
+		t.Errorf("parseProperties() = %v", props)
+	}
+}
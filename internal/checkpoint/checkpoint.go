@@ -12,10 +12,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/runtime"
 )
 
-// Filename is the checkpoint file name within the polecat directory.
+// Filename is the checkpoint file name within the polecat's home directory.
 const Filename = ".polecat-checkpoint.json"
 
 // Checkpoint represents a session recovery checkpoint.
@@ -49,19 +50,55 @@ type Checkpoint struct {
 
 	// Notes contains optional context from the session.
 	Notes string `json:"notes,omitempty"`
+
+	// PendingClosures lists step IDs the session started closing but had not
+	// confirmed closed when the checkpoint was written. gt mol resume replays
+	// these so a crash mid-close doesn't leave a finished step open.
+	PendingClosures []string `json:"pending_closures,omitempty"`
+
+	// Reason records why the checkpoint was captured (see Reason* constants).
+	Reason string `json:"reason,omitempty"`
 }
 
 // Path returns the checkpoint file path for a given polecat directory.
+// Callers pass the polecat's worktree (or a directory within it); the
+// checkpoint lives in the polecat's home (see Dir) so that neither it nor
+// its lock shows up in git status or gets committed with the work.
 func Path(polecatDir string) string {
-	return filepath.Join(polecatDir, Filename)
+	return filepath.Join(Dir(polecatDir), Filename)
+}
+
+// Dir returns the directory holding the checkpoint for polecatDir: the
+// polecat's home (polecats/<name>/) when polecatDir is in a polecat's
+// worktree, next to the snapshot history, and polecatDir itself otherwise.
+// In the old layout, where the home is the worktree, the two are the same.
+func Dir(polecatDir string) string {
+	home := ""
+	for dir := filepath.Clean(polecatDir); ; {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		// Keep the outermost match, in case the repo has a polecats/ dir
+		if filepath.Base(parent) == "polecats" {
+			home = dir
+		}
+		dir = parent
+	}
+	if home == "" {
+		return polecatDir
+	}
+	return home
 }
 
 // Read loads a checkpoint from the polecat directory.
 // Returns nil, nil if no checkpoint exists.
 func Read(polecatDir string) (*Checkpoint, error) {
-	path := Path(polecatDir)
-
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	data, err := os.ReadFile(Path(polecatDir)) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	if os.IsNotExist(err) {
+		// Checkpoints used to be written into the worktree itself
+		data, err = os.ReadFile(legacyPath(polecatDir)) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -79,30 +116,66 @@ func Read(polecatDir string) (*Checkpoint, error) {
 
 // Write saves a checkpoint to the polecat directory.
 func Write(polecatDir string, cp *Checkpoint) error {
-	// Set timestamp if not already set
-	if cp.Timestamp.IsZero() {
-		cp.Timestamp = time.Now()
+	return Update(polecatDir, func(*Checkpoint) (*Checkpoint, error) {
+		cp.stamp()
+		return cp, nil
+	})
+}
+
+// Update reads the checkpoint in the polecat directory, applies fn and writes
+// the result, holding a file lock throughout so that the polecat and the
+// witness don't overwrite each other's changes. fn receives nil if there is
+// no checkpoint, and returns the checkpoint to write or nil to write nothing.
+// Unlike Write, Update doesn't fill in the timestamp or session ID.
+func Update(polecatDir string, fn func(cp *Checkpoint) (*Checkpoint, error)) error {
+	lock := flock.New(Path(polecatDir) + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking checkpoint: %w", err)
 	}
+	defer func() { _ = lock.Unlock() }()
 
-	// Set session ID from environment if available
-	if cp.SessionID == "" {
-		cp.SessionID = runtime.SessionIDFromEnv()
-		if cp.SessionID == "" {
-			cp.SessionID = fmt.Sprintf("pid-%d", os.Getpid())
-		}
+	cp, err := Read(polecatDir)
+	if err != nil {
+		return err
+	}
+	cp, err = fn(cp)
+	if err != nil || cp == nil {
+		return err
 	}
 
 	data, err := json.MarshalIndent(cp, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling checkpoint: %w", err)
 	}
-
-	path := Path(polecatDir)
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := os.WriteFile(Path(polecatDir), data, 0600); err != nil {
 		return fmt.Errorf("writing checkpoint: %w", err)
 	}
+	return removeLegacy(polecatDir)
+}
 
-	return nil
+// stamp fills in the timestamp and, from the environment, the session ID of
+// a checkpoint the current session is writing, where not already set.
+func (cp *Checkpoint) stamp() {
+	if cp.Timestamp.IsZero() {
+		cp.Timestamp = time.Now()
+	}
+	if cp.SessionID == "" {
+		cp.SessionID = runtime.SessionIDFromEnv()
+		if cp.SessionID == "" {
+			cp.SessionID = fmt.Sprintf("pid-%d", os.Getpid())
+		}
+	}
+}
+
+// RuntimeSessionID returns the agent runtime's ID for the session that
+// wrote the checkpoint, which the runtime may be able to resume. It is ""
+// when no runtime session wrote it: SessionID is then a pid, or the
+// checkpoint was captured on the session's behalf by gt polecat preempt.
+func (cp *Checkpoint) RuntimeSessionID() string {
+	if cp.Reason == ReasonPreempt || strings.HasPrefix(cp.SessionID, "pid-") {
+		return ""
+	}
+	return cp.SessionID
 }
 
 // Remove deletes the checkpoint file.
@@ -111,6 +184,27 @@ func Remove(polecatDir string) error {
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("removing checkpoint: %w", err)
 	}
+	return removeLegacy(polecatDir)
+}
+
+// legacyPath is where the checkpoint for polecatDir was kept before it
+// moved to the polecat's home. It is Path(polecatDir) when they coincide.
+func legacyPath(polecatDir string) string {
+	return filepath.Join(polecatDir, Filename)
+}
+
+// removeLegacy deletes a checkpoint and lock left in the worktree by an
+// older gt once the checkpoint lives in the polecat's home.
+func removeLegacy(polecatDir string) error {
+	legacy := legacyPath(polecatDir)
+	if legacy == Path(polecatDir) {
+		return nil
+	}
+	for _, path := range []string{legacy, legacy + ".lock"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing checkpoint: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -130,7 +224,7 @@ func Capture(polecatDir string) (*Checkpoint, error) {
 			if len(line) > 3 {
 				// Format: XY filename
 				file := strings.TrimSpace(line[3:])
-				if file != "" {
+				if file != "" && !isCheckpointFile(file) {
 					cp.ModifiedFiles = append(cp.ModifiedFiles, file)
 				}
 			}
@@ -156,6 +250,12 @@ func Capture(polecatDir string) (*Checkpoint, error) {
 	return cp, nil
 }
 
+// isCheckpointFile reports whether a worktree-relative path is checkpoint
+// state rather than work (relevant when the polecat home is the worktree).
+func isCheckpointFile(path string) bool {
+	return path == Filename || path == Filename+".lock" || path == HistoryDir+"/" || strings.HasPrefix(path, HistoryDir+"/")
+}
+
 // WithMolecule adds molecule context to a checkpoint.
 func (cp *Checkpoint) WithMolecule(moleculeID, stepID, stepTitle string) *Checkpoint {
 	cp.MoleculeID = moleculeID
@@ -216,3 +316,199 @@ func (cp *Checkpoint) Summary() string {
 
 	return strings.Join(parts, ", ")
 }
+
+// AddPendingClosure records that a step of a molecule is about to be closed.
+// The closure stays pending until AckClosure confirms it. If no checkpoint
+// exists yet, one is captured from the worktree so gt mol resume has the
+// git state to verify as well as the closure to replay.
+func AddPendingClosure(polecatDir, moleculeID, stepID string) error {
+	return Update(polecatDir, func(cp *Checkpoint) (*Checkpoint, error) {
+		if cp == nil {
+			var err error
+			if cp, err = Capture(polecatDir); err != nil {
+				return nil, err
+			}
+			cp.WithMolecule(moleculeID, "", "")
+			cp.Reason = ReasonStep
+			cp.stamp()
+		}
+		for _, id := range cp.PendingClosures {
+			if id == stepID {
+				return nil, nil
+			}
+		}
+		cp.PendingClosures = append(cp.PendingClosures, stepID)
+		return cp, nil
+	})
+}
+
+// AckClosure removes a step from the pending closures once it is closed.
+// A missing checkpoint is not an error.
+func AckClosure(polecatDir, stepID string) error {
+	return Update(polecatDir, func(cp *Checkpoint) (*Checkpoint, error) {
+		if cp == nil {
+			return nil, nil
+		}
+		var remaining []string
+		for _, id := range cp.PendingClosures {
+			if id != stepID {
+				remaining = append(remaining, id)
+			}
+		}
+		if len(remaining) == len(cp.PendingClosures) {
+			return nil, nil
+		}
+		cp.PendingClosures = remaining
+		return cp, nil
+	})
+}
+
+// WorktreeStatus describes how a worktree compares to a checkpoint.
+type WorktreeStatus struct {
+	// Head is the worktree's current HEAD commit.
+	Head string `json:"head,omitempty"`
+
+	// Branch is the worktree's current branch.
+	Branch string `json:"branch,omitempty"`
+
+	// HeadMatches is true when HEAD is exactly the checkpoint's LastCommit.
+	HeadMatches bool `json:"head_matches"`
+
+	// Ahead is true when LastCommit is an ancestor of HEAD, i.e. the
+	// session committed more work after the checkpoint was written.
+	Ahead bool `json:"ahead,omitempty"`
+
+	// BranchMatches is true when the worktree is on the checkpoint's branch.
+	BranchMatches bool `json:"branch_matches"`
+
+	// MissingChanges lists files modified at checkpoint time that are no
+	// longer modified (their uncommitted changes were lost or committed).
+	MissingChanges []string `json:"missing_changes,omitempty"`
+}
+
+// OK reports whether the worktree can be resumed from the checkpoint as-is.
+func (ws *WorktreeStatus) OK() bool {
+	return (ws.HeadMatches || ws.Ahead) && ws.BranchMatches
+}
+
+// Problems returns human-readable descriptions of each mismatch.
+func (ws *WorktreeStatus) Problems(cp *Checkpoint) []string {
+	var problems []string
+	if !ws.HeadMatches && !ws.Ahead && cp.LastCommit != "" {
+		problems = append(problems, fmt.Sprintf("HEAD is %s, checkpoint recorded %s", shortSHA(ws.Head), shortSHA(cp.LastCommit)))
+	}
+	if !ws.BranchMatches && cp.Branch != "" {
+		problems = append(problems, fmt.Sprintf("on branch %s, checkpoint recorded %s", ws.Branch, cp.Branch))
+	}
+	if len(ws.MissingChanges) > 0 {
+		problems = append(problems, fmt.Sprintf("%d checkpointed changes no longer present: %s",
+			len(ws.MissingChanges), strings.Join(ws.MissingChanges, ", ")))
+	}
+	return problems
+}
+
+// VerifyWorktree compares the git state of dir against the checkpoint.
+func VerifyWorktree(dir string, cp *Checkpoint) (*WorktreeStatus, error) {
+	current, err := Capture(dir)
+	if err != nil {
+		return nil, err
+	}
+	if current.LastCommit == "" {
+		return nil, fmt.Errorf("%s is not a git worktree", dir)
+	}
+
+	ws := &WorktreeStatus{
+		Head:          current.LastCommit,
+		Branch:        current.Branch,
+		HeadMatches:   cp.LastCommit == "" || cp.LastCommit == current.LastCommit,
+		BranchMatches: cp.Branch == "" || cp.Branch == current.Branch,
+	}
+	if !ws.HeadMatches {
+		cmd := exec.Command("git", "merge-base", "--is-ancestor", cp.LastCommit, current.LastCommit)
+		cmd.Dir = dir
+		ws.Ahead = cmd.Run() == nil
+	}
+
+	// Files committed after the checkpoint are accounted for; only flag
+	// lost changes when HEAD hasn't moved.
+	if ws.HeadMatches {
+		modified := make(map[string]bool, len(current.ModifiedFiles))
+		for _, f := range current.ModifiedFiles {
+			modified[f] = true
+		}
+		for _, f := range cp.ModifiedFiles {
+			if !modified[f] {
+				ws.MissingChanges = append(ws.MissingChanges, f)
+			}
+		}
+	}
+
+	return ws, nil
+}
+
+// ResumePrompt builds the prompt handed to a fresh session resuming from the
+// checkpoint. ws may be nil if the worktree could not be verified.
+func (cp *Checkpoint) ResumePrompt(ws *WorktreeStatus) string {
+	var sb strings.Builder
+	sb.WriteString("You are resuming work after your previous session ended unexpectedly.\n")
+	fmt.Fprintf(&sb, "Checkpoint written %s ago", cp.Age().Round(time.Second))
+	if cp.SessionID != "" {
+		fmt.Fprintf(&sb, " by session %s", cp.SessionID)
+	}
+	sb.WriteString(".\n\n")
+
+	if cp.HookedBead != "" {
+		fmt.Fprintf(&sb, "Hooked work: %s\n", cp.HookedBead)
+	}
+	if cp.MoleculeID != "" {
+		fmt.Fprintf(&sb, "Molecule: %s\n", cp.MoleculeID)
+	}
+	if cp.CurrentStep != "" {
+		if cp.StepTitle != "" {
+			fmt.Fprintf(&sb, "Current step: %s (%s) - continue this step, do not restart earlier steps.\n", cp.CurrentStep, cp.StepTitle)
+		} else {
+			fmt.Fprintf(&sb, "Current step: %s - continue this step, do not restart earlier steps.\n", cp.CurrentStep)
+		}
+	}
+	if cp.Branch != "" {
+		fmt.Fprintf(&sb, "Branch: %s\n", cp.Branch)
+	}
+	if cp.LastCommit != "" {
+		fmt.Fprintf(&sb, "Last commit: %s\n", shortSHA(cp.LastCommit))
+	}
+	if len(cp.ModifiedFiles) > 0 {
+		sb.WriteString("Uncommitted changes at checkpoint:\n")
+		for _, f := range cp.ModifiedFiles {
+			fmt.Fprintf(&sb, "  - %s\n", f)
+		}
+	}
+	if cp.Notes != "" {
+		fmt.Fprintf(&sb, "Notes: %s\n", cp.Notes)
+	}
+
+	if ws != nil {
+		switch {
+		case !ws.OK() || len(ws.MissingChanges) > 0:
+			sb.WriteString("\nWARNING: the worktree does not match the checkpoint:\n")
+			for _, p := range ws.Problems(cp) {
+				fmt.Fprintf(&sb, "  - %s\n", p)
+			}
+			sb.WriteString("Inspect git status and git log before continuing.\n")
+		case ws.Ahead:
+			sb.WriteString("\nThe worktree has commits newer than the checkpoint; review git log for work already done.\n")
+		default:
+			sb.WriteString("\nThe worktree matches the checkpoint.\n")
+		}
+	}
+
+	sb.WriteString("\nRun `gt prime` to reload full context, then continue from the current step.\n")
+	return sb.String()
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -2,8 +2,12 @@ package checkpoint
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -17,6 +21,41 @@ func TestPath(t *testing.T) {
 	}
 }
 
+func TestPathInPolecatWorktree(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "gastown", "polecats", "Toast")
+	worktree := filepath.Join(home, "gastown")
+	for _, dir := range []string{home, worktree, filepath.Join(worktree, "internal", "polecats", "x")} {
+		if got, want := Path(dir), filepath.Join(home, Filename); got != want {
+			t.Errorf("Path(%q) = %q, want %q (the polecat home)", dir, got, want)
+		}
+	}
+
+	// A checkpoint an older gt left in the worktree is still read, and
+	// moves to the home on the next write
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(&Checkpoint{MoleculeID: "gt-mol"})
+	if err := os.WriteFile(filepath.Join(worktree, Filename), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := Read(worktree)
+	if err != nil || cp == nil || cp.MoleculeID != "gt-mol" {
+		t.Fatalf("Read(worktree) = %+v, %v; want the legacy checkpoint", cp, err)
+	}
+	if err := AddPendingClosure(worktree, "gt-mol", "gt-mol.1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{Filename, Filename + ".lock"} {
+		if _, err := os.Stat(filepath.Join(worktree, name)); !os.IsNotExist(err) {
+			t.Errorf("%s left in the worktree", name)
+		}
+	}
+	if cp, err := Read(home); err != nil || cp == nil || cp.MoleculeID != "gt-mol" || len(cp.PendingClosures) != 1 {
+		t.Errorf("Read(home) = %+v, %v", cp, err)
+	}
+}
+
 func TestReadWrite(t *testing.T) {
 	// Create temp directory
 	tmpDir := t.TempDir()
@@ -248,6 +287,23 @@ func TestWithNotes(t *testing.T) {
 	}
 }
 
+func TestRuntimeSessionID(t *testing.T) {
+	tests := []struct {
+		cp   Checkpoint
+		want string
+	}{
+		{Checkpoint{SessionID: "0b6f-4c1e"}, "0b6f-4c1e"},
+		{Checkpoint{SessionID: "pid-4242"}, ""},
+		{Checkpoint{SessionID: "preempt", Reason: ReasonPreempt}, ""},
+		{Checkpoint{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.cp.RuntimeSessionID(); got != tt.want {
+			t.Errorf("RuntimeSessionID(%q) = %q, want %q", tt.cp.SessionID, got, tt.want)
+		}
+	}
+}
+
 func TestAge(t *testing.T) {
 	cp := &Checkpoint{
 		Timestamp: time.Now().Add(-5 * time.Minute),
@@ -396,3 +452,162 @@ func TestCheckpointJSONRoundtrip(t *testing.T) {
 		t.Errorf("ModifiedFiles length mismatch")
 	}
 }
+
+func TestPendingClosures(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Ack without a checkpoint is a no-op
+	if err := AckClosure(tmpDir, "gt-mol.1"); err != nil {
+		t.Fatalf("AckClosure without checkpoint: %v", err)
+	}
+
+	if err := AddPendingClosure(tmpDir, "gt-mol", "gt-mol.1"); err != nil {
+		t.Fatalf("AddPendingClosure: %v", err)
+	}
+	if err := AddPendingClosure(tmpDir, "gt-mol", "gt-mol.1"); err != nil {
+		t.Fatalf("AddPendingClosure duplicate: %v", err)
+	}
+	if err := AddPendingClosure(tmpDir, "gt-mol", "gt-mol.2"); err != nil {
+		t.Fatalf("AddPendingClosure: %v", err)
+	}
+
+	cp, err := Read(tmpDir)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(cp.PendingClosures) != 2 {
+		t.Fatalf("PendingClosures = %v, want 2 entries", cp.PendingClosures)
+	}
+	if cp.MoleculeID != "gt-mol" || cp.Reason != ReasonStep {
+		t.Errorf("checkpoint created on demand = %+v, want molecule gt-mol, reason step", cp)
+	}
+
+	if err := AckClosure(tmpDir, "gt-mol.1"); err != nil {
+		t.Fatalf("AckClosure: %v", err)
+	}
+	cp, _ = Read(tmpDir)
+	if len(cp.PendingClosures) != 1 || cp.PendingClosures[0] != "gt-mol.2" {
+		t.Errorf("PendingClosures after ack = %v, want [gt-mol.2]", cp.PendingClosures)
+	}
+}
+
+func TestUpdateConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := Write(tmpDir, &Checkpoint{MoleculeID: "gt-mol"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent read-modify-writes must not drop each other's changes
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := AddPendingClosure(tmpDir, "gt-mol", fmt.Sprintf("gt-mol.%d", i)); err != nil {
+				t.Errorf("AddPendingClosure: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	cp, err := Read(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cp.PendingClosures) != 20 {
+		t.Errorf("PendingClosures has %d entries, want 20", len(cp.PendingClosures))
+	}
+}
+
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestVerifyWorktree(t *testing.T) {
+	dir := initGitRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "work.go"), []byte("package x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := Capture(dir)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+
+	ws, err := VerifyWorktree(dir, cp)
+	if err != nil {
+		t.Fatalf("VerifyWorktree: %v", err)
+	}
+	if !ws.OK() || len(ws.MissingChanges) != 0 {
+		t.Errorf("unchanged worktree: status = %+v, problems = %v", ws, ws.Problems(cp))
+	}
+
+	// Losing the uncommitted file is reported
+	if err := os.Remove(filepath.Join(dir, "work.go")); err != nil {
+		t.Fatal(err)
+	}
+	ws, _ = VerifyWorktree(dir, cp)
+	if len(ws.MissingChanges) != 1 || ws.MissingChanges[0] != "work.go" {
+		t.Errorf("MissingChanges = %v, want [work.go]", ws.MissingChanges)
+	}
+
+	// Committing after the checkpoint is ahead, not a mismatch
+	cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "more")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, out)
+	}
+	ws, _ = VerifyWorktree(dir, cp)
+	if ws.HeadMatches || !ws.Ahead || !ws.OK() {
+		t.Errorf("after commit: status = %+v, want ahead", ws)
+	}
+
+	// An unrelated commit is a mismatch
+	cp.LastCommit = "0123456789abcdef0123456789abcdef01234567"
+	ws, _ = VerifyWorktree(dir, cp)
+	if ws.OK() {
+		t.Errorf("unrelated commit: status = %+v, want mismatch", ws)
+	}
+	if problems := ws.Problems(cp); len(problems) != 1 || !strings.Contains(problems[0], "01234567") {
+		t.Errorf("Problems() = %v", problems)
+	}
+}
+
+func TestResumePrompt(t *testing.T) {
+	cp := &Checkpoint{
+		MoleculeID:    "gt-mol",
+		CurrentStep:   "gt-mol.3",
+		StepTitle:     "Implement",
+		HookedBead:    "gt-123",
+		Branch:        "polecat/toast",
+		LastCommit:    "abcdef0123456789",
+		ModifiedFiles: []string{"main.go"},
+		Timestamp:     time.Now(),
+	}
+
+	prompt := cp.ResumePrompt(&WorktreeStatus{HeadMatches: true, BranchMatches: true})
+	for _, want := range []string{"gt-123", "gt-mol.3 (Implement)", "polecat/toast", "abcdef01", "main.go", "matches the checkpoint"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("ResumePrompt() missing %q:\n%s", want, prompt)
+		}
+	}
+
+	prompt = cp.ResumePrompt(&WorktreeStatus{Head: "ffff0000aaaa", BranchMatches: true})
+	if !strings.Contains(prompt, "WARNING") {
+		t.Errorf("ResumePrompt() with mismatch should warn:\n%s", prompt)
+	}
+}
@@ -0,0 +1,218 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryDir is the directory within a polecat's home that holds
+// checkpoint snapshots taken by the witness.
+const HistoryDir = ".checkpoints"
+
+// Reasons a checkpoint was captured.
+const (
+	ReasonManual   = "manual"   // gt checkpoint write
+	ReasonInitial  = "initial"  // first snapshot for the polecat
+	ReasonInterval = "interval" // cadence elapsed
+	ReasonCommit   = "commit"   // HEAD moved since the last snapshot
+	ReasonStep     = "step"     // a molecule step closed since the last snapshot
+	ReasonPreempt  = "preempt"  // gt polecat preempt paused the work
+)
+
+// snapshotTimeFormat names snapshot files so they sort chronologically.
+const snapshotTimeFormat = "20060102T150405.000Z"
+
+// Snapshot is one checkpoint in a polecat's history.
+type Snapshot struct {
+	// ID is the snapshot identifier (file name without extension).
+	ID string `json:"id"`
+
+	// Checkpoint is the captured state.
+	Checkpoint *Checkpoint `json:"checkpoint"`
+}
+
+// HistoryPath returns the snapshot directory for a polecat home directory.
+func HistoryPath(polecatHome string) string {
+	return filepath.Join(polecatHome, HistoryDir)
+}
+
+// SaveSnapshot appends a checkpoint to the history in dir and prunes the
+// oldest snapshots beyond keep. Returns the new snapshot ID.
+func SaveSnapshot(dir string, cp *Checkpoint, keep int) (string, error) {
+	if cp.Timestamp.IsZero() {
+		cp.Timestamp = time.Now()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating checkpoint history: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling checkpoint: %w", err)
+	}
+
+	id := cp.Timestamp.UTC().Format(snapshotTimeFormat)
+	if err := os.WriteFile(filepath.Join(dir, id+".json"), data, 0600); err != nil {
+		return "", fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	if keep > 0 {
+		if err := prune(dir, keep); err != nil {
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+// History returns the snapshots in dir, oldest first.
+// A missing directory yields no snapshots.
+func History(dir string) ([]*Snapshot, error) {
+	ids, err := snapshotIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, id := range ids {
+		cp, err := LoadSnapshot(dir, id)
+		if err != nil {
+			continue // Skip unreadable snapshots
+		}
+		snapshots = append(snapshots, &Snapshot{ID: id, Checkpoint: cp})
+	}
+	return snapshots, nil
+}
+
+// Latest returns the most recent snapshot in dir, or nil if there is none.
+func Latest(dir string) (*Snapshot, error) {
+	snapshots, err := History(dir)
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return snapshots[len(snapshots)-1], nil
+}
+
+// LoadSnapshot reads a single snapshot by ID.
+func LoadSnapshot(dir, id string) (*Checkpoint, error) {
+	path := filepath.Join(dir, strings.TrimSuffix(id, ".json")+".json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted history dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %s not found", id)
+		}
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parsing snapshot %s: %w", id, err)
+	}
+	return &cp, nil
+}
+
+// snapshotIDs lists snapshot IDs in dir in chronological order.
+func snapshotIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading checkpoint history: %w", err)
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// prune removes the oldest snapshots so at most keep remain.
+func prune(dir string, keep int) error {
+	ids, err := snapshotIDs(dir)
+	if err != nil {
+		return err
+	}
+	for len(ids) > keep {
+		if err := os.Remove(filepath.Join(dir, ids[0]+".json")); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("pruning snapshot: %w", err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// Due decides whether a new snapshot should be taken. last is the most
+// recent snapshot (nil if none), current is freshly captured state, and
+// lastStepClose is when the polecat last closed a molecule step (zero if
+// unknown). Returns the reason for snapshotting, or "" if not due.
+func Due(last, current *Checkpoint, interval time.Duration, lastStepClose, now time.Time) string {
+	switch {
+	case last == nil:
+		return ReasonInitial
+	case current.LastCommit != "" && current.LastCommit != last.LastCommit:
+		return ReasonCommit
+	case lastStepClose.After(last.Timestamp),
+		current.CurrentStep != "" && current.CurrentStep != last.CurrentStep:
+		return ReasonStep
+	case now.Sub(last.Timestamp) >= interval:
+		return ReasonInterval
+	}
+	return ""
+}
+
+// Diff describes the differences between two checkpoints, one line per
+// changed field. Returns nil if they record the same state.
+func Diff(a, b *Checkpoint) []string {
+	var changes []string
+	field := func(name, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", name, orNone(from), orNone(to)))
+		}
+	}
+
+	field("molecule", a.MoleculeID, b.MoleculeID)
+	field("step", a.CurrentStep, b.CurrentStep)
+	field("step_title", a.StepTitle, b.StepTitle)
+	field("hooked", a.HookedBead, b.HookedBead)
+	field("branch", a.Branch, b.Branch)
+	field("commit", shortSHA(a.LastCommit), shortSHA(b.LastCommit))
+	field("session", a.SessionID, b.SessionID)
+	field("notes", a.Notes, b.Notes)
+
+	before := make(map[string]bool, len(a.ModifiedFiles))
+	for _, f := range a.ModifiedFiles {
+		before[f] = true
+	}
+	after := make(map[string]bool, len(b.ModifiedFiles))
+	for _, f := range b.ModifiedFiles {
+		after[f] = true
+		if !before[f] {
+			changes = append(changes, "+ "+f)
+		}
+	}
+	for _, f := range a.ModifiedFiles {
+		if !after[f] {
+			changes = append(changes, "- "+f)
+		}
+	}
+
+	return changes
+}
+
+// orNone renders an empty field value for diffs.
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package checkpoint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaveSnapshotPrunes(t *testing.T) {
+	dir := HistoryPath(t.TempDir())
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		cp := &Checkpoint{CurrentStep: "gt-mol.1", Timestamp: base.Add(time.Duration(i) * time.Minute)}
+		if _, err := SaveSnapshot(dir, cp, 3); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+	}
+
+	snapshots, err := History(dir)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("History() returned %d snapshots, want 3", len(snapshots))
+	}
+	if !snapshots[0].Checkpoint.Timestamp.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("oldest kept snapshot = %v, want %v", snapshots[0].Checkpoint.Timestamp, base.Add(2*time.Minute))
+	}
+
+	latest, err := Latest(dir)
+	if err != nil || latest == nil {
+		t.Fatalf("Latest() = %v, %v", latest, err)
+	}
+	if latest.ID != snapshots[2].ID {
+		t.Errorf("Latest().ID = %s, want %s", latest.ID, snapshots[2].ID)
+	}
+
+	if _, err := LoadSnapshot(dir, latest.ID+".json"); err != nil {
+		t.Errorf("LoadSnapshot with extension: %v", err)
+	}
+	if _, err := LoadSnapshot(dir, "missing"); err == nil {
+		t.Error("LoadSnapshot(missing) should fail")
+	}
+}
+
+func TestHistoryMissingDir(t *testing.T) {
+	snapshots, err := History(HistoryPath(t.TempDir()))
+	if err != nil || snapshots != nil {
+		t.Errorf("History() on missing dir = %v, %v; want nil, nil", snapshots, err)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 10, 0, 0, time.UTC)
+	last := &Checkpoint{LastCommit: "aaa", CurrentStep: "gt-mol.1", Timestamp: now.Add(-2 * time.Minute)}
+
+	tests := []struct {
+		name      string
+		last      *Checkpoint
+		current   *Checkpoint
+		stepClose time.Time
+		want      string
+	}{
+		{"no history", nil, &Checkpoint{}, time.Time{}, ReasonInitial},
+		{"unchanged", last, &Checkpoint{LastCommit: "aaa", CurrentStep: "gt-mol.1"}, time.Time{}, ""},
+		{"new commit", last, &Checkpoint{LastCommit: "bbb", CurrentStep: "gt-mol.1"}, time.Time{}, ReasonCommit},
+		{"step moved", last, &Checkpoint{LastCommit: "aaa", CurrentStep: "gt-mol.2"}, time.Time{}, ReasonStep},
+		{"step closed", last, &Checkpoint{LastCommit: "aaa", CurrentStep: "gt-mol.1"}, now.Add(-time.Minute), ReasonStep},
+		{"old step close", last, &Checkpoint{LastCommit: "aaa", CurrentStep: "gt-mol.1"}, now.Add(-time.Hour), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Due(tt.last, tt.current, 5*time.Minute, tt.stepClose, now); got != tt.want {
+				t.Errorf("Due() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := Due(last, &Checkpoint{LastCommit: "aaa"}, time.Minute, time.Time{}, now); got != ReasonInterval {
+		t.Errorf("Due() after interval = %q, want %q", got, ReasonInterval)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &Checkpoint{CurrentStep: "gt-mol.1", LastCommit: "aaaaaaaaaaaa", ModifiedFiles: []string{"a.go", "b.go"}}
+	b := &Checkpoint{CurrentStep: "gt-mol.2", LastCommit: "aaaaaaaaaaaa", ModifiedFiles: []string{"b.go", "c.go"}}
+
+	changes := Diff(a, b)
+	want := []string{"step: gt-mol.1 -> gt-mol.2", "+ c.go", "- a.go"}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("Diff()[%d] = %q, want %q", i, changes[i], want[i])
+		}
+	}
+
+	if changes := Diff(a, a); changes != nil {
+		t.Errorf("Diff(a, a) = %v, want nil", changes)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -246,6 +247,7 @@ func runAccountDefault(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Default account set to '%s'\n", handle)
+	logConfigChange("accounts", "default", events.ConfigSet, handle)
 	return nil
 }
 
@@ -445,6 +447,8 @@ func runAccountSwitch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("saving accounts config: %w", err)
 	}
 
+	_ = events.LogAudit(events.TypeAccountSwitch, detectSender(), events.AccountSwitchPayload(currentHandle, targetHandle))
+
 	fmt.Printf("Switched to account '%s'\n", targetHandle)
 	fmt.Printf("~/.claude -> %s\n", targetAcct.ConfigDir)
 	fmt.Println()
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/scorecard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	agentsStatsBy    string
+	agentsStatsSince string
+	agentsStatsJSON  bool
+)
+
+var agentsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show performance scorecards per agent preset, formula, or rig",
+	Long: `Show how well polecat work goes, grouped by agent preset (default),
+formula, or rig.
+
+Each sling to a polecat is one run. Runs are scored from the events log
+(~/gt/.events.jsonl) and the costs log (~/.gt/costs.jsonl):
+
+  RUNS     Beads slung to polecats
+  DONE     Runs that reached gt done
+  MERGED   Merges landed by the refinery
+  FAILED   Merge attempts that failed (conflicts, tests, build)
+  REWORK   Branches sent back for rebasing
+  ESC      Escalations raised by the polecat
+  TO DONE  Average time from hook to gt done
+  COST     Total session cost, and cost per merge
+
+Runs spawned before agent presets were recorded are grouped as "(default)".
+
+Examples:
+  gt agents stats
+  gt agents stats --by formula --since 7d
+  gt agents stats --by rig --json`,
+	Args: cobra.NoArgs,
+	RunE: runAgentsStats,
+}
+
+func init() {
+	agentsStatsCmd.Flags().StringVar(&agentsStatsBy, "by", scorecard.ByAgent, "Group by: agent, formula, or rig")
+	agentsStatsCmd.Flags().StringVar(&agentsStatsSince, "since", "", "Only count runs since this long ago (e.g., 24h, 7d)")
+	agentsStatsCmd.Flags().BoolVar(&agentsStatsJSON, "json", false, "Output as JSON")
+	agentsCmd.AddCommand(agentsStatsCmd)
+}
+
+// AgentsStatsCard is a scorecard row in gt agents stats --json.
+type AgentsStatsCard struct {
+	scorecard.Card
+	MergeRate    float64 `json:"merge_rate"`
+	CostPerMerge float64 `json:"cost_per_merge"`
+}
+
+func runAgentsStats(cmd *cobra.Command, args []string) error {
+	switch agentsStatsBy {
+	case scorecard.ByAgent, scorecard.ByFormula, scorecard.ByRig:
+	default:
+		return fmt.Errorf("invalid --by %q: must be agent, formula, or rig", agentsStatsBy)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var since time.Time
+	if agentsStatsSince != "" {
+		d, err := parseDuration(agentsStatsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	runs, err := scorecard.Load(townRoot, getCostsLogPath(), since)
+	if err != nil {
+		return fmt.Errorf("loading scorecard data: %w", err)
+	}
+	cards := scorecard.Aggregate(runs, agentsStatsBy)
+
+	if agentsStatsJSON {
+		out := make([]AgentsStatsCard, len(cards))
+		for i, c := range cards {
+			out[i] = AgentsStatsCard{Card: c, MergeRate: c.MergeRate(), CostPerMerge: c.CostPerMerge()}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(cards) == 0 {
+		fmt.Printf("%s No polecat runs recorded\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Scorecards by %s (%d runs)\n\n", style.Bold.Render("📊"), agentsStatsBy, len(runs))
+	fmt.Printf("%-20s %5s %5s %6s %6s %6s %6s %4s %8s %9s %9s\n",
+		"", "RUNS", "DONE", "MERGED", "FAILED", "MERGE%", "REWORK", "ESC", "TO DONE", "COST", "$/MERGE")
+	for _, c := range cards {
+		rate := "-"
+		if r := c.MergeRate(); r >= 0 {
+			rate = fmt.Sprintf("%.0f%%", r*100)
+		}
+		toDone := "-"
+		if c.Completed > 0 {
+			toDone = formatDuration(c.AvgToDone)
+		}
+		perMerge := "-"
+		if c.Merged > 0 {
+			perMerge = fmt.Sprintf("$%.2f", c.CostPerMerge())
+		}
+		fmt.Printf("%-20s %5d %5d %6d %6d %6s %6d %4d %8s %9s %9s\n",
+			truncateStr(c.Key, 20), c.Runs, c.Completed, c.Merged, c.MergeFailed, rate,
+			c.Rework, c.Escalations, toDone, fmt.Sprintf("$%.2f", c.CostUSD), perMerge)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	sessionHostName   string
+	sessionHostDir    string
+	sessionHostWidth  int
+	sessionHostHeight int
+)
+
+var attachCmd = &cobra.Command{
+	Use:     "attach <session>",
+	GroupID: GroupAgents,
+	Short:   "Attach to an agent session",
+	Long: `Attach the terminal to an agent session by its session name.
+
+With the tmux backend this is tmux attach-session (or switch-client inside
+tmux). With the headless backend it connects to the session's socket:
+the screen is redrawn, keys go to the agent, and Ctrl-\ detaches.
+
+The backend is chosen by GT_SESSION_BACKEND: "tmux", "headless", or "auto"
+(the default), which uses tmux when it is installed. Headless sessions run
+the agent on a pseudo-terminal held by a background gt process, so Gas Town
+runs where tmux doesn't exist, such as containers and CI runners.
+
+Run without a session to list the sessions you can attach to.
+
+Examples:
+  gt attach gt-greenplace-Toast
+  gt attach hq-mayor
+  GT_SESSION_BACKEND=headless gt attach`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAttach,
+}
+
+var sessionHostCmd = &cobra.Command{
+	Use:    "session-host [command]",
+	Short:  "Run a headless session (internal use)",
+	Hidden: true, // Started in the background by the headless session backend
+	Args:   cobra.MaximumNArgs(1),
+	RunE:   runSessionHost,
+}
+
+func init() {
+	sessionHostCmd.Flags().StringVar(&sessionHostName, "name", "", "Session name")
+	sessionHostCmd.Flags().StringVar(&sessionHostDir, "dir", "", "Working directory")
+	sessionHostCmd.Flags().IntVar(&sessionHostWidth, "width", headless.DefaultWidth, "Terminal width")
+	sessionHostCmd.Flags().IntVar(&sessionHostHeight, "height", headless.DefaultHeight, "Terminal height")
+
+	rootCmd.AddCommand(attachCmd)
+	rootCmd.AddCommand(sessionHostCmd)
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	t := tmux.NewTmux()
+	if len(args) == 0 {
+		sessions, err := t.ListSessions()
+		if err != nil {
+			return fmt.Errorf("listing sessions: %w", err)
+		}
+		if len(sessions) == 0 {
+			fmt.Printf("%s\n", style.Dim.Render("No sessions running"))
+			return nil
+		}
+		fmt.Printf("%s (%s backend)\n", style.Bold.Render("Sessions"), tmux.Backend())
+		for _, s := range sessions {
+			fmt.Printf("  %s\n", s)
+		}
+		return nil
+	}
+
+	session := args[0]
+	exists, err := t.HasSession(session)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("session %q not found (backend: %s)", session, tmux.Backend())
+	}
+	if t.IsHeadless() {
+		fmt.Printf("%s\n", style.Dim.Render("Attached to "+session+"; Ctrl-\\ to detach"))
+	}
+	return attachToTmuxSession(session)
+}
+
+func runSessionHost(cmd *cobra.Command, args []string) error {
+	if sessionHostName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	opts := headless.Options{
+		Name:    sessionHostName,
+		WorkDir: sessionHostDir,
+		Command: strings.Join(args, " "),
+		Width:   sessionHostWidth,
+		Height:  sessionHostHeight,
+	}
+	if err := headless.Serve(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "session-host %s: %v\n", sessionHostName, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	auditVerifyJSON  bool
+	auditVerifyKeyID string
+)
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the tamper-evident audit log",
+	Long: `Verify the hash chain and signatures of the compliance audit log.
+
+Sling, kill, nuke, force-release, account switch and config change events
+are appended to ~/gt/.audit.jsonl. Each entry carries the hash of the entry
+before it and is signed with the town key, which is kept outside the town
+in ~/.config/gastown/audit/. The log is never pruned by KRC.
+
+Verification fails at the first entry that was modified, removed,
+reordered or signed with a different key. Pass --key-id with a previously
+recorded fingerprint to also detect a replaced town key.
+
+Exit status is non-zero if verification fails.
+
+Examples:
+  gt audit verify
+  gt audit verify --key-id 3f9a1c0e5b7d2468
+  gt audit verify --json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditVerify,
+}
+
+func init() {
+	auditVerifyCmd.Flags().BoolVar(&auditVerifyJSON, "json", false, "Output as JSON")
+	auditVerifyCmd.Flags().StringVar(&auditVerifyKeyID, "key-id", "", "Expected town key fingerprint")
+
+	auditCmd.AddCommand(auditVerifyCmd)
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	key, err := auditlog.LoadKey(townRoot)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		if _, statErr := os.Stat(auditlog.Path(townRoot)); statErr == nil {
+			return fmt.Errorf("audit log exists but town key %s is missing", auditlog.KeyPath(townRoot))
+		}
+		fmt.Printf("%s No audit log yet\n", style.Dim.Render("○"))
+		return nil
+	}
+	pub := key.Public().(ed25519.PublicKey)
+
+	if auditVerifyKeyID != "" && auditlog.KeyID(pub) != auditVerifyKeyID {
+		return fmt.Errorf("town key is %s, expected %s (key replaced?)", auditlog.KeyID(pub), auditVerifyKeyID)
+	}
+
+	result, err := auditlog.Verify(townRoot, pub)
+	if err != nil {
+		return err
+	}
+
+	if auditVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else if result.OK() {
+		fmt.Printf("%s Audit log intact: %d entries, key %s\n",
+			style.Bold.Render("✓"), result.Entries, result.KeyID)
+	} else {
+		fmt.Printf("%s Audit log verification FAILED at line %d (seq %d): %s\n",
+			style.Bold.Render("✗"), result.Break.Line, result.Break.Seq, result.Break.Reason)
+		fmt.Printf("  %d entries verified before the break\n", result.Entries)
+	}
+
+	if !result.OK() {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// logConfigChange records a configuration change in the events and audit
+// logs. Only a fingerprint of the value is recorded (see
+// events.ConfigChangePayload).
+func logConfigChange(scope, key, action, value string) {
+	_ = events.LogAudit(events.TypeConfigChange, detectSender(), events.ConfigChangePayload(scope, key, action, value))
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/autoscale"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hostres"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var autoscaleJSON bool
+
+var autoscaleCmd = &cobra.Command{
+	Use:     "autoscale",
+	GroupID: GroupWork,
+	Short:   "Spawn polecats as ready work piles up",
+	RunE:    requireSubcommand,
+	Long: `Per-rig polecat autoscaling.
+
+The autoscaler watches each rig's ready work (as gt ready lists it) and its
+merge queue. When ready beads pile up it slings them to new polecats, up to
+the rig's limit; when the refinery is saturated it stops spawning so the
+merge queue can drain. Polecats exit when their work is done, so scaling
+down happens on its own.
+
+Configure in <rig>/settings/config.json:
+  "autoscale": {
+    "enabled": true,
+    "min_polecats": 0,        Keep at least this many working while work is ready
+    "max_polecats": 6,        Cap (the rig's max_polecats also applies)
+    "ready_per_polecat": 1,   Waiting ready beads per extra polecat
+    "max_step": 2,            Most polecats spawned per decision
+    "max_merge_queue": 5,     Open MRs at which spawning stops (back-pressure)
+    "cooldown": "5m"          Minimum time between scale-ups
+  }
+
+Parked and docked rigs, rigs with queued slings, and rigs on a saturated
+host (see "resources" in gt sling --help) are left alone. The
+daemon runs the autoscaler each heartbeat (patrols.autoscaler in
+mayor/daemon.json); every decision, holds included, is written to the
+town log (gt log --type autoscale).`,
+}
+
+var autoscaleStatusCmd = &cobra.Command{
+	Use:   "status [rig]",
+	Short: "Show what the autoscaler would decide now (dry run)",
+	Long: `Show each autoscaled rig's ready work, polecats, merge queue depth,
+and the decision the autoscaler would make, without acting on it.
+
+Examples:
+  gt autoscale status
+  gt autoscale status greenplace --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAutoscaleStatus,
+}
+
+var autoscaleRunCmd = &cobra.Command{
+	Use:   "run [rig]",
+	Short: "Make and act on autoscaling decisions now",
+	Long: `Decide for each autoscaled rig and sling its highest-priority ready
+beads to new polecats when it should scale up. Each decision, scale-up or
+hold, is logged to the town log. A bead
+whose sling fails is skipped for 5 minutes, doubling with each further
+failure up to 4 hours.
+
+Examples:
+  gt autoscale run
+  gt autoscale run greenplace`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAutoscaleRun,
+}
+
+func init() {
+	autoscaleStatusCmd.Flags().BoolVar(&autoscaleJSON, "json", false, "Output as JSON")
+
+	autoscaleCmd.AddCommand(autoscaleStatusCmd)
+	autoscaleCmd.AddCommand(autoscaleRunCmd)
+	rootCmd.AddCommand(autoscaleCmd)
+}
+
+// AutoscaleRig is one rig's entry in gt autoscale status --json.
+type AutoscaleRig struct {
+	State    autoscale.State    `json:"state"`
+	Decision autoscale.Decision `json:"decision"`
+
+	ready []*beads.Issue
+}
+
+// autoscaleRigs returns the named rig or, with no name, every rig that has
+// autoscaling enabled, along with their settings.
+func autoscaleRigs(args []string) ([]*rig.Rig, map[string]*config.AutoscaleConfig, error) {
+	var rigs []*rig.Rig
+	if len(args) > 0 {
+		_, r, err := getRig(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		rigs = []*rig.Rig{r}
+	} else {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return nil, nil, err
+		}
+		rigs = all
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+
+	configs := make(map[string]*config.AutoscaleConfig)
+	var enabled []*rig.Rig
+	for _, r := range rigs {
+		cfg := config.DefaultAutoscaleConfig()
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil && settings.Autoscale != nil {
+			cfg = settings.Autoscale
+		}
+		if !cfg.Enabled && len(args) == 0 {
+			continue
+		}
+		configs[r.Name] = cfg
+		enabled = append(enabled, r)
+	}
+	return enabled, configs, nil
+}
+
+// evaluateAutoscale measures a rig and decides whether it should scale up.
+func evaluateAutoscale(townRoot string, r *rig.Rig, cfg *config.AutoscaleConfig, now time.Time) (*AutoscaleRig, error) {
+	mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+	state := autoscale.State{
+		Rig:         r.Name,
+		Polecats:    mgr.Count(),
+		MaxPolecats: mgr.MaxPolecats(),
+		LastScaleUp: autoscale.LastScaleUp(r.Path),
+	}
+	if !cfg.Enabled {
+		state.Blocked = "autoscale disabled"
+	} else if opState, _ := getRigOperationalState(townRoot, r.Name); opState != "OPERATIONAL" {
+		state.Blocked = strings.ToLower(opState)
+	} else if queued, _ := polecat.NewAdmissionQueue(r.Path).List(); len(queued) > 0 {
+		// Queued slings already have first claim on freed slots
+		state.Blocked = fmt.Sprintf("%d sling(s) queued", len(queued))
+	} else if err := hostres.Check(r.Path, config.LoadResourceConfig(townRoot)); err != nil {
+		state.Blocked = err.Error()
+	}
+
+	issues, err := readyWorkAt(r.BeadsPath())
+	if err != nil {
+		return nil, fmt.Errorf("listing ready work: %w", err)
+	}
+	// Beads whose sling recently failed wait out their backoff
+	backedOff := autoscale.BackedOff(r.Path, now)
+	var ready []*beads.Issue
+	for _, issue := range issues {
+		if !schedulable(issue) {
+			continue
+		}
+		if backedOff[issue.ID] {
+			state.BackedOff++
+			continue
+		}
+		ready = append(ready, issue)
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		if ready[i].Priority != ready[j].Priority {
+			return ready[i].Priority < ready[j].Priority
+		}
+		return ready[i].CreatedAt < ready[j].CreatedAt
+	})
+	state.Ready = len(ready)
+
+	mrs, err := beads.New(r.BeadsPath()).List(beads.ListOptions{
+		Type:     "merge-request",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing merge queue: %w", err)
+	}
+	for _, mr := range mrs {
+		// bd list does not always honor --status
+		if mr.Status == "open" {
+			state.MergeQueue++
+		}
+	}
+
+	return &AutoscaleRig{
+		State:    state,
+		Decision: autoscale.Decide(cfg, state, now),
+		ready:    ready,
+	}, nil
+}
+
+func runAutoscaleStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, configs, err := autoscaleRigs(args)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var results []*AutoscaleRig
+	for _, r := range rigs {
+		res, err := evaluateAutoscale(townRoot, r, configs[r.Name], now)
+		if err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+			continue
+		}
+		results = append(results, res)
+	}
+
+	if autoscaleJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No rigs have autoscaling enabled\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, res := range results {
+		d := res.Decision
+		verdict := string(d.Action)
+		if d.Action == autoscale.ActionScaleUp {
+			verdict = style.Bold.Render(fmt.Sprintf("scale up +%d", d.Spawn))
+		}
+		fmt.Printf("%s %-16s %s  %s\n", style.Bold.Render("⚖"), d.Rig, verdict, style.Dim.Render(d.Reason))
+	}
+	return nil
+}
+
+func runAutoscaleRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, configs, err := autoscaleRigs(args)
+	if err != nil {
+		return err
+	}
+
+	logger := townlog.NewLogger(townRoot)
+	now := time.Now()
+	for _, r := range rigs {
+		agent := r.Name + "/autoscaler"
+		res, err := evaluateAutoscale(townRoot, r, configs[r.Name], now)
+		if err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+			_ = logger.Log(townlog.EventAutoscale, agent, fmt.Sprintf("error: %v", err))
+			continue
+		}
+		d := res.Decision
+		if d.Action != autoscale.ActionScaleUp {
+			fmt.Printf("%s %s: %s (%s)\n", style.Dim.Render("○"), r.Name, d.Action, d.Reason)
+			_ = logger.Log(townlog.EventAutoscale, agent, fmt.Sprintf("%s: %s", d.Action, d.Reason))
+			continue
+		}
+
+		var slung, failed []string
+		for _, issue := range res.ready[:d.Spawn] {
+			fmt.Printf("%s Scaling %s: slinging %s (P%d)\n", style.Bold.Render("▶"), r.Name, issue.ID, issue.Priority)
+			if out, err := queuedSlingCommand(townRoot, r.Name, &polecat.QueuedSling{Bead: issue.ID}).CombinedOutput(); err != nil {
+				style.PrintWarning("sling of %s failed: %v\n%s", issue.ID, err, strings.TrimSpace(string(out)))
+				failed = append(failed, issue.ID)
+				if err := autoscale.RecordSlingFailure(r.Path, issue.ID, now); err != nil {
+					style.PrintWarning("could not record sling failure of %s: %v", issue.ID, err)
+				}
+				continue
+			}
+			slung = append(slung, issue.ID)
+			_ = autoscale.ClearSlingFailure(r.Path, issue.ID)
+		}
+		if len(slung) > 0 {
+			if err := autoscale.RecordScaleUp(r.Path, now); err != nil {
+				style.PrintWarning("could not record scale-up for %s: %v", r.Name, err)
+			}
+		}
+
+		context := fmt.Sprintf("%s +%d [%s]: %s", d.Action, len(slung), strings.Join(slung, " "), d.Reason)
+		if len(failed) > 0 {
+			context += fmt.Sprintf("; sling failed for %s (backing off)", strings.Join(failed, " "))
+		}
+		_ = logger.Log(townlog.EventAutoscale, agent, context)
+		fmt.Printf("%s %s: spawned %d/%d polecat(s)\n", style.Bold.Render("⚖"), r.Name, len(slung), d.Spawn)
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -28,7 +30,10 @@ Checkpoint data includes:
 - Git branch and last commit
 - Timestamp
 
-Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
+Checkpoints are stored in polecats/<name>/.polecat-checkpoint.json, outside
+the polecat's worktree. The witness also snapshots working polecats
+periodically; snapshots are kept in polecats/<name>/.checkpoints/ (see
+'gt checkpoint history').`,
 }
 
 var checkpointWriteCmd = &cobra.Command{
@@ -141,10 +146,23 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 	}
 
 	// Write checkpoint
+	cp.Reason = checkpoint.ReasonManual
 	if err := checkpoint.Write(cwd, cp); err != nil {
 		return fmt.Errorf("writing checkpoint: %w", err)
 	}
 
+	// Record polecat checkpoints in the history alongside witness snapshots
+	if roleInfo.Role == RolePolecat {
+		home := filepath.Join(townRoot, roleInfo.Rig, "polecats", roleInfo.Polecat)
+		keep := config.DefaultCheckpointConfig().Keep
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, roleInfo.Rig))); err == nil {
+			keep = settings.Checkpoint.KeepCount()
+		}
+		if _, err := checkpoint.SaveSnapshot(checkpoint.HistoryPath(home), cp, keep); err != nil {
+			style.PrintWarning("could not record checkpoint history: %v", err)
+		}
+	}
+
 	fmt.Printf("%s Checkpoint written\n", style.Bold.Render("✓"))
 	fmt.Printf("  %s\n", cp.Summary())
 
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	checkpointHistoryJSON bool
+	checkpointDiffPolecat string
+)
+
+var checkpointHistoryCmd = &cobra.Command{
+	Use:   "history [rig/polecat]",
+	Short: "List checkpoint snapshots for a polecat",
+	Long: `List the checkpoint snapshots kept for a polecat, oldest first.
+
+The witness snapshots each working polecat on a cadence and whenever the
+polecat commits or closes a molecule step. Manual 'gt checkpoint write'
+calls are recorded too. History is bounded per polecat (checkpoint.keep in
+the rig's settings/config.json, default 20).
+
+Without an argument, shows the current polecat's history.
+
+Examples:
+  gt checkpoint history                  # Current polecat
+  gt checkpoint history gastown/Toast    # A specific polecat`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheckpointHistory,
+}
+
+var checkpointDiffCmd = &cobra.Command{
+	Use:   "diff [from] [to]",
+	Short: "Compare two checkpoint snapshots",
+	Long: `Show what changed between two checkpoint snapshots.
+
+Snapshots are named by their ID or their number in 'gt checkpoint history'.
+With no arguments, compares the two most recent snapshots. With one
+argument, compares that snapshot against the most recent.
+
+Examples:
+  gt checkpoint diff                            # Last two snapshots
+  gt checkpoint diff 3                          # Snapshot #3 vs latest
+  gt checkpoint diff 3 5 --polecat gastown/Toast`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runCheckpointDiff,
+}
+
+func init() {
+	checkpointHistoryCmd.Flags().BoolVar(&checkpointHistoryJSON, "json", false, "Output as JSON")
+	checkpointDiffCmd.Flags().StringVar(&checkpointDiffPolecat, "polecat", "", "Polecat to diff (rig/polecat, default: current)")
+
+	checkpointCmd.AddCommand(checkpointHistoryCmd)
+	checkpointCmd.AddCommand(checkpointDiffCmd)
+}
+
+func runCheckpointHistory(cmd *cobra.Command, args []string) error {
+	address := ""
+	if len(args) > 0 {
+		address = args[0]
+	}
+	historyDir, label, err := polecatHistoryDir(address)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := checkpoint.History(historyDir)
+	if err != nil {
+		return err
+	}
+
+	if checkpointHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshots)
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Printf("%s No checkpoint history for %s\n", style.Dim.Render("○"), label)
+		return nil
+	}
+
+	fmt.Printf("%s Checkpoint history for %s\n\n", style.Bold.Render("📌"), label)
+	for i, s := range snapshots {
+		reason := s.Checkpoint.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Printf("  %3d  %s  %-8s  %s\n", i+1,
+			s.Checkpoint.Timestamp.Local().Format("2006-01-02 15:04:05"), reason, s.Checkpoint.Summary())
+	}
+	return nil
+}
+
+func runCheckpointDiff(cmd *cobra.Command, args []string) error {
+	historyDir, label, err := polecatHistoryDir(checkpointDiffPolecat)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := checkpoint.History(historyDir)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) < 2 && len(args) < 2 {
+		return fmt.Errorf("need at least two snapshots to diff (%s has %d)", label, len(snapshots))
+	}
+
+	from, to := len(snapshots)-2, len(snapshots)-1
+	if len(args) > 0 {
+		if from, err = snapshotIndex(snapshots, args[0]); err != nil {
+			return err
+		}
+	}
+	if len(args) > 1 {
+		if to, err = snapshotIndex(snapshots, args[1]); err != nil {
+			return err
+		}
+	}
+
+	a, b := snapshots[from], snapshots[to]
+	fmt.Printf("%s #%d %s → #%d %s\n\n", style.Bold.Render("📌"), from+1, a.ID, to+1, b.ID)
+	changes := checkpoint.Diff(a.Checkpoint, b.Checkpoint)
+	if len(changes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no changes"))
+		return nil
+	}
+	for _, c := range changes {
+		fmt.Printf("  %s\n", c)
+	}
+	return nil
+}
+
+// snapshotIndex resolves a snapshot reference (1-based history number or
+// snapshot ID) to an index into snapshots.
+func snapshotIndex(snapshots []*checkpoint.Snapshot, ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(snapshots) {
+			return 0, fmt.Errorf("snapshot #%d out of range (1-%d)", n, len(snapshots))
+		}
+		return n - 1, nil
+	}
+	for i, s := range snapshots {
+		if s.ID == ref {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("snapshot %s not found", ref)
+}
+
+// polecatHistoryDir resolves the checkpoint history directory for a
+// rig/polecat address, or for the current polecat if address is empty.
+// Returns the directory and a display label.
+func polecatHistoryDir(address string) (string, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var rigName, polecatName string
+	if address != "" {
+		rigName, polecatName, err = parseAddress(address)
+		if err != nil {
+			return "", "", err
+		}
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", "", fmt.Errorf("getting current directory: %w", err)
+		}
+		roleInfo, err := GetRoleWithContext(cwd, townRoot)
+		if err != nil {
+			return "", "", fmt.Errorf("detecting role: %w", err)
+		}
+		if roleInfo.Role != RolePolecat {
+			return "", "", fmt.Errorf("not in a polecat directory; specify rig/polecat")
+		}
+		rigName, polecatName = roleInfo.Rig, roleInfo.Polecat
+	}
+
+	home := filepath.Join(townRoot, rigName, "polecats", polecatName)
+	return checkpoint.HistoryPath(home), rigName + "/" + polecatName, nil
+}
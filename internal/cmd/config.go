@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -349,6 +350,7 @@ func runConfigAgentSet(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Agent '%s' set to: %s\n", style.Bold.Render(name), commandLine)
+	logConfigChange("town", "agents."+name, events.ConfigSet, commandLine)
 
 	// Check if this overrides a built-in
 	builtInAgents := config.ListAgentPresets()
@@ -399,6 +401,7 @@ func runConfigAgentRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Removed custom agent '%s'\n", style.Bold.Render(name))
+	logConfigChange("town", "agents."+name, events.ConfigUnset, "")
 	return nil
 }
 
@@ -462,6 +465,7 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Default agent set to '%s'\n", style.Bold.Render(name))
+	logConfigChange("town", "default_agent", events.ConfigSet, name)
 	return nil
 }
 
@@ -509,6 +513,7 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Agent email domain set to '%s'\n", style.Bold.Render(domain))
+	logConfigChange("town", "agent_email_domain", events.ConfigSet, domain)
 	fmt.Printf("\nExample: gastown/crew/jack → gastown.crew.jack@%s\n", domain)
 	return nil
 }
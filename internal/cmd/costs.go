@@ -2,10 +2,8 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,10 +13,13 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentdriver"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -186,56 +187,6 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
-
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
 	if costsToday || costsWeek || costsByRole || costsByRig {
@@ -276,8 +227,9 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the runtime's transcript
+		driver := runtime.DriverForSession(t, session)
+		cost, err := extractCostFromWorkDir(driver, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", session, err)
@@ -287,7 +239,7 @@ func runLiveCosts() error {
 		}
 
 		// Check if an agent appears to be running
-		running := t.IsAgentRunning(session)
+		running := driver.Status(t, session) != agentdriver.StatusStopped
 
 		costs = append(costs, SessionCost{
 			Session: session,
@@ -707,144 +659,20 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
-}
-
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
-	}
-	return latestPath, nil
-}
-
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
-		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return usage, nil
-}
-
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
-	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
-}
-
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
-	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
-	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
+// extractCostFromWorkDir extracts cost from the transcript a session's runtime
+// wrote for a working directory, using the runtime's agent driver to find and
+// parse it.
+func extractCostFromWorkDir(driver agentdriver.AgentDriver, workDir string) (float64, error) {
+	transcriptPath, err := transcript.Locate(driver.Transcript(workDir, ""))
 	if err != nil {
 		return 0, fmt.Errorf("finding transcript: %w", err)
 	}
 
-	usage, err := parseTranscriptUsage(transcriptPath)
+	cost, err := driver.ParseCost(transcriptPath)
 	if err != nil {
 		return 0, fmt.Errorf("parsing transcript: %w", err)
 	}
-
-	return calculateCost(usage), nil
+	return cost, nil
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -995,11 +823,11 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Extract cost from the runtime's transcript
 	var cost float64
 	if workDir != "" {
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		cost, err = extractCostFromWorkDir(runtime.DriverForAgent(os.Getenv("GT_AGENT")), workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
@@ -12,8 +12,10 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/headless"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -197,6 +199,10 @@ func execRuntime(prompt, rigPath, configDir string) error {
 func isInTmuxSession(targetSession string) bool {
 	// TMUX env var format: /tmp/tmux-501/default,12345,0
 	// We need to get the current session name via tmux display-message
+	if tmux.Backend() == tmux.BackendHeadless {
+		// Headless sessions give their programs the session's pane ID.
+		return os.Getenv("TMUX_PANE") == "%"+targetSession
+	}
 	tmuxEnv := os.Getenv("TMUX")
 	if tmuxEnv == "" {
 		return false // Not in tmux at all
@@ -215,7 +221,12 @@ func isInTmuxSession(targetSession string) bool {
 
 // attachToTmuxSession attaches to a tmux session.
 // If already inside tmux, uses switch-client instead of attach-session.
+// Headless sessions are attached over their socket.
 func attachToTmuxSession(sessionID string) error {
+	if tmux.Backend() == tmux.BackendHeadless {
+		return headless.Attach(sessionID, os.Stdin, os.Stdout)
+	}
+
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return fmt.Errorf("tmux not found: %w", err)
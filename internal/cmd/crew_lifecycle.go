@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
@@ -611,6 +612,7 @@ func runCrewStop(cmd *cobra.Command, args []string) error {
 			agent := fmt.Sprintf("%s/crew/%s", r.Name, name)
 			logger := townlog.NewLogger(townRoot)
 			_ = logger.Log(townlog.EventKill, agent, "gt crew stop")
+			_ = events.LogAudit(events.TypeKill, detectSender(), events.KillPayload(r.Name, agent, "gt crew stop"))
 		}
 
 		// Log captured output (truncated)
@@ -697,6 +699,7 @@ func runCrewStopAll() error {
 		if townRoot != "" {
 			logger := townlog.NewLogger(townRoot)
 			_ = logger.Log(townlog.EventKill, agentName, "gt crew stop --all")
+			_ = events.LogAudit(events.TypeKill, detectSender(), events.KillPayload(agent.Rig, agentName, "gt crew stop --all"))
 		}
 
 		// Log captured output (truncated)
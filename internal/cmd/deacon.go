@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -141,6 +142,8 @@ This command is used by the Deacon during health rounds to detect stuck sessions
 It tracks consecutive failures and determines when force-kill is warranted.
 
 The detection protocol:
+0. Analyse the pane (see gt deacon diagnose); an agent waiting at a
+   blocking prompt is not pinged and counts as a failure (step 3)
 1. Send HEALTH_CHECK nudge to the agent
 2. Wait for agent to update their bead (configurable timeout, default 30s)
 3. If no activity update, increment failure counter
@@ -734,6 +737,18 @@ func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Read the pane first: an agent looping or sitting at a prompt looks
+	// busy, and a ping typed into a permission prompt could answer it.
+	if diagnosis, err := diagnoseAgentPane(t, townRoot, agent, sessionName, 200); err == nil && diagnosis.State != deacon.PaneWorking {
+		fmt.Printf("%s Agent %s pane: %s (recommend: %s)\n",
+			style.Warning.Render("⚠"), agent, diagnosis.Summary(), diagnosis.Remedy)
+		if diagnosis.State == deacon.PaneBlockedPrompt {
+			// Don't type into the prompt. An agent stuck there is as
+			// unresponsive as one that ignores the ping.
+			return recordHealthCheckFailure(townRoot, state, agentState, agent, "is blocked at a prompt")
+		}
+	}
+
 	// Get current bead update time
 	baselineTime, err := getAgentBeadUpdateTime(townRoot, beadID)
 	if err != nil {
@@ -793,13 +808,19 @@ Done:
 	}
 
 	// No response - record failure
+	return recordHealthCheckFailure(townRoot, state, agentState, agent, "did not respond")
+}
+
+// recordHealthCheckFailure counts a failed health check and exits with code
+// 2 once the agent has failed enough consecutive checks to be force-killed.
+func recordHealthCheckFailure(townRoot string, state *deacon.HealthCheckState, agentState *deacon.AgentHealthState, agent, why string) error {
 	agentState.RecordFailure()
 	if err := deacon.SaveHealthCheckState(townRoot, state); err != nil {
 		style.PrintWarning("failed to save health check state: %v", err)
 	}
 
-	fmt.Printf("%s Agent %s did not respond (consecutive failures: %d/%d)\n",
-		style.Dim.Render("⚠"), agent, agentState.ConsecutiveFailures, healthCheckFailures)
+	fmt.Printf("%s Agent %s %s (consecutive failures: %d/%d)\n",
+		style.Dim.Render("⚠"), agent, why, agentState.ConsecutiveFailures, healthCheckFailures)
 
 	// Check if force-kill threshold reached
 	if agentState.ShouldForceKill(healthCheckFailures) {
@@ -870,6 +891,7 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 	if err := t.KillSessionWithProcesses(sessionName); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	_ = events.LogAudit(events.TypeKill, detectSender(), events.KillPayload("", agent, reason))
 
 	// Step 3: Update agent bead state (optional - best effort)
 	fmt.Printf("%s Updating agent bead state to 'killed'...\n", style.Dim.Render("3."))
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	diagnoseLines int
+	diagnoseJSON  bool
+)
+
+var deaconDiagnoseCmd = &cobra.Command{
+	Use:   "diagnose <agent>",
+	Short: "Classify an agent from its pane output and recommend an intervention",
+	Long: `Read an agent's recent pane output and classify what it is doing.
+
+Activity age can't tell a busy agent from one that is looping or waiting.
+This reads the pane history and reports:
+
+  working            Nothing suggests the agent is stuck     → none
+  repeating          The same output keeps scrolling by      → nudge
+  error_loop         The same error keeps coming back        → restart
+  context_exhausted  The runtime reported a full context     → restart
+  blocked_prompt     Waiting at a permission or input prompt → escalate
+
+Blocking prompts come from the agent's runtime config (tmux.blocking_prompts),
+with defaults for each provider. gt deacon health-check runs the same analysis
+before pinging.
+
+Examples:
+  gt deacon diagnose gastown/polecats/max
+  gt deacon diagnose gastown/witness --lines 400 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDeaconDiagnose,
+}
+
+func init() {
+	deaconDiagnoseCmd.Flags().IntVar(&diagnoseLines, "lines", 200, "Pane history lines to analyse")
+	deaconDiagnoseCmd.Flags().BoolVar(&diagnoseJSON, "json", false, "Output as JSON")
+
+	deaconCmd.AddCommand(deaconDiagnoseCmd)
+}
+
+func runDeaconDiagnose(cmd *cobra.Command, args []string) error {
+	agent := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, sessionName, err := agentAddressToIDs(agent)
+	if err != nil {
+		return fmt.Errorf("invalid agent address: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	exists, err := t.HasSession(sessionName)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("agent %s session %s not running", agent, sessionName)
+	}
+
+	diagnosis, err := diagnoseAgentPane(t, townRoot, agent, sessionName, diagnoseLines)
+	if err != nil {
+		return err
+	}
+
+	if diagnoseJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diagnosis)
+	}
+
+	icon := style.Success.Render("●")
+	if diagnosis.State != deacon.PaneWorking {
+		icon = style.Warning.Render("⚠")
+	}
+	fmt.Printf("%s %s: %s\n", icon, agent, style.Bold.Render(string(diagnosis.State)))
+	fmt.Printf("  %s\n", diagnosis.Summary())
+	fmt.Printf("  recommend: %s\n", diagnosis.Remedy)
+	return nil
+}
+
+// diagnoseAgentPane captures an agent's pane and classifies it using the
+// blocking prompts of the runtime the agent's role runs.
+func diagnoseAgentPane(t *tmux.Tmux, townRoot, agent, sessionName string, lines int) (deacon.PaneDiagnosis, error) {
+	paneLines, err := t.CapturePaneLines(sessionName, lines)
+	if err != nil {
+		return deacon.PaneDiagnosis{}, fmt.Errorf("capturing pane: %w", err)
+	}
+	role, rigPath := agentRoleAndRigPath(townRoot, agent)
+	rc := config.ResolveRoleAgentConfig(role, townRoot, rigPath)
+	return deacon.DiagnosePane(paneLines, rc.BlockingPrompts()), nil
+}
+
+// agentRoleAndRigPath maps an agent address (as gt deacon health-check takes
+// it) to its role and rig directory. Town-level agents have no rig.
+func agentRoleAndRigPath(townRoot, agent string) (role, rigPath string) {
+	parts := strings.Split(agent, "/")
+	switch {
+	case len(parts) == 1:
+		return parts[0], ""
+	case len(parts) == 2:
+		return parts[1], filepath.Join(townRoot, parts[0])
+	case parts[1] == "polecats":
+		return "polecat", filepath.Join(townRoot, parts[0])
+	default:
+		return parts[1], filepath.Join(townRoot, parts[0])
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAgentRoleAndRigPath(t *testing.T) {
+	town := "/town"
+	tests := []struct {
+		agent, role, rigPath string
+	}{
+		{"deacon", "deacon", ""},
+		{"mayor", "mayor", ""},
+		{"gastown/witness", "witness", filepath.Join(town, "gastown")},
+		{"gastown/refinery", "refinery", filepath.Join(town, "gastown")},
+		{"gastown/polecats/max", "polecat", filepath.Join(town, "gastown")},
+		{"gastown/crew/joe", "crew", filepath.Join(town, "gastown")},
+	}
+	for _, tt := range tests {
+		role, rigPath := agentRoleAndRigPath(town, tt.agent)
+		if role != tt.role || rigPath != tt.rigPath {
+			t.Errorf("agentRoleAndRigPath(%q) = (%q, %q), want (%q, %q)", tt.agent, role, rigPath, tt.role, tt.rigPath)
+		}
+	}
+}
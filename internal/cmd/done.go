@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/container"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -138,6 +139,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 		mayorClone := filepath.Join(townRoot, rigName, "mayor", "rig")
 		g = git.NewGit(mayorClone)
 	}
+	g.SetBranchTemplate(polecatBranchTemplate(townRoot, rigName))
 
 	// Get current branch - try env var first if cwd is gone
 	var branch string
@@ -524,6 +526,8 @@ notifyWitness:
 				style.PrintWarning("worktree nuke failed: %v (Witness will clean up)", err)
 			} else {
 				fmt.Printf("%s Worktree nuked\n", style.Bold.Render("✓"))
+				// Slings queued behind max_polecats take the freed slot via
+				// the daemon's sling_queue patrol, not from this session
 			}
 		}
 
@@ -531,7 +535,7 @@ notifyWitness:
 		// This is the last thing we do - the process will be killed when tmux session dies
 		// All exit types kill the session - "done means gone"
 		fmt.Printf("%s Terminating session (done means gone)\n", style.Bold.Render("→"))
-		if err := selfKillSession(townRoot, roleInfo); err != nil {
+		if err := selfKillSession(townRoot, roleInfo, cwd, issueID); err != nil {
 			// If session kill fails, fall through to os.Exit
 			style.PrintWarning("session kill failed: %v", err)
 		}
@@ -635,6 +639,12 @@ func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unus
 		hookedBeadID := agentBead.HookBead
 		// Only close if the hooked bead exists and is still in "hooked" status
 		if hookedBead, err := bd.Show(hookedBeadID); err == nil && hookedBead.Status == beads.StatusHooked {
+			// Keep the molecule's step timing for gt mol stats: its steps
+			// are closed with bd close, which records no step events
+			if moleculeID := hookedMolecule(hookedBead); moleculeID != "" && townRoot != "" {
+				recordMoleculeTiming(townRoot, bd, moleculeID)
+			}
+
 			// BUG FIX: Close attached molecule (wisp) BEFORE closing hooked bead.
 			// When using formula-on-bead (gt sling formula --on bead), the base bead
 			// has attached_molecule pointing to the wisp. Without this fix, gt done
@@ -781,14 +791,15 @@ func isPolecatActor(actor string) bool {
 	return len(parts) >= 2 && parts[1] == "polecats"
 }
 
-// selfKillSession terminates the polecat's own tmux session after logging the event.
+// selfKillSession terminates the polecat's own tmux session after logging the event
+// and archiving its transcript.
 // This completes the self-cleaning model: "done means gone" - both worktree and session.
 //
 // The polecat determines its session from environment variables:
 // - GT_RIG: the rig name
 // - GT_POLECAT: the polecat name
 // Session name format: gt-<rig>-<polecat>
-func selfKillSession(townRoot string, roleInfo RoleInfo) error {
+func selfKillSession(townRoot string, roleInfo RoleInfo, workDir, bead string) error {
 	// Get session info from environment (set at session startup)
 	rigName := os.Getenv("GT_RIG")
 	polecatName := os.Getenv("GT_POLECAT")
@@ -818,12 +829,34 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	_ = events.LogFeed(events.TypeSessionDeath, agentID,
 		events.SessionDeathPayload(sessionName, agentID, "self-clean: done means gone", "gt done"))
 
+	t := tmux.NewTmux()
+
+	// Archive the transcript for gt seance search while we are still around
+	// to do it (non-fatal). The runtime keys it by the directory the session
+	// started in, not ours, and keeps it after the worktree is nuked.
+	if dir, err := t.GetPaneWorkDir(sessionName); err == nil && dir != "" {
+		workDir = dir
+	}
+	if townRoot != "" && workDir != "" {
+		if _, err := archiveAgentTranscript(townRoot, agentID, sessionName, workDir, bead); err != nil {
+			style.PrintWarning("could not archive transcript: %v", err)
+		}
+	}
+
+	// In a container there is no tmux to reach. Stopping the container ends
+	// the pane's process, and with it the session.
+	if container.Inside() {
+		if err := container.StopSelf(); err != nil {
+			return fmt.Errorf("stopping container %s: %w", sessionName, err)
+		}
+		return nil
+	}
+
 	// Kill our own tmux session with proper process cleanup
 	// This will terminate Claude and all child processes, completing the self-cleaning cycle.
 	// We use KillSessionWithProcessesExcluding to ensure no orphaned processes are left behind,
 	// while excluding our own PID to avoid killing ourselves before cleanup completes.
 	// The tmux kill-session at the end will terminate us along with the session.
-	t := tmux.NewTmux()
 	myPID := strconv.Itoa(os.Getpid())
 	if err := t.KillSessionWithProcessesExcluding(sessionName, []string{myPID}); err != nil {
 		return fmt.Errorf("killing session %s: %w", sessionName, err)
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/base32"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -33,6 +34,11 @@ var (
 	formulaFormatWrite bool
 	formulaFormatCheck bool
 	formulaFormatDiff  bool
+	formulaTestVars    []string
+	formulaTestGolden  string
+	formulaTestDir     string
+	formulaTestUpdate  bool
+	formulaTestJSON    bool
 )
 
 var formulaCmd = &cobra.Command{
@@ -52,6 +58,7 @@ Commands:
   show    Display formula details (steps, variables, composition)
   run     Execute a formula (pour and dispatch)
   create  Create a new formula template
+  test    Cook a formula and compare against golden output
 
 Search paths (in order):
   1. .beads/formulas/ (project)
@@ -62,7 +69,8 @@ Examples:
   gt formula list                    # List all formulas
   gt formula show shiny              # Show formula details
   gt formula run shiny --pr=123      # Run formula on PR #123
-  gt formula create my-workflow      # Create new formula template`,
+  gt formula create my-workflow      # Create new formula template
+  gt formula test mol-polecat-work --var issue=gt-123`,
 }
 
 var formulaListCmd = &cobra.Command{
@@ -170,6 +178,36 @@ Examples:
 	RunE: runFormulaFormat,
 }
 
+var formulaTestCmd = &cobra.Command{
+	Use:   "test [name]",
+	Short: "Cook a formula and compare against golden output",
+	Long: `Cook a formula with fixture inputs and check the resulting molecule.
+
+The formula is expanded in memory (no beads are created) into the step
+beads it would pour: step IDs, dependencies and rendered descriptions.
+The result is printed, or compared against a golden file so that edits
+to step text or needs show up as regressions.
+
+Formulas are looked up in the usual search paths, falling back to the
+formulas embedded in gt.
+
+With --dir, every *.fixture.toml in the directory is run against its
+paired .golden file. A fixture looks like:
+
+  formula = "mol-polecat-work"
+
+  [vars]
+  issue = "gt-123"
+
+Examples:
+  gt formula test mol-polecat-work --var issue=gt-123
+  gt formula test mol-polecat-work --var issue=gt-123 --golden work.golden
+  gt formula test mol-polecat-work --var issue=gt-123 --golden work.golden --update
+  gt formula test --dir internal/formula/testdata/golden`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFormulaTest,
+}
+
 func init() {
 	// List flags
 	formulaListCmd.Flags().BoolVar(&formulaListJSON, "json", false, "Output as JSON")
@@ -190,12 +228,20 @@ func init() {
 	formulaFormatCmd.Flags().BoolVar(&formulaFormatCheck, "check", false, "Check if formatting needed (exit 1 if not formatted)")
 	formulaFormatCmd.Flags().BoolVar(&formulaFormatDiff, "diff", false, "Show diff of changes")
 
+	// Test flags
+	formulaTestCmd.Flags().StringArrayVar(&formulaTestVars, "var", nil, "Variable value (key=value, repeatable)")
+	formulaTestCmd.Flags().StringVar(&formulaTestGolden, "golden", "", "Golden file to compare against")
+	formulaTestCmd.Flags().StringVar(&formulaTestDir, "dir", "", "Run all fixtures in directory")
+	formulaTestCmd.Flags().BoolVar(&formulaTestUpdate, "update", false, "Rewrite golden files instead of comparing")
+	formulaTestCmd.Flags().BoolVar(&formulaTestJSON, "json", false, "Output cooked molecule as JSON")
+
 	// Add subcommands
 	formulaCmd.AddCommand(formulaListCmd)
 	formulaCmd.AddCommand(formulaShowCmd)
 	formulaCmd.AddCommand(formulaRunCmd)
 	formulaCmd.AddCommand(formulaCreateCmd)
 	formulaCmd.AddCommand(formulaFormatCmd)
+	formulaCmd.AddCommand(formulaTestCmd)
 
 	rootCmd.AddCommand(formulaCmd)
 }
@@ -1296,3 +1342,103 @@ func runFormulaFormat(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// runFormulaTest cooks a formula in memory and prints or checks the result
+func runFormulaTest(cmd *cobra.Command, args []string) error {
+	if formulaTestDir != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--dir cannot be combined with a formula name")
+		}
+		return runFormulaTestDir(formulaTestDir)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("formula name required (or use --dir)")
+	}
+
+	vars, err := parseFormulaTestVars(formulaTestVars)
+	if err != nil {
+		return err
+	}
+
+	f, err := loadFormulaByName(args[0])
+	if err != nil {
+		return err
+	}
+	store := beads.NewMemoryStore("test")
+	root, err := formula.Cook(f, vars, store)
+	if err != nil {
+		return fmt.Errorf("cooking %s: %w", args[0], err)
+	}
+
+	if formulaTestGolden != "" {
+		if err := formula.CompareGolden(store, root, formulaTestGolden, formulaTestUpdate); err != nil {
+			return err
+		}
+		if formulaTestUpdate {
+			fmt.Printf("%s Updated %s\n", style.Bold.Render("✓"), formulaTestGolden)
+		} else {
+			fmt.Printf("%s %s matches %s\n", style.Bold.Render("✓"), args[0], formulaTestGolden)
+		}
+		return nil
+	}
+
+	if formulaTestJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(append([]*beads.Issue{root}, store.Children(root.ID)...))
+	}
+	fmt.Print(string(formula.Golden(store, root)))
+	return nil
+}
+
+// runFormulaTestDir runs every fixture in dir against its golden file
+func runFormulaTestDir(dir string) error {
+	fixtures, err := formula.FindFixtures(dir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no *%s files in %s", formula.FixtureSuffix, dir)
+	}
+
+	failed := 0
+	for _, path := range fixtures {
+		fx, err := formula.LoadFixture(path)
+		if err == nil {
+			err = formula.CheckFixture(fx, loadFormulaByName, formulaTestUpdate)
+		}
+		if err != nil {
+			fmt.Printf("%s %s\n  %v\n", style.Error.Render("✗"), filepath.Base(path), err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), filepath.Base(path))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d formula fixtures failed", failed, len(fixtures))
+	}
+	return nil
+}
+
+// loadFormulaByName finds a formula in the search paths, falling back to
+// the formulas embedded in the binary
+func loadFormulaByName(name string) (*formula.Formula, error) {
+	if path, err := findFormulaFile(name); err == nil {
+		return formula.ParseFile(path)
+	}
+	return formula.ParseEmbedded(name)
+}
+
+// parseFormulaTestVars parses repeated key=value flags into a map
+func parseFormulaTestVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected key=value)", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
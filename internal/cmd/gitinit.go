@@ -84,6 +84,14 @@ const HQGitignore = `# Gas Town HQ .gitignore
 # =============================================================================
 **/.runtime/
 
+# Archived session transcripts (gt seance search)
+.transcripts/
+
+# =============================================================================
+# Secrets (never commit)
+# =============================================================================
+mayor/audit.key
+
 # =============================================================================
 # Rig .beads symlinks (point to ignored mayor/rig/.beads, recreated on setup)
 # =============================================================================
@@ -182,6 +182,16 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Sent handoff mail %s (auto-hooked)\n", style.Bold.Render("📬"), beadID)
 	}
 
+	// Archive this session's transcript for gt seance search (best effort;
+	// the respawn is more important)
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if agent := sessionToGTRole(currentSession); agent != "" {
+			if cwd, err := os.Getwd(); err == nil {
+				_, _ = archiveAgentTranscript(townRoot, agent, currentSession, cwd, "")
+			}
+		}
+	}
+
 	// NOTE: reportAgentState("stopped") removed (gt-zecmc)
 	// Agent liveness is observable from tmux - no need to record it in bead.
 	// "Discover, don't track" principle: reality is truth, state is derived.
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/krc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -359,6 +360,7 @@ func runKrcConfigSet(cmd *cobra.Command, args []string) error {
 	if err := krc.SaveConfig(townRoot, config); err != nil {
 		return fmt.Errorf("saving config: %w", err)
 	}
+	logConfigChange("krc", pattern, events.ConfigSet, krcFormatDuration(ttl))
 
 	return nil
 }
@@ -375,6 +377,7 @@ func runKrcConfigReset(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println("Reset KRC configuration to defaults.")
+	logConfigChange("krc", "*", events.ConfigReset, "")
 	return nil
 }
 
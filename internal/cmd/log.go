@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cgroup"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -41,6 +44,7 @@ Events logged include:
   done    - agent finished work
   crash   - agent exited unexpectedly
   kill    - agent killed intentionally
+  autoscale - polecat autoscaling decision for a rig
 
 Examples:
   gt log                     # Show last 20 events
@@ -211,6 +215,8 @@ func printEvent(e townlog.Event) {
 		typeStr = style.Error.Render("[escalation_sent]")
 	case townlog.EventPatrolComplete:
 		typeStr = style.Success.Render("[patrol_complete]")
+	case townlog.EventAutoscale:
+		typeStr = style.Bold.Render("[autoscale]")
 	default:
 		typeStr = fmt.Sprintf("[%s]", e.Type)
 	}
@@ -287,6 +293,11 @@ func formatEventDetail(e townlog.Event) string {
 			return fmt.Sprintf("patrol complete (%s)", e.Context)
 		}
 		return "patrol complete"
+	case townlog.EventAutoscale:
+		if e.Context != "" {
+			return e.Context
+		}
+		return "autoscale decision"
 	default:
 		if e.Context != "" {
 			return fmt.Sprintf("%s (%s)", e.Type, e.Context)
@@ -338,6 +349,16 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 		eventType = townlog.EventCrash
 		context = fmt.Sprintf("exit code %d", crashExitCode)
 		if crashSession != "" {
+			// Sessions confined to a cgroup record whether a limit killed them
+			if limit := cgroup.DeathReason(crashSession); limit != "" {
+				context = limit + ", " + context
+				// The daemon reports polecat deaths, limit included, when it
+				// restarts them; don't put them in the feed twice.
+				if id, err := session.ParseSessionName(crashSession); err != nil || id.Role != session.RolePolecat {
+					_ = events.LogFeed(events.TypeSessionDeath, crashAgent,
+						events.SessionDeathPayload(crashSession, crashAgent, limit, "gt log crash"))
+				}
+			}
 			context += fmt.Sprintf(" (session: %s)", crashSession)
 		}
 	}
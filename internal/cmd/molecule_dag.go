@@ -6,10 +6,13 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/molstats"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // DAGNode represents a node in the dependency graph.
@@ -22,6 +25,9 @@ type DAGNode struct {
 	Dependents   []string   `json:"dependents,omitempty"`
 	Tier         int        `json:"tier"` // Execution tier (0 = root, higher = later)
 	Children     []*DAGNode `json:"children,omitempty"`
+
+	// Timing is the step's claim/start/close record from the events log, if any.
+	Timing *molstats.StepRun `json:"timing,omitempty"`
 }
 
 // DAGInfo contains the full DAG information for a molecule.
@@ -102,6 +108,15 @@ func runMoleculeDag(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("building DAG: %w", err)
 	}
 
+	// Attach step timing (best-effort - timing is only recorded inside a town)
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if runs, err := molstats.Load(townRoot); err == nil {
+			for id, node := range dag.Nodes {
+				node.Timing = molstats.Find(runs, id)
+			}
+		}
+	}
+
 	// JSON output
 	if moleculeJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -373,7 +388,7 @@ func printNode(dag *DAGInfo, id, prefix string, isLast bool, visited map[string]
 	}
 
 	// Print node
-	fmt.Printf("%s%s %s %s%s\n", prefix, connector, icon, node.ID, parallelMark)
+	fmt.Printf("%s%s %s %s%s%s\n", prefix, connector, icon, node.ID, parallelMark, dagTimingSuffix(node))
 
 	// Child prefix
 	childPrefix := prefix
@@ -390,6 +405,26 @@ func printNode(dag *DAGInfo, id, prefix string, isLast bool, visited map[string]
 	}
 }
 
+// dagTimingSuffix renders a node's duration and agent, if timing was recorded.
+func dagTimingSuffix(node *DAGNode) string {
+	if node.Timing == nil {
+		return ""
+	}
+	var parts []string
+	if d := node.Timing.Duration(); d > 0 {
+		parts = append(parts, formatDuration(d))
+	} else if elapsed := node.Timing.Elapsed(time.Now()); elapsed > 0 {
+		parts = append(parts, formatDuration(elapsed)+" so far")
+	}
+	if node.Timing.Agent != "" {
+		parts = append(parts, node.Timing.Agent)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return style.Dim.Render(" (" + strings.Join(parts, ", ") + ")")
+}
+
 // outputDAGTiers outputs the DAG grouped by execution tier.
 func outputDAGTiers(dag *DAGInfo) error {
 	fmt.Printf("\n%s %s\n", style.Bold.Render("📊 DAG Tiers:"), dag.RootTitle)
@@ -437,7 +472,7 @@ func outputDAGTiers(dag *DAGInfo) error {
 				depStr = fmt.Sprintf(" ← %s", strings.Join(node.Dependencies, ", "))
 			}
 
-			fmt.Printf("       %s %s%s%s\n", icon, id, parallelMark, depStr)
+			fmt.Printf("       %s %s%s%s%s\n", icon, id, parallelMark, depStr, dagTimingSuffix(node))
 		}
 		fmt.Println()
 	}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	molResumeDir    string
+	molResumeDryRun bool
+	molResumeJSON   bool
+	molResumePrompt bool
+)
+
+var moleculeResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Rebuild molecule state from the latest checkpoint",
+	Long: `Resume molecule execution from the checkpoint left by a previous session.
+
+Recovery steps:
+  1. Read the polecat's .polecat-checkpoint.json
+  2. Verify the worktree still matches the checkpoint (HEAD, branch,
+     uncommitted files)
+  3. Replay step closures the previous session started but never
+     confirmed (closes steps that are still open)
+  4. Print a resume prompt describing exactly where to pick up
+
+The daemon runs this automatically with --prompt when it restarts a polecat
+after a session death, and hands the prompt to the new session.
+
+Examples:
+  gt mol resume                          # Resume in the current worktree
+  gt mol resume --dir <polecat-worktree> # Resume a specific worktree
+  gt mol resume --dry-run                # Show what would be replayed
+  gt mol resume --prompt                 # Print only the resume prompt`,
+	Args: cobra.NoArgs,
+	RunE: runMoleculeResume,
+}
+
+func init() {
+	moleculeResumeCmd.Flags().StringVar(&molResumeDir, "dir", "", "Worktree to resume (default: current directory)")
+	moleculeResumeCmd.Flags().BoolVarP(&molResumeDryRun, "dry-run", "n", false, "Show what would be replayed without closing steps")
+	moleculeResumeCmd.Flags().BoolVar(&molResumeJSON, "json", false, "Output as JSON")
+	moleculeResumeCmd.Flags().BoolVar(&molResumePrompt, "prompt", false, "Print only the resume prompt")
+
+	moleculeCmd.AddCommand(moleculeResumeCmd)
+}
+
+// ResumeResult is the outcome of gt mol resume.
+type ResumeResult struct {
+	Checkpoint *checkpoint.Checkpoint     `json:"checkpoint"`
+	Worktree   *checkpoint.WorktreeStatus `json:"worktree,omitempty"`
+	Problems   []string                   `json:"problems,omitempty"`
+	Replayed   []string                   `json:"replayed,omitempty"`
+	Prompt     string                     `json:"prompt"`
+}
+
+func runMoleculeResume(cmd *cobra.Command, args []string) error {
+	dir := molResumeDir
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		dir = cwd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", molResumeDir, err)
+	}
+
+	cp, err := checkpoint.Read(dir)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		return fmt.Errorf("no checkpoint in %s", dir)
+	}
+
+	result := ResumeResult{Checkpoint: cp}
+
+	ws, err := checkpoint.VerifyWorktree(dir, cp)
+	if err != nil {
+		result.Problems = append(result.Problems, err.Error())
+	} else {
+		result.Worktree = ws
+		result.Problems = ws.Problems(cp)
+	}
+
+	result.Replayed = replayPendingClosures(dir, cp, molResumeDryRun)
+	result.Prompt = cp.ResumePrompt(ws)
+
+	if molResumePrompt {
+		fmt.Print(result.Prompt)
+		return nil
+	}
+	if molResumeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Printf("%s Checkpoint: %s\n", style.Bold.Render("📌"), cp.Summary())
+	if len(result.Problems) == 0 {
+		fmt.Printf("%s Worktree matches checkpoint\n", style.Bold.Render("✓"))
+	} else {
+		for _, p := range result.Problems {
+			style.PrintWarning("%s", p)
+		}
+	}
+	for _, id := range result.Replayed {
+		if molResumeDryRun {
+			fmt.Printf("[dry-run] Would close step %s\n", id)
+		} else {
+			fmt.Printf("%s Replayed closure of step %s\n", style.Bold.Render("✓"), id)
+		}
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Resume prompt:"))
+	fmt.Print(result.Prompt)
+	return nil
+}
+
+// replayPendingClosures closes steps the previous session started closing
+// but never acknowledged. Steps already closed are simply acked. Returns the
+// IDs of steps that were (or, in dry-run, would be) closed.
+func replayPendingClosures(dir string, cp *checkpoint.Checkpoint, dryRun bool) []string {
+	if len(cp.PendingClosures) == 0 {
+		return nil
+	}
+
+	b := beads.New(dir)
+	var replayed []string
+	for _, stepID := range cp.PendingClosures {
+		step, err := b.Show(stepID)
+		if err != nil {
+			style.PrintWarning("could not look up pending step %s: %v", stepID, err)
+			continue
+		}
+		if step.Status != "closed" {
+			replayed = append(replayed, stepID)
+			if dryRun {
+				continue
+			}
+			if err := b.Close(stepID); err != nil {
+				style.PrintWarning("could not close step %s: %v", stepID, err)
+				continue
+			}
+			logStepEvent(b, events.TypeStepClosed, detectSender(), step)
+		}
+		if !dryRun {
+			_ = checkpoint.AckClosure(dir, stepID)
+		}
+	}
+	return replayed
+}
@@ -0,0 +1,481 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/molstats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	molStatsRuns   bool
+	molStatsJSON   bool
+	molSLADryRun   bool
+	molSLASeverity string
+)
+
+var moleculeStatsCmd = &cobra.Command{
+	Use:   "stats <formula>",
+	Short: "Show per-step duration stats for a formula",
+	Long: `Show how long each step of a formula takes across historical runs.
+
+Step timing is recorded to the events log as agents claim, start and close
+molecule steps with gt mol step (step_claimed, step_started, step_closed).
+Steps closed any other way, such as with bd close, are timed from their
+beads' timestamps when the molecule's polecat runs gt done and by the SLA
+patrol. This command folds both and reports p50/p90/max duration per step,
+so you can see which steps are the bottleneck.
+
+Durations run from step start (or claim, if no start was recorded) to close.
+A step timed from its bead starts when the steps blocking it closed.
+Runs are grouped by formula step ID, recorded on the molecule when it is
+slung, so steps whose titles vary with --var still aggregate. Steps with an SLA in the formula also show how many runs exceeded it.
+
+Examples:
+  gt mol stats mol-polecat-work          # Percentiles per step
+  gt mol stats mol-polecat-work --runs   # Individual step runs with agents
+  gt mol stats mol-polecat-work --json   # Machine-readable output`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStats,
+}
+
+var moleculeSLACmd = &cobra.Command{
+	Use:   "sla",
+	Short: "Escalate molecule steps running past their SLA",
+	Long: `Check in-flight molecule steps against the SLAs declared in their formulas.
+
+A formula step can declare an SLA:
+
+  [[steps]]
+  id = "implement"
+  title = "Implement the solution"
+  sla = "2h"
+
+Any open step that has been running longer than its SLA is escalated once
+(a step_sla_breached event is recorded so later checks skip it). Steps that
+close late are escalated by 'gt mol step done' as they close. Besides the
+recorded step timing, the molecules on every rig's hooks are read, so steps
+worked with plain bd commands are covered too.
+
+Enable patrols.step_sla in mayor/daemon.json to have the daemon run this
+every heartbeat.
+
+Examples:
+  gt mol sla              # Escalate overdue steps
+  gt mol sla --dry-run    # Show overdue steps without escalating`,
+	Args: cobra.NoArgs,
+	RunE: runMoleculeSLA,
+}
+
+func init() {
+	moleculeStatsCmd.Flags().BoolVar(&molStatsRuns, "runs", false, "List individual step runs")
+	moleculeStatsCmd.Flags().BoolVar(&molStatsJSON, "json", false, "Output as JSON")
+
+	moleculeSLACmd.Flags().BoolVarP(&molSLADryRun, "dry-run", "n", false, "Show overdue steps without escalating")
+	moleculeSLACmd.Flags().StringVar(&molSLASeverity, "severity", config.SeverityMedium, "Severity for SLA escalations")
+
+	moleculeCmd.AddCommand(moleculeStatsCmd)
+	moleculeCmd.AddCommand(moleculeSLACmd)
+}
+
+func runMoleculeStats(cmd *cobra.Command, args []string) error {
+	formulaName := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	runs, err := molstats.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if molStatsRuns {
+		var matching []*molstats.StepRun
+		for _, r := range runs {
+			if r.Formula == formulaName {
+				matching = append(matching, r)
+			}
+		}
+		if molStatsJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(matching)
+		}
+		return outputStepRuns(formulaName, matching)
+	}
+
+	stats := molstats.Summarize(runs, formulaName)
+	if molStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Printf("No completed step runs recorded for %s\n", formulaName)
+		return nil
+	}
+
+	// SLAs are optional; stats still print if the formula can't be found
+	f, _ := loadFormulaByName(formulaName)
+
+	fmt.Printf("%s Step durations for %s\n\n", style.Bold.Render("⏱"), formulaName)
+	fmt.Printf("  %-40s %5s %10s %10s %10s  %s\n", "STEP", "RUNS", "P50", "P90", "MAX", "SLA")
+	for _, s := range stats {
+		sla := "-"
+		if f != nil {
+			if step := f.GetStep(s.Step); step != nil && step.SLA != "" {
+				sla = step.SLA
+				if s.Breaches > 0 {
+					sla = fmt.Sprintf("%s (%d over)", step.SLA, s.Breaches)
+				}
+			}
+		}
+		name := s.Step
+		if name == "" {
+			name = s.Title
+		}
+		fmt.Printf("  %-40s %5d %10s %10s %10s  %s\n",
+			truncateStr(name, 40), s.Runs,
+			formatDuration(s.P50), formatDuration(s.P90), formatDuration(s.Max), sla)
+	}
+	return nil
+}
+
+// outputStepRuns prints individual step runs for a formula.
+func outputStepRuns(formulaName string, runs []*molstats.StepRun) error {
+	if len(runs) == 0 {
+		fmt.Printf("No step runs recorded for %s\n", formulaName)
+		return nil
+	}
+
+	fmt.Printf("%s Step runs for %s\n\n", style.Bold.Render("⏱"), formulaName)
+	for _, r := range runs {
+		duration := style.Dim.Render("running")
+		if r.Closed() {
+			duration = formatDuration(r.Duration())
+		}
+		fmt.Printf("  %s  %-30s %-28s %s\n",
+			r.Step, truncateStr(r.Title, 30), r.Agent, duration)
+		fmt.Printf("      claimed %s  started %s  closed %s\n",
+			formatStepTime(r.ClaimedAt), formatStepTime(r.StartedAt), formatStepTime(r.ClosedAt))
+	}
+	return nil
+}
+
+func runMoleculeSLA(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !config.IsValidSeverity(molSLASeverity) {
+		return fmt.Errorf("invalid severity '%s': must be critical, high, medium, or low", molSLASeverity)
+	}
+
+	runs, err := molstats.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	live := hookedMoleculeRuns(townRoot)
+	if err := molstats.Record(townRoot, runs, live); err != nil {
+		style.PrintWarning("could not record step timing: %v", err)
+	}
+	runs = molstats.Merge(runs, live)
+
+	now := time.Now()
+	formulas := make(map[string]*formula.Formula)
+	overdue := 0
+	for _, r := range runs {
+		if r.Closed() || r.Breached || r.Formula == "" || r.FormulaStep == "" {
+			continue
+		}
+		sla := stepSLA(formulas, r.Formula, r.FormulaStep)
+		if sla == 0 || r.Elapsed(now) <= sla {
+			continue
+		}
+		overdue++
+
+		if molSLADryRun {
+			fmt.Printf("[dry-run] Would escalate %s (%s): running %s, SLA %s\n",
+				r.Step, r.Title, formatDuration(r.Elapsed(now)), sla)
+			continue
+		}
+		if err := escalateStepSLA(townRoot, r, r.Elapsed(now), sla, molSLASeverity); err != nil {
+			style.PrintWarning("could not escalate %s: %v", r.Step, err)
+			continue
+		}
+		fmt.Printf("%s Escalated %s (%s): running %s, SLA %s\n",
+			style.Bold.Render("⚠"), r.Step, r.Title, formatDuration(r.Elapsed(now)), sla)
+	}
+
+	if overdue == 0 {
+		fmt.Printf("%s No steps over SLA\n", style.Bold.Render("✓"))
+	}
+	return nil
+}
+
+// stepSLA returns the SLA for a step of the named formula, caching parsed
+// formulas across calls. Returns 0 if the formula or step has no SLA.
+func stepSLA(cache map[string]*formula.Formula, formulaName, stepID string) time.Duration {
+	f, ok := cache[formulaName]
+	if !ok {
+		f, _ = loadFormulaByName(formulaName)
+		cache[formulaName] = f
+	}
+	if f == nil {
+		return 0
+	}
+	step := f.GetStep(stepID)
+	if step == nil {
+		return 0
+	}
+	return step.SLADuration()
+}
+
+// escalateStepSLA records an SLA breach for a step run and raises an escalation.
+func escalateStepSLA(townRoot string, r *molstats.StepRun, took, sla time.Duration, severity string) error {
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+
+	from := detectSender()
+	payload := events.StepPayload(r.Step, r.Molecule, r.Formula, r.FormulaStep, r.Title)
+	payload["duration"] = took.Round(time.Second).String()
+	payload["sla"] = sla.String()
+	_ = events.LogAudit(events.TypeStepSLABreached, from, payload)
+
+	_, _, _, err = createEscalation(townRoot, escalationConfig, escalationRequest{
+		Description: fmt.Sprintf("Step over SLA: %s (%s)", r.Title, r.Formula),
+		Severity:    severity,
+		Reason:      fmt.Sprintf("step %s by %s has taken %s (SLA %s)", r.Step, r.Agent, formatDuration(took), sla),
+		Source:      "sla:" + r.Formula,
+		RelatedBead: r.Step,
+		From:        from,
+	})
+	return err
+}
+
+// recordMoleculeFormula notes on a freshly poured molecule's root which
+// formula it came from and which formula step each step bead is, so step
+// timing is keyed on the formula rather than the proto bead or rendered
+// titles. Steps are matched by cooking the formula locally with the same
+// vars. Best-effort: an unmatched step is timed by its title.
+func recordMoleculeFormula(workDir, rootID, formulaName string, vars []string) error {
+	b := beads.New(workDir)
+	root, err := b.Show(rootID)
+	if err != nil {
+		return err
+	}
+
+	fields := &beads.MoleculeFormulaFields{Formula: formulaName, Steps: make(map[string]string)}
+	if f, err := loadFormulaByName(formulaName); err == nil {
+		if err := matchFormulaSteps(b, f, rootID, vars, fields.Steps); err != nil {
+			style.PrintWarning("could not match steps of %s to formula %s: %v", rootID, formulaName, err)
+		}
+	}
+
+	desc := beads.SetMoleculeFormulaFields(root, fields)
+	return b.Update(rootID, beads.UpdateOptions{Description: &desc})
+}
+
+// matchFormulaSteps maps the step beads under rootID to the formula steps
+// they were poured from, matching on the titles the formula renders to with
+// vars. Repeated titles are matched in order.
+func matchFormulaSteps(b *beads.Beads, f *formula.Formula, rootID string, vars []string, steps map[string]string) error {
+	varMap, err := parseFormulaTestVars(vars)
+	if err != nil {
+		return err
+	}
+	store := beads.NewMemoryStore("cook")
+	cooked, err := formula.Cook(f, varMap, store)
+	if err != nil {
+		return err
+	}
+	prefix := formula.StepBeadID(cooked.ID, "")
+	byTitle := make(map[string][]string)
+	for _, s := range store.Children(cooked.ID) {
+		byTitle[s.Title] = append(byTitle[s.Title], strings.TrimPrefix(s.ID, prefix))
+	}
+
+	children, err := b.List(beads.ListOptions{Parent: rootID, Status: "all", Priority: -1})
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if ids := byTitle[child.Title]; len(ids) > 0 {
+			steps[child.ID] = ids[0]
+			byTitle[child.Title] = ids[1:]
+		}
+	}
+	return nil
+}
+
+// stepFormula returns the formula a step bead's molecule was poured from and
+// the step's ID within it, as recorded by recordMoleculeFormula. Either may
+// be empty for molecules poured before formulas were recorded.
+func stepFormula(b *beads.Beads, step *beads.Issue) (formulaName, formulaStep string) {
+	root, err := b.Show(stepMoleculeID(step))
+	if err != nil {
+		return "", ""
+	}
+	fields := beads.ParseMoleculeFormulaFields(root)
+	if fields == nil {
+		return "", ""
+	}
+	return fields.Formula, fields.Steps[step.ID]
+}
+
+// stepMoleculeID returns the molecule root a step bead belongs to.
+func stepMoleculeID(step *beads.Issue) string {
+	if step.Parent != "" {
+		return step.Parent
+	}
+	return extractMoleculeIDFromStep(step.ID)
+}
+
+// moleculeStepRuns derives the step runs of a molecule from its step beads.
+func moleculeStepRuns(b *beads.Beads, rootID string) ([]*molstats.StepRun, error) {
+	root, err := b.Show(rootID)
+	if err != nil {
+		return nil, err
+	}
+	children, err := b.List(beads.ListOptions{Parent: rootID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(children))
+	for _, child := range children {
+		ids = append(ids, child.ID)
+	}
+	// bd list doesn't return dependencies, but bd show does
+	details, err := b.ShowMultiple(ids)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]*beads.Issue, 0, len(children))
+	for _, child := range children {
+		if step, ok := details[child.ID]; ok {
+			child = step
+		}
+		steps = append(steps, child)
+	}
+	return molstats.FromBeads(root, steps), nil
+}
+
+// recordMoleculeTiming keeps the timing of a molecule's closed steps for
+// gt mol stats before the molecule is closed or burned. Best-effort.
+func recordMoleculeTiming(townRoot string, b *beads.Beads, rootID string) {
+	derived, err := moleculeStepRuns(b, rootID)
+	if err != nil {
+		return
+	}
+	known, err := molstats.Load(townRoot)
+	if err != nil {
+		return
+	}
+	if err := molstats.Record(townRoot, known, derived); err != nil {
+		style.PrintWarning("could not record step timing of %s: %v", rootID, err)
+	}
+}
+
+// hookedMolecule returns the formula molecule a hooked bead carries: the
+// bead itself when it is a molecule root, or the molecule attached to it.
+func hookedMolecule(hooked *beads.Issue) string {
+	if beads.ParseMoleculeFormulaFields(hooked) != nil {
+		return hooked.ID
+	}
+	if attachment := beads.ParseAttachmentFields(hooked); attachment != nil {
+		return attachment.AttachedMolecule
+	}
+	return ""
+}
+
+// hookedMoleculeRuns derives the step runs of the molecules on the hooks
+// of the town's and every rig's agents. Best-effort: beads that can't be
+// read are skipped.
+func hookedMoleculeRuns(townRoot string) []*molstats.StepRun {
+	dirs := []string{beads.GetTownBeadsPath(townRoot)}
+	rigNames, _ := getKnownRigs(townRoot)
+	for _, name := range rigNames {
+		dirs = append(dirs, filepath.Join(townRoot, name))
+	}
+
+	var runs []*molstats.StepRun
+	for _, dir := range dirs {
+		b := beads.New(dir)
+		hooked, err := b.List(beads.ListOptions{Status: beads.StatusHooked, Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range hooked {
+			rootID := hookedMolecule(issue)
+			if rootID == "" {
+				continue
+			}
+			if derived, err := moleculeStepRuns(b, rootID); err == nil {
+				runs = append(runs, derived...)
+			}
+		}
+	}
+	return runs
+}
+
+// logStepEvent records a molecule step timing event for gt mol stats.
+// Best-effort: failures are ignored like other event logging.
+func logStepEvent(b *beads.Beads, eventType, actor string, step *beads.Issue) {
+	formulaName, formulaStep := stepFormula(b, step)
+	_ = events.LogAudit(eventType, actor,
+		events.StepPayload(step.ID, stepMoleculeID(step), formulaName, formulaStep, step.Title))
+}
+
+// checkClosedStepSLA escalates a just-closed step if it took longer than the
+// SLA declared in its formula. Best-effort: missing timing data or formula
+// definitions mean no check.
+func checkClosedStepSLA(townRoot string, b *beads.Beads, step *beads.Issue) {
+	formulaName, formulaStep := stepFormula(b, step)
+	if formulaName == "" || formulaStep == "" {
+		return
+	}
+	sla := stepSLA(make(map[string]*formula.Formula), formulaName, formulaStep)
+	if sla == 0 {
+		return
+	}
+
+	runs, err := molstats.Load(townRoot)
+	if err != nil {
+		return
+	}
+	r := molstats.Find(runs, step.ID)
+	if r == nil || r.Breached || r.Duration() <= sla {
+		return
+	}
+
+	if err := escalateStepSLA(townRoot, r, r.Duration(), sla, config.SeverityMedium); err != nil {
+		style.PrintWarning("could not escalate SLA breach: %v", err)
+		return
+	}
+	fmt.Printf("%s Step took %s (SLA %s) - escalated\n",
+		style.Dim.Render("⚠"), formatDuration(r.Duration()), sla)
+}
+
+// formatStepTime formats a step timestamp, or "-" if unset.
+func formatStepTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
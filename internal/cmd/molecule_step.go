@@ -10,6 +10,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -111,11 +113,25 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
 	} else {
+		// Record the closure as pending in the checkpoint so that gt mol
+		// resume can replay it if this session dies mid-close. Polecats get
+		// a checkpoint captured on demand if they haven't written one.
+		trackClosure := false
+		if cp, _ := checkpoint.Read(cwd); cp != nil || os.Getenv("GT_POLECAT") != "" {
+			trackClosure = checkpoint.AddPendingClosure(cwd, moleculeID, stepID) == nil
+		}
 		if err := b.Close(stepID); err != nil {
 			return fmt.Errorf("closing step: %w", err)
 		}
+		if trackClosure {
+			_ = checkpoint.AckClosure(cwd, stepID)
+		}
 		result.StepClosed = true
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
+
+		// Record timing and check the step's SLA
+		logStepEvent(b, events.TypeStepClosed, detectSender(), step)
+		checkClosedStepSLA(townRoot, b, step)
 	}
 
 	// Step 4: Find all ready steps (supports fan-out pattern)
@@ -411,6 +427,7 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 	}
 
 	fmt.Printf("%s Next step pinned: %s\n", style.Bold.Render("📌"), nextStep.ID)
+	logStepEvent(beads.New(gitRoot), events.TypeStepClaimed, agentID, nextStep)
 
 	// Respawn the pane
 	if !tmux.IsInsideTmux() {
@@ -481,13 +498,16 @@ func handleParallelSteps(cwd, townRoot, workDir string, steps []*beads.Issue, dr
 		return fmt.Errorf("finding git root: %w", err)
 	}
 
+	b := beads.New(gitRoot)
 	for _, step := range steps {
 		markCmd := exec.Command("bd", "update", step.ID, "--status=in_progress")
 		markCmd.Dir = gitRoot
 		markCmd.Stderr = os.Stderr
 		if err := markCmd.Run(); err != nil {
 			style.PrintWarning("could not mark step %s as in_progress: %v", step.ID, err)
+			continue
 		}
+		logStepEvent(b, events.TypeStepStarted, detectSender(), step)
 	}
 
 	// Execute steps concurrently using goroutines
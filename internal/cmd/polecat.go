@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -171,6 +172,9 @@ This command removes orphaned branches:
   - Branches for polecats that no longer exist
   - Old timestamped branches (keeps only the current one per polecat)
 
+Branches of preempted work (gt polecat preempt) are kept until the work
+is resumed.
+
 Examples:
   gt polecat gc greenplace
   gt polecat gc greenplace --dry-run`,
@@ -460,7 +464,12 @@ func runPolecatAdd(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Adding polecat %s to rig %s...\n", polecatName, rigName)
 
+	unlockAdmission, err := mgr.LockAdmission()
+	if err != nil {
+		return err
+	}
 	p, err := mgr.Add(polecatName)
+	unlockAdmission()
 	if err != nil {
 		return fmt.Errorf("adding polecat: %w", err)
 	}
@@ -550,6 +559,7 @@ type PolecatStatus struct {
 	Branch         string        `json:"branch"`
 	SessionRunning bool          `json:"session_running"`
 	SessionID      string        `json:"session_id,omitempty"`
+	AgentStatus    string        `json:"agent_status,omitempty"`
 	Attached       bool          `json:"attached,omitempty"`
 	Windows        int           `json:"windows,omitempty"`
 	CreatedAt      string        `json:"created_at,omitempty"`
@@ -596,6 +606,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			Branch:         p.Branch,
 			SessionRunning: sessInfo.Running,
 			SessionID:      sessInfo.SessionID,
+			AgentStatus:    string(sessInfo.AgentStatus),
 			Attached:       sessInfo.Attached,
 			Windows:        sessInfo.Windows,
 		}
@@ -645,6 +656,9 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	if sessInfo.Running {
 		fmt.Printf("  Status:        %s\n", style.Success.Render("running"))
 		fmt.Printf("  Session ID:    %s\n", style.Dim.Render(sessInfo.SessionID))
+		if sessInfo.AgentStatus != "" {
+			fmt.Printf("  Agent:         %s\n", sessInfo.AgentStatus)
+		}
 
 		if sessInfo.Attached {
 			fmt.Printf("  Attached:      %s\n", style.Info.Render("yes"))
@@ -1009,15 +1023,19 @@ func runPolecatGC(cmd *cobra.Command, args []string) error {
 		for _, p := range polecats {
 			currentBranches[p.Branch] = true
 		}
+		preempted := polecat.PreemptedBranches(r.Path)
 
 		// Show what would be deleted
 		toDelete := 0
 		for _, branch := range branches {
-			if !currentBranches[branch] {
+			switch {
+			case currentBranches[branch]:
+				fmt.Printf("  Keep (in use): %s\n", style.Success.Render(branch))
+			case preempted[branch]:
+				fmt.Printf("  Keep (preempted work): %s\n", style.Success.Render(branch))
+			default:
 				fmt.Printf("  Would delete: %s\n", style.Dim.Render(branch))
 				toDelete++
-			} else {
-				fmt.Printf("  Keep (in use): %s\n", style.Success.Render(branch))
 			}
 		}
 
@@ -1087,6 +1105,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 	t := tmux.NewTmux()
 	var nukeErrors []string
 	nuked := 0
+	freedRigs := make(map[string]*rig.Rig) // rigs with a freed slot for queued slings
 
 	for _, p := range targets {
 		if polecatNukeDryRun {
@@ -1172,6 +1191,8 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s closed agent bead %s\n", style.Success.Render("✓"), agentBeadID)
 		}
 
+		_ = events.LogAudit(events.TypeNuke, detectSender(), events.NukePayload(p.rigName, p.polecatName, polecatNukeForce))
+		freedRigs[p.rigName] = p.r
 		nuked++
 	}
 
@@ -1198,6 +1219,11 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		cleanupOrphanedProcesses()
 	}
 
+	// Freed slots go to slings queued behind max_polecats
+	for rigName, r := range freedRigs {
+		startQueuedSlings(filepath.Dir(r.Path), rigName)
+	}
+
 	if len(nukeErrors) > 0 {
 		return fmt.Errorf("%d nuke(s) failed", len(nukeErrors))
 	}
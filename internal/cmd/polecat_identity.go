@@ -14,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/style"
@@ -999,33 +1000,27 @@ func formatCountStyled(count int, s lipgloss.Style) string {
 	return s.Render(strconv.Itoa(count))
 }
 
-// countPolecatSessions counts the number of sessions from checkpoint files.
+// countPolecatSessions counts the number of sessions from checkpoint history.
+// Each distinct session ID in the polecat's snapshots counts once.
 func countPolecatSessions(rigPath, polecatName string) int {
-	// Look for checkpoint files in the polecat's directory
-	checkpointDir := filepath.Join(rigPath, "polecats", polecatName, ".checkpoints")
-	entries, err := os.ReadDir(checkpointDir)
-	if err != nil {
-		// Also check at rig level
-		checkpointDir = filepath.Join(rigPath, ".checkpoints")
-		entries, err = os.ReadDir(checkpointDir)
-		if err != nil {
-			return 0
-		}
+	historyDir := checkpoint.HistoryPath(filepath.Join(rigPath, "polecats", polecatName))
+	snapshots, err := checkpoint.History(historyDir)
+	if err != nil || snapshots == nil {
+		return 0
 	}
 
-	// Count checkpoint files that contain this polecat's name
-	count := 0
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.Contains(entry.Name(), polecatName) {
-			count++
+	sessions := make(map[string]bool)
+	for _, s := range snapshots {
+		if id := s.Checkpoint.SessionID; id != "" && id != "witness" {
+			sessions[id] = true
 		}
 	}
 
-	// If no checkpoint files found, return at least 1 if polecat exists
-	if count == 0 {
+	// If no sessions recorded, return at least 1 if polecat exists
+	if len(sessions) == 0 {
 		return 1
 	}
-	return count
+	return len(sessions)
 }
 
 // formatLanguageStats formats language statistics for display.
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	polecatPoolJSON    bool
+	polecatPoolFillAll bool
+)
+
+var polecatPoolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage the warm pool of pre-created polecat worktrees",
+	RunE:  requireSubcommand,
+	Long: `Manage a rig's warm pool of polecat worktrees.
+
+A warm pool keeps N worktrees ready ahead of time: detached at the rig's
+default branch, with Gas Town setup applied and setup hooks (dependency
+installs, secrets, local config) already run. Spawning a polecat claims a
+ready worktree and checks out its branch instead of creating a worktree and
+running hooks, and the pool refills in the background.
+
+Only the worktree is pre-warmed; the agent session still starts at spawn.
+Pool worktrees live in polecats/.warm/ and do not count toward max_polecats.
+
+Configure in <rig>/settings/config.json:
+  "warm_pool": {
+    "size": 2,                  Ready worktrees to keep (0 = disabled)
+    "refresh_interval": "30m"   Move idle worktrees to the latest default
+  }                             branch and rerun hooks this often
+
+The daemon refills and refreshes pools each heartbeat unless
+patrols.warm_pool is disabled in mayor/daemon.json.`,
+}
+
+var polecatPoolStatusCmd = &cobra.Command{
+	Use:   "status [rig]",
+	Short: "Show warm pool contents",
+	Long: `Show each rig's warm pool: configured size, ready worktrees and their
+age, and worktrees being built.
+
+Examples:
+  gt polecat pool status
+  gt polecat pool status greenplace --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolecatPoolStatus,
+}
+
+var polecatPoolFillCmd = &cobra.Command{
+	Use:   "fill [rig]",
+	Short: "Fill and refresh a rig's warm pool",
+	Long: `Create missing warm worktrees, refresh stale ones against the latest
+default branch, and trim the pool down to its configured size. A pool with
+size 0 is drained.
+
+Examples:
+  gt polecat pool fill greenplace
+  gt polecat pool fill --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolecatPoolFill,
+}
+
+var polecatPoolDrainCmd = &cobra.Command{
+	Use:   "drain <rig>",
+	Short: "Remove all ready worktrees from a rig's warm pool",
+	Long: `Remove every ready worktree from a rig's warm pool. Worktrees being built
+are left alone. The daemon refills the pool unless its size is set to 0.
+
+Examples:
+  gt polecat pool drain greenplace`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPoolDrain,
+}
+
+func init() {
+	polecatPoolStatusCmd.Flags().BoolVar(&polecatPoolJSON, "json", false, "Output as JSON")
+	polecatPoolFillCmd.Flags().BoolVar(&polecatPoolFillAll, "all", false, "Fill every rig with a warm pool configured")
+
+	polecatPoolCmd.AddCommand(polecatPoolStatusCmd)
+	polecatPoolCmd.AddCommand(polecatPoolFillCmd)
+	polecatPoolCmd.AddCommand(polecatPoolDrainCmd)
+	polecatCmd.AddCommand(polecatPoolCmd)
+}
+
+// WarmPoolStatus is a rig's entry in gt polecat pool status --json.
+type WarmPoolStatus struct {
+	Rig      string              `json:"rig"`
+	Size     int                 `json:"size"`
+	Ready    []polecat.WarmEntry `json:"ready"`
+	Building int                 `json:"building"`
+}
+
+// poolRigs returns the named rig, or every rig when name is empty.
+func poolRigs(name string) ([]*rig.Rig, error) {
+	if name != "" {
+		_, r, err := getRig(name)
+		if err != nil {
+			return nil, err
+		}
+		return []*rig.Rig{r}, nil
+	}
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	return rigs, nil
+}
+
+func runPolecatPoolStatus(cmd *cobra.Command, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	rigs, err := poolRigs(name)
+	if err != nil {
+		return err
+	}
+
+	var statuses []WarmPoolStatus
+	for _, r := range rigs {
+		pool := polecat.NewManager(r, git.NewGit(r.Path), nil).WarmPool()
+		ready, err := pool.List()
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		s := WarmPoolStatus{Rig: r.Name, Size: pool.Size(), Ready: ready, Building: pool.Building()}
+		if s.Size == 0 && len(s.Ready) == 0 && s.Building == 0 && name == "" {
+			continue
+		}
+		statuses = append(statuses, s)
+	}
+
+	if polecatPoolJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Printf("%s No warm pools configured\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, s := range statuses {
+		fmt.Printf("%s %s: %d/%d ready", style.Bold.Render("♨"), s.Rig, len(s.Ready), s.Size)
+		if s.Building > 0 {
+			fmt.Printf(", %d building", s.Building)
+		}
+		if s.Size == 0 {
+			fmt.Print(style.Dim.Render("  (disabled)"))
+		}
+		fmt.Println()
+		for _, e := range s.Ready {
+			commit := e.Commit
+			if len(commit) > 8 {
+				commit = commit[:8]
+			}
+			fmt.Printf("  %-14s %s  ready %s ago\n", e.ID, commit, formatDuration(time.Since(e.ReadyAt)))
+		}
+	}
+	return nil
+}
+
+func runPolecatPoolFill(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !polecatPoolFillAll {
+		return fmt.Errorf("specify a rig or --all")
+	}
+	if len(args) > 0 && polecatPoolFillAll {
+		return fmt.Errorf("cannot use --all with a rig name")
+	}
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	rigs, err := poolRigs(name)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rigs {
+		pool := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux()).WarmPool()
+		if polecatPoolFillAll && pool.Size() == 0 && pool.Building() == 0 {
+			if ready, _ := pool.List(); len(ready) == 0 {
+				continue
+			}
+		}
+		result, err := pool.Fill()
+		if err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+			continue
+		}
+		for _, e := range result.Errors {
+			style.PrintWarning("%s: %s", r.Name, e)
+		}
+		ready, _ := pool.List()
+		fmt.Printf("%s %s: %d/%d ready (created %d, refreshed %d, removed %d)\n",
+			style.Bold.Render("♨"), r.Name, len(ready), pool.Size(),
+			result.Created, result.Refreshed, result.Removed)
+	}
+	return nil
+}
+
+func runPolecatPoolDrain(cmd *cobra.Command, args []string) error {
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+	removed, err := mgr.WarmPool().Drain()
+	if err != nil {
+		return fmt.Errorf("draining warm pool: %w", err)
+	}
+	fmt.Printf("%s Drained %d warm worktree(s) from %s\n", style.Bold.Render("✓"), removed, r.Name)
+	return nil
+}
+
+// refillWarmPoolAsync starts gt polecat pool fill for a rig in the
+// background after a spawn claimed from its warm pool. Errors are ignored:
+// the daemon refills the pool on its next heartbeat anyway.
+func refillWarmPoolAsync(townRoot, rigName string) {
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	cmd := exec.Command(gtPath, "polecat", "pool", "fill", rigName) //nolint:gosec // G204: rig name from rig discovery
+	cmd.Dir = townRoot
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
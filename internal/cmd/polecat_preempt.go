@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	polecatPreemptFor    string
+	polecatPreemptDryRun bool
+)
+
+var polecatPreemptCmd = &cobra.Command{
+	Use:   "preempt <rig>[/<polecat>]",
+	Short: "Pause a polecat's work to free its slot for urgent work",
+	Long: `Pause the lowest-priority working polecat in a rig and give its slot to
+higher-priority work.
+
+The preempted polecat's state is checkpointed, its uncommitted changes are
+committed to its branch as WIP, its hook is parked (the bead goes back to
+open), and its worktree is removed. Its bead then waits in the rig's sling
+queue at its own priority; when a slot frees up it is slung again and the
+new polecat continues from that branch, on a branch of its own, with the
+saved checkpoint, so gt prime shows where the old session left off.
+
+Which work gets the slot:
+  --for <bead>   Sling that bead into the freed slot right away. Only
+                 polecats on lower-priority work than it are eligible.
+  (default)      The head of the rig's sling queue (see gt sling --queue-status).
+
+Name a polecat (<rig>/<polecat>) to preempt it regardless of priority.
+
+Examples:
+  gt polecat preempt greenplace --for gt-urgent
+  gt polecat preempt greenplace
+  gt polecat preempt greenplace/Toast --for gt-urgent --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPreempt,
+}
+
+func init() {
+	polecatPreemptCmd.Flags().StringVar(&polecatPreemptFor, "for", "", "Bead to sling into the freed slot")
+	polecatPreemptCmd.Flags().BoolVar(&polecatPreemptDryRun, "dry-run", false, "Show which polecat would be preempted")
+
+	polecatCmd.AddCommand(polecatPreemptCmd)
+}
+
+func runPolecatPreempt(cmd *cobra.Command, args []string) error {
+	rigName, polecatName := args[0], ""
+	if strings.Contains(args[0], "/") {
+		var err error
+		if rigName, polecatName, err = parseAddress(args[0]); err != nil {
+			return err
+		}
+	}
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	townRoot := filepath.Dir(r.Path)
+	queue := polecat.NewAdmissionQueue(r.Path)
+
+	// Work the slot is freed for: --for, else the head of the sling queue
+	var waiting *polecat.QueuedSling
+	forPriority := -1
+	if polecatPreemptFor != "" {
+		info, err := getBeadInfo(polecatPreemptFor)
+		if err != nil {
+			return err
+		}
+		forPriority = info.Priority
+		waiting = &polecat.QueuedSling{Bead: polecatPreemptFor, Priority: info.Priority}
+		if queued, err := queue.List(); err == nil {
+			for i := range queued {
+				if queued[i].Bead == polecatPreemptFor {
+					waiting = &queued[i]
+				}
+			}
+		}
+	} else {
+		queued, err := queue.List()
+		if err != nil {
+			return fmt.Errorf("reading sling queue: %w", err)
+		}
+		if len(queued) > 0 {
+			waiting = &queued[0]
+			forPriority = waiting.Priority
+		} else if polecatName == "" {
+			return fmt.Errorf("no work is queued for %s; use --for <bead> or name a polecat", rigName)
+		}
+	}
+
+	candidates, err := preemptCandidates(mgr)
+	if err != nil {
+		return err
+	}
+	var victim *polecat.PreemptCandidate
+	if polecatName != "" {
+		for i := range candidates {
+			if candidates[i].Name == polecatName {
+				victim = &candidates[i]
+			}
+		}
+		if victim == nil {
+			return fmt.Errorf("polecat %s/%s is not working on a hooked bead", rigName, polecatName)
+		}
+	} else {
+		if waiting != nil {
+			// The urgent bead may already be running somewhere
+			kept := candidates[:0]
+			for _, c := range candidates {
+				if c.Bead != waiting.Bead {
+					kept = append(kept, c)
+				}
+			}
+			candidates = kept
+		}
+		if victim = polecat.ChoosePreemptee(candidates, forPriority); victim == nil {
+			return fmt.Errorf("no polecat in %s is working on lower-priority work than P%d", rigName, forPriority)
+		}
+	}
+
+	forBead := ""
+	if waiting != nil {
+		forBead = waiting.Bead
+	}
+	if polecatPreemptDryRun {
+		fmt.Printf("Would preempt %s/%s (%s, P%d)", rigName, victim.Name, victim.Bead, victim.Priority)
+		if waiting != nil {
+			fmt.Printf(" for %s (P%d)", waiting.Bead, waiting.Priority)
+		}
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Printf("%s Preempting %s/%s (%s, P%d)...\n", style.Bold.Render("⏸"), rigName, victim.Name, victim.Bead, victim.Priority)
+	if err := preemptPolecat(mgr, r, victim, forBead); err != nil {
+		return err
+	}
+
+	// The paused bead resumes from the queue when a slot frees up
+	pos, err := queue.Enqueue(polecat.QueuedSling{
+		Bead:     victim.Bead,
+		Priority: victim.Priority,
+		QueuedBy: detectSender(),
+	})
+	if err != nil {
+		style.PrintWarning("could not requeue %s: %v (sling it again to resume)", victim.Bead, err)
+	} else {
+		fmt.Printf("  %s requeued %s (position %d)\n", style.Success.Render("✓"), victim.Bead, pos)
+	}
+
+	if polecatPreemptFor != "" {
+		_, _ = queue.Remove(waiting.Bead)
+		fmt.Printf("%s Slinging %s into the freed slot\n", style.Bold.Render("▶"), waiting.Bead)
+		if out, err := queuedSlingCommand(townRoot, rigName, waiting).CombinedOutput(); err != nil {
+			return fmt.Errorf("slinging %s: %w\n%s", waiting.Bead, err, strings.TrimSpace(string(out)))
+		}
+	}
+	startQueuedSlings(townRoot, rigName)
+	return nil
+}
+
+// preemptCandidates returns the rig's polecats that are working on a hooked
+// bead, with that bead's priority.
+func preemptCandidates(mgr *polecat.Manager) ([]polecat.PreemptCandidate, error) {
+	polecats, err := mgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing polecats: %w", err)
+	}
+	var candidates []polecat.PreemptCandidate
+	for _, p := range polecats {
+		if !p.State.IsActive() || p.Issue == "" {
+			continue
+		}
+		info, err := getBeadInfo(p.Issue)
+		if err != nil {
+			continue
+		}
+		c := polecat.PreemptCandidate{Name: p.Name, Bead: p.Issue, Priority: info.Priority}
+		// The worktree's .git file is written once, when the polecat is created
+		if fi, err := os.Stat(filepath.Join(p.ClonePath, ".git")); err == nil {
+			c.Started = fi.ModTime()
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// preemptPolecat stops a polecat, saves its work to its branch, parks its
+// hook, and frees its slot.
+func preemptPolecat(mgr *polecat.Manager, r *rig.Rig, victim *polecat.PreemptCandidate, forBead string) error {
+	sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+	if running, _ := sessMgr.IsRunning(victim.Name); running {
+		if err := sessMgr.Stop(victim.Name, true); err != nil {
+			return fmt.Errorf("stopping session: %w", err)
+		}
+		fmt.Printf("  %s stopped session\n", style.Success.Render("✓"))
+	}
+
+	rec := &polecat.Preemption{Bead: victim.Bead, Priority: victim.Priority, For: forBead}
+	if err := mgr.Preempt(victim.Name, rec); err != nil {
+		return fmt.Errorf("preempting %s: %w", victim.Name, err)
+	}
+	commit := rec.Commit
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	fmt.Printf("  %s saved work on %s (%s) and removed worktree\n", style.Success.Render("✓"), rec.Branch, commit)
+
+	// Park the hook: the bead goes back to open and unassigned
+	b := beads.New(r.Path)
+	agentBeadID := polecatBeadIDForRig(r, r.Name, victim.Name)
+	if err := b.ClearHookBead(agentBeadID); err != nil {
+		style.PrintWarning("could not clear hook on %s: %v", agentBeadID, err)
+	}
+	openStatus := "open"
+	emptyAssignee := ""
+	if err := b.Update(victim.Bead, beads.UpdateOptions{
+		Status:   &openStatus,
+		Assignee: &emptyAssignee,
+	}); err != nil {
+		style.PrintWarning("could not reopen %s: %v", victim.Bead, err)
+	} else {
+		fmt.Printf("  %s parked %s\n", style.Success.Render("✓"), victim.Bead)
+	}
+
+	closeArgs := []string{"close", agentBeadID, "--reason=preempted"}
+	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
+		closeArgs = append(closeArgs, "--session="+sessionID)
+	}
+	closeCmd := exec.Command("bd", closeArgs...)
+	closeCmd.Dir = filepath.Join(r.Path, "mayor", "rig")
+	_ = closeCmd.Run()
+
+	_ = events.LogAudit(events.TypePreempt, detectSender(), events.PreemptPayload(r.Name, victim.Name, victim.Bead, forBead))
+	return nil
+}
@@ -9,12 +9,15 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hostres"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -29,8 +32,9 @@ type SpawnedPolecatInfo struct {
 	Pane        string // Tmux pane ID (empty until StartSession is called)
 
 	// Internal fields for deferred session start
-	account string
-	agent   string
+	account  string
+	agent    string
+	hookBead string
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -84,6 +88,22 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// Reap stale polecats before allocating a new one (ephemeral model: done means gone).
 	cleanupStalePolecatsForSling(polecatMgr, r)
 
+	// Admission control: refuse to spawn past max_polecats or while the host
+	// is saturated. The caller queues the sling (see spawnDeferred). The
+	// admission lock is held until the new polecat's directory exists, so a
+	// concurrent sling counts it.
+	unlockAdmission, err := polecatMgr.LockAdmission()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockAdmission()
+	if err := polecatMgr.CheckCapacity(); err != nil {
+		return nil, err
+	}
+	if err := hostres.Check(r.Path, config.LoadResourceConfig(townRoot)); err != nil {
+		return nil, err
+	}
+
 	// Allocate a new polecat name
 	polecatName, err := polecatMgr.AllocateName()
 	if err != nil {
@@ -99,6 +119,16 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		HookBead: opts.HookBead,
 	}
 
+	// Work paused by gt polecat preempt resumes from its saved branch
+	var preempted *polecat.Preemption
+	if opts.HookBead != "" {
+		var loadErr error
+		if preempted, loadErr = polecat.LoadPreemption(r.Path, opts.HookBead); loadErr != nil {
+			style.PrintWarning("ignoring preemption record for %s: %v", opts.HookBead, loadErr)
+		}
+	}
+	resumed := false
+
 	if err == nil {
 		// Stale state: polecat exists despite fresh name allocation - repair it
 		// Check for uncommitted work first
@@ -130,12 +160,32 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	} else if err == polecat.ErrPolecatNotFound {
 		// Create new polecat
 		fmt.Printf("Creating polecat %s...\n", polecatName)
+		if preempted != nil {
+			addOpts.ResumeBranch = preempted.Branch
+			fmt.Printf("Resuming preempted work from %s\n", preempted.Branch)
+		}
 		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
 			return nil, fmt.Errorf("creating polecat: %w", err)
 		}
+		resumed = preempted != nil
 	} else {
 		return nil, fmt.Errorf("getting polecat: %w", err)
 	}
+	unlockAdmission()
+
+	// The bead has its polecat; if it was waiting in the admission queue
+	// (a replayed queued sling), it leaves the queue now
+	if opts.HookBead != "" {
+		if _, err := polecat.NewAdmissionQueue(r.Path).Remove(opts.HookBead); err != nil {
+			style.PrintWarning("could not remove %s from the sling queue: %v", opts.HookBead, err)
+		}
+	}
+
+	// Top the warm pool back up in the background so the next sling can
+	// claim a ready worktree too
+	if polecatMgr.WarmPool().Size() > 0 {
+		refillWarmPoolAsync(townRoot, rigName)
+	}
 
 	// Get polecat object for path info
 	polecatObj, err := polecatMgr.Get(polecatName)
@@ -152,14 +202,39 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			polecatName, err, rigName, polecatName)
 	}
 
+	// Hand the preempted session's checkpoint to the new one (gt prime shows it)
+	if resumed {
+		if preempted.Checkpoint != nil {
+			preempted.Checkpoint.Branch = polecatObj.Branch
+			if err := checkpoint.Write(polecatObj.ClonePath, preempted.Checkpoint); err != nil {
+				style.PrintWarning("could not restore checkpoint: %v", err)
+			}
+		}
+		if err := polecat.ClearPreemption(r.Path, opts.HookBead); err != nil {
+			style.PrintWarning("%v", err)
+		}
+	}
+
 	// Get session manager for session name (session start is deferred)
 	polecatSessMgr := polecat.NewSessionManager(t, r)
 	sessionName := polecatSessMgr.SessionName(polecatName)
 
 	fmt.Printf("%s Polecat %s spawned (session start deferred)\n", style.Bold.Render("✓"), polecatName)
 
-	// Log spawn event to activity feed
-	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
+	// Without an explicit --agent, route the bead to the cheapest preset
+	// that has the capabilities its labels require
+	agent := opts.Agent
+	if agent == "" && opts.HookBead != "" {
+		agent, _ = routeAgentForBead(townRoot, r.Path, opts.HookBead)
+	}
+
+	// Log spawn event to activity feed, with the agent the polecat will run
+	// (used by gt agents stats)
+	spawnPayload := events.SpawnPayload(rigName, polecatName)
+	if _, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, r.Path, agent); err == nil && agentName != "" {
+		spawnPayload["agent"] = agentName
+	}
+	_ = events.LogFeed(events.TypeSpawn, "gt", spawnPayload)
 
 	return &SpawnedPolecatInfo{
 		RigName:     rigName,
@@ -168,10 +243,32 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		SessionName: sessionName,
 		Pane:        "", // Empty until StartSession is called
 		account:     opts.Account,
-		agent:       opts.Agent,
+		agent:       agent,
+		hookBead:    opts.HookBead,
 	}, nil
 }
 
+// routeAgentForBead picks the agent preset for a bead from its needs:* and
+// lang:* labels (see config.RequiredCapabilities). Returns "" when the bead
+// requires nothing or no preset matches, leaving the rig's default agent.
+func routeAgentForBead(townRoot, rigPath, beadID string) (agent string, required []string) {
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return "", nil
+	}
+	required = config.RequiredCapabilities(info.Labels)
+	if len(required) == 0 {
+		return "", nil
+	}
+	agent = config.SelectAgentForCapabilities(townRoot, rigPath, required)
+	if agent == "" {
+		style.PrintWarning("no agent preset has capabilities %s; using the rig default", strings.Join(required, ", "))
+		return "", required
+	}
+	fmt.Printf("Routing %s to agent %s (requires %s)\n", beadID, agent, strings.Join(required, ", "))
+	return agent, required
+}
+
 // StartSession starts the tmux session for a spawned polecat.
 // This is called after the molecule/bead is attached, so the polecat
 // sees its work when gt prime runs on session start.
@@ -214,21 +311,16 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
-	}
-	if s.agent != "" {
-		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent, s.ClonePath)
-		if err != nil {
-			return "", err
-		}
-		startOpts.Command = cmd
+		HookBead:         s.hookBead,
+		Agent:            s.agent,
 	}
 	if err := polecatSessMgr.Start(s.PolecatName, startOpts); err != nil {
 		return "", fmt.Errorf("starting session: %w", err)
 	}
 
 	// Wait for runtime to be fully ready before returning.
-	runtimeConfig := config.LoadRuntimeConfig(r.Path)
-	if err := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
+	driver := runtime.DriverWithOverride(config.LoadRuntimeConfig(r.Path), s.agent)
+	if err := driver.WaitReady(t, s.SessionName, 30*time.Second); err != nil {
 		fmt.Printf("Warning: runtime may not be fully ready: %v\n", err)
 	}
 
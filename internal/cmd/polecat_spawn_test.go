@@ -62,3 +62,40 @@ func TestCleanupStalePolecatsForSlingSkipsDirty(t *testing.T) {
 		t.Fatalf("expected polecat to remain, stat err: %v", err)
 	}
 }
+
+func TestSpawnPolecatForSlingWithoutPreemptionRecord(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	townRoot := setupTestTownForDotDir(t)
+	addRigEntry(t, townRoot, "demo")
+
+	mayorRig := filepath.Join(townRoot, "demo", "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"checkout", "-b", "main"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "init"},
+		{"remote", "add", "origin", mayorRig},
+		{"update-ref", "refs/remotes/origin/main", "HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	t.Chdir(townRoot)
+
+	// A bead that was never preempted takes the new-polecat path
+	info, err := SpawnPolecatForSling("demo", SlingSpawnOptions{HookBead: "gt-nopre"})
+	if err != nil {
+		t.Fatalf("SpawnPolecatForSling: %v", err)
+	}
+	if _, err := os.Stat(info.ClonePath); err != nil {
+		t.Fatalf("expected polecat worktree at %s: %v", info.ClonePath, err)
+	}
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	policyListRole string
+	policyListRig  string
+)
+
+var policyCmd = &cobra.Command{
+	Use:     "policy",
+	GroupID: GroupConfig,
+	Short:   "Control which commands and tools agents may use",
+	Long: `Control which commands and tools agents may use.
+
+Rules live in settings/policy.json of the town and of each rig:
+
+  {
+    "type": "policy",
+    "version": 1,
+    "rules": [
+      {"decision": "ask", "pattern": "^(curl|wget|nc)\\b", "reason": "network access"},
+      {"decision": "deny", "pattern": "^npm publish\\b", "roles": ["polecat"]},
+      {"decision": "deny", "tool": "Write", "pattern": "/\\.env$"}
+    ]
+  }
+
+A rule's pattern is a regular expression searched for in each command of a
+shell command line, or for other tools ("tool", "*" for any) in the file or
+URL they act on. The rig's rules are consulted first, then the town's, then
+the built-in ones (no force-push to main or master, no recursive delete of /
+or ~); the first match decides. A command line gets the strictest decision of
+its commands, and is allowed only if every command in it is. Calls no rule
+matches go through the runtime's usual permission handling.
+
+Agents check each tool call with gt policy check, installed as their
+pre-tool-use hook. Every decision is recorded in the audit log.`,
+	RunE: requireSubcommand,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Decide a tool call (pre-tool-use hook)",
+	Long: `Decide a tool call against the policy of the calling agent's role.
+
+Reads the pre-tool-use hook input ({"tool_name": ..., "tool_input": ...})
+from standard input and, when a rule matches, writes the decision in Claude
+Code's hook output format and records it in the audit log. Writes nothing
+when no rule matches or outside a Gas Town workspace.`,
+	Args: cobra.NoArgs,
+	RunE: runPolicyCheck,
+}
+
+var policyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the rules that apply to a role",
+	Long: `Show the rules that apply to a role, in the order they are consulted.
+
+Defaults to the current agent's role and rig.
+
+Examples:
+  gt policy list
+  gt policy list --role polecat --rig gastown`,
+	Args: cobra.NoArgs,
+	RunE: runPolicyList,
+}
+
+func init() {
+	policyListCmd.Flags().StringVar(&policyListRole, "role", "", "Role to show rules for (default: current role)")
+	policyListCmd.Flags().StringVar(&policyListRig, "rig", "", "Rig to include rules of (default: current rig)")
+
+	policyCmd.AddCommand(policyCheckCmd)
+	policyCmd.AddCommand(policyListCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+// policyHookInput is the part of a pre-tool-use hook's input gt policy
+// check reads.
+type policyHookInput struct {
+	ToolName  string         `json:"tool_name"`
+	ToolInput map[string]any `json:"tool_input"`
+	Cwd       string         `json:"cwd"`
+}
+
+// policyHookOutput is Claude Code's pre-tool-use hook output.
+type policyHookOutput struct {
+	HookSpecificOutput struct {
+		HookEventName            string `json:"hookEventName"`
+		PermissionDecision       string `json:"permissionDecision"`
+		PermissionDecisionReason string `json:"permissionDecisionReason,omitempty"`
+	} `json:"hookSpecificOutput"`
+}
+
+// maxAuditSubject bounds how much of a command goes into the audit log.
+const maxAuditSubject = 500
+
+func runPolicyCheck(cmd *cobra.Command, args []string) error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("reading hook input: %w", err)
+	}
+	var input policyHookInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("parsing hook input: %w", err)
+	}
+
+	cwd := input.Cwd
+	if cwd == "" {
+		if cwd, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+	townRoot := os.Getenv("GT_ROOT")
+	if townRoot == "" {
+		if townRoot, _ = workspace.Find(cwd); townRoot == "" {
+			return nil
+		}
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return err
+	}
+	var rigPath string
+	if roleInfo.Rig != "" {
+		rigPath = filepath.Join(townRoot, roleInfo.Rig)
+	}
+
+	// A broken rule is reported but doesn't switch off the others
+	p, err := policy.Load(townRoot, rigPath, string(roleInfo.Role))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt policy: %v\n", err)
+	}
+	result := p.Check(input.ToolName, input.ToolInput)
+	if !result.Matched() {
+		return nil
+	}
+
+	subject := result.Subject
+	if len(subject) > maxAuditSubject {
+		subject = subject[:maxAuditSubject] + "…"
+	}
+	_ = events.LogAudit(events.TypePolicyDecision, roleInfo.ActorString(),
+		events.PolicyPayload(input.ToolName, subject, string(result.Decision), result.Rule.Source, result.Rule.Reason))
+
+	var out policyHookOutput
+	out.HookSpecificOutput.HookEventName = "PreToolUse"
+	out.HookSpecificOutput.PermissionDecision = string(result.Decision)
+	if result.Decision != policy.Allow {
+		reason := result.Rule.Reason
+		if reason == "" {
+			reason = "matches " + result.Rule.Pattern
+		}
+		out.HookSpecificOutput.PermissionDecisionReason = fmt.Sprintf("Gas Town %s policy: %s (%s)", result.Rule.Source, reason, result.Subject)
+	}
+	return json.NewEncoder(os.Stdout).Encode(out)
+}
+
+func runPolicyList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	role, rig := policyListRole, policyListRig
+	if role == "" || rig == "" {
+		if info, err := GetRole(); err == nil {
+			if role == "" && info.Role != RoleUnknown {
+				role = string(info.Role)
+			}
+			if rig == "" {
+				rig = info.Rig
+			}
+		}
+	}
+	var rigPath string
+	if rig != "" {
+		rigPath = filepath.Join(townRoot, rig)
+	}
+
+	p, loadErr := policy.Load(townRoot, rigPath, role)
+
+	scope := "town"
+	if rig != "" {
+		scope = "rig " + rig
+	}
+	roleLabel := role
+	if roleLabel == "" {
+		roleLabel = "any role"
+	}
+	fmt.Printf("%s %s, %s\n\n", style.Bold.Render("Policy for"), roleLabel, scope)
+	for i, r := range p.Rules {
+		decision := string(r.Decision)
+		switch r.Decision {
+		case policy.Deny:
+			decision = style.Error.Render(decision)
+		case policy.Ask:
+			decision = style.Warning.Render(decision)
+		default:
+			decision = style.Success.Render(decision)
+		}
+		tool := r.Tool
+		if tool == "" {
+			tool = "Bash"
+		}
+		fmt.Printf("%3d. %s %s %s\n", i+1, decision, tool, r.Pattern)
+		var notes []string
+		notes = append(notes, r.Source)
+		if len(r.Roles) > 0 {
+			notes = append(notes, "roles: "+strings.Join(r.Roles, ", "))
+		}
+		if r.Reason != "" {
+			notes = append(notes, r.Reason)
+		}
+		fmt.Printf("     %s\n", style.Dim.Render(strings.Join(notes, "; ")))
+	}
+	if loadErr != nil {
+		fmt.Printf("\n%s %v\n", style.Warning.Render("⚠"), loadErr)
+	}
+	return nil
+}
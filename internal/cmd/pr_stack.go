@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -406,6 +407,9 @@ func updateMRDependsOn(r *rig.Rig, branch, dependsOn string) error {
 }
 
 func forcePushBranch(repoDir, remote, branch string) error {
+	if err := git.NewGit(repoDir).CheckPush(branch, git.PushForce); err != nil {
+		return err
+	}
 	cmd := exec.Command("git", "push", "--force-with-lease", remote, branch)
 	cmd.Dir = repoDir
 	cmd.Stdout = os.Stdout
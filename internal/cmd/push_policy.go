@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	pushPolicyShowRole string
+	pushPolicyShowRig  string
+)
+
+var pushPolicyCmd = &cobra.Command{
+	Use:     "push-policy",
+	GroupID: GroupConfig,
+	Short:   "Control which branches agents may push",
+	Long: `Control which branches agents may push, force-push and delete.
+
+By default polecats may push only their own branches (polecat/<name>,
+polecat/<name>/*, polecat/<name>-*, or those the rig's
+polecat_branch_template names) and beads-sync, and the refinery only
+the rig's target branch and integration/* branches, deleting polecat and
+integration branches once merged. Other roles may push anywhere. A rig
+overrides the rules of any role in settings/config.json:
+
+  "push_policy": {
+    "roles": {
+      "polecat": {
+        "push": ["polecat/{name}/*"],
+        "force_push": ["polecat/{name}/*"]
+      },
+      "witness": {"push": []}
+    }
+  }
+
+"*" matches anything, "{name}" is the agent's name, "{default}" the
+rig's target branch and "{branch}" the rig's polecat_branch_template with
+every variable but {name} matching anything. Pushes through gt are checked before they run; the
+pre-push hook installed in each clone checks agents' own git pushes. A
+denied push fails and is escalated to the mayor.`,
+	RunE: requireSubcommand,
+}
+
+var pushPolicyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the branches each role may push",
+	Long: `Show the branches each role may push, force-push and delete.
+
+Defaults to every restricted role in the current rig.
+
+Examples:
+  gt push-policy show
+  gt push-policy show --rig gastown --role polecat`,
+	Args: cobra.NoArgs,
+	RunE: runPushPolicyShow,
+}
+
+var pushPolicyInstallCmd = &cobra.Command{
+	Use:   "install [rig...]",
+	Short: "Install the pre-push hook in every clone of a rig",
+	Long: `Install the push policy pre-push hook in the clones of the given rigs
+(default: all rigs): mayor/rig, refinery/rig, crew and polecat clones.
+
+New polecat and crew clones get the hook when they are created. A pre-push
+hook already in place keeps running after the policy check. Clones whose
+hooks come from core.hooksPath are skipped.`,
+	RunE: runPushPolicyInstall,
+}
+
+var pushPolicyPrePushCmd = &cobra.Command{
+	Use:    "pre-push <remote> [url]",
+	Short:  "Check a push against the push policy (pre-push hook)",
+	Hidden: true, // Run by the installed pre-push hook
+	Args:   cobra.RangeArgs(1, 2),
+	RunE:   runPushPolicyPrePush,
+}
+
+func init() {
+	pushPolicyShowCmd.Flags().StringVar(&pushPolicyShowRole, "role", "", "Role to show (default: every restricted role)")
+	pushPolicyShowCmd.Flags().StringVar(&pushPolicyShowRig, "rig", "", "Rig to show rules of (default: current rig)")
+
+	pushPolicyCmd.AddCommand(pushPolicyShowCmd)
+	pushPolicyCmd.AddCommand(pushPolicyInstallCmd)
+	pushPolicyCmd.AddCommand(pushPolicyPrePushCmd)
+	rootCmd.AddCommand(pushPolicyCmd)
+}
+
+func runPushPolicyShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := pushPolicyShowRig
+	if rigName == "" {
+		if info, err := GetRole(); err == nil {
+			rigName = info.Rig
+		}
+	}
+	var rigPath string
+	if rigName != "" {
+		rigPath = filepath.Join(townRoot, rigName)
+	}
+
+	roles := []string{pushPolicyShowRole}
+	if pushPolicyShowRole == "" {
+		roles = pushPolicyRoles(rigPath)
+	}
+
+	scope := "defaults"
+	if rigName != "" {
+		scope = "rig " + rigName
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Push policy for"), scope)
+	for _, role := range roles {
+		fmt.Printf("\n%s\n", style.Bold.Render(role))
+		rules := config.LoadPushRules(rigPath, role)
+		if rules == nil {
+			fmt.Printf("  %s\n", style.Dim.Render("may push anywhere"))
+			continue
+		}
+		rules = rules.WithBranchTemplate(polecatBranchTemplate(townRoot, rigName))
+		for _, line := range []struct {
+			label    string
+			patterns []string
+		}{
+			{"push", rules.Push},
+			{"force-push", rules.ForcePush},
+			{"delete", rules.Delete},
+		} {
+			patterns := style.Dim.Render("none")
+			if len(line.patterns) > 0 {
+				patterns = strings.Join(line.patterns, ", ")
+			}
+			fmt.Printf("  %-11s %s\n", line.label, patterns)
+		}
+	}
+	return nil
+}
+
+// pushPolicyRoles returns the roles with push rules, built in or declared by
+// the rig, sorted.
+func pushPolicyRoles(rigPath string) []string {
+	seen := make(map[string]bool)
+	for role := range config.DefaultPushRules() {
+		seen[role] = true
+	}
+	if rigPath != "" {
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings.PushPolicy != nil {
+			for role := range settings.PushPolicy.Roles {
+				seen[role] = true
+			}
+		}
+	}
+	roles := make([]string, 0, len(seen))
+	for role := range seen {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func runPushPolicyInstall(cmd *cobra.Command, args []string) error {
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		byName := make(map[string]*rig.Rig)
+		for _, r := range rigs {
+			byName[r.Name] = r
+		}
+		rigs = nil
+		for _, name := range args {
+			r, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("rig '%s' not found", name)
+			}
+			rigs = append(rigs, r)
+		}
+	}
+
+	// Worktrees of one repository share its hooks directory
+	installed := make(map[string]bool)
+	for _, r := range rigs {
+		for _, clone := range rigClones(r) {
+			hookPath, err := git.NewGit(clone).InstallPrePushHook()
+			rel, _ := filepath.Rel(r.Path, clone)
+			switch {
+			case errors.Is(err, git.ErrHooksPathSet):
+				fmt.Printf("  %s %s/%s: %v\n", style.Dim.Render("○"), r.Name, rel, err)
+			case err != nil:
+				fmt.Printf("  %s %s/%s: %v\n", style.Warning.Render("⚠"), r.Name, rel, err)
+			case !installed[hookPath]:
+				installed[hookPath] = true
+				fmt.Printf("  %s %s/%s\n", style.Success.Render("✓"), r.Name, rel)
+			}
+		}
+	}
+	fmt.Printf("\nInstalled %d pre-push hook(s)\n", len(installed))
+	return nil
+}
+
+// rigClones returns the rig's git clones and worktrees: mayor/rig,
+// refinery/rig, crew members' and polecats'.
+func rigClones(r *rig.Rig) []string {
+	candidates := []string{
+		filepath.Join(r.Path, "mayor", "rig"),
+		filepath.Join(r.Path, "refinery", "rig"),
+	}
+	if entries, err := os.ReadDir(filepath.Join(r.Path, "crew")); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				candidates = append(candidates, filepath.Join(r.Path, "crew", entry.Name()))
+			}
+		}
+	}
+	if entries, err := os.ReadDir(filepath.Join(r.Path, "polecats")); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				// New layout polecats/<name>/<rig>, old layout polecats/<name>
+				candidates = append(candidates,
+					filepath.Join(r.Path, "polecats", entry.Name(), r.Name),
+					filepath.Join(r.Path, "polecats", entry.Name()))
+			}
+		}
+	}
+
+	var clones []string
+	for _, path := range candidates {
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			clones = append(clones, path)
+		}
+	}
+	return clones
+}
+
+func runPushPolicyPrePush(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	g := git.NewGit(cwd)
+	if id := git.PushIdentityFromEnv(); id.Role == "polecat" {
+		g.SetBranchTemplate(polecatBranchTemplate(id.Town, id.Rig))
+	}
+	if err := g.CheckPrePush(os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", style.Error.Render("✗"), err)
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// polecatBranchTemplate returns a rig's polecat_branch_template from its
+// layered config (wisp, rig bead label), or "" when the rig uses the
+// default branch layout. Push policy "{branch}" patterns name it.
+func polecatBranchTemplate(townRoot, rigName string) string {
+	if townRoot == "" || rigName == "" {
+		return ""
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return ""
+	}
+	r, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+	if err != nil {
+		return ""
+	}
+	return r.GetStringConfig("polecat_branch_template")
+}
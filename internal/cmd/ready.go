@@ -121,9 +121,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			townBeadsPath := beads.GetTownBeadsPath(townRoot)
-			townBeads := beads.New(townBeadsPath)
-			issues, err := townBeads.Ready()
+			issues, err := readyWorkAt(beads.GetTownBeadsPath(townRoot))
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -131,14 +129,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				src.Error = err.Error()
 			} else {
-				// Filter out formula scaffolds (gt-579)
-				formulaNames := getFormulaNames(townBeadsPath)
-				filtered := filterFormulaScaffolds(issues, formulaNames)
-				// Defense-in-depth: also filter wisps that shouldn't appear in ready work
-				wispIDs := getWispIDs(townBeadsPath)
-				filtered = filterWisps(filtered, wispIDs)
-				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				src.Issues = issues
 			}
 			sources = append(sources, src)
 		}()
@@ -151,8 +142,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 			defer wg.Done()
 			// Use rig root path where rig-level beads are stored
 			// BeadsPath returns rig root; redirect system handles mayor/rig routing
-			rigBeads := beads.New(r.BeadsPath())
-			issues, err := rigBeads.Ready()
+			issues, err := readyWorkAt(r.BeadsPath())
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -160,14 +150,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				src.Error = err.Error()
 			} else {
-				// Filter out formula scaffolds (gt-579)
-				formulaNames := getFormulaNames(r.BeadsPath())
-				filtered := filterFormulaScaffolds(issues, formulaNames)
-				// Defense-in-depth: also filter wisps that shouldn't appear in ready work
-				wispIDs := getWispIDs(r.BeadsPath())
-				filtered = filterWisps(filtered, wispIDs)
-				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				src.Issues = issues
 			}
 			sources = append(sources, src)
 		}(r)
@@ -233,6 +216,21 @@ func runReady(cmd *cobra.Command, args []string) error {
 	return printReadyHuman(result)
 }
 
+// readyWorkAt returns the actionable ready issues in a beads location,
+// without formula scaffolds, wisps, or identity beads.
+func readyWorkAt(beadsPath string) ([]*beads.Issue, error) {
+	issues, err := beads.New(beadsPath).Ready()
+	if err != nil {
+		return nil, err
+	}
+	// Filter out formula scaffolds (gt-579)
+	filtered := filterFormulaScaffolds(issues, getFormulaNames(beadsPath))
+	// Defense-in-depth: also filter wisps that shouldn't appear in ready work
+	filtered = filterWisps(filtered, getWispIDs(beadsPath))
+	// Filter identity beads (agents, roles, rigs) - not actionable work
+	return filterIdentityBeads(filtered), nil
+}
+
 func printReadyHuman(result ReadyResult) error {
 	if result.Summary.Total == 0 {
 		fmt.Println("No ready work across town.")
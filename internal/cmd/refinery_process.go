@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var refineryProcessDryRun bool
+
+var refineryProcessCmd = &cobra.Command{
+	Use:   "process [rig]",
+	Short: "Merge a batch of independent MRs in parallel",
+	Long: `Merge ready MRs concurrently, up to merge_queue.max_concurrent at a time.
+
+Picks a batch of independent MRs from the ready queue - MRs that target
+different branches, or touch no files in common - and merges each one in its
+own worktree under refinery/workers/. Tests run in parallel against each
+merged result; pushes to the target branch are serialized, and a worker that
+loses the race rebases onto the new target before pushing.
+
+Each MR is claimed (gt refinery claim) while it is processed. Failed MRs are
+released back to the queue; MRs not picked for the batch stay queued.
+
+max_concurrent comes from the rig's merge queue config (rig settings,
+overridden by the rig's config.json). With max_concurrent = 1 (the default)
+the rig merges serially: this merges nothing and says so, leaving MRs to the
+refinery patrol's one-at-a-time flow. The patrol (mol-refinery-patrol) runs
+it every cycle.
+
+Examples:
+  gt refinery process
+  gt refinery process gastown --dry-run   # Show the batch without merging`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryProcess,
+}
+
+func init() {
+	refineryProcessCmd.Flags().BoolVarP(&refineryProcessDryRun, "dry-run", "n", false, "Show the batch that would be merged")
+	refineryCmd.AddCommand(refineryProcessCmd)
+}
+
+func runRefineryProcess(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	if eng.Config().MaxConcurrent <= 1 {
+		fmt.Printf("%s Serial merge queue in '%s' (max_concurrent %d): nothing to merge in parallel\n",
+			style.Dim.Render("○"), rigName, eng.Config().MaxConcurrent)
+		return nil
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	if len(ready) == 0 {
+		fmt.Printf("%s No MRs ready in '%s'\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+
+	pool := refinery.NewMergePool(eng)
+	if refineryProcessDryRun {
+		files := make(map[string][]string, len(ready))
+		for _, mr := range ready {
+			if changed, err := pool.ChangedFiles(mr); err == nil {
+				files[mr.ID] = append([]string{}, changed...)
+			}
+		}
+		batch := refinery.SelectIndependent(ready, files, pool.Size())
+		fmt.Printf("Would merge %d of %d ready MR(s) in '%s' (max_concurrent %d):\n",
+			len(batch), len(ready), rigName, pool.Size())
+		for i, mr := range batch {
+			fmt.Printf("  %s  %s → %s  (%d files)\n", pool.WorkerID(i), mr.Branch, mr.Target, len(files[mr.ID]))
+		}
+		return nil
+	}
+
+	results := pool.ProcessReady(context.Background(), ready)
+	merged := 0
+	for _, res := range results {
+		if res.Result.Success {
+			merged++
+		}
+	}
+	fmt.Printf("\n%s Merged %d/%d MR(s) in batch (%d ready)\n",
+		style.Bold.Render("📊"), merged, len(results), len(ready))
+	for _, res := range results {
+		if !res.Result.Success {
+			fmt.Printf("  %s %s: %s\n", style.Dim.Render("✗"), res.MR.ID, res.Result.Error)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -61,6 +62,7 @@ func runRelease(cmd *cobra.Command, args []string) error {
 			failed++
 		} else {
 			fmt.Printf("%s Released %s → open\n", style.Bold.Render("✓"), id)
+			_ = events.LogAudit(events.TypeForceRelease, detectSender(), events.ForceReleasePayload(id, releaseReason))
 			released++
 		}
 	}
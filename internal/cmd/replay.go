@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hostres"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	replaySpeed   float64
+	replayMaxIdle time.Duration
+	replayList    bool
+	replayJSON    bool
+
+	recordPaneDir          string
+	recordPaneSession      string
+	recordPaneAgent        string
+	recordPaneBead         string
+	recordPaneWidth        int
+	recordPaneHeight       int
+	recordPaneMaxFileBytes int64
+	recordPaneMaxFiles     int
+)
+
+var replayCmd = &cobra.Command{
+	Use:     "replay [session|bead|rig/polecat]",
+	GroupID: GroupDiag,
+	Short:   "Play back a recorded polecat session",
+	Long: `Play back recordings of polecat sessions in the terminal.
+
+Rigs with recording enabled capture every polecat pane as an asciicast v2
+file under <rig>/polecats/.recordings/<polecat>/, kept after the polecat is
+nuked so its work can be audited. Enable it in <rig>/settings/config.json:
+
+  "recording": {"enabled": true, "max_file_mb": 16, "max_files": 50}
+
+Recordings rotate at max_file_mb, each polecat keeps max_files of them, and
+the daemon prunes them after the "session_recording" TTL in .krc.yaml
+(default 14 days).
+
+A session, bead or polecat may have several recordings; they play oldest
+first. The files also play in asciinema and in the dashboard.
+
+Examples:
+  gt replay gt-greenplace-Toast
+  gt replay gt-abc12 --speed 4
+  gt replay greenplace/Toast --max-idle 500ms
+  gt replay --list`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReplay,
+}
+
+var recordPaneCmd = &cobra.Command{
+	Use:    "record-pane",
+	Short:  "Record pane output from stdin as asciicast (internal use)",
+	Hidden: true, // Internal command run by tmux pipe-pane
+	Args:   cobra.NoArgs,
+	RunE:   runRecordPane,
+}
+
+func init() {
+	replayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "Playback speed multiplier")
+	replayCmd.Flags().DurationVar(&replayMaxIdle, "max-idle", 2*time.Second, "Cap pauses between output at this long (0 keeps real pauses)")
+	replayCmd.Flags().BoolVar(&replayList, "list", false, "List recordings instead of playing them")
+	replayCmd.Flags().BoolVar(&replayJSON, "json", false, "Output the list as JSON (with --list)")
+
+	recordPaneCmd.Flags().StringVar(&recordPaneDir, "dir", "", "Directory for recordings")
+	recordPaneCmd.Flags().StringVar(&recordPaneSession, "session", "", "Tmux session name")
+	recordPaneCmd.Flags().StringVar(&recordPaneAgent, "agent", "", "Agent address")
+	recordPaneCmd.Flags().StringVar(&recordPaneBead, "bead", "", "Hooked bead")
+	recordPaneCmd.Flags().IntVar(&recordPaneWidth, "width", 80, "Pane width")
+	recordPaneCmd.Flags().IntVar(&recordPaneHeight, "height", 24, "Pane height")
+	recordPaneCmd.Flags().Int64Var(&recordPaneMaxFileBytes, "max-file-bytes", 0, "Rotate files at this size")
+	recordPaneCmd.Flags().IntVar(&recordPaneMaxFiles, "max-files", 0, "Recordings to keep in --dir")
+
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(recordPaneCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var recs []recording.Recording
+	if len(args) == 0 {
+		if !replayList {
+			return fmt.Errorf("name a session, bead or rig/polecat to replay (or use --list)")
+		}
+		recs, err = recording.List(townRoot)
+	} else {
+		recs, err = recording.Find(townRoot, args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("finding recordings: %w", err)
+	}
+
+	if replayList {
+		if replayJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(recs)
+		}
+		if len(recs) == 0 {
+			fmt.Printf("%s No recordings\n", style.Dim.Render("○"))
+			return nil
+		}
+		for _, rec := range recs {
+			bead := rec.Bead
+			if bead == "" {
+				bead = "-"
+			}
+			fmt.Printf("%s  %-24s %-12s %8s  %s\n",
+				rec.Started.Local().Format("2006-01-02 15:04"), rec.Session, bead,
+				hostres.FormatKB(rec.Size/1024), style.Dim.Render(relPath(townRoot, rec.Path)))
+		}
+		return nil
+	}
+
+	if len(recs) == 0 {
+		return fmt.Errorf("no recordings of %s", args[0])
+	}
+	for i, rec := range recs {
+		if i > 0 || len(recs) > 1 {
+			fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("── %s %s (%d/%d) ──",
+				rec.Session, rec.Started.Local().Format("2006-01-02 15:04:05"), i+1, len(recs))))
+		}
+		if err := recording.Play(os.Stdout, rec.Path, recording.PlayOptions{
+			Speed:   replaySpeed,
+			MaxIdle: replayMaxIdle,
+		}); err != nil {
+			return fmt.Errorf("playing %s: %w", rec.Path, err)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// relPath shortens a path under the town root for display.
+func relPath(townRoot, path string) string {
+	if rel, err := filepath.Rel(townRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// runRecordPane copies a pane's output (from tmux pipe-pane) into
+// asciicast recordings until the pane closes.
+func runRecordPane(cmd *cobra.Command, args []string) error {
+	if recordPaneDir == "" || recordPaneSession == "" {
+		return fmt.Errorf("--dir and --session are required")
+	}
+	env := map[string]string{
+		"TERM":               "screen-256color",
+		recording.EnvSession: recordPaneSession,
+	}
+	if recordPaneAgent != "" {
+		env[recording.EnvAgent] = recordPaneAgent
+	}
+	if recordPaneBead != "" {
+		env[recording.EnvBead] = recordPaneBead
+	}
+	rec := &recording.Recorder{
+		Dir:          recordPaneDir,
+		Session:      recordPaneSession,
+		Width:        recordPaneWidth,
+		Height:       recordPaneHeight,
+		Env:          env,
+		MaxFileBytes: recordPaneMaxFileBytes,
+		MaxFiles:     recordPaneMaxFiles,
+	}
+	return rec.Record(os.Stdin)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/wisp"
@@ -170,6 +171,7 @@ func runRigConfigSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("blocking %s: %w", key, err)
 		}
 		fmt.Printf("%s Blocked %s for rig %s\n", style.Success.Render("✓"), key, rigName)
+		logConfigChange("rig:"+rigName+":wisp", key, events.ConfigBlock, "")
 		return nil
 	}
 
@@ -181,6 +183,7 @@ func runRigConfigSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("setting bead label: %w", err)
 		}
 		fmt.Printf("%s Set %s=%s in bead layer for rig %s\n", style.Success.Render("✓"), key, value, rigName)
+		logConfigChange("rig:"+rigName+":bead", key, events.ConfigSet, value)
 	} else {
 		// Set in wisp layer
 		wispCfg := wisp.NewConfig(townRoot, r.Name)
@@ -195,6 +198,7 @@ func runRigConfigSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("setting %s: %w", key, err)
 		}
 		fmt.Printf("%s Set %s=%s in wisp layer for rig %s\n", style.Success.Render("✓"), key, value, rigName)
+		logConfigChange("rig:"+rigName+":wisp", key, events.ConfigSet, value)
 	}
 
 	return nil
@@ -215,6 +219,7 @@ func runRigConfigUnset(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Unset %s from wisp layer for rig %s\n", style.Success.Render("✓"), key, rigName)
+	logConfigChange("rig:"+rigName+":wisp", key, events.ConfigUnset, "")
 	return nil
 }
 
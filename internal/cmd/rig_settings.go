@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -161,6 +162,7 @@ func runRigSettingsSet(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Set %s=%v in settings for rig %s\n",
 		style.Success.Render("✓"), keyPath, formatValueForDisplay(value), rigName)
+	logConfigChange("rig:"+rigName+":settings", keyPath, events.ConfigSet, valueStr)
 	return nil
 }
 
@@ -196,6 +198,7 @@ func runRigSettingsUnset(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Unset %s from settings for rig %s\n",
 		style.Success.Render("✓"), keyPath, rigName)
+	logConfigChange("rig:"+rigName+":settings", keyPath, events.ConfigUnset, "")
 	return nil
 }
 
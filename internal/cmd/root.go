@@ -30,41 +30,50 @@ across distributed teams of AI agents working on shared codebases.`,
 // NOTE: Gas Town has migrated to Dolt for beads storage. The bd version
 // check is obsolete. Exempt all common commands.
 var beadsExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
-	"crew":       true,
-	"polecat":    true,
-	"witness":    true,
-	"refinery":   true,
-	"status":     true,
-	"mail":       true,
-	"hook":       true,
-	"prime":      true,
-	"nudge":      true,
-	"seance":     true,
-	"doctor":     true,
-	"dolt":       true,
-	"handoff":    true,
-	"costs":      true,
-	"feed":       true,
-	"rig":        true,
-	"config":     true,
-	"install":    true,
-	"tap":        true,
-	"dnd":        true,
-	"krc":        true, // KRC doesn't require beads
+	"version":      true,
+	"help":         true,
+	"completion":   true,
+	"crew":         true,
+	"polecat":      true,
+	"witness":      true,
+	"refinery":     true,
+	"status":       true,
+	"mail":         true,
+	"hook":         true,
+	"prime":        true,
+	"nudge":        true,
+	"seance":       true,
+	"doctor":       true,
+	"dolt":         true,
+	"handoff":      true,
+	"costs":        true,
+	"feed":         true,
+	"rig":          true,
+	"config":       true,
+	"install":      true,
+	"tap":          true,
+	"dnd":          true,
+	"krc":          true, // KRC doesn't require beads
+	"attach":       true,
+	"session-host": true, // Background host for a headless session
+	"exec":         true, // gt secrets/sandbox exec, run in front of agents at startup
+	"check":        true, // gt policy check, run before every agent tool call
+	"pre-push":     true, // gt push-policy pre-push, run by the pre-push hook
 }
 
 // Commands exempt from the town root branch warning.
 // These are commands that help fix the problem or are diagnostic.
 var branchCheckExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
-	"doctor":     true, // Used to fix the problem
-	"install":    true, // Initial setup
-	"git-init":   true, // Git setup
+	"version":      true,
+	"help":         true,
+	"completion":   true,
+	"doctor":       true, // Used to fix the problem
+	"install":      true, // Initial setup
+	"git-init":     true, // Git setup
+	"session-host": true, // Background host for a headless session
+	"exec":         true, // gt secrets/sandbox exec, run in front of agents at startup
+	"check":        true, // gt policy check, run before every agent tool call
+	"pre-push":     true, // gt push-policy pre-push, run by the pre-push hook
 }
 
 // persistentPreRun runs before every command.
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/sandbox"
+)
+
+var sandboxWritable []string
+
+var sandboxCmd = &cobra.Command{
+	Use:    "sandbox",
+	Short:  "Confine agent writes (internal use)",
+	Hidden: true, // Only reached through polecat startup commands
+	RunE:   requireSubcommand,
+}
+
+var sandboxExecCmd = &cobra.Command{
+	Use:   "exec [--write <path>]... -- <command> [args...]",
+	Short: "Exec a command that may write only beneath the given paths",
+	Long: `Exec a command confined with Landlock to writing beneath the --write
+paths. The confinement is inherited by everything the command starts and
+can't be lifted. Reads are not restricted.
+
+Polecat startup commands run this in front of the agent when the rig's
+settings enable "sandbox". On systems without Landlock the command runs
+unconfined, with a warning.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSandboxExec,
+}
+
+func init() {
+	sandboxExecCmd.Flags().StringArrayVar(&sandboxWritable, "write", nil, "Path the command may write beneath (repeatable)")
+
+	sandboxCmd.AddCommand(sandboxExecCmd)
+	rootCmd.AddCommand(sandboxCmd)
+}
+
+func runSandboxExec(cmd *cobra.Command, args []string) error {
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", args[0], err)
+	}
+
+	// Confinement is best effort: an agent on an older kernel still starts
+	env := os.Environ()
+	if err := sandbox.Restrict(sandboxWritable); err != nil {
+		if !errors.Is(err, sandbox.ErrUnsupported) {
+			return fmt.Errorf("confining writes: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "gt sandbox: %v; running unconfined\n", err)
+	} else {
+		env = append(env, sandbox.EnvVar+"=1")
+	}
+
+	// Exec from the thread Restrict confined
+	return syscall.Exec(path, args, env)
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hostres"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	scheduleJSON bool
+	scheduleMax  int
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	GroupID: GroupWork,
+	Short:   "Place ready work onto idle polecat capacity across rigs",
+	RunE:    requireSubcommand,
+	Long: `Town-wide work scheduler.
+
+The scheduler pulls ready beads from every rig (as gt ready does) and slings
+them to their rig while it has free polecat slots. Parked and docked rigs,
+rigs at max_polecats, rigs with a non-empty sling queue, and rigs whose host
+is saturated (see "resources" in gt sling --help) are skipped.
+
+Policies (settings/config.json "scheduler" section):
+  policy             fair_share (default): serve the rig furthest below its
+                     weighted share of running polecats first
+                     priority: always take the highest-scoring bead in town
+  priority_weight    Points per priority level (P0 highest)      default 100
+  age_weight         Points per hour since the bead was created  default 1
+  convoy_age_weight  Points per hour of its convoy's age         default 10
+  rig_weights        Share of capacity per rig (default 1, 0 = never schedule)
+  rig_quotas         Polecat cap per rig, below max_polecats
+  max_dispatch       Beads slung per run                         default 5
+
+The daemon runs the scheduler each heartbeat when the "scheduler" patrol is
+enabled in mayor/daemon.json (it is off by default).`,
+}
+
+var schedulePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what the scheduler would dispatch next (dry run)",
+	Long: `Show the beads the scheduler would sling next, in dispatch order,
+along with each rig's capacity and why skipped rigs take no work.
+
+Examples:
+  gt schedule plan
+  gt schedule plan --max 20
+  gt schedule plan --json`,
+	Args: cobra.NoArgs,
+	RunE: runSchedulePlan,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Dispatch the planned work now",
+	Long: `Compute the plan and sling each bead to its rig with gt sling.
+
+Examples:
+  gt schedule run
+  gt schedule run --max 1`,
+	Args: cobra.NoArgs,
+	RunE: runScheduleRun,
+}
+
+func init() {
+	schedulePlanCmd.Flags().BoolVar(&scheduleJSON, "json", false, "Output as JSON")
+	schedulePlanCmd.Flags().IntVar(&scheduleMax, "max", 0, "Override max_dispatch for this plan")
+	scheduleRunCmd.Flags().IntVar(&scheduleMax, "max", 0, "Override max_dispatch for this run")
+
+	scheduleCmd.AddCommand(schedulePlanCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+// SchedulePlan is the output of gt schedule plan.
+type SchedulePlan struct {
+	Policy     string               `json:"policy"`
+	Rigs       []scheduler.RigState `json:"rigs"`
+	Candidates int                  `json:"candidates"`
+	Dispatch   []scheduler.Dispatch `json:"dispatch"`
+}
+
+// buildSchedulePlan gathers rig capacity and ready work across town and
+// runs the scheduling policy over it.
+func buildSchedulePlan(townRoot string) (*SchedulePlan, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	cfg := settings.Scheduler
+	if cfg == nil {
+		cfg = config.DefaultSchedulerConfig()
+	}
+	if scheduleMax > 0 {
+		c := *cfg
+		c.MaxDispatch = scheduleMax
+		cfg = &c
+	}
+	policy := cfg.Policy
+	if policy == "" {
+		policy = config.SchedulerPolicyFairShare
+	}
+	resources := settings.Resources
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigs, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).DiscoverRigs()
+	if err != nil {
+		return nil, fmt.Errorf("discovering rigs: %w", err)
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+
+	plan := &SchedulePlan{Policy: policy}
+	var candidates []scheduler.Candidate
+	convoys := make(map[string]*time.Time)
+	townBeads := beads.New(beads.GetTownBeadsPath(townRoot))
+
+	for _, r := range rigs {
+		mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+		state := scheduler.RigState{
+			Name:        r.Name,
+			Polecats:    mgr.Count(),
+			MaxPolecats: mgr.MaxPolecats(),
+		}
+		if opState, _ := getRigOperationalState(townRoot, r.Name); opState != "OPERATIONAL" {
+			state.Blocked = strings.ToLower(opState)
+		} else if queued, _ := polecat.NewAdmissionQueue(r.Path).List(); len(queued) > 0 {
+			// Queued slings already have first claim on freed slots
+			state.Blocked = fmt.Sprintf("%d sling(s) queued", len(queued))
+		} else if cfg.RigWeight(r.Name) <= 0 {
+			state.Blocked = "weight 0"
+		} else if err := hostres.Check(r.Path, resources); err != nil {
+			state.Blocked = err.Error()
+		}
+		plan.Rigs = append(plan.Rigs, state)
+		if state.Blocked != "" {
+			continue
+		}
+
+		issues, err := readyWorkAt(r.BeadsPath())
+		if err != nil {
+			style.PrintWarning("could not list ready work in %s: %v", r.Name, err)
+			continue
+		}
+		for _, issue := range issues {
+			if !schedulable(issue) {
+				continue
+			}
+			c := scheduler.Candidate{
+				ID:       issue.ID,
+				Title:    issue.Title,
+				Rig:      r.Name,
+				Priority: issue.Priority,
+			}
+			c.Created, _ = time.Parse(time.RFC3339, issue.CreatedAt)
+			if cfg.ConvoyAgeWeight != 0 {
+				if convoyID := isTrackedByConvoy(issue.ID); convoyID != "" {
+					if _, seen := convoys[convoyID]; !seen {
+						convoys[convoyID] = nil
+						if convoy, err := townBeads.Show(convoyID); err == nil {
+							if t, err := time.Parse(time.RFC3339, convoy.CreatedAt); err == nil {
+								convoys[convoyID] = &t
+							}
+						}
+					}
+					c.Convoy = convoyID
+					c.ConvoyCreated = convoys[convoyID]
+				}
+			}
+			candidates = append(candidates, c)
+		}
+	}
+
+	plan.Candidates = len(candidates)
+	plan.Dispatch = scheduler.Plan(cfg, plan.Rigs, candidates, time.Now())
+	return plan, nil
+}
+
+// schedulable reports whether a ready issue is work a polecat can pick up:
+// unassigned, and not a container or infrastructure bead.
+func schedulable(issue *beads.Issue) bool {
+	if issue.Assignee != "" {
+		return false
+	}
+	switch issue.Type {
+	case "epic", "convoy", "molecule", "merge-request", "message", "gate", "event":
+		return false
+	}
+	return true
+}
+
+func runSchedulePlan(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	plan, err := buildSchedulePlan(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if scheduleJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+
+	fmt.Printf("%s Schedule plan (%s)\n\n", style.Bold.Render("📅"), plan.Policy)
+	fmt.Println(style.Bold.Render("Rigs:"))
+	for _, r := range plan.Rigs {
+		limit := "unlimited"
+		if r.MaxPolecats > 0 {
+			limit = fmt.Sprintf("%d", r.MaxPolecats)
+		}
+		line := fmt.Sprintf("  %-16s %d/%s polecats", r.Name, r.Polecats, limit)
+		if r.Blocked != "" {
+			line += style.Dim.Render("  skipped: " + r.Blocked)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	if len(plan.Dispatch) == 0 {
+		fmt.Printf("%s Nothing to dispatch (%d ready candidate(s))\n", style.Dim.Render("○"), plan.Candidates)
+		return nil
+	}
+	fmt.Printf("%s (%d of %d candidate(s)):\n", style.Bold.Render("Would dispatch"), len(plan.Dispatch), plan.Candidates)
+	for i, d := range plan.Dispatch {
+		convoy := ""
+		if d.Convoy != "" {
+			convoy = style.Dim.Render("  convoy " + d.Convoy)
+		}
+		fmt.Printf("  %2d. %-14s → %-12s P%d  score %.0f  %s%s\n",
+			i+1, d.ID, d.Rig, d.Priority, d.Score, truncateStr(d.Title, 40), convoy)
+	}
+	return nil
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	plan, err := buildSchedulePlan(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(plan.Dispatch) == 0 {
+		fmt.Printf("%s Nothing to dispatch\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	dispatched := 0
+	for _, d := range plan.Dispatch {
+		fmt.Printf("%s Dispatching %s → %s (P%d, score %.0f)\n",
+			style.Bold.Render("▶"), d.ID, d.Rig, d.Priority, d.Score)
+		if out, err := queuedSlingCommand(townRoot, d.Rig, &polecat.QueuedSling{Bead: d.ID}).CombinedOutput(); err != nil {
+			style.PrintWarning("sling of %s failed: %v\n%s", d.ID, err, strings.TrimSpace(string(out)))
+			continue
+		}
+		dispatched++
+	}
+	fmt.Printf("%s Dispatched %d/%d bead(s)\n", style.Bold.Render("📅"), dispatched, len(plan.Dispatch))
+	return nil
+}
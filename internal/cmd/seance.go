@@ -54,6 +54,10 @@ THE SEANCE (talk to predecessor):
   gt seance --talk <session-id>              # Interactive conversation
   gt seance --talk <id> -p "Where is X?"     # One-shot question
 
+SEARCH (archived transcripts):
+  gt seance search "<text>"               # Search past sessions' transcripts
+  gt seance search "<text>" --bead gt-123 # Only sessions that worked a bead
+
 The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
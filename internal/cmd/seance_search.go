@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	seanceSearchBead  string
+	seanceSearchAgent string
+	seanceSearchRig   string
+	seanceSearchSince string
+	seanceSearchLimit int
+	seanceSearchJSON  bool
+
+	seanceArchiveAgent   string
+	seanceArchiveBead    string
+	seanceArchiveWorkDir string
+	seanceArchiveSession string
+)
+
+var seanceSearchCmd = &cobra.Command{
+	Use:   "search [text]",
+	Short: "Search archived session transcripts",
+	Long: `Search the transcripts of past sessions.
+
+When a polecat session stops or an agent hands off, its runtime transcript
+is compressed into ~/gt/.transcripts/ and indexed by bead, agent, rig and
+time. The archive keeps sessions from every account and runtime (Claude,
+Codex, or any runtime with session.transcript_dir set), even after the
+runtime's own files are gone.
+
+Text matches case-insensitively against message content. With no text,
+lists the archived sessions that pass the filters.
+
+Examples:
+  gt seance search "migration failed"
+  gt seance search "lock timeout" --bead gt-123
+  gt seance search --agent gastown/polecats --since 7d
+  gt seance search "flaky" --rig gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeanceSearch,
+}
+
+var seanceArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive the current session's transcript",
+	Long: `Copy the current session's runtime transcript into the archive.
+
+Polecat sessions are archived when they stop and agents are archived when
+they hand off; use this for other session ends (e.g. a runtime SessionEnd
+hook). Defaults come from GT_ROLE, the current directory and the agent's
+hooked bead.
+
+Examples:
+  gt seance archive
+  gt seance archive --agent gastown/crew/max --bead gt-123`,
+	Args: cobra.NoArgs,
+	RunE: runSeanceArchive,
+}
+
+func init() {
+	seanceSearchCmd.Flags().StringVar(&seanceSearchBead, "bead", "", "Only sessions that worked this bead")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchAgent, "agent", "", "Only agents whose address contains this")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchRig, "rig", "", "Only sessions in this rig")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchSince, "since", "", "Only sessions that ended within this long (e.g. 24h, 7d)")
+	seanceSearchCmd.Flags().IntVarP(&seanceSearchLimit, "limit", "n", 20, "Most sessions to show (0 for all)")
+	seanceSearchCmd.Flags().BoolVar(&seanceSearchJSON, "json", false, "Output as JSON")
+
+	seanceArchiveCmd.Flags().StringVar(&seanceArchiveAgent, "agent", "", "Agent address (default: GT_ROLE)")
+	seanceArchiveCmd.Flags().StringVar(&seanceArchiveBead, "bead", "", "Bead the session worked (default: hooked bead)")
+	seanceArchiveCmd.Flags().StringVar(&seanceArchiveWorkDir, "work-dir", "", "Directory the runtime ran in (default: current directory)")
+	seanceArchiveCmd.Flags().StringVar(&seanceArchiveSession, "session", "", "Tmux session name (default: current session)")
+
+	seanceCmd.AddCommand(seanceSearchCmd)
+	seanceCmd.AddCommand(seanceArchiveCmd)
+}
+
+func runSeanceSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := transcript.Filter{
+		Bead:  seanceSearchBead,
+		Agent: seanceSearchAgent,
+		Rig:   seanceSearchRig,
+		Limit: seanceSearchLimit,
+	}
+	if seanceSearchSince != "" {
+		d, err := parseDuration(seanceSearchSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	matches, err := transcript.Search(townRoot, query, filter)
+	if err != nil {
+		return fmt.Errorf("searching transcripts: %w", err)
+	}
+
+	if seanceSearchJSON {
+		if matches == nil {
+			matches = []transcript.Match{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No archived sessions match.")
+		fmt.Println(style.Dim.Render("Transcripts are archived to ~/gt/.transcripts when sessions end"))
+		return nil
+	}
+
+	for _, m := range matches {
+		bead := m.Bead
+		if bead == "" {
+			bead = "-"
+		}
+		fmt.Printf("%s  %s  %s  %s\n",
+			style.Bold.Render(m.ID),
+			m.Ended.Local().Format("2006-01-02 15:04"),
+			m.Agent,
+			style.Dim.Render(bead+" · "+m.Runtime))
+		if m.Hits == 1 {
+			fmt.Printf("  1 matching message\n")
+		} else if m.Hits > 1 {
+			fmt.Printf("  %d matching messages\n", m.Hits)
+		}
+		for _, s := range m.Snippets {
+			fmt.Printf("  %s %s\n", style.Dim.Render("│"), s)
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Dim.Render("Talk to one: gt seance --talk <id>"))
+	return nil
+}
+
+func runSeanceArchive(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	agent := seanceArchiveAgent
+	if agent == "" {
+		agent = os.Getenv("GT_ROLE")
+	}
+	if agent == "" {
+		return fmt.Errorf("--agent required (or set GT_ROLE)")
+	}
+	workDir := seanceArchiveWorkDir
+	if workDir == "" {
+		if workDir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+	}
+	sessionName := seanceArchiveSession
+	if sessionName == "" {
+		sessionName = detectCurrentTmuxSession()
+	}
+
+	entry, err := archiveAgentTranscript(townRoot, agent, sessionName, workDir, seanceArchiveBead)
+	if err != nil {
+		return fmt.Errorf("archiving transcript: %w", err)
+	}
+	fmt.Printf("%s Archived %s (%s)\n", style.Success.Render("✓"), entry.ID, entry.Agent)
+	return nil
+}
+
+// archiveAgentTranscript archives the transcript of an agent's session that
+// ran in workDir, using the runtime its role is configured with. An empty
+// bead falls back to the bead in the agent's checkpoint.
+func archiveAgentTranscript(townRoot, agent, sessionName, workDir, bead string) (*transcript.Entry, error) {
+	role, rigPath := agentRoleAndRigPath(townRoot, agent)
+	rc := config.ResolveRoleAgentConfig(role, townRoot, rigPath)
+
+	configDir := ""
+	if rc.Session != nil && rc.Session.ConfigDirEnv != "" {
+		configDir = os.Getenv(rc.Session.ConfigDirEnv)
+	}
+	src := runtime.Driver(rc).Transcript(workDir, configDir)
+
+	if bead == "" {
+		if cp, err := checkpoint.Read(workDir); err == nil && cp != nil {
+			bead = cp.HookedBead
+		}
+	}
+	entry := transcript.Entry{
+		Agent:   agent,
+		Session: sessionName,
+		Bead:    bead,
+	}
+	if parts := strings.Split(agent, "/"); len(parts) > 1 {
+		entry.Rig = parts[0]
+	}
+	return transcript.ArchiveSession(townRoot, src, entry)
+}
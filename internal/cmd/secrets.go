@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var (
+	secretsListJSON bool
+	secretsFromFile string
+)
+
+var secretsCmd = &cobra.Command{
+	Use:     "secrets",
+	GroupID: GroupConfig,
+	Short:   "Manage credentials issued to agent sessions",
+	Long: `Manage the town's secrets broker.
+
+Secrets are stored encrypted in the town (mayor/secrets.json) under a key
+kept in ~/.config/gastown/secrets.key, and are issued only to the sessions
+of roles they are granted to. Grant them in town or rig settings
+(settings/config.json):
+
+  "secrets": [
+    {"secret": "github-token", "roles": ["refinery"], "env": "GITHUB_TOKEN_FILE"},
+    {"secret": "npm-token", "roles": ["polecat", "refinery"], "env": "NPM_TOKEN", "inline": true}
+  ]
+
+A granted session gets each secret as a file readable only by you, under
+$XDG_RUNTIME_DIR, with the variable holding its path; "inline" grants put
+the value in the variable instead, for tools that only read it from there.
+The files are removed once the agent exits. Every issue is recorded in the
+audit log (gt audit verify); values never are.
+
+Grants are advisory unless agents are isolated from the host: the role comes
+from GT_ROLE, which an agent can set itself, and any process running as you
+can read the key. Use container isolation (rig settings "container") to keep
+polecats from secrets they aren't granted.`,
+	RunE: requireSubcommand,
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secrets, their grants and the agents holding them",
+	Args:  cobra.NoArgs,
+	RunE:  runSecretsList,
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret",
+	Long: `Store a secret's value, read from --from-file, standard input, or a
+prompt that doesn't echo. Values are never taken from the command line, so
+they stay out of shell history. Sessions already running keep the old value;
+use rotate to update them.
+
+Examples:
+  gt secrets set github-token
+  gh auth token | gt secrets set github-token
+  gt secrets set deploy-key --from-file ~/.ssh/deploy_key`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsSet,
+}
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate <name>",
+	Short: "Replace a secret and refresh running agents",
+	Long: `Replace an existing secret's value (read like gt secrets set) and
+rewrite the files of running agents that hold it, so they pick up the new
+value on their next read. Agents holding it inline keep the old value until
+they restart; they are listed.
+
+Examples:
+  gt secrets rotate github-token`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsRotate,
+}
+
+var secretsExecCmd = &cobra.Command{
+	Use:    "exec -- <command> [args...]",
+	Short:  "Issue the session's secrets and exec the agent (internal use)",
+	Hidden: true, // Put in front of the agent by startup commands of granted roles
+	Args:   cobra.MinimumNArgs(1),
+	RunE:   runSecretsExec,
+}
+
+func init() {
+	secretsListCmd.Flags().BoolVar(&secretsListJSON, "json", false, "Output as JSON")
+	secretsSetCmd.Flags().StringVar(&secretsFromFile, "from-file", "", "Read the value from a file")
+	secretsRotateCmd.Flags().StringVar(&secretsFromFile, "from-file", "", "Read the value from a file")
+
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsRotateCmd)
+	secretsCmd.AddCommand(secretsExecCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
+
+// secretListing is one row of gt secrets list.
+type secretListing struct {
+	secrets.Info
+	Grants  []string `json:"grants"`  // "scope: role" pairs
+	Holders []string `json:"holders"` // Running agents holding it
+}
+
+func runSecretsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	store, err := secrets.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	grants := secretGrantsByName(townRoot)
+	active, err := secrets.Active()
+	if err != nil {
+		return err
+	}
+
+	var rows []secretListing
+	for _, info := range store.List() {
+		row := secretListing{Info: info, Grants: grants[info.Name]}
+		for _, i := range active {
+			_, file := i.Files[info.Name]
+			_, inline := i.Inline[info.Name]
+			if i.Town == townRoot && (file || inline) {
+				row.Holders = append(row.Holders, i.Agent)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if secretsListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No secrets stored (gt secrets set <name>)"))
+		return nil
+	}
+	for _, row := range rows {
+		fmt.Printf("%s %s\n", style.Bold.Render(row.Name),
+			style.Dim.Render(fmt.Sprintf("v%d, set %s by %s", row.Version, row.UpdatedAt.Local().Format("2006-01-02 15:04"), row.UpdatedBy)))
+		if len(row.Grants) == 0 {
+			fmt.Printf("  granted to: %s\n", style.Warning.Render("nobody"))
+		} else {
+			fmt.Printf("  granted to: %s\n", strings.Join(row.Grants, ", "))
+		}
+		if len(row.Holders) > 0 {
+			fmt.Printf("  held by:    %s\n", strings.Join(row.Holders, ", "))
+		}
+	}
+	for name := range grants {
+		if _, ok := store.Secrets[name]; !ok {
+			fmt.Printf("%s %s\n", style.Warning.Render("⚠"), fmt.Sprintf("%s is granted but not set", name))
+		}
+	}
+	return nil
+}
+
+// secretGrantsByName returns "scope: role" descriptions of every grant in
+// the town and its rigs, keyed by secret.
+func secretGrantsByName(townRoot string) map[string][]string {
+	grants := make(map[string][]string)
+	add := func(scope string, list []config.SecretGrant) {
+		for _, g := range list {
+			for _, role := range g.Roles {
+				grants[g.Secret] = append(grants[g.Secret], scope+": "+role)
+			}
+		}
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		add("town", settings.Secrets)
+	}
+	if rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		var rigs []string
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+		sort.Strings(rigs)
+		for _, rig := range rigs {
+			if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rig))); err == nil {
+				add(rig, settings.Secrets)
+			}
+		}
+	}
+	return grants
+}
+
+func runSecretsSet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	name := args[0]
+	if err := secrets.ValidName(name); err != nil {
+		return err
+	}
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+
+	actor := detectActor()
+	version, err := secrets.Set(townRoot, name, value, actor)
+	if err != nil {
+		return err
+	}
+	_ = events.LogAudit(events.TypeSecretSet, actor, events.SecretPayload(name, version, ""))
+
+	fmt.Printf("%s Stored %s (v%d)\n", style.Success.Render("✓"), name, version)
+	if len(secretGrantsByName(townRoot)[name]) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Not granted to any role yet; add it to \"secrets\" in settings/config.json"))
+	}
+	return nil
+}
+
+func runSecretsRotate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	name := args[0]
+	store, err := secrets.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if _, ok := store.Secrets[name]; !ok {
+		return fmt.Errorf("%w: %s (use gt secrets set)", secrets.ErrNotFound, name)
+	}
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+
+	actor := detectActor()
+	version, err := secrets.Rotate(townRoot, name, value, actor)
+	if err != nil {
+		return err
+	}
+	_ = events.LogAudit(events.TypeSecretRotated, actor, events.SecretPayload(name, version, ""))
+	fmt.Printf("%s Rotated %s (v%d)\n", style.Success.Render("✓"), name, version)
+
+	refreshed, stale, err := secrets.Refresh(townRoot, name)
+	for _, agent := range refreshed {
+		fmt.Printf("  refreshed %s\n", agent)
+	}
+	if len(stale) > 0 {
+		fmt.Printf("%s Holding the old value until restarted: %s\n", style.Warning.Render("⚠"), strings.Join(stale, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("refreshing running agents: %w", err)
+	}
+	return nil
+}
+
+// readSecretValue reads a secret from --from-file, a no-echo prompt, or
+// piped standard input. One trailing newline is dropped.
+func readSecretValue(name string) (string, error) {
+	var data []byte
+	var err error
+	switch {
+	case secretsFromFile != "":
+		data, err = os.ReadFile(secretsFromFile) //nolint:gosec // G304: path given by the user
+	case term.IsTerminal(int(os.Stdin.Fd())):
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		data, err = term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+	default:
+		data, err = io.ReadAll(bufio.NewReader(os.Stdin))
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading value: %w", err)
+	}
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return "", fmt.Errorf("empty value for %s", name)
+	}
+	return value, nil
+}
+
+// runSecretsExec issues the secrets granted to the session's role (from
+// GT_ROLE, GT_RIG and GT_ROOT, set by the startup command) and execs the
+// agent in its place. Secrets that can't be issued are reported and the
+// agent starts without them rather than not at all.
+func runSecretsExec(cmd *cobra.Command, args []string) error {
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", args[0], err)
+	}
+
+	env := os.Environ()
+	townRoot := os.Getenv("GT_ROOT")
+	if townRoot == "" {
+		townRoot, _ = workspace.FindFromCwd()
+	}
+	role := os.Getenv("GT_ROLE")
+	if townRoot != "" && role != "" {
+		_, _ = secrets.Reap()
+		var rigPath string
+		if rig := os.Getenv("GT_RIG"); rig != "" {
+			rigPath = filepath.Join(townRoot, rig)
+		}
+		grants := config.LoadSecretGrants(townRoot, rigPath, role)
+		issued, err := secrets.Issue(townRoot, role, os.Getpid(), grants)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gt secrets: %v\n", err)
+		}
+		for k, v := range issued {
+			env = append(env, k+"="+v)
+		}
+	}
+
+	// The agent replaces this process, keeping its PID and so its secrets
+	return syscall.Exec(path, args, env)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -315,6 +316,7 @@ func runSessionStop(cmd *cobra.Command, args []string) error {
 		}
 		logger := townlog.NewLogger(townRoot)
 		_ = logger.Log(townlog.EventKill, agent, reason)
+		_ = events.LogAudit(events.TypeKill, detectSender(), events.KillPayload(rigName, agent, reason))
 	}
 
 	return nil
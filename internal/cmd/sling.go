@@ -82,8 +82,38 @@ Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
 
   When multiple beads are provided with a rig target, each bead gets its own
-  polecat. This parallelizes work dispatch without running gt sling N times.`,
-	Args: cobra.MinimumNArgs(1),
+  polecat. This parallelizes work dispatch without running gt sling N times.
+
+Admission Control:
+  A rig runs at most max_polecats polecats (see gt rig config). Slings that
+  would exceed the limit are queued per rig in bead priority order and start
+  automatically when a polecat in that rig is nuked, or on the daemon's next
+  heartbeat after a polecat finishes with gt done. A formula slung without
+  a bead isn't queued: it fails with "at capacity, retry later".
+
+  With a "resources" section in settings/config.json, slings are also
+  queued while the host is saturated: available memory, load per CPU or
+  free disk past its thresholds (min_memory_mb 1024, max_load_per_cpu 2.0,
+  min_disk_gb 2 for fields left out; 0 disables a check). The daemon
+  starts them once the host has room.
+
+  gt sling --queue-status               # Queues for all rigs
+  gt sling --queue-status gastown       # Queue for one rig
+  gt sling --start-queued               # Start queued work that fits now
+
+Agent Routing:
+  Without --agent, a polecat spawned for a bead labeled needs:<capability>
+  or lang:<language> runs the cheapest agent preset that declares all those
+  capabilities ("capabilities" and "cost_tier" in settings/agents.json).
+  If no preset matches, the rig's default agent is used.
+
+  bd label add gt-abc needs:long-context   # Route to a long-context preset`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if slingQueueStatus || slingStartQueued {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runSling,
 }
 
@@ -104,6 +134,10 @@ var (
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
 	slingNoMerge  bool   // --no-merge: skip merge queue on completion (for upstream PRs/human review)
 	slingWorkers  string // --workers: worker type for batch sling (crew or polecats)
+
+	slingQueueStatus bool // --queue-status: show per-rig admission queues
+	slingStartQueued bool // --start-queued: start queued slings that now fit
+	slingJSON        bool // --json: machine-readable --queue-status output
 )
 
 func init() {
@@ -123,11 +157,21 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
 	slingCmd.Flags().BoolVar(&slingNoMerge, "no-merge", false, "Skip merge queue on completion (keep work on feature branch for review)")
 	slingCmd.Flags().StringVar(&slingWorkers, "workers", "", "Worker type for batch sling: crew or polecats (default: crew if crew exist)")
+	slingCmd.Flags().BoolVar(&slingQueueStatus, "queue-status", false, "Show slings queued behind max_polecats (optionally for one rig)")
+	slingCmd.Flags().BoolVar(&slingStartQueued, "start-queued", false, "Start queued slings on rigs with free slots while the host has room (optionally for one rig)")
+	slingCmd.Flags().BoolVar(&slingJSON, "json", false, "Output as JSON (with --queue-status)")
 
 	rootCmd.AddCommand(slingCmd)
 }
 
 func runSling(cmd *cobra.Command, args []string) error {
+	if slingQueueStatus {
+		return runSlingQueueStatus(args)
+	}
+	if slingStartQueued {
+		return runSlingStartQueued(args)
+	}
+
 	// Polecats cannot sling - check early before writing anything
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
 		return fmt.Errorf("polecats cannot sling (use gt done for handoff)")
@@ -251,6 +295,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 			if slingDryRun {
 				// Dry run - just indicate what would happen
 				fmt.Printf("Would spawn fresh polecat in rig '%s'\n", rigName)
+				if slingAgent == "" {
+					if townRoot, r, err := getRig(rigName); err == nil {
+						_, _ = routeAgentForBead(townRoot, r.Path, beadID)
+					}
+				}
 				targetAgent = fmt.Sprintf("%s/polecats/<new>", rigName)
 				targetPane = "<new-pane>"
 			} else {
@@ -264,6 +313,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 					Agent:    slingAgent,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
+				if spawnDeferred(spawnErr) {
+					return queueSlingForRig(rigName, beadID, spawnErr)
+				}
 				if spawnErr != nil {
 					return fmt.Errorf("spawning polecat: %w", spawnErr)
 				}
@@ -296,6 +348,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 							Agent:    slingAgent,
 						}
 						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
+						if spawnDeferred(spawnErr) {
+							return queueSlingForRig(rigName, beadID, spawnErr)
+						}
 						if spawnErr != nil {
 							return fmt.Errorf("spawning polecat to replace dead polecat: %w", spawnErr)
 						}
@@ -542,7 +597,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	slingPayload := events.SlingPayload(beadID, targetAgent)
+	if formulaName != "" {
+		slingPayload["formula"] = formulaName
+	}
+	_ = events.LogFeed(events.TypeSling, actor, slingPayload)
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
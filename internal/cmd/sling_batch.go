@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hostres"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -74,6 +76,18 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Agent:    slingAgent,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
+		if spawnDeferred(err) {
+			if qErr := queueSlingForRig(rigName, beadID, err); qErr != nil {
+				results = append(results, slingResult{beadID: beadID, success: false, errMsg: qErr.Error()})
+				continue
+			}
+			reason := "rig at max_polecats"
+			if errors.Is(err, hostres.ErrSaturated) {
+				reason = "host saturated"
+			}
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "queued (" + reason + ")"})
+			continue
+		}
 		if err != nil {
 			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
 			fmt.Printf("  %s Failed to spawn polecat: %v\n", style.Dim.Render("✗"), err)
@@ -137,7 +151,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		slingPayload := events.SlingPayload(beadToHook, targetAgent)
+		if attachedMoleculeID != "" {
+			slingPayload["formula"] = formulaName
+		}
+		_ = events.LogFeed(events.TypeSling, actor, slingPayload)
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
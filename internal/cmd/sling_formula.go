@@ -144,6 +144,9 @@ func runSlingFormula(args []string) error {
 					Agent:   slingAgent,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
+				if spawnDeferred(spawnErr) {
+					return rigAtCapacityError(rigName, spawnErr)
+				}
 				if spawnErr != nil {
 					return fmt.Errorf("spawning polecat: %w", spawnErr)
 				}
@@ -218,6 +221,11 @@ func runSlingFormula(args []string) error {
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 	attachedMoleculeID := wispRootID
 
+	// Record the formula and its step IDs for step timing (gt mol stats)
+	if err := recordMoleculeFormula(beads.ResolveHookDir(townRoot, wispRootID, ""), wispRootID, formulaName, slingVars); err != nil {
+		fmt.Printf("%s Could not record formula on wisp: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Step 3: Hook the wisp bead using bd update.
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookCmd := exec.Command("bd", "--no-daemon", "update", wispRootID, "--status=hooked", "--assignee="+targetAgent)
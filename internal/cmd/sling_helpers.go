@@ -8,17 +8,21 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/agentdriver"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-// beadInfo holds status and assignee for a bead.
+// beadInfo holds status, assignee, and routing labels for a bead.
 type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"`
+	Assignee string   `json:"assignee"`
+	Priority int      `json:"priority"`
+	Labels   []string `json:"labels"`
 }
 
 // verifyBeadExists checks that the bead exists using bd show.
@@ -336,13 +340,14 @@ func getSessionFromPane(pane string) string {
 }
 
 // ensureAgentReady waits for an agent to be ready before nudging an existing session.
-// Uses a pragmatic approach: wait for the pane to leave a shell, then (Claude-only)
-// accept the bypass permissions warning and give it a moment to finish initializing.
+// Waits for the pane to leave a shell, then lets the session's agent driver
+// dismiss anything the runtime shows at startup and wait for its prompt.
 func ensureAgentReady(sessionName string) error {
 	t := tmux.NewTmux()
+	driver := runtime.DriverForSession(t, sessionName)
 
 	// If an agent is already running, assume it's ready (session was started earlier)
-	if t.IsAgentRunning(sessionName) {
+	if driver.Status(t, sessionName) != agentdriver.StatusStopped {
 		return nil
 	}
 
@@ -351,18 +356,12 @@ func ensureAgentReady(sessionName string) error {
 		return fmt.Errorf("waiting for agent to start: %w", err)
 	}
 
-	// Claude-only: accept bypass permissions warning if present
-	agentName, _ := t.GetEnvironment(sessionName, "GT_AGENT")
-	if agentName == "" || agentName == "claude" {
-		_ = t.AcceptBypassPermissionsWarning(sessionName)
+	// Accept startup dialogs (e.g. Claude's bypass permissions warning)
+	_ = driver.AfterStart(t, sessionName)
 
-		// PRAGMATIC APPROACH: fixed delay rather than prompt detection.
-		// Claude startup takes ~5-8 seconds on typical machines.
-		time.Sleep(8 * time.Second)
-	} else {
-		time.Sleep(1 * time.Second)
+	if err := driver.WaitReady(t, sessionName, constants.ClaudeStartTimeout); err != nil {
+		return fmt.Errorf("waiting for agent prompt: %w", err)
 	}
-
 	return nil
 }
 
@@ -570,6 +569,12 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 		return nil, fmt.Errorf("parsing wisp output: %w", err)
 	}
 
+	// Record the formula and its step IDs for step timing (gt mol stats)
+	wispVars := append([]string{featureVar, issueVar}, extraVars...)
+	if err := recordMoleculeFormula(formulaWorkDir, wispRootID, formulaName, wispVars); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: couldn't record formula on wisp %s: %v\n", wispRootID, err)
+	}
+
 	// Step 3: Bond wisp to original bead (creates compound)
 	bondArgs := []string{"--no-daemon", "mol", "bond", wispRootID, beadID, "--json"}
 	bondCmd := exec.Command("bd", bondArgs...)
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hostres"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// RigQueueStatus is the admission queue state for one rig.
+type RigQueueStatus struct {
+	Rig         string                `json:"rig"`
+	Polecats    int                   `json:"polecats"`
+	MaxPolecats int                   `json:"max_polecats"`
+	Queued      []polecat.QueuedSling `json:"queued"`
+}
+
+// spawnDeferred reports whether a spawn error means the sling should wait in
+// the rig's queue: the rig is at max_polecats or the host is saturated.
+func spawnDeferred(err error) bool {
+	return errors.Is(err, polecat.ErrAtCapacity) || errors.Is(err, hostres.ErrSaturated)
+}
+
+// queueSlingForRig records a sling that could not spawn because the rig is
+// at max_polecats or the host is saturated (cause). The bead's priority
+// decides its place in the queue; the spawn-related flags are kept so the
+// sling replays the same way later.
+func queueSlingForRig(rigName, beadID string, cause error) error {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	priority := 2 // bd default when the bead can't be read
+	if info, err := getBeadInfo(beadID); err == nil {
+		priority = info.Priority
+	}
+
+	q := polecat.NewAdmissionQueue(r.Path)
+	position, err := q.Enqueue(polecat.QueuedSling{
+		Bead:     beadID,
+		Priority: priority,
+		Args:     slingArgs,
+		Agent:    slingAgent,
+		Account:  slingAccount,
+		NoMerge:  slingNoMerge,
+		QueuedBy: detectActor(),
+	})
+	if err != nil {
+		return fmt.Errorf("queueing sling: %w", err)
+	}
+
+	if errors.Is(cause, hostres.ErrSaturated) {
+		fmt.Printf("%s Rig '%s' deferred, %v; queued %s (P%d, position %d)\n",
+			style.Bold.Render("⏳"), rigName, cause, beadID, priority, position)
+		fmt.Printf("  Work starts automatically once the host has room. See: gt sling --queue-status %s\n", rigName)
+		return nil
+	}
+	fmt.Printf("%s Rig '%s' is at max_polecats; queued %s (P%d, position %d)\n",
+		style.Bold.Render("⏳"), rigName, beadID, priority, position)
+	fmt.Printf("  Work starts automatically when a polecat is nuked. See: gt sling --queue-status %s\n", rigName)
+	return nil
+}
+
+// rigAtCapacityError reports a sling that could not spawn for the reason
+// queueSlingForRig handles, when there is no bead to queue (a formula
+// slung on its own). It names the queued work the sling would wait behind.
+func rigAtCapacityError(rigName string, cause error) error {
+	queued := 0
+	if _, r, err := getRig(rigName); err == nil {
+		if list, err := polecat.NewAdmissionQueue(r.Path).List(); err == nil {
+			queued = len(list)
+		}
+	}
+	return fmt.Errorf("rig '%s' at capacity, retry later (%d queued sling(s) ahead; see gt sling --queue-status %s): %w",
+		rigName, queued, rigName, cause)
+}
+
+// maxQueuedSlingAttempts is how many times a queued sling may fail to
+// replay before it is dropped from the queue.
+const maxQueuedSlingAttempts = 3
+
+// startQueuedSlings slings queued work into a rig while it has free polecat
+// slots. Called after gt polecat nuke frees slots and by gt sling
+// --start-queued (the daemon's sling_queue patrol). Each entry is replayed as a
+// `gt sling <bead> <rig>` from the town root, which takes the rig's
+// admission lock to check capacity and create the polecat, and takes the
+// bead off the queue once it has a polecat. If another sling took the slot
+// first, the bead stays queued in its place. A replay that fails keeps its
+// place too, until it has failed maxQueuedSlingAttempts times.
+// Best-effort: failures are reported and the remaining queue is kept.
+func startQueuedSlings(townRoot, rigName string) {
+	// Polecats cannot sling; their replays would only fail and use up the
+	// entries' attempts, so the daemon's patrol starts the queue instead
+	if os.Getenv("GT_POLECAT") != "" {
+		return
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		style.PrintWarning("could not start queued slings for %s: loading rigs config: %v", rigName, err)
+		return
+	}
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	r, err := rigMgr.GetRig(rigName)
+	if err != nil {
+		style.PrintWarning("could not start queued slings for %s: %v", rigName, err)
+		return
+	}
+
+	mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+	q := polecat.NewAdmissionQueue(r.Path)
+	resources := config.LoadResourceConfig(townRoot)
+	for mgr.CheckCapacity() == nil && hostres.Check(r.Path, resources) == nil {
+		next, err := q.Peek()
+		if err != nil {
+			style.PrintWarning("could not read sling queue for %s: %v", rigName, err)
+			return
+		}
+		if next == nil {
+			return
+		}
+
+		fmt.Printf("%s Starting queued work %s in %s (queued %s ago)\n",
+			style.Bold.Render("▶"), next.Bead, rigName, formatDuration(time.Since(next.QueuedAt)))
+		if out, err := queuedSlingCommand(townRoot, rigName, next).CombinedOutput(); err != nil {
+			style.PrintWarning("queued sling of %s failed: %v\n%s", next.Bead, err, strings.TrimSpace(string(out)))
+			if dropped, qErr := q.RecordFailure(next.Bead, maxQueuedSlingAttempts); qErr != nil {
+				style.PrintWarning("could not update sling queue for %s: %v", rigName, qErr)
+			} else if dropped {
+				style.PrintWarning("dropped %s from the sling queue after %d failed attempts", next.Bead, maxQueuedSlingAttempts)
+			}
+			// Retry on a later call rather than spinning on a failing entry
+			return
+		}
+
+		// The sling deferred again (slot taken or host saturated meanwhile):
+		// the bead is still first in line, so stop until the next call
+		if head, _ := q.Peek(); head != nil && head.Bead == next.Bead {
+			return
+		}
+	}
+}
+
+// queuedSlingCommand builds the gt sling invocation that replays a queued
+// sling.
+func queuedSlingCommand(townRoot, rigName string, q *polecat.QueuedSling) *exec.Cmd {
+	args := []string{"sling", q.Bead, rigName}
+	if q.Args != "" {
+		args = append(args, "--args", q.Args)
+	}
+	if q.Agent != "" {
+		args = append(args, "--agent", q.Agent)
+	}
+	if q.Account != "" {
+		args = append(args, "--account", q.Account)
+	}
+	if q.NoMerge {
+		args = append(args, "--no-merge")
+	}
+
+	cmd := exec.Command("gt", args...) //nolint:gosec // G204: args come from the rig's own sling queue
+	cmd.Dir = townRoot
+	cmd.Env = os.Environ()
+	return cmd
+}
+
+// slingQueueRigs returns the rig named in args, or every rig.
+func slingQueueRigs(townRoot string, args []string) ([]*rig.Rig, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	if len(args) > 0 {
+		r, err := rigMgr.GetRig(strings.TrimRight(args[0], "/"))
+		if err != nil {
+			return nil, fmt.Errorf("rig '%s' not found", args[0])
+		}
+		return []*rig.Rig{r}, nil
+	}
+	rigs, err := rigMgr.DiscoverRigs()
+	if err != nil {
+		return nil, fmt.Errorf("discovering rigs: %w", err)
+	}
+	return rigs, nil
+}
+
+// runSlingStartQueued starts queued slings on one rig, or all rigs, while
+// they have free slots and the host has room. The daemon runs this every
+// heartbeat so work deferred on a saturated host starts once it recovers.
+func runSlingStartQueued(args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, err := slingQueueRigs(townRoot, args)
+	if err != nil {
+		return err
+	}
+	for _, r := range rigs {
+		if queued, _ := polecat.NewAdmissionQueue(r.Path).List(); len(queued) > 0 {
+			startQueuedSlings(townRoot, r.Name)
+		}
+	}
+	return nil
+}
+
+// runSlingQueueStatus shows the admission queue for one rig, or all rigs.
+func runSlingQueueStatus(args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, err := slingQueueRigs(townRoot, args)
+	if err != nil {
+		return err
+	}
+
+	var statuses []RigQueueStatus
+	for _, r := range rigs {
+		mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+		queued, err := polecat.NewAdmissionQueue(r.Path).List()
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		statuses = append(statuses, RigQueueStatus{
+			Rig:         r.Name,
+			Polecats:    mgr.Count(),
+			MaxPolecats: mgr.MaxPolecats(),
+			Queued:      queued,
+		})
+	}
+
+	if slingJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	for i, s := range statuses {
+		if i > 0 {
+			fmt.Println()
+		}
+		limit := "unlimited"
+		if s.MaxPolecats > 0 {
+			limit = fmt.Sprintf("%d", s.MaxPolecats)
+		}
+		fmt.Printf("%s %s: %d/%s polecats, %d queued\n",
+			style.Bold.Render("⏳"), s.Rig, s.Polecats, limit, len(s.Queued))
+		for pos, q := range s.Queued {
+			fmt.Printf("  %2d. %-14s P%d  queued %s ago by %s\n",
+				pos+1, q.Bead, q.Priority, formatDuration(time.Since(q.QueuedAt)), q.QueuedBy)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -17,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hostres"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -86,6 +88,7 @@ type AgentRuntime struct {
 	State        string `json:"state,omitempty"`         // Agent state from agent bead
 	UnreadMail   int    `json:"unread_mail"`             // Number of unread messages
 	FirstSubject string `json:"first_subject,omitempty"` // Subject of first unread message
+	RSSKB        int64  `json:"rss_kb,omitempty"`        // Resident memory of the session's processes
 }
 
 // RigStatus represents status of a single rig.
@@ -409,6 +412,11 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 
 	wg.Wait()
 
+	// Per-session memory (skip in --fast mode)
+	if !statusFast {
+		fillSessionRSS(t, &status)
+	}
+
 	// Aggregate summary (after parallel work completes)
 	for i, rs := range status.Rigs {
 		status.Summary.PolecatCount += rs.PolecatCount
@@ -430,6 +438,33 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	return outputStatusText(status)
 }
 
+// fillSessionRSS sets RSSKB on every running agent from one read of the
+// process table.
+func fillSessionRSS(t *tmux.Tmux, status *TownStatus) {
+	procs, err := hostres.ListProcesses()
+	if err != nil {
+		return
+	}
+	fill := func(agents []AgentRuntime) {
+		for i := range agents {
+			if !agents[i].Running {
+				continue
+			}
+			pidStr, err := t.GetPanePID(agents[i].Session)
+			if err != nil {
+				continue
+			}
+			if pid, err := strconv.Atoi(pidStr); err == nil {
+				agents[i].RSSKB = procs.TreeRSS(pid)
+			}
+		}
+	}
+	fill(status.Agents)
+	for i := range status.Rigs {
+		fill(status.Rigs[i].Agents)
+	}
+}
+
 func outputStatusJSON(status TownStatus) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -658,6 +693,10 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 		}
 	}
 
+	if agent.RSSKB > 0 {
+		stateInfo += style.Dim.Render(" " + hostres.FormatKB(agent.RSSKB))
+	}
+
 	fmt.Printf("%s%s %s%s\n", indent, style.Dim.Render(agentBeadID), statusStr, stateInfo)
 
 	// Line 2: Hook bead (pinned work)
@@ -785,6 +824,9 @@ func renderAgentCompactWithSuffix(agent AgentRuntime, indent string, hooks []Age
 	if agent.UnreadMail > 0 {
 		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
 	}
+	if agent.RSSKB > 0 {
+		mailSuffix += style.Dim.Render(" " + hostres.FormatKB(agent.RSSKB))
+	}
 
 	// Print single line: name + status + hook + mail + suffix
 	fmt.Printf("%s%-12s %s%s%s%s\n", indent, agent.Name, statusIndicator, hookSuffix, mailSuffix, suffix)
@@ -825,6 +867,9 @@ func renderAgentCompact(agent AgentRuntime, indent string, hooks []AgentHookInfo
 	if agent.UnreadMail > 0 {
 		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
 	}
+	if agent.RSSKB > 0 {
+		mailSuffix += style.Dim.Render(" " + hostres.FormatKB(agent.RSSKB))
+	}
 
 	// Print single line: name + status + hook + mail
 	fmt.Printf("%s%-12s %s%s%s\n", indent, agent.Name, statusIndicator, hookSuffix, mailSuffix)
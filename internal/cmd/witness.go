@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
@@ -324,16 +323,7 @@ func runWitnessAttach(cmd *cobra.Command, args []string) error {
 	}
 
 	// Attach to the session
-	tmuxPath, err := exec.LookPath("tmux")
-	if err != nil {
-		return fmt.Errorf("tmux not found: %w", err)
-	}
-
-	attachCmd := exec.Command(tmuxPath, "attach-session", "-t", sessionName)
-	attachCmd.Stdin = os.Stdin
-	attachCmd.Stdout = os.Stdout
-	attachCmd.Stderr = os.Stderr
-	return attachCmd.Run()
+	return attachToTmuxSession(sessionName)
 }
 
 func runWitnessRestart(cmd *cobra.Command, args []string) error {
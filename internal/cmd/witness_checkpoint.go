@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/molstats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var witnessCheckpointJSON bool
+
+var witnessCheckpointCmd = &cobra.Command{
+	Use:   "checkpoint <rig>",
+	Short: "Snapshot checkpoints for all working polecats",
+	Long: `Capture checkpoints for every working polecat in a rig.
+
+Run by the witness patrol each cycle. A snapshot is taken for a polecat when:
+  - it has no snapshot yet
+  - HEAD moved since the last snapshot (new commit)
+  - it closed a molecule step since the last snapshot
+  - the checkpoint interval has elapsed
+
+Each snapshot is appended to polecats/<name>/.checkpoints/ (bounded history)
+and refreshes the polecat's live checkpoint, so a crashed polecat loses at
+most one interval of context when resumed with 'gt mol resume'.
+
+Cadence and retention come from the rig's settings/config.json:
+
+  "checkpoint": {"interval": "5m", "keep": 20}
+
+Examples:
+  gt witness checkpoint gastown
+  gt witness checkpoint gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessCheckpoint,
+}
+
+func init() {
+	witnessCheckpointCmd.Flags().BoolVar(&witnessCheckpointJSON, "json", false, "Output as JSON")
+	witnessCmd.AddCommand(witnessCheckpointCmd)
+}
+
+func runWitnessCheckpoint(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	polecats, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("listing polecats: %w", err)
+	}
+
+	cfg := config.DefaultCheckpointConfig()
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil && settings.Checkpoint != nil {
+		cfg = settings.Checkpoint
+	}
+
+	lastClose := lastStepCloseByAgent(filepath.Dir(r.Path))
+
+	now := time.Now()
+	var results []witness.CheckpointResult
+	for _, p := range polecats {
+		if !p.State.IsWorking() {
+			continue
+		}
+		results = append(results, witness.CheckpointPolecat(witness.CheckpointTarget{
+			Name:          p.Name,
+			HomeDir:       filepath.Join(r.Path, "polecats", p.Name),
+			WorkDir:       p.ClonePath,
+			HookBead:      p.Issue,
+			LastStepClose: lastClose[fmt.Sprintf("%s/polecats/%s", rigName, p.Name)],
+		}, cfg, now))
+	}
+
+	if witnessCheckpointJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No working polecats in %s\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+	for _, res := range results {
+		switch {
+		case res.Error != "":
+			style.PrintWarning("%s: %s", res.Polecat, res.Error)
+		case res.SnapshotID == "":
+			fmt.Printf("  %s %s: up to date\n", style.Dim.Render("○"), res.Polecat)
+		default:
+			fmt.Printf("  %s %s: snapshot %s (%s)\n", style.Bold.Render("✓"), res.Polecat, res.SnapshotID, res.Reason)
+		}
+	}
+	return nil
+}
+
+// lastStepCloseByAgent returns the latest molecule step close time per agent
+// from the events log. Errors yield an empty map (step triggers are skipped).
+func lastStepCloseByAgent(townRoot string) map[string]time.Time {
+	latest := make(map[string]time.Time)
+	runs, err := molstats.Load(townRoot)
+	if err != nil {
+		return latest
+	}
+	for _, run := range runs {
+		if run.Closed() && run.ClosedAt.After(latest[run.Agent]) {
+			latest[run.Agent] = run.ClosedAt
+		}
+	}
+	return latest
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -82,6 +83,15 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// Capabilities are what this preset is suited for (e.g., "long-context",
+	// "lang:rust", "docs"). gt sling routes a bead to a preset only if the
+	// preset has every capability the bead's labels require.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// CostTier ranks presets by cost, 1 being cheapest. When several presets
+	// match a bead, the lowest tier wins. 0 means unknown and sorts last.
+	CostTier int `json:"cost_tier,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -357,6 +367,119 @@ func ListAgentPresets() []string {
 	return names
 }
 
+// Bead label prefixes that express capability requirements.
+const (
+	// CapabilityLabelPrefix marks a required capability: "needs:long-context"
+	// requires the "long-context" capability.
+	CapabilityLabelPrefix = "needs:"
+
+	// LanguageLabelPrefix marks the bead's language: "lang:rust" requires
+	// the "lang:rust" capability.
+	LanguageLabelPrefix = "lang:"
+)
+
+// RequiredCapabilities returns the capabilities required by a bead's labels,
+// sorted and deduplicated. Labels other than needs:* and lang:* are ignored.
+func RequiredCapabilities(labels []string) []string {
+	seen := make(map[string]bool)
+	var caps []string
+	for _, label := range labels {
+		var capability string
+		switch {
+		case strings.HasPrefix(label, CapabilityLabelPrefix):
+			capability = strings.TrimPrefix(label, CapabilityLabelPrefix)
+		case strings.HasPrefix(label, LanguageLabelPrefix):
+			capability = label
+		}
+		if capability != "" && !seen[capability] {
+			seen[capability] = true
+			caps = append(caps, capability)
+		}
+	}
+	sort.Strings(caps)
+	return caps
+}
+
+// HasCapabilities reports whether the preset declares every required capability.
+func (p *AgentPresetInfo) HasCapabilities(required []string) bool {
+	have := make(map[string]bool, len(p.Capabilities))
+	for _, c := range p.Capabilities {
+		have[c] = true
+	}
+	for _, c := range required {
+		if !have[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadRigAgentRegistryCopy returns the presets available to one rig: the
+// built-in presets, overlaid with the town registry at townPath and then the
+// rig registry at rigPath. Unlike LoadRigAgentRegistry it builds a private
+// copy, so one rig's presets are never seen when routing work to another.
+// Missing registry files are skipped.
+func LoadRigAgentRegistryCopy(townPath, rigPath string) (*AgentRegistry, error) {
+	reg := &AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Agents:  make(map[string]*AgentPresetInfo, len(builtinPresets)),
+	}
+	for name, preset := range builtinPresets {
+		reg.Agents[string(name)] = preset
+	}
+	for _, path := range []string{townPath, rigPath} {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from config
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var fileRegistry AgentRegistry
+		if err := json.Unmarshal(data, &fileRegistry); err != nil {
+			return nil, err
+		}
+		for name, preset := range fileRegistry.Agents {
+			preset.Name = AgentPreset(name)
+			reg.Agents[name] = preset
+		}
+	}
+	return reg, nil
+}
+
+// MatchPresets returns the names of the registry's presets that have all
+// the required capabilities, cheapest first (ties broken by name). Presets
+// that declare no capabilities never match, so routing is opt-in per preset.
+func (r *AgentRegistry) MatchPresets(required []string) []string {
+	type match struct {
+		name string
+		tier int
+	}
+	var matches []match
+	for name, p := range r.Agents {
+		if len(p.Capabilities) == 0 || !p.HasCapabilities(required) {
+			continue
+		}
+		matches = append(matches, match{name: name, tier: p.CostTier})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		ti, tj := matches[i].tier, matches[j].tier
+		if (ti == 0) != (tj == 0) {
+			return tj == 0 // unknown cost sorts last
+		}
+		if ti != tj {
+			return ti < tj
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.name
+	}
+	return names
+}
+
 // DefaultAgentPreset returns the default agent preset (Claude).
 func DefaultAgentPreset() AgentPreset {
 	return AgentClaude
@@ -484,6 +607,13 @@ func (rc *RuntimeConfig) MergeWithPreset(preset AgentPreset) *RuntimeConfig {
 	return result
 }
 
+// IsBuiltinPreset reports whether name is one of the built-in agent presets
+// rather than a user-defined agent.
+func IsBuiltinPreset(name string) bool {
+	_, ok := builtinPresets[AgentPreset(name)]
+	return ok
+}
+
 // IsKnownPreset checks if a string is a known agent preset name.
 func IsKnownPreset(name string) bool {
 	ensureRegistry()
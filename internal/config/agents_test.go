@@ -805,3 +805,86 @@ func TestOpenCodeRuntimeConfigFromPreset(t *testing.T) {
 		t.Error("Mutation of RuntimeConfig.Env affected original preset")
 	}
 }
+
+func TestRequiredCapabilities(t *testing.T) {
+	t.Parallel()
+	got := RequiredCapabilities([]string{"lang:rust", "gt:task", "needs:long-context", "needs:long-context", "needs:"})
+	want := []string{"lang:rust", "long-context"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("RequiredCapabilities() = %v, want %v", got, want)
+	}
+	if got := RequiredCapabilities([]string{"docs"}); len(got) != 0 {
+		t.Errorf("RequiredCapabilities(no routing labels) = %v, want none", got)
+	}
+}
+
+func TestSelectAgentForCapabilities(t *testing.T) {
+	townRoot := t.TempDir()
+	registry := AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Agents: map[string]*AgentPresetInfo{
+			"cheap":   {Command: "cheap-bin", Capabilities: []string{"docs"}, CostTier: 1},
+			"mid":     {Command: "mid-bin", Capabilities: []string{"docs", "lang:rust"}, CostTier: 2},
+			"strong":  {Command: "strong-bin", Capabilities: []string{"docs", "lang:rust", "long-context"}, CostTier: 3},
+			"unknown": {Command: "unknown-bin", Capabilities: []string{"docs", "lang:rust", "long-context"}},
+		},
+	}
+	if err := SaveAgentRegistry(DefaultAgentRegistryPath(townRoot), &registry); err != nil {
+		t.Fatal(err)
+	}
+	ResetRegistryForTesting()
+	defer ResetRegistryForTesting()
+
+	rigPath := filepath.Join(townRoot, "testrig")
+	tests := []struct {
+		required []string
+		want     string
+	}{
+		{nil, ""},
+		{[]string{"docs"}, "cheap"},
+		{[]string{"lang:rust"}, "mid"},
+		{[]string{"lang:rust", "long-context"}, "strong"},
+		{[]string{"gpu"}, ""},
+	}
+	for _, tt := range tests {
+		if got := SelectAgentForCapabilities(townRoot, rigPath, tt.required); got != tt.want {
+			t.Errorf("SelectAgentForCapabilities(%v) = %q, want %q", tt.required, got, tt.want)
+		}
+	}
+
+	// Unknown cost tier sorts after every priced match
+	reg, err := LoadRigAgentRegistryCopy(DefaultAgentRegistryPath(townRoot), RigAgentRegistryPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reg.MatchPresets([]string{"long-context"}); strings.Join(got, ",") != "strong,unknown" {
+		t.Errorf("MatchPresets(long-context) = %v, want [strong unknown]", got)
+	}
+}
+
+func TestSelectAgentForCapabilitiesPerRig(t *testing.T) {
+	townRoot := t.TempDir()
+	rigA := filepath.Join(townRoot, "rig-a")
+	rigB := filepath.Join(townRoot, "rig-b")
+	registry := AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Agents: map[string]*AgentPresetInfo{
+			"gpu-agent": {Command: "gpu-bin", Capabilities: []string{"gpu"}, CostTier: 1},
+		},
+	}
+	if err := SaveAgentRegistry(RigAgentRegistryPath(rigA), &registry); err != nil {
+		t.Fatal(err)
+	}
+	ResetRegistryForTesting()
+	defer ResetRegistryForTesting()
+
+	// Resolving rig A's agent loads its registry into the global one
+	_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigA))
+
+	if got := SelectAgentForCapabilities(townRoot, rigA, []string{"gpu"}); got != "gpu-agent" {
+		t.Errorf("SelectAgentForCapabilities(rig-a, gpu) = %q, want gpu-agent", got)
+	}
+	if got := SelectAgentForCapabilities(townRoot, rigB, []string{"gpu"}); got != "" {
+		t.Errorf("SelectAgentForCapabilities(rig-b, gpu) = %q, want none: rig-a's preset leaked", got)
+	}
+}
@@ -760,6 +760,93 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	return &settings, nil
 }
 
+// LoadResourceConfig returns the town's resource thresholds. Returns nil,
+// which disables the host resource checks, when town settings have no
+// resources section or can't be read.
+func LoadResourceConfig(townRoot string) *ResourceConfig {
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.Resources
+}
+
+// LoadCgroupConfig returns the session isolation settings for a rig (the
+// rig's own, else the town's), or for town-level agents when rigPath is
+// empty. Returns nil when isolation is not configured.
+func LoadCgroupConfig(townRoot, rigPath string) *CgroupConfig {
+	if rigPath != "" {
+		if settings, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil && settings.Cgroup != nil {
+			return settings.Cgroup
+		}
+	}
+	if settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil {
+		return settings.Cgroup
+	}
+	return nil
+}
+
+// LoadContainerConfig returns a rig's polecat container settings, or nil
+// when the rig doesn't run polecats in containers.
+func LoadContainerConfig(rigPath string) *ContainerConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Container
+}
+
+// LoadSandboxConfig returns a rig's polecat write confinement settings, or
+// nil when the rig doesn't confine polecats.
+func LoadSandboxConfig(rigPath string) *SandboxConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Sandbox
+}
+
+// LoadPushRules returns the push rules for an agent role (simple or compound
+// GT_ROLE form): the rig's own when rigPath is set and declares them, else
+// the defaults. Returns nil for roles that may push anywhere.
+func LoadPushRules(rigPath, role string) *PushRules {
+	role = extractSimpleRole(role)
+	if rigPath != "" {
+		if settings, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil && settings.PushPolicy != nil {
+			if rules, ok := settings.PushPolicy.Roles[role]; ok {
+				return rules
+			}
+		}
+	}
+	return DefaultPushRules()[role]
+}
+
+// LoadSecretGrants returns the secret grants for an agent role (simple or
+// compound GT_ROLE form): the town's grants, plus the rig's when rigPath is
+// set.
+func LoadSecretGrants(townRoot, rigPath, role string) []SecretGrant {
+	role = extractSimpleRole(role)
+	var all []SecretGrant
+	if settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil {
+		all = append(all, settings.Secrets...)
+	}
+	if rigPath != "" {
+		if settings, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil {
+			all = append(all, settings.Secrets...)
+		}
+	}
+	var grants []SecretGrant
+	for _, g := range all {
+		for _, r := range g.Roles {
+			if r == role {
+				grants = append(grants, g)
+				break
+			}
+		}
+	}
+	return grants
+}
+
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
 	if settings.Type != "town-settings" && settings.Type != "" {
@@ -902,6 +989,24 @@ func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*R
 	return lookupAgentConfig(agentName, townSettings, rigSettings), agentName, nil
 }
 
+// SelectAgentForCapabilities returns the cheapest agent preset, from the
+// built-in presets and the town and rig agent registries, that has every
+// required capability. Returns "" if nothing is required or nothing matches.
+// Only this rig's registry is consulted, never presets other rigs loaded.
+func SelectAgentForCapabilities(townRoot, rigPath string, required []string) string {
+	if len(required) == 0 {
+		return ""
+	}
+	reg, err := LoadRigAgentRegistryCopy(DefaultAgentRegistryPath(townRoot), RigAgentRegistryPath(rigPath))
+	if err != nil {
+		return ""
+	}
+	if matches := reg.MatchPresets(required); len(matches) > 0 {
+		return matches[0]
+	}
+	return ""
+}
+
 // ValidateAgentConfig checks if an agent configuration is valid and the binary exists.
 // Returns an error describing the issue, or nil if valid.
 func ValidateAgentConfig(agentName string, townSettings *TownSettings, rigSettings *RigSettings) error {
@@ -1077,6 +1182,13 @@ func lookupAgentConfig(name string, townSettings *TownSettings, rigSettings *Rig
 	return DefaultRuntimeConfig()
 }
 
+// LookupAgentConfig returns the runtime config for a built-in agent preset
+// with full defaults, without consulting town or rig settings. Unknown names
+// get the Claude defaults.
+func LookupAgentConfig(name string) *RuntimeConfig {
+	return lookupAgentConfig(name, nil, nil)
+}
+
 // fillRuntimeDefaults fills in default values for empty RuntimeConfig fields.
 // It creates a deep copy to prevent mutation of the original config.
 //
@@ -1117,8 +1229,9 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 	// Deep copy nested structs (nil checks prevent panic on access)
 	if rc.Session != nil {
 		result.Session = &RuntimeSessionConfig{
-			SessionIDEnv: rc.Session.SessionIDEnv,
-			ConfigDirEnv: rc.Session.ConfigDirEnv,
+			SessionIDEnv:  rc.Session.SessionIDEnv,
+			ConfigDirEnv:  rc.Session.ConfigDirEnv,
+			TranscriptDir: rc.Session.TranscriptDir,
 		}
 	}
 
@@ -1140,6 +1253,10 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 			result.Tmux.ProcessNames = make([]string, len(rc.Tmux.ProcessNames))
 			copy(result.Tmux.ProcessNames, rc.Tmux.ProcessNames)
 		}
+		if rc.Tmux.BlockingPrompts != nil {
+			result.Tmux.BlockingPrompts = make([]string, len(rc.Tmux.BlockingPrompts))
+			copy(result.Tmux.BlockingPrompts, rc.Tmux.BlockingPrompts)
+		}
 	}
 
 	if rc.Instructions != nil {
@@ -1305,6 +1422,25 @@ func extractSimpleRole(gtRole string) string {
 // (ResolveRoleAgentConfig) to select the appropriate agent for the role.
 // This enables per-role model selection via role_agents in settings.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
+	prefix, rc, _ := ResolveStartup(envVars, rigPath, "")
+	if prompt != "" {
+		return prefix + rc.BuildCommandWithPrompt(prompt)
+	}
+	return prefix + rc.BuildCommand()
+}
+
+// ResolveStartup resolves the runtime an agent starts with and returns it
+// with the prefix its command runs behind: the environment exports and, for
+// agents granted secrets, the secrets wrapper. Callers append the runtime's
+// start or resume command, e.g. from its agent driver.
+//
+// Resolution priority:
+//  1. agentOverride (explicit override)
+//  2. role_agents[GT_ROLE] (if GT_ROLE is in envVars)
+//  3. Default agent resolution (rig's Agent → town's DefaultAgent → "claude")
+//
+// rigPath is optional - if empty, tries to detect town root from cwd.
+func ResolveStartup(envVars map[string]string, rigPath, agentOverride string) (string, *RuntimeConfig, error) {
 	var rc *RuntimeConfig
 	var townRoot string
 
@@ -1316,26 +1452,24 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	if rigPath != "" {
 		// Derive town root from rig path
 		townRoot = filepath.Dir(rigPath)
-		if role != "" {
-			// Use role-based agent resolution for per-role model selection
-			rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
-		} else {
-			rc = ResolveAgentConfig(townRoot, rigPath)
-		}
-	} else {
-		// Try to detect town root from cwd for town-level agents (mayor, deacon)
+	} else if root, err := findTownRootFromCwd(); err == nil {
+		// Town-level agents (mayor, deacon) find the town from cwd
+		townRoot = root
+	}
+	switch {
+	case townRoot == "":
+		rc = DefaultRuntimeConfig()
+	case agentOverride != "":
 		var err error
-		townRoot, err = findTownRootFromCwd()
+		rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
 		if err != nil {
-			rc = DefaultRuntimeConfig()
-		} else {
-			if role != "" {
-				// Use role-based agent resolution for per-role model selection
-				rc = ResolveRoleAgentConfig(role, townRoot, "")
-			} else {
-				rc = ResolveAgentConfig(townRoot, "")
-			}
+			return "", nil, err
 		}
+	case role != "":
+		// Use role-based agent resolution for per-role model selection
+		rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
+	default:
+		rc = ResolveAgentConfig(townRoot, rigPath)
 	}
 
 	// Copy env vars to avoid mutating caller map
@@ -1350,6 +1484,10 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
 		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
 	}
+	// Record agent override so handoff can preserve it
+	if agentOverride != "" {
+		resolvedEnv["GT_AGENT"] = agentOverride
+	}
 	// Merge agent-specific env vars (e.g., OPENCODE_PERMISSION for yolo mode)
 	for k, v := range rc.Env {
 		resolvedEnv[k] = v
@@ -1364,23 +1502,27 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	// Sort for deterministic output
 	sort.Strings(exports)
 
-	var cmd string
+	var prefix string
 	if len(exports) > 0 {
 		// Use 'exec env' instead of 'export ... &&' so the agent process
 		// replaces the shell. This allows WaitForCommand to detect the
 		// running agent via pane_current_command (which shows the direct
 		// process, not child processes).
-		cmd = "exec env " + strings.Join(exports, " ") + " "
+		prefix = "exec env " + strings.Join(exports, " ") + " "
 	}
+	prefix += secretsExecPrefix(townRoot, rigPath, role)
 
-	// Add runtime command
-	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
-	} else {
-		cmd += rc.BuildCommand()
-	}
+	return prefix, rc, nil
+}
 
-	return cmd
+// secretsExecPrefix returns the "gt secrets exec" wrapper for agents that are
+// granted secrets. It issues them to the session and execs the agent, so
+// secret values never appear on a command line.
+func secretsExecPrefix(townRoot, rigPath, role string) string {
+	if townRoot == "" || role == "" || len(LoadSecretGrants(townRoot, rigPath, role)) == 0 {
+		return ""
+	}
+	return "gt secrets exec -- "
 }
 
 // PrependEnv prepends export statements to a command string.
@@ -1407,91 +1549,14 @@ func PrependEnv(command string, envVars map[string]string) string {
 //  2. role_agents[GT_ROLE] (if GT_ROLE is in envVars)
 //  3. Default agent resolution (rig's Agent → town's DefaultAgent → "claude")
 func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, prompt, agentOverride string) (string, error) {
-	var rc *RuntimeConfig
-	var townRoot string
-
-	// Extract role from envVars for role-based agent resolution (when no override)
-	role := envVars["GT_ROLE"]
-
-	if rigPath != "" {
-		townRoot = filepath.Dir(rigPath)
-		if agentOverride != "" {
-			var err error
-			rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
-			if err != nil {
-				return "", err
-			}
-		} else if role != "" {
-			// No override, use role-based agent resolution
-			rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
-		} else {
-			rc = ResolveAgentConfig(townRoot, rigPath)
-		}
-	} else {
-		var err error
-		townRoot, err = findTownRootFromCwd()
-		if err != nil {
-			rc = DefaultRuntimeConfig()
-		} else {
-			if agentOverride != "" {
-				var resolveErr error
-				rc, _, resolveErr = ResolveAgentConfigWithOverride(townRoot, "", agentOverride)
-				if resolveErr != nil {
-					return "", resolveErr
-				}
-			} else if role != "" {
-				// No override, use role-based agent resolution
-				rc = ResolveRoleAgentConfig(role, townRoot, "")
-			} else {
-				rc = ResolveAgentConfig(townRoot, "")
-			}
-		}
-	}
-
-	// Copy env vars to avoid mutating caller map
-	resolvedEnv := make(map[string]string, len(envVars)+2)
-	for k, v := range envVars {
-		resolvedEnv[k] = v
-	}
-	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
-	if townRoot != "" {
-		resolvedEnv["GT_ROOT"] = townRoot
-	}
-	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
-		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
-	}
-	// Record agent override so handoff can preserve it
-	if agentOverride != "" {
-		resolvedEnv["GT_AGENT"] = agentOverride
-	}
-	// Merge agent-specific env vars (e.g., OPENCODE_PERMISSION for yolo mode)
-	for k, v := range rc.Env {
-		resolvedEnv[k] = v
-	}
-
-	// Build environment export prefix
-	var exports []string
-	for k, v := range resolvedEnv {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, ShellQuote(v)))
-	}
-	sort.Strings(exports)
-
-	var cmd string
-	if len(exports) > 0 {
-		// Use 'exec env' instead of 'export ... &&' so the agent process
-		// replaces the shell. This allows WaitForCommand to detect the
-		// running agent via pane_current_command (which shows the direct
-		// process, not child processes).
-		cmd = "exec env " + strings.Join(exports, " ") + " "
+	prefix, rc, err := ResolveStartup(envVars, rigPath, agentOverride)
+	if err != nil {
+		return "", err
 	}
-
 	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
-	} else {
-		cmd += rc.BuildCommand()
+		return prefix + rc.BuildCommandWithPrompt(prompt), nil
 	}
-
-	return cmd, nil
+	return prefix + rc.BuildCommand(), nil
 }
 
 // BuildAgentStartupCommand is a convenience function for starting agent sessions.
@@ -1521,34 +1586,32 @@ func BuildAgentStartupCommandWithAgentOverride(role, rig, townRoot, rigPath, pro
 // Sets GT_ROLE, GT_RIG, GT_POLECAT, BD_ACTOR, GIT_AUTHOR_NAME, and GT_ROOT.
 // workDir is the polecat's working directory, used to check for beads redirects.
 func BuildPolecatStartupCommand(rigName, polecatName, rigPath, prompt, workDir string) string {
-	var townRoot string
-	if rigPath != "" {
-		townRoot = filepath.Dir(rigPath)
-	}
-	envVars := AgentEnv(AgentEnvConfig{
-		Role:      "polecat",
-		Rig:       rigName,
-		AgentName: polecatName,
-		TownRoot:  townRoot,
-		WorkDir:   workDir,
-	})
-	return BuildStartupCommand(envVars, rigPath, prompt)
+	return BuildStartupCommand(polecatEnv(rigName, polecatName, rigPath, workDir), rigPath, prompt)
 }
 
 // BuildPolecatStartupCommandWithAgentOverride is like BuildPolecatStartupCommand, but uses agentOverride if non-empty.
 func BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, rigPath, prompt, agentOverride, workDir string) (string, error) {
+	return BuildStartupCommandWithAgentOverride(polecatEnv(rigName, polecatName, rigPath, workDir), rigPath, prompt, agentOverride)
+}
+
+// ResolvePolecatStartup is ResolveStartup for a polecat, with the
+// environment BuildPolecatStartupCommand sets.
+func ResolvePolecatStartup(rigName, polecatName, rigPath, agentOverride, workDir string) (string, *RuntimeConfig, error) {
+	return ResolveStartup(polecatEnv(rigName, polecatName, rigPath, workDir), rigPath, agentOverride)
+}
+
+func polecatEnv(rigName, polecatName, rigPath, workDir string) map[string]string {
 	var townRoot string
 	if rigPath != "" {
 		townRoot = filepath.Dir(rigPath)
 	}
-	envVars := AgentEnv(AgentEnvConfig{
+	return AgentEnv(AgentEnvConfig{
 		Role:      "polecat",
 		Rig:       rigName,
 		AgentName: polecatName,
 		TownRoot:  townRoot,
 		WorkDir:   workDir,
 	})
-	return BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agentOverride)
 }
 
 // BuildCrewStartupCommand builds the startup command for a crew member.
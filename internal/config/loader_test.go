@@ -3523,3 +3523,105 @@ func TestBuildStartupCommandWithAgentOverride_NoGTAgentWhenNoOverride(t *testing
 		t.Errorf("expected no GT_AGENT in command when no override, got: %q", cmd)
 	}
 }
+
+func TestBuildStartupCommand_SecretsExecForGrantedRoles(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.Secrets = []SecretGrant{{Secret: "github-token", Roles: []string{"refinery"}, Env: "GITHUB_TOKEN_FILE"}}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	rigSettings := NewRigSettings()
+	rigSettings.Secrets = []SecretGrant{{Secret: "npm-token", Roles: []string{"polecat", "refinery"}, Env: "NPM_TOKEN", Inline: true}}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	if got := LoadSecretGrants(townRoot, rigPath, "testrig/refinery"); len(got) != 2 {
+		t.Errorf("LoadSecretGrants(refinery) = %+v, want town and rig grants", got)
+	}
+	if got := LoadSecretGrants(townRoot, rigPath, "testrig/polecats/Toast"); len(got) != 1 || got[0].Secret != "npm-token" {
+		t.Errorf("LoadSecretGrants(polecat) = %+v, want the rig grant", got)
+	}
+	if got := LoadSecretGrants(townRoot, "", "mayor"); len(got) != 0 {
+		t.Errorf("LoadSecretGrants(mayor) = %+v, want none", got)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/refinery"}, rigPath, "")
+	if !strings.Contains(cmd, " gt secrets exec -- ") {
+		t.Errorf("granted role's command lacks gt secrets exec: %q", cmd)
+	}
+	cmd = BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/witness"}, rigPath, "")
+	if strings.Contains(cmd, "gt secrets exec") {
+		t.Errorf("ungranted role's command has gt secrets exec: %q", cmd)
+	}
+}
+
+func TestLoadTownSettingsPartialScheduler(t *testing.T) {
+	t.Parallel()
+	settingsPath := filepath.Join(t.TempDir(), "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "town-settings", "version": 1, "scheduler": {"age_weight": 0, "rig_weights": {"gastown": 2}}}`
+	if err := os.WriteFile(settingsPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings: %v", err)
+	}
+	got := settings.Scheduler
+	if got == nil {
+		t.Fatal("Scheduler is nil")
+	}
+	want := DefaultSchedulerConfig()
+	if got.PriorityWeight != want.PriorityWeight || got.ConvoyAgeWeight != want.ConvoyAgeWeight {
+		t.Errorf("unset weights = %v/%v, want defaults %v/%v",
+			got.PriorityWeight, got.ConvoyAgeWeight, want.PriorityWeight, want.ConvoyAgeWeight)
+	}
+	if got.Policy != want.Policy || got.MaxDispatch != want.MaxDispatch {
+		t.Errorf("Policy/MaxDispatch = %q/%d, want defaults %q/%d", got.Policy, got.MaxDispatch, want.Policy, want.MaxDispatch)
+	}
+	if got.AgeWeight != 0 {
+		t.Errorf("AgeWeight = %v, want explicit 0", got.AgeWeight)
+	}
+	if got.RigWeight("gastown") != 2 {
+		t.Errorf("RigWeight(gastown) = %v, want 2", got.RigWeight("gastown"))
+	}
+}
+
+func TestLoadResourceConfigOptIn(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	if cfg := LoadResourceConfig(townRoot); cfg != nil {
+		t.Errorf("LoadResourceConfig() without a resources section = %+v, want nil", cfg)
+	}
+
+	settings := NewTownSettings()
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "{", `{"resources": {"min_disk_gb": 0},`, 1))
+	if err := os.WriteFile(TownSettingsPath(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := LoadResourceConfig(townRoot)
+	if cfg == nil {
+		t.Fatal("LoadResourceConfig() with a resources section = nil")
+	}
+	if want := DefaultResourceConfig(); cfg.MinMemoryMB != want.MinMemoryMB || cfg.MaxLoadPerCPU != want.MaxLoadPerCPU {
+		t.Errorf("unset thresholds = %+v, want defaults %+v", cfg, want)
+	}
+	if cfg.MinDiskGB != 0 {
+		t.Errorf("MinDiskGB = %v, want explicit 0", cfg.MinDiskGB)
+	}
+}
@@ -2,9 +2,11 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -68,6 +70,24 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Scheduler configures the town-wide work scheduler (gt schedule).
+	// If nil, DefaultSchedulerConfig() is used.
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+
+	// Resources sets the host resource thresholds below which polecat
+	// spawns are deferred. If nil, spawns are not checked against host
+	// resources; fields left out of the section get DefaultResourceConfig().
+	Resources *ResourceConfig `json:"resources,omitempty"`
+
+	// Cgroup sets resource limits for town-level agent sessions (dogs) and
+	// the default for rigs that don't set their own. If nil, sessions are
+	// not isolated.
+	Cgroup *CgroupConfig `json:"cgroup,omitempty"`
+
+	// Secrets grants secrets from the town store (gt secrets) to agent
+	// roles across all rigs. Rigs add their own grants.
+	Secrets []SecretGrant `json:"secrets,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -232,6 +252,15 @@ type RigSettings struct {
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
+	Checkpoint *CheckpointConfig `json:"checkpoint,omitempty"`  // witness checkpoint settings
+	WarmPool   *WarmPoolConfig   `json:"warm_pool,omitempty"`   // pre-created polecat worktrees
+	Autoscale  *AutoscaleConfig  `json:"autoscale,omitempty"`   // polecat autoscaling
+	Cgroup     *CgroupConfig     `json:"cgroup,omitempty"`      // per-session resource limits
+	Recording  *RecordingConfig  `json:"recording,omitempty"`   // polecat pane recordings
+	Container  *ContainerConfig  `json:"container,omitempty"`   // polecat container isolation
+	Sandbox    *SandboxConfig    `json:"sandbox,omitempty"`     // polecat write confinement
+	Secrets    []SecretGrant     `json:"secrets,omitempty"`     // secrets for the rig's agents
+	PushPolicy *PushPolicyConfig `json:"push_policy,omitempty"` // branches each role may push
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
@@ -322,6 +351,11 @@ type RuntimeSessionConfig struct {
 	// ConfigDirEnv is the environment variable that selects a runtime account/config dir.
 	// Default: "CLAUDE_CONFIG_DIR" for claude, empty for codex/generic.
 	ConfigDirEnv string `json:"config_dir_env,omitempty"`
+
+	// TranscriptDir is where the runtime writes session transcripts (*.jsonl),
+	// for archiving when a session ends. Claude and Codex locations are known;
+	// other runtimes need this set. "~/" expands to the home directory.
+	TranscriptDir string `json:"transcript_dir,omitempty"`
 }
 
 // RuntimeHooksConfig configures runtime hook installation.
@@ -346,6 +380,12 @@ type RuntimeTmuxConfig struct {
 
 	// ReadyDelayMs is a fixed delay used when prompt detection is unavailable.
 	ReadyDelayMs int `json:"ready_delay_ms,omitempty"`
+
+	// BlockingPrompts are pane text fragments that mean the runtime is
+	// waiting on input it will not get by itself (a permission question,
+	// "press enter"). Used by stuck detection (gt deacon diagnose). Nil
+	// means the provider defaults (see RuntimeConfig.BlockingPrompts).
+	BlockingPrompts []string `json:"blocking_prompts,omitempty"`
 }
 
 // RuntimeInstructionsConfig controls the name of the role instruction file.
@@ -616,6 +656,45 @@ func defaultReadyPromptPrefix(provider string) string {
 	return ""
 }
 
+// genericBlockingPrompts are input prompts any CLI may stop at.
+var genericBlockingPrompts = []string{
+	"Press Enter to continue",
+	"Press any key",
+	"(y/n)",
+	"[y/N]",
+	"[Y/n]",
+	"(yes/no)",
+}
+
+func defaultBlockingPrompts(provider string) []string {
+	prompts := append([]string{}, genericBlockingPrompts...)
+	switch provider {
+	case "claude":
+		prompts = append(prompts,
+			"Do you want to proceed?",
+			"Do you want to make this edit",
+			"Do you want to create",
+			"Bypass Permissions mode",
+		)
+	case "codex":
+		prompts = append(prompts, "Allow command?")
+	}
+	return prompts
+}
+
+// BlockingPrompts returns the runtime's blocking prompt fragments, falling
+// back to the provider defaults when none are configured.
+func (rc *RuntimeConfig) BlockingPrompts() []string {
+	if rc.Tmux != nil && rc.Tmux.BlockingPrompts != nil {
+		return rc.Tmux.BlockingPrompts
+	}
+	provider := rc.Provider
+	if provider == "" {
+		provider = "claude"
+	}
+	return defaultBlockingPrompts(provider)
+}
+
 func defaultReadyDelayMs(provider string) int {
 	if provider == "claude" {
 		return 10000
@@ -779,6 +858,472 @@ func DefaultNamepoolConfig() *NamepoolConfig {
 	}
 }
 
+// CheckpointConfig represents witness checkpointing settings for a rig.
+// The witness patrol snapshots each working polecat's checkpoint on this
+// cadence (and on commits and step closes), keeping a bounded history.
+type CheckpointConfig struct {
+	// Interval is the maximum time between snapshots (e.g., "5m").
+	// Default is 5m.
+	Interval string `json:"interval,omitempty"`
+
+	// Keep is how many snapshots to retain per polecat. Default is 20.
+	Keep int `json:"keep,omitempty"`
+}
+
+// DefaultCheckpointConfig returns a CheckpointConfig with sensible defaults.
+func DefaultCheckpointConfig() *CheckpointConfig {
+	return &CheckpointConfig{
+		Interval: "5m",
+		Keep:     20,
+	}
+}
+
+// IntervalDuration returns the snapshot interval, falling back to the
+// default for empty or invalid values.
+func (c *CheckpointConfig) IntervalDuration() time.Duration {
+	if c != nil && c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	d, _ := time.ParseDuration(DefaultCheckpointConfig().Interval)
+	return d
+}
+
+// KeepCount returns the number of snapshots to retain, falling back to the
+// default when unset.
+func (c *CheckpointConfig) KeepCount() int {
+	if c != nil && c.Keep > 0 {
+		return c.Keep
+	}
+	return DefaultCheckpointConfig().Keep
+}
+
+// WarmPoolConfig represents the warm polecat pool for a rig: worktrees
+// created ahead of time, with setup hooks already run, that slings claim
+// instead of building a worktree from scratch.
+type WarmPoolConfig struct {
+	// Size is how many ready worktrees to keep. Zero (default) disables the pool.
+	Size int `json:"size,omitempty"`
+
+	// RefreshInterval is how stale an idle worktree may get before it is
+	// moved to the latest default branch and its setup hooks rerun
+	// (e.g., "30m"). Default is 30m.
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+}
+
+// DefaultWarmPoolConfig returns a WarmPoolConfig with sensible defaults.
+func DefaultWarmPoolConfig() *WarmPoolConfig {
+	return &WarmPoolConfig{
+		Size:            0,
+		RefreshInterval: "30m",
+	}
+}
+
+// PoolSize returns the configured pool size, or 0 when the pool is disabled.
+func (c *WarmPoolConfig) PoolSize() int {
+	if c != nil && c.Size > 0 {
+		return c.Size
+	}
+	return 0
+}
+
+// RefreshDuration returns the refresh interval, falling back to the
+// default for empty or invalid values.
+func (c *WarmPoolConfig) RefreshDuration() time.Duration {
+	if c != nil && c.RefreshInterval != "" {
+		if d, err := time.ParseDuration(c.RefreshInterval); err == nil && d > 0 {
+			return d
+		}
+	}
+	d, _ := time.ParseDuration(DefaultWarmPoolConfig().RefreshInterval)
+	return d
+}
+
+// AutoscaleConfig represents polecat autoscaling for a rig. When enabled,
+// the daemon slings ready beads to new polecats as work piles up, and holds
+// off while the merge queue is saturated. Polecats exit on their own when
+// their work is done, so scaling down needs no action.
+type AutoscaleConfig struct {
+	// Enabled turns autoscaling on for the rig. Default false.
+	Enabled bool `json:"enabled"`
+
+	// MinPolecats keeps at least this many polecats working while there is
+	// ready work, ignoring the cooldown. Default 0.
+	MinPolecats int `json:"min_polecats,omitempty"`
+
+	// MaxPolecats caps autoscaled polecats. The rig's max_polecats still
+	// applies; the lower of the two wins. 0 means max_polecats alone.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+
+	// ReadyPerPolecat is how many waiting ready beads justify one more
+	// polecat. Default 1.
+	ReadyPerPolecat int `json:"ready_per_polecat,omitempty"`
+
+	// MaxStep is the most polecats spawned in one decision. Default 2.
+	MaxStep int `json:"max_step,omitempty"`
+
+	// MaxMergeQueue is the open merge request count at which the refinery
+	// counts as saturated and spawning stops (back-pressure). Default 5.
+	MaxMergeQueue int `json:"max_merge_queue,omitempty"`
+
+	// Cooldown is the minimum time between scale-ups (e.g., "5m"). Default 5m.
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+// DefaultAutoscaleConfig returns an AutoscaleConfig with sensible defaults.
+func DefaultAutoscaleConfig() *AutoscaleConfig {
+	return &AutoscaleConfig{
+		Enabled:         false,
+		ReadyPerPolecat: 1,
+		MaxStep:         2,
+		MaxMergeQueue:   5,
+		Cooldown:        "5m",
+	}
+}
+
+// CooldownDuration returns the scale-up cooldown, falling back to the
+// default for empty or invalid values.
+func (c *AutoscaleConfig) CooldownDuration() time.Duration {
+	if c != nil && c.Cooldown != "" {
+		if d, err := time.ParseDuration(c.Cooldown); err == nil && d >= 0 {
+			return d
+		}
+	}
+	d, _ := time.ParseDuration(DefaultAutoscaleConfig().Cooldown)
+	return d
+}
+
+// ReadyPerPolecatOrDefault returns ReadyPerPolecat, or the default when unset.
+func (c *AutoscaleConfig) ReadyPerPolecatOrDefault() int {
+	if c != nil && c.ReadyPerPolecat > 0 {
+		return c.ReadyPerPolecat
+	}
+	return DefaultAutoscaleConfig().ReadyPerPolecat
+}
+
+// MaxStepOrDefault returns MaxStep, or the default when unset.
+func (c *AutoscaleConfig) MaxStepOrDefault() int {
+	if c != nil && c.MaxStep > 0 {
+		return c.MaxStep
+	}
+	return DefaultAutoscaleConfig().MaxStep
+}
+
+// MaxMergeQueueOrDefault returns MaxMergeQueue, or the default when unset.
+func (c *AutoscaleConfig) MaxMergeQueueOrDefault() int {
+	if c != nil && c.MaxMergeQueue > 0 {
+		return c.MaxMergeQueue
+	}
+	return DefaultAutoscaleConfig().MaxMergeQueue
+}
+
+// Scheduler policies.
+const (
+	// SchedulerPolicyFairShare dispatches to the rig using the least of its
+	// weighted share of capacity, then by bead score within that rig.
+	SchedulerPolicyFairShare = "fair_share"
+
+	// SchedulerPolicyPriority dispatches the highest-scoring bead in town
+	// regardless of which rig it belongs to.
+	SchedulerPolicyPriority = "priority"
+)
+
+// SchedulerConfig represents town-wide work scheduler settings.
+// The scheduler pulls ready beads from every rig and slings them onto free
+// polecat capacity. Fields missing from the section keep their defaults, so
+// a weight is zero only when written as 0.
+type SchedulerConfig struct {
+	// Policy is "fair_share" (default) or "priority".
+	Policy string `json:"policy,omitempty"`
+
+	// PriorityWeight is multiplied by (4 - priority) so P0 scores highest.
+	PriorityWeight float64 `json:"priority_weight"`
+
+	// AgeWeight is points added per hour since the bead was created.
+	AgeWeight float64 `json:"age_weight"`
+
+	// ConvoyAgeWeight is points added per hour of age of the convoy
+	// tracking the bead, so old convoys are not starved.
+	ConvoyAgeWeight float64 `json:"convoy_age_weight"`
+
+	// RigWeights sets each rig's share of capacity under fair_share.
+	// Rigs not listed have weight 1. A weight of 0 excludes the rig.
+	RigWeights map[string]float64 `json:"rig_weights,omitempty"`
+
+	// RigQuotas caps how many polecats the scheduler fills a rig to,
+	// below its max_polecats. Rigs not listed are bounded by max_polecats only.
+	RigQuotas map[string]int `json:"rig_quotas,omitempty"`
+
+	// MaxDispatch is the most beads slung per scheduler run. Default is 5.
+	MaxDispatch int `json:"max_dispatch,omitempty"`
+}
+
+// DefaultSchedulerConfig returns a SchedulerConfig with sensible defaults.
+// The priority and convoy age weights match the refinery's MR scoring.
+func DefaultSchedulerConfig() *SchedulerConfig {
+	return &SchedulerConfig{
+		Policy:          SchedulerPolicyFairShare,
+		PriorityWeight:  100.0,
+		AgeWeight:       1.0,
+		ConvoyAgeWeight: 10.0,
+		MaxDispatch:     5,
+	}
+}
+
+// UnmarshalJSON decodes a scheduler section over DefaultSchedulerConfig(),
+// so a partial section overrides only the fields it sets.
+func (c *SchedulerConfig) UnmarshalJSON(data []byte) error {
+	type plain SchedulerConfig // without this method
+	cfg := plain(*DefaultSchedulerConfig())
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	*c = SchedulerConfig(cfg)
+	return nil
+}
+
+// RigWeight returns the fair-share weight for a rig.
+func (c *SchedulerConfig) RigWeight(rigName string) float64 {
+	if w, ok := c.RigWeights[rigName]; ok {
+		return w
+	}
+	return 1.0
+}
+
+// ResourceConfig sets host resource thresholds for spawning polecats.
+// While any threshold is crossed the host counts as saturated: slings queue
+// instead of spawning, and the scheduler and autoscaler hold. A zero value
+// disables that check. Checks are opt-in: they apply only when the town
+// settings have a "resources" section.
+type ResourceConfig struct {
+	// MinMemoryMB is the available memory required to spawn.
+	MinMemoryMB int `json:"min_memory_mb"`
+
+	// MaxLoadPerCPU is the highest 1-minute load average per CPU at which
+	// spawning is allowed.
+	MaxLoadPerCPU float64 `json:"max_load_per_cpu"`
+
+	// MinDiskGB is the free space required on the filesystem holding the
+	// rig's worktrees.
+	MinDiskGB float64 `json:"min_disk_gb"`
+}
+
+// CgroupConfig isolates each agent session in its own cgroup (a transient
+// systemd scope) with resource limits, so a runaway process in one polecat
+// cannot take down the rest of the town. It needs Linux with cgroup v2 and a
+// systemd user manager; elsewhere sessions run unconfined.
+type CgroupConfig struct {
+	// Enabled turns isolation on.
+	Enabled bool `json:"enabled"`
+
+	// MemoryMax is the memory limit in systemd syntax (e.g., "4G", "50%").
+	// Empty means no limit.
+	MemoryMax string `json:"memory_max,omitempty"`
+
+	// CPUQuota is the CPU limit in systemd syntax (e.g., "200%" for two
+	// CPUs). Empty means no limit.
+	CPUQuota string `json:"cpu_quota,omitempty"`
+
+	// TasksMax is the most processes and threads a session may run.
+	// Zero means no limit.
+	TasksMax int `json:"tasks_max,omitempty"`
+}
+
+// RecordingConfig controls asciicast recordings of polecat panes (replayed
+// with gt replay). Recordings older than the KRC "session_recording" TTL are
+// pruned by the daemon.
+type RecordingConfig struct {
+	// Enabled turns recording on for the rig's polecat sessions.
+	Enabled bool `json:"enabled"`
+
+	// MaxFileMB is the size at which a recording rotates to a new file.
+	// Default is 16.
+	MaxFileMB int `json:"max_file_mb,omitempty"`
+
+	// MaxFiles is how many recording files each polecat keeps; the oldest
+	// are deleted first. Default is 50.
+	MaxFiles int `json:"max_files,omitempty"`
+}
+
+// ContainerConfig runs each polecat's agent in an OCI container (podman or
+// docker) that sees only the polecat's directory, the shared beads and the
+// git objects its worktree needs. The session itself stays in tmux, so
+// nudge, peek and attach work as usual.
+type ContainerConfig struct {
+	// Enabled turns container isolation on for the rig's polecats.
+	Enabled bool `json:"enabled"`
+
+	// Runtime is "podman" or "docker". Empty uses podman if installed,
+	// else docker.
+	Runtime string `json:"runtime,omitempty"`
+
+	// Image is the container image. It must provide the agent runtime
+	// (e.g., claude) and git; on Linux hosts gt and bd are mounted in.
+	Image string `json:"image"`
+
+	// Network is passed to --network. "none" disables networking; empty
+	// uses the runtime's default.
+	Network string `json:"network,omitempty"`
+
+	// Env lists host environment variables passed into the container
+	// (e.g., "ANTHROPIC_API_KEY"). No other host variable is.
+	Env []string `json:"env,omitempty"`
+
+	// Mounts lists extra host paths (e.g., a credentials dir) mounted at
+	// the same path. Read-only unless suffixed with ":rw".
+	Mounts []string `json:"mounts,omitempty"`
+}
+
+// PushPolicyConfig declares, per agent role, the branches the role's agents
+// may push to. Roles it doesn't list keep the defaults (DefaultPushRules);
+// roles without rules at all (crew, mayor, humans) may push anywhere.
+type PushPolicyConfig struct {
+	// Roles maps a role (polecat, refinery, witness, crew, ...) to its rules.
+	Roles map[string]*PushRules `json:"roles"`
+}
+
+// PushRules are the branches one role may push to, as patterns where "*"
+// matches anything (including "/"), "{name}" is the agent's name,
+// "{default}" the rig's target branch and "{branch}" the rig's
+// polecat_branch_template (see WithBranchTemplate).
+type PushRules struct {
+	// Push lists branches the role may fast-forward.
+	Push []string `json:"push"`
+
+	// ForcePush lists branches the role may also rewrite.
+	ForcePush []string `json:"force_push,omitempty"`
+
+	// Delete lists branches the role may delete.
+	Delete []string `json:"delete,omitempty"`
+}
+
+// DefaultPushRules returns the built-in rules: polecats push only their own
+// branches (and beads sync), the refinery only the target and integration
+// branches, deleting the polecat branches it has merged. A polecat's own
+// branches are those named by the default layout or, if the rig sets one,
+// by its polecat_branch_template.
+func DefaultPushRules() map[string]*PushRules {
+	own := []string{"polecat/{name}", "polecat/{name}/*", "polecat/{name}-*", "{branch}"}
+	return map[string]*PushRules{
+		"polecat": {
+			Push:      append(own, "beads-sync"),
+			ForcePush: own,
+			Delete:    own,
+		},
+		"refinery": {
+			Push:   []string{"{default}", "integration/*"},
+			Delete: []string{"polecat/*", "integration/*"},
+		},
+	}
+}
+
+// templateVarPattern matches a polecat_branch_template variable.
+var templateVarPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
+// WithBranchTemplate returns the rules with "{branch}" replaced by the
+// branches a rig's polecat_branch_template names: every variable but
+// "{name}" becomes "*", so "adam/{year}/{month}/{description}" allows
+// "adam/*/*/*". Patterns using "{branch}" are dropped when template is
+// empty, the rig then using the default layout.
+func (r *PushRules) WithBranchTemplate(template string) *PushRules {
+	if r == nil {
+		return nil
+	}
+	branch := templateVarPattern.ReplaceAllStringFunc(template, func(v string) string {
+		if v == "{name}" {
+			return v
+		}
+		return "*"
+	})
+	expand := func(patterns []string) []string {
+		var out []string
+		for _, p := range patterns {
+			if strings.Contains(p, "{branch}") {
+				if template == "" {
+					continue
+				}
+				p = strings.ReplaceAll(p, "{branch}", branch)
+			}
+			out = append(out, p)
+		}
+		return out
+	}
+	return &PushRules{Push: expand(r.Push), ForcePush: expand(r.ForcePush), Delete: expand(r.Delete)}
+}
+
+// SandboxConfig confines each polecat's agent, and every process it starts,
+// to writing within its own directory plus the shared paths agents need
+// (beads, the rig's git objects, temp and tool caches). Linux only, using
+// Landlock (kernel 5.13+); elsewhere it has no effect.
+type SandboxConfig struct {
+	// Enabled turns write confinement on for the rig's polecats.
+	Enabled bool `json:"enabled"`
+
+	// Writable lists extra paths the agent may write beneath (e.g., a
+	// language toolchain cache). A leading "~/" is the user's home.
+	Writable []string `json:"writable,omitempty"`
+}
+
+// SecretGrant gives agent roles a secret from the town's encrypted store
+// (gt secrets). Sessions of those roles receive it when they start; other
+// sessions aren't issued it, though without container isolation nothing
+// stops an agent from reading the store itself.
+type SecretGrant struct {
+	// Secret is the name in the store (e.g., "github-token").
+	Secret string `json:"secret"`
+
+	// Roles receiving the secret: polecat, crew, witness, refinery, or the
+	// town roles mayor, deacon, boot.
+	Roles []string `json:"roles"`
+
+	// Env is the variable the session sees. It holds the path of a file
+	// containing the secret (e.g., GITHUB_TOKEN_FILE) unless Inline is set.
+	Env string `json:"env"`
+
+	// Inline puts the secret itself in Env, for tools that only read it
+	// from the environment (e.g., GH_TOKEN).
+	Inline bool `json:"inline,omitempty"`
+}
+
+// MaxFileBytes returns the rotation size in bytes.
+func (c *RecordingConfig) MaxFileBytes() int64 {
+	if c != nil && c.MaxFileMB > 0 {
+		return int64(c.MaxFileMB) * 1024 * 1024
+	}
+	return 16 * 1024 * 1024
+}
+
+// MaxFilesOrDefault returns how many recordings each polecat keeps.
+func (c *RecordingConfig) MaxFilesOrDefault() int {
+	if c != nil && c.MaxFiles > 0 {
+		return c.MaxFiles
+	}
+	return 50
+}
+
+// DefaultResourceConfig returns thresholds that only trip on a host that is
+// genuinely out of room. They fill in fields a "resources" section leaves out.
+func DefaultResourceConfig() *ResourceConfig {
+	return &ResourceConfig{
+		MinMemoryMB:   1024,
+		MaxLoadPerCPU: 2.0,
+		MinDiskGB:     2,
+	}
+}
+
+// UnmarshalJSON decodes a resources section over DefaultResourceConfig(),
+// so "resources": {} turns the checks on with the default thresholds.
+func (c *ResourceConfig) UnmarshalJSON(data []byte) error {
+	type plain ResourceConfig // without this method
+	cfg := plain(*DefaultResourceConfig())
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	*c = ResourceConfig(cfg)
+	return nil
+}
+
 // AccountsConfig represents Claude Code account configuration (mayor/accounts.json).
 // This enables Gas Town to manage multiple Claude Code accounts with easy switching.
 type AccountsConfig struct {
@@ -0,0 +1,243 @@
+// Package container runs polecat agent sessions inside an OCI container
+// (podman or docker). The session's startup command is wrapped in a
+// "run -it" of the rig's image, so the agent still lives in a tmux pane and
+// is driven the same way, but sees only its own polecat directory, the
+// shared beads, and the host environment variables the rig allowlists.
+package container
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// EnvVar is the session environment variable naming the container runtime
+// a session's agent runs under. The agent isn't a child of the pane then,
+// so liveness checks look for the runtime's client instead. It is set in
+// the container too, so gt knows it can't reach tmux from there.
+const EnvVar = "GT_CONTAINER"
+
+// binDir is where host tools are mounted inside the container.
+const binDir = "/usr/local/bin"
+
+// runtimes are the supported container runtimes, in order of preference.
+var runtimes = []string{"podman", "docker"}
+
+// Spec describes one polecat's container.
+type Spec struct {
+	Name    string   // Container name: the tmux session
+	HomeDir string   // Polecat directory, mounted read-write and used as HOME
+	WorkDir string   // Where the agent starts (the worktree, inside HomeDir)
+	Shared  []string // Host directories mounted read-write at the same path
+	Tools   []string // Host binaries mounted read-only into /usr/local/bin
+}
+
+// Enabled reports whether cfg asks for container isolation.
+func Enabled(cfg *config.ContainerConfig) bool {
+	return cfg != nil && cfg.Enabled
+}
+
+// Runtime returns the container runtime cfg selects, or the first one
+// installed.
+func Runtime(cfg *config.ContainerConfig) (string, error) {
+	if cfg != nil && cfg.Runtime != "" {
+		if _, err := exec.LookPath(cfg.Runtime); err != nil {
+			return "", fmt.Errorf("container runtime %q not found: %w", cfg.Runtime, err)
+		}
+		return cfg.Runtime, nil
+	}
+	for _, rt := range runtimes {
+		if _, err := exec.LookPath(rt); err == nil {
+			return rt, nil
+		}
+	}
+	return "", errors.New("container isolation needs podman or docker installed")
+}
+
+// Wrap returns command wrapped to run in spec's container with cfg's
+// settings and limits' resource limits, along with the runtime that will
+// run it. Any container left over from an earlier session of the same name
+// is removed first.
+func Wrap(spec Spec, command string, cfg *config.ContainerConfig, limits *config.CgroupConfig) (string, string, error) {
+	if cfg.Image == "" {
+		return "", "", errors.New("container isolation is enabled but no image is set")
+	}
+	rt, err := Runtime(cfg)
+	if err != nil {
+		return "", "", err
+	}
+	Remove(rt, spec.Name)
+	// exec, so the runtime's client is the pane's process
+	return "exec " + strings.Join(runArgs(rt, spec, command, cfg, limits), " "), rt, nil
+}
+
+// runArgs builds the shell-quoted run command line.
+func runArgs(rt string, spec Spec, command string, cfg *config.ContainerConfig, limits *config.CgroupConfig) []string {
+	args := []string{rt, "run", "--rm", "-it", "--init",
+		"--name", config.ShellQuote(spec.Name),
+		"--hostname", config.ShellQuote(spec.Name),
+		"--cap-drop=ALL", "--security-opt=no-new-privileges",
+	}
+
+	// Run as the host user so files in the worktree keep their owner
+	if rt == "podman" {
+		args = append(args, "--userns=keep-id")
+	} else {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+
+	if cfg.Network != "" {
+		args = append(args, "--network", config.ShellQuote(cfg.Network))
+	}
+	args = append(args, limitArgs(limits)...)
+
+	args = append(args, "-v", config.ShellQuote(spec.HomeDir+":"+spec.HomeDir))
+	for _, dir := range spec.Shared {
+		args = append(args, "-v", config.ShellQuote(dir+":"+dir))
+	}
+	for _, tool := range spec.Tools {
+		args = append(args, "-v", config.ShellQuote(tool+":"+filepath.Join(binDir, filepath.Base(tool))+":ro"))
+	}
+	for _, m := range cfg.Mounts {
+		args = append(args, "-v", config.ShellQuote(mountSpec(m)))
+	}
+
+	args = append(args, "-w", config.ShellQuote(spec.WorkDir), "-e", config.ShellQuote("HOME="+spec.HomeDir), "-e", "TERM",
+		"-e", config.ShellQuote(EnvVar+"="+rt))
+	for _, name := range cfg.Env {
+		// A bare name passes the host's value through
+		args = append(args, "-e", config.ShellQuote(name))
+	}
+
+	return append(args, config.ShellQuote(cfg.Image), "sh", "-c", config.ShellQuote(command))
+}
+
+// mountSpec turns a configured mount ("path" or "path:rw") into a volume
+// spec mounting it at the same path.
+func mountSpec(m string) string {
+	path, mode := m, "ro"
+	if strings.HasSuffix(m, ":rw") {
+		path, mode = strings.TrimSuffix(m, ":rw"), "rw"
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	return path + ":" + path + ":" + mode
+}
+
+// limitArgs passes the rig's cgroup limits to the runtime, which enforces
+// them on the container itself. Percentage memory limits have no runtime
+// equivalent and are skipped.
+func limitArgs(limits *config.CgroupConfig) []string {
+	if limits == nil || !limits.Enabled {
+		return nil
+	}
+	var args []string
+	if m := limits.MemoryMax; m != "" && !strings.HasSuffix(m, "%") {
+		args = append(args, "--memory="+strings.ToLower(m), "--memory-swap="+strings.ToLower(m))
+	}
+	if q := strings.TrimSuffix(limits.CPUQuota, "%"); q != "" {
+		if pct, err := strconv.ParseFloat(q, 64); err == nil && pct > 0 {
+			args = append(args, "--cpus="+strconv.FormatFloat(pct/100, 'f', -1, 64))
+		}
+	}
+	if limits.TasksMax > 0 {
+		args = append(args, fmt.Sprintf("--pids-limit=%d", limits.TasksMax))
+	}
+	return args
+}
+
+// SharedDirs returns the host directories a polecat's container needs
+// besides its own: the rig's and town's beads, and the git directory its
+// worktree commits into. Directories under homeDir are already mounted.
+func SharedDirs(townRoot, homeDir, workDir string) []string {
+	candidates := []string{
+		beads.ResolveBeadsDir(workDir),
+		filepath.Join(townRoot, ".beads"),
+		git.CommonDir(workDir),
+	}
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		if dir == "" || seen[dir] || within(dir, homeDir) {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// Tools returns the host's gt and bd binaries, which agents call from
+// their hooks, for mounting into the container. Only a Linux host's
+// binaries run in a Linux container, so elsewhere the image must provide
+// them.
+func Tools() []string {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	var tools []string
+	if exe, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		tools = append(tools, exe)
+	}
+	if bd, err := exec.LookPath("bd"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(bd); err == nil {
+			bd = resolved
+		}
+		tools = append(tools, bd)
+	}
+	return tools
+}
+
+// Inside reports whether this process runs in a polecat's container.
+func Inside() bool {
+	if os.Getenv(EnvVar) == "" {
+		return false
+	}
+	// podman and docker each leave a marker file in the container's root
+	for _, marker := range []string{"/run/.containerenv", "/.dockerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Release removes a stopped session's container, killing anything still
+// running in it, when cfg enables isolation.
+func Release(session string, cfg *config.ContainerConfig) {
+	if !Enabled(cfg) {
+		return
+	}
+	if rt, err := Runtime(cfg); err == nil {
+		Remove(rt, session)
+	}
+}
+
+// Remove force-removes a container. Errors are ignored: usually there is
+// no container.
+func Remove(rt, name string) {
+	_ = exec.Command(rt, "rm", "-f", name).Run()
+}
@@ -0,0 +1,111 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRunArgs(t *testing.T) {
+	spec := Spec{
+		Name:    "gt-rig-Toast",
+		HomeDir: "/town/rig/polecats/Toast",
+		WorkDir: "/town/rig/polecats/Toast/rig",
+		Shared:  []string{"/town/rig/.beads", "/town/.beads"},
+		Tools:   []string{"/usr/bin/gt"},
+	}
+	cfg := &config.ContainerConfig{
+		Enabled: true,
+		Image:   "ghcr.io/example/agent:latest",
+		Network: "none",
+		Env:     []string{"ANTHROPIC_API_KEY"},
+		Mounts:  []string{"/opt/cache:rw", "/etc/ssl/certs"},
+	}
+	got := strings.Join(runArgs("podman", spec, "exec env GT_ROLE=polecat claude 'hi there'", cfg, nil), " ")
+
+	for _, want := range []string{
+		"podman run --rm -it --init --name gt-rig-Toast --hostname gt-rig-Toast",
+		"--cap-drop=ALL --security-opt=no-new-privileges",
+		"--userns=keep-id",
+		"--network none",
+		"-v /town/rig/polecats/Toast:/town/rig/polecats/Toast",
+		"-v /town/rig/.beads:/town/rig/.beads",
+		"-v /town/.beads:/town/.beads",
+		"-v /usr/bin/gt:/usr/local/bin/gt:ro",
+		"-v /opt/cache:/opt/cache:rw",
+		"-v /etc/ssl/certs:/etc/ssl/certs:ro",
+		"-w /town/rig/polecats/Toast/rig -e HOME=/town/rig/polecats/Toast",
+		"-e GT_CONTAINER=podman",
+		"-e ANTHROPIC_API_KEY",
+		`ghcr.io/example/agent:latest sh -c 'exec env GT_ROLE=polecat claude '\''hi there'\'''`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("runArgs() = %q, missing %q", got, want)
+		}
+	}
+
+	docker := strings.Join(runArgs("docker", spec, "claude", cfg, nil), " ")
+	if strings.Contains(docker, "--userns") || !strings.Contains(docker, "--user ") {
+		t.Errorf("docker runArgs() = %q, want --user and no --userns", docker)
+	}
+}
+
+func TestLimitArgs(t *testing.T) {
+	tests := []struct {
+		limits *config.CgroupConfig
+		want   []string
+	}{
+		{nil, nil},
+		{&config.CgroupConfig{MemoryMax: "4G"}, nil},
+		{
+			&config.CgroupConfig{Enabled: true, MemoryMax: "4G", CPUQuota: "150%", TasksMax: 512},
+			[]string{"--memory=4g", "--memory-swap=4g", "--cpus=1.5", "--pids-limit=512"},
+		},
+		{&config.CgroupConfig{Enabled: true, MemoryMax: "50%", CPUQuota: "200%"}, []string{"--cpus=2"}},
+	}
+	for _, tt := range tests {
+		if got := limitArgs(tt.limits); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("limitArgs(%+v) = %v, want %v", tt.limits, got, tt.want)
+		}
+	}
+}
+
+func TestSharedDirs(t *testing.T) {
+	town := t.TempDir()
+	home := filepath.Join(town, "rig", "polecats", "Toast")
+	work := filepath.Join(home, "rig")
+	for _, dir := range []string{filepath.Join(town, ".beads"), filepath.Join(work, ".beads")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The worktree's own beads are under the home dir, which is mounted
+	// anyway; only the town's beads need a mount of their own.
+	got := SharedDirs(town, home, work)
+	want := []string{filepath.Join(town, ".beads")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SharedDirs() = %v, want %v", got, want)
+	}
+}
+
+func TestWithin(t *testing.T) {
+	tests := []struct {
+		path, dir string
+		want      bool
+	}{
+		{"/a/b", "/a", true},
+		{"/a", "/a", true},
+		{"/ab", "/a", false},
+		{"/x/y", "/a", false},
+	}
+	for _, tt := range tests {
+		if got := within(tt.path, tt.dir); got != tt.want {
+			t.Errorf("within(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package container
+
+import "syscall"
+
+// StopSelf stops the container this process runs in by terminating every
+// other process in it. The init process exits once its child does, which
+// ends the runtime's client in the session's pane, and with it the session.
+func StopSelf() error {
+	// In the container's PID namespace, -1 reaches only its own processes
+	return syscall.Kill(-1, syscall.SIGTERM)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package container
+
+import "errors"
+
+// StopSelf is not supported on Windows, where polecats don't run in
+// containers.
+func StopSelf() error {
+	return errors.New("stopping a container from inside is not supported on Windows")
+}
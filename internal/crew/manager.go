@@ -234,6 +234,11 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*CrewWorker, err
 		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
 	}
 
+	// Check the crew member's own git pushes against the push policy
+	if _, err := git.NewGit(crewPath).InstallPrePushHook(); err != nil && !errors.Is(err, git.ErrHooksPathSet) {
+		fmt.Printf("Warning: could not install pre-push hook: %v\n", err)
+	}
+
 	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
 	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
 
@@ -18,16 +18,22 @@ import (
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/cgroup"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/headless"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -53,6 +59,9 @@ type Daemon struct {
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
 
+	// Warm pool refills run in the background; at most one at a time.
+	poolFillRunning sync.Mutex
+
 	// Deacon startup tracking: prevents race condition where newly started
 	// sessions are immediately killed by the heartbeat check.
 	// See: https://github.com/steveyegge/gastown/issues/567
@@ -291,6 +300,33 @@ func (d *Daemon) heartbeat(state *State) {
 	// Uses regex-based WaitForRuntimeReady, which is acceptable for daemon bootstrap.
 	d.triggerPendingSpawns()
 
+	// 6a. Start queued slings that now fit (slots freed by gt done or without
+	// a nuke, or the host recovered from resource pressure)
+	if IsPatrolEnabled(d.patrolConfig, "sling_queue") {
+		d.startQueuedSlings()
+	}
+
+	// 6b. Place ready work on idle polecat capacity (opt-in town scheduler)
+	if IsPatrolEnabled(d.patrolConfig, "scheduler") {
+		d.runScheduler()
+	}
+
+	// 6c. Spawn polecats for rigs whose ready work is piling up (rigs opt in
+	// via autoscale settings)
+	if IsPatrolEnabled(d.patrolConfig, "autoscaler") {
+		d.runAutoscaler()
+	}
+
+	// 6d. Refill and refresh warm polecat pools (rigs with warm_pool configured)
+	if IsPatrolEnabled(d.patrolConfig, "warm_pool") {
+		d.fillWarmPools()
+	}
+
+	// 6e. Escalate molecule steps running past their formula's SLA
+	if IsPatrolEnabled(d.patrolConfig, "step_sla") {
+		d.checkStepSLAs()
+	}
+
 	// 7. Process lifecycle requests
 	d.processLifecycleRequests()
 
@@ -311,6 +347,15 @@ func (d *Daemon) heartbeat(state *State) {
 	// This is a safety net - Deacon patrol also does this more frequently.
 	d.cleanupOrphanedProcesses()
 
+	// 13. Clean up after headless session hosts that died without removing
+	// their sockets (headless session backend only)
+	if d.tmux.IsHeadless() {
+		d.reapHeadlessSessions()
+	}
+
+	// 14. Remove the secret files of agents that have exited
+	d.reapIssuedSecrets()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -1027,8 +1072,22 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
 
+	// A session confined to a cgroup records whether a limit killed it;
+	// read it before the restart resets the scope.
+	reason := "crash detected"
+	if limit := cgroup.DeathReason(sessionName); limit != "" {
+		reason += ": " + limit
+		d.logger.Printf("Polecat %s/%s died from a resource limit: %s", rigName, polecatName, limit)
+	}
+
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
+	_ = events.LogFeed(events.TypeSessionDeath, "daemon",
+		events.SessionDeathPayload(sessionName, fmt.Sprintf("%s/polecats/%s", rigName, polecatName), reason, "daemon"))
+
+	// Archive what the dead session left behind before a restart starts
+	// a new transcript (non-fatal)
+	d.archivePolecatTranscript(rigName, polecatName, sessionName, info.HookBead)
 
 	// Auto-restart the polecat
 	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
@@ -1090,6 +1149,34 @@ func (d *Daemon) emitMassDeathEvent() {
 	d.recentDeaths = nil
 }
 
+// polecatWorkDir returns a polecat's clone, handling both the new
+// polecats/<name>/<rigname>/ and the old polecats/<name>/ structure.
+func polecatWorkDir(rigPath, rigName, polecatName string) string {
+	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		// Fall back to old structure
+		workDir = filepath.Join(rigPath, "polecats", polecatName)
+	}
+	return workDir
+}
+
+// archivePolecatTranscript archives the transcript of a polecat session
+// that died, for gt seance search. With the session gone its environment
+// is too, so the runtime's default config dir is assumed.
+func (d *Daemon) archivePolecatTranscript(rigName, polecatName, sessionName, bead string) {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	src := runtime.Driver(config.LoadRuntimeConfig(rigPath)).Transcript(polecatWorkDir(rigPath, rigName, polecatName), "")
+	entry := transcript.Entry{
+		Agent:   fmt.Sprintf("%s/polecats/%s", rigName, polecatName),
+		Rig:     rigName,
+		Session: sessionName,
+		Bead:    bead,
+	}
+	if _, err := transcript.ArchiveSession(d.config.TownRoot, src, entry); err != nil {
+		d.logger.Printf("Could not archive transcript of %s: %v", sessionName, err)
+	}
+}
+
 // restartPolecatSession restarts a crashed polecat session.
 func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string) error {
 	// Check rig operational state before auto-restarting
@@ -1100,14 +1187,7 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Calculate rig path for agent config resolution
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
 
-	// Determine working directory (handle both new and old structures)
-	// New structure: polecats/<name>/<rigname>/
-	// Old structure: polecats/<name>/
-	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
-	if _, err := os.Stat(workDir); os.IsNotExist(err) {
-		// Fall back to old structure
-		workDir = filepath.Join(rigPath, "polecats", polecatName)
-	}
+	workDir := polecatWorkDir(rigPath, rigName, polecatName)
 
 	// Verify the worktree exists
 	if _, err := os.Stat(workDir); os.IsNotExist(err) {
@@ -1145,23 +1225,182 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
 	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
 
-	// Launch Claude with environment exported inline
+	// Launch the agent with environment exported inline
 	// Pass rigPath so rig agent settings are honored (not town-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+	prefix, rc, err := config.ResolveStartup(envVars, rigPath, "")
+	if err != nil {
+		return fmt.Errorf("resolving agent: %w", err)
+	}
+	driver := runtime.Driver(rc)
+
+	// Resume the dead session's conversation if its checkpoint names one the
+	// runtime can resume; the resume prompt then follows once it is ready.
+	prompt := d.checkpointResumePrompt(workDir)
+	startCmd := prefix + driver.StartCommand(prompt)
+	resumed := false
+	if cp, err := checkpoint.Read(workDir); err == nil && cp != nil && cp.RuntimeSessionID() != "" {
+		if resume := driver.ResumeCommand(cp.RuntimeSessionID()); resume != "" {
+			startCmd, resumed = prefix+resume, true
+			d.logger.Printf("Resuming %s session %s for polecat %s/%s", driver.Name(), cp.RuntimeSessionID(), rigName, polecatName)
+		}
+	}
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
 
-	// Wait for Claude to start, then accept bypass permissions warning if it appears.
-	// This ensures automated restarts aren't blocked by the warning dialog.
+	// Wait for the agent to start, then accept anything it shows at startup
+	// (e.g. Claude's bypass permissions warning). This ensures automated
+	// restarts aren't blocked by the dialog.
 	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Non-fatal - Claude might still start
+		// Non-fatal - the agent might still start
+	}
+	_ = driver.AfterStart(d.tmux, sessionName)
+
+	if resumed && prompt != "" {
+		if err := driver.WaitReady(d.tmux, sessionName, constants.ClaudeStartTimeout); err != nil {
+			d.logger.Printf("Resumed polecat %s/%s not ready for its resume prompt: %v", rigName, polecatName, err)
+		}
+		if err := driver.SendPrompt(d.tmux, sessionName, prompt); err != nil {
+			d.logger.Printf("Could not send resume prompt to %s: %v", sessionName, err)
+		}
 	}
-	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
 
 	return nil
 }
 
+// checkpointResumePrompt runs gt mol resume in a crashed polecat's worktree
+// and returns the resume prompt for the new session. This replays any step
+// closures the dead session left pending. Returns "" if there is no
+// checkpoint, resume is disabled, or the resume fails.
+func (d *Daemon) checkpointResumePrompt(workDir string) string {
+	if !IsPatrolEnabled(d.patrolConfig, "checkpoint_resume") {
+		return ""
+	}
+	if cp, err := checkpoint.Read(workDir); err != nil || cp == nil {
+		return ""
+	}
+
+	cmd := exec.Command("gt", "mol", "resume", "--dir", workDir, "--prompt") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = workDir
+	cmd.Env = os.Environ()
+	output, err := cmd.Output()
+	if err != nil {
+		d.logger.Printf("Checkpoint resume failed in %s: %v", workDir, err)
+		return ""
+	}
+
+	// The prompt is passed on the agent command line via send-keys, so
+	// flatten it to a single line.
+	return strings.Join(strings.Fields(string(output)), " ")
+}
+
+// runScheduler runs one pass of the town work scheduler (gt schedule run),
+// slinging ready beads onto rigs with free polecat slots.
+func (d *Daemon) runScheduler() {
+	d.runGtPatrol("Scheduler", "schedule", "run")
+}
+
+// runGtPatrol runs a gt subcommand from the town root on behalf of a
+// heartbeat patrol, logging each line of its output under the patrol's name.
+func (d *Daemon) runGtPatrol(name string, args ...string) {
+	cmd := exec.Command("gt", args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("%s failed: %v: %s", name, err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("%s: %s", name, line)
+		}
+	}
+}
+
+// startQueuedSlings runs gt sling --start-queued when any rig has slings
+// waiting in its admission queue. The command itself checks capacity and
+// host resources before starting each one.
+func (d *Daemon) startQueuedSlings() {
+	waiting := false
+	for _, rigName := range d.getKnownRigs() {
+		queued, err := polecat.NewAdmissionQueue(filepath.Join(d.config.TownRoot, rigName)).List()
+		if err == nil && len(queued) > 0 {
+			waiting = true
+			break
+		}
+	}
+	if !waiting {
+		return
+	}
+
+	d.runGtPatrol("Sling queue", "sling", "--start-queued")
+}
+
+// runAutoscaler runs gt autoscale run when any rig has autoscaling enabled.
+// The command logs each decision to the town log.
+func (d *Daemon) runAutoscaler() {
+	enabled := false
+	for _, rigName := range d.getKnownRigs() {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(d.config.TownRoot, rigName)))
+		if err == nil && settings.Autoscale != nil && settings.Autoscale.Enabled {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return
+	}
+
+	d.runGtPatrol("Autoscaler", "autoscale", "run")
+}
+
+// checkStepSLAs runs gt mol sla, which escalates molecule steps running
+// past the SLA their formula declares and records the timing of steps
+// closed with bd. Off unless patrols.step_sla is enabled in mayor/daemon.json.
+func (d *Daemon) checkStepSLAs() {
+	d.runGtPatrol("Step SLA", "mol", "sla")
+}
+
+// fillWarmPools starts gt polecat pool fill for each rig with a warm pool
+// configured (or entries left over from one). Fills run setup hooks and can
+// take minutes, so they run in the background; a heartbeat that finds the
+// previous fill still running skips this step. A fill only fetches origin
+// when it has worktrees to build, at most every few minutes. Disable with
+// patrols.warm_pool in mayor/daemon.json.
+func (d *Daemon) fillWarmPools() {
+	var rigs []string
+	for _, rigName := range d.getKnownRigs() {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+		hasPool := err == nil && settings.WarmPool.PoolSize() > 0
+		if _, err := os.Stat(filepath.Join(rigPath, "polecats", ".warm")); err == nil {
+			hasPool = true
+		}
+		if hasPool {
+			rigs = append(rigs, rigName)
+		}
+	}
+	if len(rigs) == 0 || !d.poolFillRunning.TryLock() {
+		return
+	}
+
+	go func() {
+		defer d.poolFillRunning.Unlock()
+		for _, rigName := range rigs {
+			cmd := exec.Command("gt", "polecat", "pool", "fill", rigName) //nolint:gosec // G204: rig name from rigs.json
+			cmd.Dir = d.config.TownRoot
+			cmd.Env = os.Environ()
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				d.logger.Printf("Warm pool fill for %s failed: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+				continue
+			}
+			d.logger.Printf("Warm pool: %s", strings.TrimSpace(string(output)))
+		}
+	}()
+}
+
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat restart fails.
 func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
 	witnessAddr := rigName + "/witness"
@@ -1204,3 +1443,30 @@ func (d *Daemon) cleanupOrphanedProcesses() {
 		}
 	}
 }
+
+// reapHeadlessSessions removes the sockets of headless sessions whose host
+// process is gone, so they stop showing up as sessions.
+func (d *Daemon) reapHeadlessSessions() {
+	reaped, err := headless.Reap()
+	if err != nil {
+		d.logger.Printf("Warning: headless session reap failed: %v", err)
+		return
+	}
+	if reaped > 0 {
+		d.logger.Printf("Reaped %d dead headless session(s)", reaped)
+	}
+}
+
+// reapIssuedSecrets removes the secret files issued to agent processes that
+// have since exited, so credentials don't outlive the sessions they were
+// issued to.
+func (d *Daemon) reapIssuedSecrets() {
+	reaped, err := secrets.Reap()
+	if err != nil {
+		d.logger.Printf("Warning: issued secrets reap failed: %v", err)
+		return
+	}
+	if reaped > 0 {
+		d.logger.Printf("Removed secrets of %d exited agent(s)", reaped)
+	}
+}
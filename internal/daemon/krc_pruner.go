@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/krc"
+	"github.com/steveyegge/gastown/internal/recording"
 )
 
 // KRCPruner manages automatic pruning of expired ephemeral records.
@@ -87,4 +88,13 @@ func (p *KRCPruner) prune() {
 			result.BytesBefore-result.BytesAfter,
 			result.Duration.Round(time.Millisecond))
 	}
+
+	recs, err := recording.Prune(p.townRoot, p.config.GetTTL("session_recording"), time.Now())
+	if err != nil {
+		p.logger("KRC recording prune error: %v", err)
+		return
+	}
+	if recs.Removed > 0 {
+		p.logger("KRC pruned %d session recordings (saved %d bytes)", recs.Removed, recs.Bytes)
+	}
 }
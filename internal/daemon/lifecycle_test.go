@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // testDaemon creates a minimal Daemon for testing.
@@ -252,3 +254,35 @@ func TestBeadsMessage_Serialization(t *testing.T) {
 		t.Errorf("From mismatch")
 	}
 }
+
+func TestArchivePolecatTranscript(t *testing.T) {
+	d := testDaemon()
+	d.config.TownRoot = t.TempDir()
+	rigPath := filepath.Join(d.config.TownRoot, "gastown")
+	transcripts := t.TempDir()
+
+	settings := `{"type": "rig-settings", "runtime": {"session": {"transcript_dir": "` + transcripts + `"}}}`
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(transcripts, "abc123.jsonl"), []byte(`{"type":"user"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d.archivePolecatTranscript("gastown", "toast", "gt-gastown-toast", "gt-42")
+
+	entries, err := transcript.Load(d.config.TownRoot)
+	if err != nil {
+		t.Fatalf("loading archive index: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d archived transcripts, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.ID != "abc123" || e.Agent != "gastown/polecats/toast" || e.Session != "gt-gastown-toast" || e.Bead != "gt-42" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
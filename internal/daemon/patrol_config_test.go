@@ -20,7 +20,11 @@ func TestLoadPatrolConfig(t *testing.T) {
 		"version": 1,
 		"patrols": {
 			"refinery": {"enabled": false},
-			"witness": {"enabled": true}
+			"witness": {"enabled": true},
+			"checkpoint_resume": {"enabled": false},
+			"warm_pool": {"enabled": false},
+			"sling_queue": {"enabled": false},
+			"step_sla": {"enabled": true}
 		}
 	}`
 	if err := os.WriteFile(filepath.Join(mayorDir, "daemon.json"), []byte(configJSON), 0644); err != nil {
@@ -43,6 +47,24 @@ func TestLoadPatrolConfig(t *testing.T) {
 	if !IsPatrolEnabled(config, "deacon") {
 		t.Error("expected deacon to be enabled (default)")
 	}
+	if IsPatrolEnabled(config, "checkpoint_resume") {
+		t.Error("expected checkpoint_resume to be disabled")
+	}
+	if IsPatrolEnabled(config, "scheduler") {
+		t.Error("expected scheduler to be disabled (opt-in)")
+	}
+	if !IsPatrolEnabled(config, "autoscaler") {
+		t.Error("expected autoscaler to be enabled (default; rigs opt in)")
+	}
+	if IsPatrolEnabled(config, "warm_pool") {
+		t.Error("expected warm_pool to be disabled")
+	}
+	if IsPatrolEnabled(config, "sling_queue") {
+		t.Error("expected sling_queue to be disabled")
+	}
+	if !IsPatrolEnabled(config, "step_sla") {
+		t.Error("expected step_sla to be enabled")
+	}
 }
 
 func TestIsPatrolEnabled_NilConfig(t *testing.T) {
@@ -50,4 +72,10 @@ func TestIsPatrolEnabled_NilConfig(t *testing.T) {
 	if !IsPatrolEnabled(nil, "refinery") {
 		t.Error("expected default to be enabled")
 	}
+	if IsPatrolEnabled(nil, "scheduler") {
+		t.Error("expected scheduler to default to disabled")
+	}
+	if IsPatrolEnabled(nil, "step_sla") {
+		t.Error("expected step_sla to default to disabled")
+	}
 }
@@ -114,6 +114,30 @@ type PatrolsConfig struct {
 	Witness    *PatrolConfig     `json:"witness,omitempty"`
 	Deacon     *PatrolConfig     `json:"deacon,omitempty"`
 	DoltServer *DoltServerConfig `json:"dolt_server,omitempty"`
+
+	// CheckpointResume controls whether crashed polecats are restarted with
+	// a resume prompt built from their checkpoint (gt mol resume).
+	CheckpointResume *PatrolConfig `json:"checkpoint_resume,omitempty"`
+
+	// Scheduler runs gt schedule run each heartbeat to place ready work on
+	// idle polecat capacity. Off unless explicitly enabled.
+	Scheduler *PatrolConfig `json:"scheduler,omitempty"`
+
+	// Autoscaler runs gt autoscale run each heartbeat for rigs that enable
+	// autoscaling in their settings. On by default.
+	Autoscaler *PatrolConfig `json:"autoscaler,omitempty"`
+
+	// SlingQueue runs gt sling --start-queued each heartbeat when a rig has
+	// queued slings. On by default.
+	SlingQueue *PatrolConfig `json:"sling_queue,omitempty"`
+
+	// WarmPool runs gt polecat pool fill each heartbeat for rigs with a
+	// warm pool configured. On by default.
+	WarmPool *PatrolConfig `json:"warm_pool,omitempty"`
+
+	// StepSLA runs gt mol sla each heartbeat to escalate molecule steps
+	// running past their formula's SLA. Off unless explicitly enabled.
+	StepSLA *PatrolConfig `json:"step_sla,omitempty"`
 }
 
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
@@ -146,10 +170,12 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 }
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
-// Returns true if the config doesn't exist (default enabled for backwards compatibility).
+// Returns true if the config doesn't exist (default enabled for backwards compatibility),
+// except for the scheduler patrol, which dispatches work, and the step_sla patrol,
+// which only matters to towns whose formulas declare SLAs; both must be enabled explicitly.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	if config == nil || config.Patrols == nil {
-		return true // Default: enabled
+		return patrol != "scheduler" && patrol != "step_sla" // Default: enabled, except opt-in patrols
 	}
 
 	switch patrol {
@@ -165,6 +191,26 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "checkpoint_resume":
+		if config.Patrols.CheckpointResume != nil {
+			return config.Patrols.CheckpointResume.Enabled
+		}
+	case "scheduler":
+		return config.Patrols.Scheduler != nil && config.Patrols.Scheduler.Enabled
+	case "autoscaler":
+		if config.Patrols.Autoscaler != nil {
+			return config.Patrols.Autoscaler.Enabled
+		}
+	case "sling_queue":
+		if config.Patrols.SlingQueue != nil {
+			return config.Patrols.SlingQueue.Enabled
+		}
+	case "warm_pool":
+		if config.Patrols.WarmPool != nil {
+			return config.Patrols.WarmPool.Enabled
+		}
+	case "step_sla":
+		return config.Patrols.StepSLA != nil && config.Patrols.StepSLA.Enabled
 	}
 	return true // Default: enabled
 }
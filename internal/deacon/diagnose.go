@@ -0,0 +1,166 @@
+package deacon
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PaneState classifies what an agent's pane shows it doing.
+// Activity age alone can't tell a busy agent from one that loops on the same
+// failing command or sits at a prompt; the pane history can.
+type PaneState string
+
+const (
+	// PaneWorking means nothing in the pane suggests the agent is stuck.
+	PaneWorking PaneState = "working"
+	// PaneBlockedPrompt means the agent is waiting at an input prompt.
+	PaneBlockedPrompt PaneState = "blocked_prompt"
+	// PaneContextExhausted means the runtime reported its context is full.
+	PaneContextExhausted PaneState = "context_exhausted"
+	// PaneErrorLoop means the same error keeps coming back.
+	PaneErrorLoop PaneState = "error_loop"
+	// PaneRepeating means the pane keeps printing the same output.
+	PaneRepeating PaneState = "repeating"
+)
+
+// Remedy is the recommended intervention for a pane state.
+type Remedy string
+
+const (
+	RemedyNone     Remedy = "none"     // Leave the agent alone
+	RemedyNudge    Remedy = "nudge"    // Nudge it to reconsider
+	RemedyRestart  Remedy = "restart"  // Cycle to a fresh session (gt handoff)
+	RemedyEscalate Remedy = "escalate" // Needs a decision the agent can't make
+)
+
+// PaneDiagnosis is the result of analysing an agent's pane.
+type PaneDiagnosis struct {
+	State    PaneState `json:"state"`
+	Remedy   Remedy    `json:"remedy"`
+	Evidence string    `json:"evidence,omitempty"` // Pane line that decided it
+	Count    int       `json:"count,omitempty"`    // Repeats, for loops
+}
+
+// Thresholds for pane analysis.
+const (
+	paneTailLines       = 12 // Lines from the bottom where prompts and banners count
+	errorLoopThreshold  = 4  // Same error this many times is a loop
+	repeatWindow        = 60 // Non-empty lines examined for repetition
+	repeatMinLines      = 20 // Fewer lines than this is too little to judge
+	repeatDistinctRatio = 4  // At most 1 in this many lines distinct is repetition
+)
+
+// contextBanners are messages runtimes print when the conversation no longer
+// fits in the model's context.
+var contextBanners = []string{
+	"prompt is too long",
+	"conversation is too long",
+	"context window exceeded",
+	"maximum context length",
+	"context limit reached",
+	"context left until auto-compact: 0%",
+}
+
+// errorLinePattern matches lines that report a failure.
+var errorLinePattern = regexp.MustCompile(`(?i)(\berror\b|\bfatal:|\bpanic:|traceback \(most recent|command not found|--- fail|\bfailed\b|exit (code|status) [1-9])`)
+
+// volatilePattern matches the parts of a line that change between otherwise
+// identical repeats: numbers, hex ids, durations.
+var volatilePattern = regexp.MustCompile(`\b([0-9a-f]{7,40}|\d+(\.\d+)?(ms|s|m|h)?)\b`)
+
+// DiagnosePane classifies an agent from its recent pane lines (oldest first,
+// as tmux.CapturePaneLines returns them). prompts are the runtime's blocking
+// prompt fragments (RuntimeConfig.BlockingPrompts).
+//
+// The checks run from most to least specific: a full context, then a prompt
+// at the bottom of the pane, then a recurring error, then output that keeps
+// repeating.
+func DiagnosePane(lines []string, prompts []string) PaneDiagnosis {
+	var nonEmpty []string
+	for _, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			nonEmpty = append(nonEmpty, trimmed)
+		}
+	}
+	tail := nonEmpty
+	if len(tail) > paneTailLines {
+		tail = tail[len(tail)-paneTailLines:]
+	}
+
+	for _, line := range tail {
+		lower := strings.ToLower(line)
+		for _, banner := range contextBanners {
+			if strings.Contains(lower, banner) {
+				return PaneDiagnosis{State: PaneContextExhausted, Remedy: RemedyRestart, Evidence: line}
+			}
+		}
+	}
+
+	// Only the last lines count: a prompt that scrolled away was answered
+	for i := len(tail) - 1; i >= 0; i-- {
+		lower := strings.ToLower(tail[i])
+		for _, prompt := range prompts {
+			if prompt != "" && strings.Contains(lower, strings.ToLower(prompt)) {
+				return PaneDiagnosis{State: PaneBlockedPrompt, Remedy: RemedyEscalate, Evidence: tail[i]}
+			}
+		}
+	}
+
+	var errLines []string
+	for _, line := range nonEmpty {
+		if errorLinePattern.MatchString(line) {
+			errLines = append(errLines, line)
+		}
+	}
+	if line, n, _ := mostRepeated(errLines); n >= errorLoopThreshold {
+		return PaneDiagnosis{State: PaneErrorLoop, Remedy: RemedyRestart, Evidence: line, Count: n}
+	}
+
+	window := nonEmpty
+	if len(window) > repeatWindow {
+		window = window[len(window)-repeatWindow:]
+	}
+	if len(window) >= repeatMinLines {
+		if line, n, distinct := mostRepeated(window); distinct*repeatDistinctRatio <= len(window) {
+			return PaneDiagnosis{State: PaneRepeating, Remedy: RemedyNudge, Evidence: line, Count: n}
+		}
+	}
+
+	return PaneDiagnosis{State: PaneWorking, Remedy: RemedyNone}
+}
+
+// mostRepeated returns the line that recurs most often (ignoring volatile
+// parts), how often, and how many distinct lines there are.
+func mostRepeated(lines []string) (line string, count, distinct int) {
+	counts := make(map[string]int)
+	for _, l := range lines {
+		key := normalizePaneLine(l)
+		counts[key]++
+		if counts[key] > count {
+			line, count = l, counts[key]
+		}
+	}
+	return line, count, len(counts)
+}
+
+// normalizePaneLine reduces a line to what stays the same across repeats.
+func normalizePaneLine(line string) string {
+	return volatilePattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(line)), "#")
+}
+
+// Summary renders the diagnosis for humans.
+func (d PaneDiagnosis) Summary() string {
+	switch d.State {
+	case PaneBlockedPrompt:
+		return fmt.Sprintf("waiting at a prompt: %q", d.Evidence)
+	case PaneContextExhausted:
+		return fmt.Sprintf("context exhausted: %q", d.Evidence)
+	case PaneErrorLoop:
+		return fmt.Sprintf("same error %d times: %q", d.Count, d.Evidence)
+	case PaneRepeating:
+		return fmt.Sprintf("output repeating (%d times): %q", d.Count, d.Evidence)
+	default:
+		return "no sign of being stuck"
+	}
+}
@@ -0,0 +1,67 @@
+package deacon
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiagnosePane(t *testing.T) {
+	prompts := []string{"Do you want to proceed?", "Press Enter to continue"}
+
+	var progress []string
+	for i := 0; i < 40; i++ {
+		progress = append(progress, fmt.Sprintf("● Edit(internal/pkg/file%d.go)", i), fmt.Sprintf("  ⎿  Updated with %d additions", i))
+	}
+
+	var spinning []string
+	for i := 0; i < 30; i++ {
+		spinning = append(spinning, "● Bash(make check)", fmt.Sprintf("  ⎿  Waiting for lock (attempt %d)", i))
+	}
+
+	var failing []string
+	for i := 0; i < 5; i++ {
+		failing = append(failing,
+			"● Bash(go test ./internal/foo/...)",
+			fmt.Sprintf("  ⎿  Error: foo_test.go:%d: undefined: NewThing", 40+i),
+			"● Let me fix that.",
+			fmt.Sprintf("● Update(internal/foo/foo.go) attempt %d", i),
+		)
+	}
+
+	tests := []struct {
+		name   string
+		lines  []string
+		state  PaneState
+		remedy Remedy
+	}{
+		{"empty pane", nil, PaneWorking, RemedyNone},
+		{"steady progress", progress, PaneWorking, RemedyNone},
+		{"permission prompt", append(append([]string{}, progress...),
+			"● Bash(rm -rf build)", "Do you want to proceed?", "❯ 1. Yes", "  2. No", ""),
+			PaneBlockedPrompt, RemedyEscalate},
+		{"prompt scrolled away", append([]string{"Press Enter to continue"}, progress...), PaneWorking, RemedyNone},
+		{"context banner", append(append([]string{}, progress...), "  ⎿  API Error: Prompt is too long"),
+			PaneContextExhausted, RemedyRestart},
+		{"error loop", failing, PaneErrorLoop, RemedyRestart},
+		{"repeating output", spinning, PaneRepeating, RemedyNudge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiagnosePane(tt.lines, prompts)
+			if got.State != tt.state || got.Remedy != tt.remedy {
+				t.Errorf("DiagnosePane() = %s/%s (%s), want %s/%s", got.State, got.Remedy, got.Summary(), tt.state, tt.remedy)
+			}
+		})
+	}
+}
+
+func TestDiagnosePaneErrorLoopCount(t *testing.T) {
+	var lines []string
+	for i := 0; i < 6; i++ {
+		lines = append(lines, "running step", fmt.Sprintf("fatal: unable to access 'https://x/': timeout after %ds", i+1))
+	}
+	got := DiagnosePane(lines, nil)
+	if got.State != PaneErrorLoop || got.Count != 6 {
+		t.Errorf("DiagnosePane() = %+v, want error_loop with count 6", got)
+	}
+}
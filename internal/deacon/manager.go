@@ -118,8 +118,9 @@ func (m *Manager) Start(agentOverride string) error {
 		return fmt.Errorf("waiting for deacon to start: %w", err)
 	}
 
-	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+	// Dismiss anything the runtime shows at startup (e.g. Claude's bypass
+	// permissions warning).
+	_ = runtime.DriverWithOverride(runtimeConfig, agentOverride).AfterStart(t, sessionID)
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
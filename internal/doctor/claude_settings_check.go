@@ -338,6 +338,7 @@ func (c *ClaudeSettingsCheck) checkSettings(path, _ string) []string {
 	// 2. PATH export in hooks
 	// 3. Stop hook with gt costs record (for autonomous)
 	// 4. gt nudge deacon session-started in SessionStart
+	// 5. PreToolUse hook with gt policy check
 
 	// Check enabledPlugins
 	if _, ok := actual["enabledPlugins"]; !ok {
@@ -365,6 +366,11 @@ func (c *ClaudeSettingsCheck) checkSettings(path, _ string) []string {
 		missing = append(missing, "Stop hook")
 	}
 
+	// Check PreToolUse hook enforces the tool-use policy (for all roles)
+	if !c.hookHasPattern(hooks, "PreToolUse", "gt policy check") {
+		missing = append(missing, "policy hook")
+	}
+
 	return missing
 }
 
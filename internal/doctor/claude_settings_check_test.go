@@ -67,6 +67,17 @@ func createValidSettings(t *testing.T, path string) {
 					},
 				},
 			},
+			"PreToolUse": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt policy check",
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -117,6 +128,17 @@ func createStaleSettings(t *testing.T, path string, missingElements ...string) {
 					},
 				},
 			},
+			"PreToolUse": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt policy check",
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -159,6 +181,9 @@ func createStaleSettings(t *testing.T, path string, missingElements ...string) {
 		case "Stop":
 			hooks := settings["hooks"].(map[string]any)
 			delete(hooks, "Stop")
+		case "PreToolUse":
+			hooks := settings["hooks"].(map[string]any)
+			delete(hooks, "PreToolUse")
 		}
 	}
 
@@ -401,6 +426,33 @@ func TestClaudeSettingsCheck_MissingStopHook(t *testing.T) {
 	}
 }
 
+func TestClaudeSettingsCheck_MissingPolicyHook(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create stale settings missing the policy hook (at correct location)
+	mayorSettings := filepath.Join(tmpDir, "mayor", ".claude", "settings.json")
+	createStaleSettings(t, mayorSettings, "PreToolUse")
+
+	check := NewClaudeSettingsCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+
+	result := check.Run(ctx)
+
+	if result.Status != StatusError {
+		t.Errorf("expected StatusError for missing policy hook, got %v", result.Status)
+	}
+	found := false
+	for _, d := range result.Details {
+		if strings.Contains(d, "policy hook") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("expected details to mention policy hook, got %v", result.Details)
+	}
+}
+
 func TestClaudeSettingsCheck_WrongLocationWitness(t *testing.T) {
 	tmpDir := t.TempDir()
 	rigName := "testrig"
@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/hostres"
+)
+
+// ResourcePressureCheck reports when the host is short on memory, CPU or
+// disk. While it is, polecat spawns are deferred to the rig's sling queue.
+type ResourcePressureCheck struct {
+	BaseCheck
+}
+
+// NewResourcePressureCheck creates a new host resource pressure check.
+func NewResourcePressureCheck() *ResourcePressureCheck {
+	return &ResourcePressureCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "resource-pressure",
+			CheckDescription: "Check host memory, CPU load and free disk against spawn thresholds",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run samples the host and compares it with the town's resource thresholds.
+func (c *ResourcePressureCheck) Run(ctx *CheckContext) *CheckResult {
+	diskPath := ctx.TownRoot
+	if rigPath := ctx.RigPath(); rigPath != "" {
+		diskPath = rigPath
+	}
+	snap := hostres.Sample(diskPath)
+	resources := config.LoadResourceConfig(ctx.TownRoot)
+	if resources == nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "spawn thresholds not configured; " + resourceSummary(snap),
+			FixHint: "Add a \"resources\" section to settings/config.json to defer spawns on a saturated host",
+		}
+	}
+	pressures := snap.Pressures(resources)
+	if len(pressures) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "host is saturated; new polecats will be queued",
+			Details: pressures,
+			FixHint: "Free memory or disk, stop idle sessions, or adjust \"resources\" in settings/config.json",
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: resourceSummary(snap),
+	}
+}
+
+// resourceSummary renders the parts of a snapshot the platform reported.
+func resourceSummary(s *hostres.Snapshot) string {
+	msg := "no resource pressure"
+	if s.MemAvailableMB >= 0 {
+		msg += fmt.Sprintf(", %d MB memory available", s.MemAvailableMB)
+	}
+	if load := s.LoadPerCPU(); load >= 0 {
+		msg += fmt.Sprintf(", load %.2f per CPU", load)
+	}
+	if s.DiskFreeGB >= 0 {
+		msg += fmt.Sprintf(", %.1f GB disk free", s.DiskFreeGB)
+	}
+	return msg
+}
@@ -0,0 +1,45 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeResourceSettings(t *testing.T, townRoot, resources string) {
+	t.Helper()
+	dir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "town-settings", "version": 1, "resources": ` + resources + `}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResourcePressureCheck(t *testing.T) {
+	t.Run("thresholds disabled", func(t *testing.T) {
+		townRoot := t.TempDir()
+		writeResourceSettings(t, townRoot, `{"min_memory_mb": 0, "max_load_per_cpu": 0, "min_disk_gb": 0}`)
+
+		result := NewResourcePressureCheck().Run(&CheckContext{TownRoot: townRoot})
+		if result.Status != StatusOK {
+			t.Errorf("Status = %v, want OK: %s %v", result.Status, result.Message, result.Details)
+		}
+	})
+
+	t.Run("disk below threshold", func(t *testing.T) {
+		townRoot := t.TempDir()
+		writeResourceSettings(t, townRoot, `{"min_memory_mb": 0, "max_load_per_cpu": 0, "min_disk_gb": 1000000000}`)
+
+		result := NewResourcePressureCheck().Run(&CheckContext{TownRoot: townRoot})
+		if result.Status != StatusWarning {
+			t.Fatalf("Status = %v, want warning", result.Status)
+		}
+		if len(result.Details) != 1 || !strings.HasPrefix(result.Details[0], "disk:") {
+			t.Errorf("Details = %v, want one disk pressure", result.Details)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// WriteScopeCheck looks for work in polecat and crew clones that their own
+// agent didn't do. Each agent claims its clone with an identity lock when it
+// primes (lock.LockInfo); a clone with uncommitted changes that no agent has
+// claimed, or that another agent's session has claimed, was written to from
+// outside (another worktree or by hand).
+type WriteScopeCheck struct {
+	BaseCheck
+}
+
+// NewWriteScopeCheck creates a new cross-worktree write check.
+func NewWriteScopeCheck() *WriteScopeCheck {
+	return &WriteScopeCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "worktree-write-scope",
+			CheckDescription: "Detect changes in clones made by agents that don't own them",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// agentClone is a polecat or crew clone and the agent it belongs to.
+type agentClone struct {
+	dir  string
+	rig  string
+	name string
+}
+
+// Run checks each dirty clone's changes against its identity lock.
+func (c *WriteScopeCheck) Run(ctx *CheckContext) *CheckResult {
+	clones := findAgentClones(ctx.TownRoot)
+	panes, _ := tmux.NewTmux().ListPaneSessions()
+
+	var problems []string
+	checked := 0
+	for _, clone := range clones {
+		changed := changedFiles(clone.dir, setupTime(clone.dir))
+		if len(changed) == 0 {
+			continue
+		}
+		checked++
+
+		rel, _ := filepath.Rel(ctx.TownRoot, clone.dir)
+		summary := fmt.Sprintf("%d changed file(s), newest %s", len(changed), newestChange(clone.dir, changed))
+
+		info, err := lock.New(clone.dir).Read()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s, but no agent has claimed it", rel, summary))
+			continue
+		}
+		if owner, ok := lockOwner(info, panes); ok && (owner.rig != clone.rig || owner.name != clone.name) {
+			problems = append(problems, fmt.Sprintf("%s: %s, claimed by %s/%s", rel, summary, owner.rig, owner.name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d clone(s) with changes, all by their owners", checked),
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d clone(s) changed by an agent that doesn't own them", len(problems)),
+		Details: problems,
+		FixHint: "Review with 'git -C <clone> status'; set \"sandbox\" in the rig's settings/config.json to confine polecat writes",
+	}
+}
+
+// lockOwner resolves the agent holding an identity lock from its live
+// session. Locks taken outside tmux record "<rig>/<name>" instead of a
+// pane. ok is false when the owner can't be told (e.g., its session ended).
+func lockOwner(info *lock.LockInfo, panes map[string]string) (agentClone, bool) {
+	if rig, name, found := strings.Cut(info.SessionID, "/"); found {
+		return agentClone{rig: rig, name: name}, true
+	}
+	sessionName, live := panes[info.SessionID]
+	if !live {
+		return agentClone{}, false
+	}
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return agentClone{}, false
+	}
+	return agentClone{rig: id.Rig, name: id.Name}, true
+}
+
+// findAgentClones lists the polecat and crew clones of every rig.
+func findAgentClones(townRoot string) []agentClone {
+	var clones []agentClone
+	entries, err := os.ReadDir(townRoot)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == "mayor" {
+			continue
+		}
+		rigName := entry.Name()
+		rigPath := filepath.Join(townRoot, rigName)
+
+		crewPath := filepath.Join(rigPath, "crew")
+		for _, name := range subdirs(crewPath) {
+			if dir := filepath.Join(crewPath, name); isGitClone(dir) {
+				clones = append(clones, agentClone{dir: dir, rig: rigName, name: name})
+			}
+		}
+
+		// Polecats: polecats/<name>/<rig>/, or polecats/<name>/ in the old layout
+		polecatsPath := filepath.Join(rigPath, "polecats")
+		for _, name := range subdirs(polecatsPath) {
+			dir := filepath.Join(polecatsPath, name, rigName)
+			if !isGitClone(dir) {
+				dir = filepath.Join(polecatsPath, name)
+			}
+			if isGitClone(dir) {
+				clones = append(clones, agentClone{dir: dir, rig: rigName, name: name})
+			}
+		}
+	}
+	return clones
+}
+
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func isGitClone(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// setupGrace covers the edits gt itself makes while creating a clone
+// (.gitignore patterns, overlay files).
+const setupGrace = time.Minute
+
+// setupTime returns when a clone was created, from its .git entry.
+func setupTime(dir string) time.Time {
+	info, err := os.Stat(filepath.Join(dir, ".git"))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime().Add(setupGrace)
+}
+
+// changedFiles returns the paths git status reports as modified or
+// untracked in a clone, leaving out those last written before since.
+// Deleted files can't be dated and are always included.
+func changedFiles(dir string, since time.Time) []string {
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		if _, to, renamed := strings.Cut(path, " -> "); renamed {
+			path = to
+		}
+		path = strings.Trim(path, `"`)
+		if info, err := os.Stat(filepath.Join(dir, path)); err == nil && info.ModTime().Before(since) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+// newestChange describes the most recently modified of files.
+func newestChange(dir string, files []string) string {
+	var newest string
+	var newestTime time.Time
+	for _, f := range files {
+		info, err := os.Stat(filepath.Join(dir, f))
+		if err == nil && info.ModTime().After(newestTime) {
+			newest, newestTime = f, info.ModTime()
+		}
+	}
+	if newest == "" {
+		return "(deleted)"
+	}
+	return fmt.Sprintf("%s at %s", newest, newestTime.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,103 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lock"
+)
+
+// setupPolecatClone creates rig/polecats/<name>/rig as a clone that was set
+// up an hour ago, with the identity lock ignored like .gitignore does.
+func setupPolecatClone(t *testing.T, townRoot, name string) string {
+	t.Helper()
+	dir := filepath.Join(townRoot, "rig", "polecats", name, "rig")
+	initGitRepo(t, dir)
+	exclude := filepath.Join(dir, ".git", "info", "exclude")
+	if err := os.WriteFile(exclude, []byte(".runtime/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, ".git"), past, past); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestWriteScopeCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		lockedBy   string // Session ID of the lock on Toast's clone; empty for none
+		wantStatus CheckStatus
+		wantDetail string
+	}{
+		{"owner", "rig/Toast", StatusOK, ""},
+		{"unclaimed", "", StatusWarning, "no agent has claimed it"},
+		{"other agent", "rig/Nux", StatusWarning, "claimed by rig/Nux"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			dir := setupPolecatClone(t, townRoot, "Toast")
+			if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.lockedBy != "" {
+				if err := lock.New(dir).Acquire(tt.lockedBy); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result := NewWriteScopeCheck().Run(&CheckContext{TownRoot: townRoot})
+			if result.Status != tt.wantStatus {
+				t.Fatalf("status = %v, want %v (%s: %v)", result.Status, tt.wantStatus, result.Message, result.Details)
+			}
+			if tt.wantDetail != "" && (len(result.Details) != 1 || !strings.Contains(result.Details[0], tt.wantDetail)) {
+				t.Errorf("details = %v, want one containing %q", result.Details, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestWriteScopeCheckIgnoresSetupChanges(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := setupPolecatClone(t, townRoot, "Toast")
+
+	// Written while the clone was being set up, before any agent claimed it
+	path := filepath.Join(dir, ".gitignore")
+	if err := os.WriteFile(path, []byte(".runtime/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewWriteScopeCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("status = %v, want OK (%v)", result.Status, result.Details)
+	}
+}
+
+func TestLockOwner(t *testing.T) {
+	panes := map[string]string{"%3": "gt-rig-Toast", "%7": "gt-rig-crew-max"}
+	tests := []struct {
+		sessionID string
+		want      agentClone
+		wantOK    bool
+	}{
+		{"rig/Toast", agentClone{rig: "rig", name: "Toast"}, true},
+		{"%3", agentClone{rig: "rig", name: "Toast"}, true},
+		{"%7", agentClone{rig: "rig", name: "max"}, true},
+		{"%9", agentClone{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := lockOwner(&lock.LockInfo{SessionID: tt.sessionID}, panes)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("lockOwner(%q) = %+v, %v; want %+v, %v", tt.sessionID, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -66,6 +66,10 @@ func TestZombieSessionCheck_FixProtectsCrewSessions(t *testing.T) {
 
 	ctx := &CheckContext{TownRoot: t.TempDir()}
 
+	// Fix logs each kill to the events file of the town around the working
+	// directory; keep it out of the source tree (internal/ has a mayor/ dir)
+	t.Chdir(t.TempDir())
+
 	// Fix should skip crew sessions due to safeguard
 	// (We can't fully test this without mocking tmux, but the safeguard is in place)
 	_ = check.Fix(ctx)
@@ -8,9 +8,10 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -103,9 +104,10 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 		}
 	}
 
-	// Ensure Claude settings exist for dogs
-	if err := claude.EnsureSettingsForRole(kennelDir, "dog"); err != nil {
-		return fmt.Errorf("ensuring Claude settings: %w", err)
+	// Ensure runtime settings exist for dogs
+	runtimeConfig := config.ResolveRoleAgentConfig("dog", m.townRoot, "")
+	if err := runtime.EnsureSettingsForRole(kennelDir, "dog", runtimeConfig); err != nil {
+		return fmt.Errorf("ensuring runtime settings: %w", err)
 	}
 
 	// Build startup prompt - dogs check mail for work
@@ -127,6 +129,9 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 		return fmt.Errorf("building startup command: %w", err)
 	}
 
+	// Confine the session to its own cgroup if the town asks for it
+	startupCmd = cgroup.Wrap(sessionID, startupCmd, config.LoadCgroupConfig(m.townRoot, ""))
+
 	// Create session with command
 	if err := m.tmux.NewSessionWithCommand(sessionID, kennelDir, startupCmd); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
@@ -151,8 +156,9 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 		return fmt.Errorf("waiting for dog to start: %w", err)
 	}
 
-	// Accept bypass permissions warning if it appears
-	_ = m.tmux.AcceptBypassPermissionsWarning(sessionID)
+	// Dismiss anything the runtime shows at startup (e.g. Claude's bypass
+	// permissions warning)
+	_ = runtime.DriverWithOverride(runtimeConfig, opts.AgentOverride).AfterStart(m.tmux, sessionID)
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
@@ -189,6 +195,7 @@ func (m *SessionManager) Stop(dogName string, force bool) error {
 	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	cgroup.Release(sessionID, config.LoadCgroupConfig(m.townRoot, ""))
 
 	return nil
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
 )
 
 // ConflictInfo describes a merge conflict.
@@ -168,8 +170,12 @@ func RebaseBranch(workDir, branch, ontoBranch string) (*RebaseResult, error) {
 	}, nil
 }
 
-// ForcePushBranch force-pushes a branch to remote.
+// ForcePushBranch force-pushes a branch to remote, if the push policy allows
+// the calling agent to.
 func ForcePushBranch(workDir, remote, branch string) error {
+	if err := git.NewGit(workDir).CheckPush(branch, git.PushForce); err != nil {
+		return err
+	}
 	cmd := exec.Command("git", "push", "--force-with-lease", remote, branch)
 	cmd.Dir = workDir
 	var stderr bytes.Buffer
@@ -5,6 +5,8 @@
 package events
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/auditlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -68,8 +71,56 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// TypeReworkRequested is emitted when the refinery sends a branch back
+	// to its polecat for rebasing.
+	TypeReworkRequested = "rework_requested"
+
+	// Molecule step timing events (for gt mol stats)
+	TypeStepClaimed     = "step_claimed"
+	TypeStepStarted     = "step_started"
+	TypeStepClosed      = "step_closed"
+	TypeStepSLABreached = "step_sla_breached"
+
+	// Compliance events (also recorded in the tamper-evident audit log)
+	TypeNuke          = "nuke"
+	TypePreempt       = "preempt"
+	TypeForceRelease  = "force_release"
+	TypeAccountSwitch = "account_switch"
+	TypeConfigChange  = "config_change"
+
+	// Secrets broker events (gt secrets); values are never logged
+	TypeSecretSet     = "secret_set"
+	TypeSecretRotated = "secret_rotated"
+	TypeSecretIssued  = "secret_issued"
+
+	// Tool-use policy decisions (gt policy check)
+	TypePolicyDecision = "policy_decision"
 )
 
+// auditedTypes are event types mirrored to the hash-chained audit log
+// (internal/auditlog) for compliance. Unlike the events log, that log is
+// never pruned.
+var auditedTypes = map[string]bool{
+	TypeSling:         true,
+	TypeKill:          true,
+	TypeNuke:          true,
+	TypePreempt:       true,
+	TypeForceRelease:  true,
+	TypeAccountSwitch: true,
+	TypeConfigChange:  true,
+	TypeSecretSet:     true,
+	TypeSecretRotated: true,
+	TypeSecretIssued:  true,
+
+	TypePolicyDecision: true,
+}
+
+// IsAudited reports whether events of this type go to the audit log.
+func IsAudited(eventType string) bool {
+	return auditedTypes[eventType]
+}
+
 // EventsFile is the name of the raw events log.
 const EventsFile = ".events.jsonl"
 
@@ -78,7 +129,9 @@ var mutex sync.Mutex
 
 // Log writes an event to the events log.
 // The event is appended to ~/gt/.events.jsonl.
-// Returns nil if logging fails (events are best-effort).
+// Outside a Gas Town workspace nothing is logged. Audited event types are
+// also appended to the audit log; since most callers treat events as
+// best-effort, a failure there is reported on stderr as well as returned.
 func Log(eventType, actor string, payload map[string]interface{}, visibility string) error {
 	event := Event{
 		Timestamp:  time.Now().UTC().Format(time.RFC3339),
@@ -88,7 +141,17 @@ func Log(eventType, actor string, payload map[string]interface{}, visibility str
 		Payload:    payload,
 		Visibility: visibility,
 	}
-	return write(event)
+	var auditErr error
+	if IsAudited(eventType) {
+		if err := auditlog.Record(eventType, actor, payload); err != nil {
+			auditErr = fmt.Errorf("recording %s in audit log: %w", eventType, err)
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", auditErr)
+		}
+	}
+	if err := write(event); err != nil {
+		return err
+	}
+	return auditErr
 }
 
 // LogFeed is a convenience wrapper for feed-visible events.
@@ -213,6 +276,15 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// ReworkPayload creates a payload for rework request events.
+func ReworkPayload(worker, branch, issue string) map[string]interface{} {
+	return map[string]interface{}{
+		"worker": worker,
+		"branch": branch,
+		"issue":  issue,
+	}
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -273,6 +345,103 @@ func KillPayload(rig, target, reason string) map[string]interface{} {
 	}
 }
 
+// NukePayload creates a payload for polecat nuke events.
+func NukePayload(rig, polecat string, force bool) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":     rig,
+		"polecat": polecat,
+		"force":   force,
+	}
+}
+
+// PreemptPayload creates a payload for polecat preemption events.
+// forBead is the bead the slot was freed for (may be empty).
+func PreemptPayload(rig, polecat, beadID, forBead string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":     rig,
+		"polecat": polecat,
+		"bead":    beadID,
+	}
+	if forBead != "" {
+		p["for"] = forBead
+	}
+	return p
+}
+
+// ForceReleasePayload creates a payload for force-release events.
+func ForceReleasePayload(beadID, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"bead": beadID,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// AccountSwitchPayload creates a payload for account switch events.
+func AccountSwitchPayload(from, to string) map[string]interface{} {
+	return map[string]interface{}{
+		"from": from,
+		"to":   to,
+	}
+}
+
+// Config change actions.
+const (
+	ConfigSet   = "set"
+	ConfigUnset = "unset"
+	ConfigBlock = "block" // Inheritance of the key blocked for a rig
+	ConfigReset = "reset" // Settings restored to defaults
+)
+
+// ConfigChangePayload creates a payload for configuration change events.
+// scope: what was changed (e.g., "rig:gastown", "town", "krc")
+// key: the setting changed; action: one of the Config* actions
+// value: the new value for ConfigSet. Values may hold credentials (agent
+// command lines, tokens), so only a fingerprint is recorded.
+func ConfigChangePayload(scope, key, action, value string) map[string]interface{} {
+	p := map[string]interface{}{
+		"scope":  scope,
+		"key":    key,
+		"action": action,
+	}
+	if action == ConfigSet {
+		sum := sha256.Sum256([]byte(value))
+		p["value_sha256"] = hex.EncodeToString(sum[:8])
+	}
+	return p
+}
+
+// SecretPayload creates a payload for secrets broker events.
+// to: the agent a secret was issued to ("" for set/rotate)
+func SecretPayload(name string, version int, to string) map[string]interface{} {
+	p := map[string]interface{}{
+		"secret":  name,
+		"version": version,
+	}
+	if to != "" {
+		p["to"] = to
+	}
+	return p
+}
+
+// PolicyPayload creates a payload for tool-use policy decisions.
+// subject: the command or target the rule matched; source: "rig", "town"
+// or "default"
+func PolicyPayload(tool, subject, decision, source, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"tool":     tool,
+		"subject":  subject,
+		"decision": decision,
+		"source":   source,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
 // HaltPayload creates a payload for halt events.
 func HaltPayload(services []string) map[string]interface{} {
 	return map[string]interface{}{
@@ -311,6 +480,27 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// StepPayload creates a payload for molecule step timing events.
+// step: step bead ID (e.g., "gt-abc.2")
+// molecule: molecule root ID the step belongs to
+// formula: formula the molecule was poured from (may be empty)
+// formulaStep: step ID within the formula, used to correlate steps across runs (may be empty)
+// title: rendered step title
+func StepPayload(step, molecule, formula, formulaStep, title string) map[string]interface{} {
+	p := map[string]interface{}{
+		"step":     step,
+		"molecule": molecule,
+		"title":    title,
+	}
+	if formula != "" {
+		p["formula"] = formula
+	}
+	if formulaStep != "" {
+		p["formula_step"] = formulaStep
+	}
+	return p
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
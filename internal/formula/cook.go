@@ -0,0 +1,197 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// cookVarRegex matches {{variable}} placeholders.
+// Mirrors the substitution bd performs when pouring a molecule; Go template
+// actions such as {{.leg.id}} are left untouched for later rendering.
+var cookVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
+
+// Cook pours a formula into store as a molecule: a root bead titled with
+// the formula name, and a child bead per step with variables substituted
+// and dependencies wired from needs. Declared defaults are applied for
+// missing vars; a missing required var is an error. Steps are created in
+// declaration order with IDs <root>.<step id>. Returns the root bead.
+func Cook(f *Formula, vars map[string]string, store *beads.MemoryStore) (*beads.Issue, error) {
+	resolved, err := f.resolveVars(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// The root records the formula type and the vars it was cooked with
+	header := []string{"type: " + string(f.Type)}
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header = append(header, fmt.Sprintf("var %s = %s", name, resolved[name]))
+	}
+	root, err := store.Create(beads.CreateOptions{
+		Title:       f.Name,
+		Type:        "molecule",
+		Description: strings.Join(header, "\n"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating molecule: %w", err)
+	}
+
+	type step struct {
+		id, title, description string
+		needs                  []string
+	}
+	var steps []step
+	switch f.Type {
+	case TypeWorkflow:
+		for _, s := range f.Steps {
+			steps = append(steps, step{s.ID, s.Title, s.Description, s.Needs})
+		}
+	case TypeExpansion:
+		for _, tmpl := range f.Template {
+			steps = append(steps, step{tmpl.ID, tmpl.Title, tmpl.Description, tmpl.Needs})
+		}
+	case TypeConvoy:
+		var legIDs []string
+		for _, leg := range f.Legs {
+			steps = append(steps, step{leg.ID, leg.Title, leg.Description, nil})
+			legIDs = append(legIDs, leg.ID)
+		}
+		if f.Synthesis != nil {
+			needs := f.Synthesis.DependsOn
+			if len(needs) == 0 {
+				needs = legIDs
+			}
+			steps = append(steps, step{"synthesis", f.Synthesis.Title, f.Synthesis.Description, needs})
+		}
+	case TypeAspect:
+		for _, aspect := range f.Aspects {
+			steps = append(steps, step{aspect.ID, aspect.Title, aspect.Description, nil})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported formula type %q", f.Type)
+	}
+
+	// Create every step before wiring needs, which may point forward
+	for _, st := range steps {
+		_, err := store.CreateWithID(StepBeadID(root.ID, st.id), beads.CreateOptions{
+			Title:       expandVars(st.title, resolved),
+			Type:        "task",
+			Description: expandVars(strings.TrimSpace(st.description), resolved),
+			Parent:      root.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating step %q: %w", st.id, err)
+		}
+	}
+	for _, st := range steps {
+		for _, need := range st.needs {
+			if err := store.AddDependency(StepBeadID(root.ID, st.id), StepBeadID(root.ID, need)); err != nil {
+				return nil, fmt.Errorf("step %q needs %q: %w", st.id, need, err)
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// StepBeadID returns the ID of a cooked step's bead.
+func StepBeadID(rootID, stepID string) string {
+	return rootID + "." + stepID
+}
+
+// resolveVars merges caller-provided values with declared defaults and
+// checks that every required var or input has a value. An input with
+// required_unless is satisfied when any of its alternatives is set.
+func (f *Formula) resolveVars(vars map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for name, v := range f.Vars {
+		if v.Default != "" {
+			resolved[name] = v.Default
+		}
+	}
+	for name, in := range f.Inputs {
+		if in.Default != "" {
+			resolved[name] = in.Default
+		}
+	}
+	for name, value := range vars {
+		resolved[name] = value
+	}
+
+	var missing []string
+	for name, v := range f.Vars {
+		if v.Required && resolved[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	for name, in := range f.Inputs {
+		if (!in.Required && len(in.RequiredUnless) == 0) || resolved[name] != "" {
+			continue
+		}
+		satisfied := false
+		for _, alt := range in.RequiredUnless {
+			if resolved[alt] != "" {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required vars: %s", strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
+
+// expandVars replaces {{name}} placeholders with values from vars.
+// Unknown placeholders are left as-is so they show up in golden output.
+func expandVars(text string, vars map[string]string) string {
+	return cookVarRegex.ReplaceAllStringFunc(text, func(match string) string {
+		if value, ok := vars[match[2:len(match)-2]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// Golden renders a cooked molecule in the stable text form used by golden
+// files. The format is line-oriented so that edits to step text or needs
+// produce readable diffs.
+func Golden(store *beads.MemoryStore, root *beads.Issue) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "formula: %s\n", root.Title)
+	sb.WriteString(root.Description)
+	sb.WriteString("\n")
+
+	prefix := StepBeadID(root.ID, "")
+	for _, step := range store.Children(root.ID) {
+		fmt.Fprintf(&sb, "\n=== step %s\n", strings.TrimPrefix(step.ID, prefix))
+		fmt.Fprintf(&sb, "title: %s\n", step.Title)
+		if len(step.DependsOn) > 0 {
+			needs := make([]string, len(step.DependsOn))
+			for i, dep := range step.DependsOn {
+				needs[i] = strings.TrimPrefix(dep, prefix)
+			}
+			fmt.Fprintf(&sb, "needs: %s\n", strings.Join(needs, ", "))
+		}
+		if step.Description != "" {
+			sb.WriteString("---\n")
+			sb.WriteString(step.Description)
+			sb.WriteString("\n")
+		}
+	}
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,132 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestCook_Workflow(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "release"
+type = "workflow"
+
+[vars.version]
+required = true
+
+[vars.channel]
+default = "stable"
+
+[[steps]]
+id = "test"
+title = "Test {{version}}"
+description = "Run tests on {{channel}}"
+
+[[steps]]
+id = "publish"
+title = "Publish"
+description = "Publish {{version}} using {{.unrendered}} and {{unknown}}"
+needs = ["test"]
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	store := beads.NewMemoryStore("gt")
+	root, err := Cook(f, map[string]string{"version": "1.2.3"}, store)
+	if err != nil {
+		t.Fatalf("Cook() error: %v", err)
+	}
+	if root.Title != "release" || root.Type != "molecule" {
+		t.Errorf("root = %q (%s), want the release molecule", root.Title, root.Type)
+	}
+
+	steps := store.Children(root.ID)
+	if len(steps) != 2 {
+		t.Fatalf("len(steps) = %d, want 2", len(steps))
+	}
+	if got := steps[0].Title; got != "Test 1.2.3" {
+		t.Errorf("steps[0].Title = %q, want %q", got, "Test 1.2.3")
+	}
+	if got := steps[0].Description; got != "Run tests on stable" {
+		t.Errorf("steps[0].Description = %q, want default var applied", got)
+	}
+	publish, err := store.Show(StepBeadID(root.ID, "publish"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Publish 1.2.3 using {{.unrendered}} and {{unknown}}"; publish.Description != want {
+		t.Errorf("publish.Description = %q, want %q", publish.Description, want)
+	}
+	if want := []string{StepBeadID(root.ID, "test")}; !reflect.DeepEqual(publish.DependsOn, want) {
+		t.Errorf("publish.DependsOn = %v, want %v", publish.DependsOn, want)
+	}
+}
+
+func TestCook_MissingRequiredVar(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "release"
+
+[vars.version]
+required = true
+
+[[steps]]
+id = "test"
+title = "Test"
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	_, err = Cook(f, nil, beads.NewMemoryStore("gt"))
+	if err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("Cook() error = %v, want missing version", err)
+	}
+}
+
+func TestCook_ConvoyRequiredUnless(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "review"
+type = "convoy"
+
+[inputs.pr]
+required_unless = ["files"]
+
+[inputs.files]
+required_unless = ["pr"]
+
+[[legs]]
+id = "a"
+title = "Leg A"
+
+[[legs]]
+id = "b"
+title = "Leg B"
+
+[synthesis]
+title = "Combine"
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	store := beads.NewMemoryStore("gt")
+	if _, err := Cook(f, nil, store); err == nil {
+		t.Error("Cook() should fail when neither pr nor files is set")
+	}
+
+	root, err := Cook(f, map[string]string{"files": "*.go"}, store)
+	if err != nil {
+		t.Fatalf("Cook() error: %v", err)
+	}
+	synth, err := store.Show(StepBeadID(root.ID, "synthesis"))
+	if err != nil {
+		t.Fatalf("convoy molecule should have a synthesis step: %v", err)
+	}
+	want := []string{StepBeadID(root.ID, "a"), StepBeadID(root.ID, "b")}
+	if !reflect.DeepEqual(synth.DependsOn, want) {
+		t.Errorf("synthesis.DependsOn = %v, want all legs", synth.DependsOn)
+	}
+}
@@ -0,0 +1,140 @@
+package formula
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// FixtureSuffix is the filename suffix for golden test fixtures.
+const FixtureSuffix = ".fixture.toml"
+
+// GoldenSuffix is the filename suffix for golden molecule outputs.
+const GoldenSuffix = ".golden"
+
+// Fixture describes one golden test case: which formula to cook and the
+// variable values to cook it with.
+//
+// Example fixture (mol-polecat-work.fixture.toml):
+//
+//	formula = "mol-polecat-work"
+//
+//	[vars]
+//	issue = "gt-123"
+type Fixture struct {
+	Formula string            `toml:"formula"`
+	Vars    map[string]string `toml:"vars"`
+
+	// Path is the fixture file location (not read from TOML).
+	Path string `toml:"-"`
+}
+
+// GoldenPath returns the golden file paired with this fixture.
+func (fx *Fixture) GoldenPath() string {
+	return strings.TrimSuffix(fx.Path, FixtureSuffix) + GoldenSuffix
+}
+
+// LoadFixture reads a golden test fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	var fx Fixture
+	if _, err := toml.DecodeFile(path, &fx); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	if fx.Formula == "" {
+		return nil, fmt.Errorf("fixture %s: formula field is required", path)
+	}
+	fx.Path = path
+	return &fx, nil
+}
+
+// FindFixtures returns all fixture files in dir, sorted by name.
+func FindFixtures(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+FixtureSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("listing fixtures: %w", err)
+	}
+	return matches, nil
+}
+
+// ParseEmbedded parses one of the formulas embedded in the gt binary.
+func ParseEmbedded(name string) (*Formula, error) {
+	data, err := formulasFS.ReadFile("formulas/" + name + ".formula.toml")
+	if err != nil {
+		return nil, fmt.Errorf("embedded formula %q not found", name)
+	}
+	return Parse(data)
+}
+
+// CompareGolden compares the golden rendering of the molecule cooked into
+// store under root against the file at goldenPath. With update set, the
+// golden file is (re)written instead. A mismatch returns an error describing
+// the first differing line.
+func CompareGolden(store *beads.MemoryStore, root *beads.Issue, goldenPath string, update bool) error {
+	got := Golden(store, root)
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			return fmt.Errorf("creating golden directory: %w", err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+			return fmt.Errorf("writing golden file: %w", err)
+		}
+		return nil
+	}
+
+	want, err := os.ReadFile(goldenPath) //nolint:gosec // G304: path is a test fixture chosen by the caller
+	if err != nil {
+		return fmt.Errorf("reading golden file: %w", err)
+	}
+	if bytes.Equal(want, got) {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", goldenPath, describeDiff(want, got))
+}
+
+// CheckFixture cooks the fixture's formula and compares it against the
+// fixture's golden file. The formula is resolved by load, which lets callers
+// choose between embedded formulas and on-disk search paths.
+func CheckFixture(fx *Fixture, load func(name string) (*Formula, error), update bool) error {
+	f, err := load(fx.Formula)
+	if err != nil {
+		return err
+	}
+	store := beads.NewMemoryStore("test")
+	root, err := Cook(f, fx.Vars, store)
+	if err != nil {
+		return fmt.Errorf("cooking %s: %w", fx.Formula, err)
+	}
+	return CompareGolden(store, root, fx.GoldenPath(), update)
+}
+
+// describeDiff reports the first line where want and got differ.
+func describeDiff(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i >= len(wantLines) {
+			return fmt.Sprintf("line %d: unexpected extra output %q", i+1, g)
+		}
+		if i >= len(gotLines) {
+			return fmt.Sprintf("line %d: missing expected output %q", i+1, w)
+		}
+		if w != g {
+			return fmt.Sprintf("line %d differs:\n  want: %q\n  got:  %q", i+1, w, g)
+		}
+	}
+	return "outputs differ"
+}
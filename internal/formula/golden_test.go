@@ -0,0 +1,94 @@
+package formula
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// TestEmbeddedFormulaGolden cooks each fixture in testdata/golden and compares
+// the molecule structure against its golden file.
+// Run with -update after intentionally editing an embedded formula.
+func TestEmbeddedFormulaGolden(t *testing.T) {
+	fixtures, err := FindFixtures(filepath.Join("testdata", "golden"))
+	if err != nil {
+		t.Fatalf("FindFixtures() error: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no golden fixtures found")
+	}
+
+	for _, path := range fixtures {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			fx, err := LoadFixture(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := CheckFixture(fx, ParseEmbedded, *updateGolden); err != nil {
+				t.Errorf("%v\n(run 'go test ./internal/formula -run Golden -update' to accept)", err)
+			}
+		})
+	}
+}
+
+func TestCompareGolden_Mismatch(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "test"
+type = "workflow"
+
+[[steps]]
+id = "a"
+title = "Step A"
+description = "Work on {{issue}}"
+
+[[steps]]
+id = "b"
+title = "Step B"
+needs = ["a"]
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	store := beads.NewMemoryStore("gt")
+	root, err := Cook(f, map[string]string{"issue": "gt-1"}, store)
+	if err != nil {
+		t.Fatalf("Cook() error: %v", err)
+	}
+
+	goldenPath := filepath.Join(t.TempDir(), "test.golden")
+	if err := CompareGolden(store, root, goldenPath, true); err != nil {
+		t.Fatalf("CompareGolden(update) error: %v", err)
+	}
+	if err := CompareGolden(store, root, goldenPath, false); err != nil {
+		t.Errorf("CompareGolden() after update should match: %v", err)
+	}
+
+	b, err := store.Show(StepBeadID(root.ID, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.DependsOn = nil
+	err = CompareGolden(store, root, goldenPath, false)
+	if err == nil {
+		t.Fatal("CompareGolden() should fail when needs change")
+	}
+	if !strings.Contains(err.Error(), "needs: a") {
+		t.Errorf("error should point at the changed line, got: %v", err)
+	}
+}
+
+func TestLoadFixture_RequiresFormula(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad"+FixtureSuffix)
+	if err := os.WriteFile(path, []byte("[vars]\nissue = \"gt-1\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixture(path); err == nil {
+		t.Error("LoadFixture() should reject fixture without formula")
+	}
+}
@@ -3,6 +3,7 @@ package formula
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -132,6 +133,16 @@ func (f *Formula) validateWorkflow() error {
 		}
 	}
 
+	// Validate step SLAs
+	for _, step := range f.Steps {
+		if step.SLA == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(step.SLA); err != nil || d <= 0 {
+			return fmt.Errorf("step %q has invalid sla %q (expected duration like \"30m\")", step.ID, step.SLA)
+		}
+	}
+
 	// Check for cycles
 	if err := f.checkCycles(); err != nil {
 		return err
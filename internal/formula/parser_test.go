@@ -2,6 +2,7 @@ package formula
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParse_Workflow(t *testing.T) {
@@ -222,6 +223,39 @@ needs = ["step1"]
 	}
 }
 
+func TestValidate_StepSLA(t *testing.T) {
+	data := []byte(`
+formula = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "step1"
+title = "Step 1"
+sla = "45m"
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := f.GetStep("step1").SLADuration(); got != 45*time.Minute {
+		t.Errorf("SLADuration() = %v, want 45m", got)
+	}
+
+	bad := []byte(`
+formula = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "step1"
+title = "Step 1"
+sla = "soon"
+`)
+	if _, err := Parse(bad); err == nil {
+		t.Error("expected error for invalid sla")
+	}
+}
+
 func TestTopologicalSort(t *testing.T) {
 	data := []byte(`
 formula = "test"
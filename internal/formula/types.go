@@ -8,6 +8,8 @@
 //   - aspect: Multi-aspect parallel analysis (like convoy but for analysis)
 package formula
 
+import "time"
+
 // FormulaType represents the type of formula.
 type FormulaType string
 
@@ -94,6 +96,7 @@ type Step struct {
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
 	Parallel    bool     `toml:"parallel"` // If true, this step can run concurrently with other parallel steps that share the same needs
+	SLA         string   `toml:"sla"`      // Optional max duration (e.g., "30m"); exceeding it raises an escalation
 }
 
 // Template represents a template step in an expansion formula.
@@ -121,6 +124,19 @@ func (t FormulaType) IsValid() bool {
 	}
 }
 
+// SLADuration returns the step's SLA as a duration, or 0 if none is set.
+// Invalid values are rejected during validation, so parse errors yield 0.
+func (s *Step) SLADuration() time.Duration {
+	if s.SLA == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s.SLA)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
 // GetDependencies returns the ordered dependencies for a step/template.
 // For convoy formulas, legs are parallel so this returns an empty slice.
 // For workflow and expansion formulas, this returns the Needs field.
@@ -55,8 +55,9 @@ func moveDir(src, dest string) error {
 
 // Git wraps git operations for a working directory.
 type Git struct {
-	workDir string
-	gitDir  string // Optional: explicit git directory (for bare repos)
+	workDir        string
+	gitDir         string // Optional: explicit git directory (for bare repos)
+	branchTemplate string // Rig's polecat_branch_template, for the push policy
 }
 
 // NewGit creates a new Git wrapper for the given directory.
@@ -64,6 +65,13 @@ func NewGit(workDir string) *Git {
 	return &Git{workDir: workDir}
 }
 
+// SetBranchTemplate sets the rig's polecat_branch_template, which the push
+// policy's "{branch}" patterns name for pushes made through g. Left empty,
+// those patterns are dropped (the default branch layout).
+func (g *Git) SetBranchTemplate(template string) {
+	g.branchTemplate = template
+}
+
 // NewGitWithDir creates a Git wrapper with an explicit git directory.
 // This is used for bare repos where gitDir points to the .git directory
 // and workDir may be empty or point to a worktree.
@@ -361,12 +369,18 @@ func (g *Git) PullFastForward(remote, branch string) error {
 	return err
 }
 
-// Push pushes to the remote branch.
+// Push pushes to the remote branch, if the push policy allows the calling
+// agent to.
 func (g *Git) Push(remote, branch string, force bool) error {
+	op := PushUpdate
 	args := []string{"push", remote, branch}
 	if force {
+		op = PushForce
 		args = append(args, "--force")
 	}
+	if err := g.CheckPush(branch, op); err != nil {
+		return err
+	}
 	_, err := g.run(args...)
 	return err
 }
@@ -571,8 +585,12 @@ func (g *Git) GetBranchCommitMessage(branch string) (string, error) {
 	return g.run("log", "-1", "--format=%B", branch)
 }
 
-// DeleteRemoteBranch deletes a branch on the remote.
+// DeleteRemoteBranch deletes a branch on the remote, if the push policy
+// allows the calling agent to.
 func (g *Git) DeleteRemoteBranch(remote, branch string) error {
+	if err := g.CheckPush(branch, PushDelete); err != nil {
+		return err
+	}
 	_, err := g.run("push", remote, "--delete", branch)
 	return err
 }
@@ -755,6 +773,19 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// ChangedFiles returns the files changed on branch since it diverged from base
+// (git diff --name-only base...branch).
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -816,6 +847,21 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 	return ConfigureSparseCheckout(path)
 }
 
+// CommonDir returns the directory holding a worktree's objects and refs,
+// which commits write to and which for a linked worktree is outside it.
+// Returns "" if workDir is not in a git repository.
+func CommonDir(workDir string) string {
+	out, err := exec.Command("git", "-C", workDir, "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
 // ConfigureSparseCheckout sets up sparse checkout for a clone or worktree to exclude .claude/.
 // This ensures source repo settings don't override Gas Town agent settings.
 // Exported for use by doctor checks.
@@ -957,6 +1003,27 @@ func (g *Git) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
+// WorktreeMove moves a worktree to a new path, keeping its git metadata linked.
+func (g *Git) WorktreeMove(from, to string) error {
+	_, err := g.run("worktree", "move", from, to)
+	return err
+}
+
+// ForceCheckoutNewBranch creates (or resets) branch at startPoint and checks
+// it out, discarding local changes to tracked files. Untracked files, such
+// as installed dependencies, are kept.
+func (g *Git) ForceCheckoutNewBranch(branch, startPoint string) error {
+	_, err := g.run("checkout", "-f", "-B", branch, startPoint)
+	return err
+}
+
+// ForceCheckoutDetached checks out ref with a detached HEAD, discarding local
+// changes to tracked files. Untracked files are kept.
+func (g *Git) ForceCheckoutDetached(ref string) error {
+	_, err := g.run("checkout", "-f", "--detach", ref)
+	return err
+}
+
 // WorktreePrune removes worktree entries for deleted paths.
 func (g *Git) WorktreePrune() error {
 	_, err := g.run("worktree", "prune")
@@ -379,6 +379,46 @@ func TestFetchBranch(t *testing.T) {
 	}
 }
 
+func TestChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	if err := g.Add("a.txt", "b.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add files"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	files, err := g.ChangedFiles(mainBranch, "feature")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(files) != 2 || files[0] != "a.txt" || files[1] != "b.txt" {
+		t.Errorf("ChangedFiles = %v, want [a.txt b.txt]", files)
+	}
+
+	files, err = g.ChangedFiles("feature", mainBranch)
+	if err != nil {
+		t.Fatalf("ChangedFiles reversed: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("ChangedFiles(feature, main) = %v, want none", files)
+	}
+}
+
 func TestCheckConflicts_NoConflict(t *testing.T) {
 	dir := initTestRepo(t)
 	g := NewGit(dir)
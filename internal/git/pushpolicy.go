@@ -0,0 +1,300 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// PushOp is the kind of change a push makes to a branch.
+type PushOp string
+
+const (
+	PushUpdate PushOp = "push"       // Fast-forward or create
+	PushForce  PushOp = "force-push" // Rewrite
+	PushDelete PushOp = "delete"     // Remove
+)
+
+// PushIdentity is the agent a push is made as.
+type PushIdentity struct {
+	Role string // polecat, crew, witness, refinery, mayor, deacon; empty outside agents
+	Rig  string
+	Name string // Polecat or crew member name
+	Town string
+}
+
+// PushIdentityFromEnv returns the identity of the agent this process runs
+// for, from GT_ROLE (simple or compound form) with GT_RIG, GT_POLECAT,
+// GT_CREW and GT_ROOT filling gaps.
+func PushIdentityFromEnv() PushIdentity {
+	id := PushIdentity{Rig: os.Getenv("GT_RIG"), Town: os.Getenv("GT_ROOT")}
+	parts := strings.Split(os.Getenv("GT_ROLE"), "/")
+	switch len(parts) {
+	case 1:
+		id.Role = parts[0]
+	case 2:
+		id.Rig, id.Role = parts[0], parts[1]
+	default:
+		id.Rig, id.Role, id.Name = parts[0], strings.TrimSuffix(parts[1], "s"), parts[2]
+	}
+	if id.Name == "" {
+		switch id.Role {
+		case "polecat":
+			id.Name = os.Getenv("GT_POLECAT")
+		case "crew":
+			id.Name = os.Getenv("GT_CREW")
+		}
+	}
+	return id
+}
+
+// String returns the identity in GT_ROLE form, e.g. "gastown/polecats/Toast".
+func (id PushIdentity) String() string {
+	switch {
+	case id.Rig == "":
+		return id.Role
+	case id.Name == "":
+		return id.Rig + "/" + id.Role
+	case id.Role == "polecat":
+		return id.Rig + "/polecats/" + id.Name
+	default:
+		return id.Rig + "/" + id.Role + "/" + id.Name
+	}
+}
+
+// PushDeniedError reports a push to a branch the pushing role's rules don't
+// allow.
+type PushDeniedError struct {
+	Identity PushIdentity
+	Op       PushOp
+	Branch   string
+	Allowed  []string // Branches the role may make this kind of push to
+}
+
+func (e *PushDeniedError) Error() string {
+	allowed := "none"
+	if len(e.Allowed) > 0 {
+		allowed = strings.Join(e.Allowed, ", ")
+	}
+	return fmt.Sprintf("push policy: %s may not %s %s (allowed: %s)", e.Identity, e.Op, e.Branch, allowed)
+}
+
+// CheckPushRules checks a push against a role's rules, with defaultBranch
+// standing in for "{default}". Nil rules allow every push; tags are never
+// restricted.
+func CheckPushRules(id PushIdentity, rules *config.PushRules, defaultBranch, ref string, op PushOp) error {
+	if rules == nil || strings.HasPrefix(ref, "refs/tags/") {
+		return nil
+	}
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+
+	var patterns []string
+	switch op {
+	case PushForce:
+		patterns = rules.ForcePush
+	case PushDelete:
+		patterns = rules.Delete
+	default:
+		patterns = rules.Push
+	}
+
+	var allowed []string
+	for _, p := range patterns {
+		p = strings.NewReplacer("{name}", id.Name, "{default}", defaultBranch).Replace(p)
+		if branchMatches(p, branch) {
+			return nil
+		}
+		allowed = append(allowed, p)
+	}
+	return &PushDeniedError{Identity: id, Op: op, Branch: branch, Allowed: allowed}
+}
+
+// branchMatches reports whether branch matches pattern, where "*" matches
+// any run of characters.
+func branchMatches(pattern, branch string) bool {
+	re := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(re, branch)
+	return ok
+}
+
+// CheckPush checks a push of refspec (a branch, "src:dst" or "+src:dst")
+// against the rules of the agent this process runs for, as every push made
+// through this package is. Only repositories inside the agent's town
+// (GT_ROOT) are covered. "{branch}" patterns name the branch template set
+// with SetBranchTemplate. A denied push is escalated before the error is
+// returned.
+func (g *Git) CheckPush(refspec string, op PushOp) error {
+	id := PushIdentityFromEnv()
+	if id.Town == "" || !g.within(id.Town) {
+		return nil
+	}
+	var rigPath string
+	if id.Rig != "" {
+		rigPath = filepath.Join(id.Town, id.Rig)
+	}
+	rules := config.LoadPushRules(rigPath, id.Role)
+	if rules == nil {
+		return nil
+	}
+	rules = rules.WithBranchTemplate(g.branchTemplate)
+
+	if strings.HasPrefix(refspec, "+") {
+		refspec, op = refspec[1:], PushForce
+	}
+	ref := refspec
+	if i := strings.LastIndex(refspec, ":"); i >= 0 {
+		ref = refspec[i+1:]
+	}
+	if ref == "HEAD" || ref == "" {
+		branch, err := g.CurrentBranch()
+		if err != nil {
+			return fmt.Errorf("push policy: resolving %s: %w", refspec, err)
+		}
+		ref = branch
+	}
+
+	err := CheckPushRules(id, rules, g.pushTarget(rigPath), ref, op)
+	if denied, ok := err.(*PushDeniedError); ok {
+		escalatePushDenied(denied)
+	}
+	return err
+}
+
+// within reports whether the repository lies beneath dir.
+func (g *Git) within(dir string) bool {
+	repo := g.workDir
+	if repo == "" {
+		repo = g.gitDir
+	}
+	resolve := func(path string) string {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			path = real
+		}
+		return path
+	}
+	rel, err := filepath.Rel(resolve(dir), resolve(repo))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// pushTarget returns the branch "{default}" stands for: the rig's merge
+// queue target if set, else the remote's default branch.
+func (g *Git) pushTarget(rigPath string) string {
+	if rigPath != "" {
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil &&
+			settings.MergeQueue != nil && settings.MergeQueue.TargetBranch != "" {
+			return settings.MergeQueue.TargetBranch
+		}
+	}
+	return g.RemoteDefaultBranch()
+}
+
+// escalatePushDenied raises a denied push with gt escalate, so the mayor
+// hears about an agent pushing outside its branches. Best-effort; a
+// variable so tests can replace it.
+var escalatePushDenied = func(e *PushDeniedError) {
+	cmd := exec.Command("gt", "escalate", "--severity", "high", "--source", "push-policy", //nolint:gosec // G204: args are not shell-interpreted
+		"--reason", e.Error(),
+		fmt.Sprintf("%s tried to %s %s", e.Identity, e.Op, e.Branch))
+	_ = cmd.Run()
+}
+
+// zeroSHA is the object name git's pre-push hook input uses for a ref that
+// doesn't exist on one side.
+var zeroSHA = strings.Repeat("0", 40)
+
+// CheckPrePush checks the ref updates a pre-push hook reads from standard
+// input ("<local ref> <local sha> <remote ref> <remote sha>" lines) against
+// the push policy, and returns the first denial. An update that doesn't
+// fast-forward the remote ref counts as a force push.
+func (g *Git) CheckPrePush(input io.Reader) error {
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		localSHA, remoteRef, remoteSHA := fields[1], fields[2], fields[3]
+
+		op := PushUpdate
+		switch {
+		case strings.Trim(localSHA, "0") == "":
+			op = PushDelete
+		case strings.Trim(remoteSHA, "0") != "":
+			// A remote commit we don't have can't be an ancestor either
+			if ok, err := g.IsAncestor(remoteSHA, localSHA); err != nil || !ok {
+				op = PushForce
+			}
+		}
+		if err := g.CheckPush(remoteRef, op); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// PrePushHookMarker identifies the pre-push hook Gas Town installs.
+const PrePushHookMarker = "# Gas Town push policy"
+
+// ErrHooksPathSet means the clone takes its hooks from core.hooksPath (such
+// as a tracked .githooks directory), which InstallPrePushHook leaves alone.
+var ErrHooksPathSet = errors.New("core.hooksPath is set")
+
+// prePushHook checks agents' pushes with gt push-policy pre-push, then runs
+// the hook it replaced, if any.
+const prePushHook = `#!/bin/sh
+` + PrePushHookMarker + `: checks agents' pushes against the rig's
+# push_policy. Installed by gt push-policy install; a pre-push hook that was
+# here before runs afterwards as pre-push.local.
+input=$(cat)
+if [ -n "$GT_ROLE" ] && command -v gt >/dev/null 2>&1; then
+	printf '%s\n' "$input" | gt push-policy pre-push "$@" || exit 1
+fi
+local_hook="$(dirname "$0")/pre-push.local"
+if [ -x "$local_hook" ]; then
+	printf '%s\n' "$input" | "$local_hook" "$@" || exit 1
+fi
+exit 0
+`
+
+// InstallPrePushHook installs the push policy pre-push hook in the clone's
+// hooks directory, which worktrees of one repository share, and returns its
+// path. A pre-push hook already there is kept as pre-push.local and run
+// after the policy check. Returns ErrHooksPathSet if core.hooksPath is set.
+func (g *Git) InstallPrePushHook() (string, error) {
+	if hooksPath, _ := g.ConfigGet("core.hooksPath"); hooksPath != "" {
+		return "", fmt.Errorf("%w (%s)", ErrHooksPathSet, hooksPath)
+	}
+	hooksDir, err := g.run("rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("finding hooks directory: %w", err)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("creating hooks directory: %w", err)
+	}
+
+	hookPath := filepath.Join(hooksDir, "pre-push")
+	if data, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(data), PrePushHookMarker) { //nolint:gosec // G304: path is the clone's own hooks dir
+		localPath := hookPath + ".local"
+		if _, err := os.Stat(localPath); err == nil {
+			return "", fmt.Errorf("%s and %s both exist", hookPath, localPath)
+		}
+		if err := os.Rename(hookPath, localPath); err != nil {
+			return "", fmt.Errorf("keeping existing hook: %w", err)
+		}
+	}
+	if err := os.WriteFile(hookPath, []byte(prePushHook), 0755); err != nil { //nolint:gosec // G306: hooks must be executable
+		return "", fmt.Errorf("writing hook: %w", err)
+	}
+	return hookPath, nil
+}
@@ -0,0 +1,333 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// noEscalations records denied pushes instead of running gt escalate.
+func noEscalations(t *testing.T) *[]*PushDeniedError {
+	t.Helper()
+	var denied []*PushDeniedError
+	orig := escalatePushDenied
+	escalatePushDenied = func(e *PushDeniedError) { denied = append(denied, e) }
+	t.Cleanup(func() { escalatePushDenied = orig })
+	return &denied
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCheckPushRules(t *testing.T) {
+	defaults := config.DefaultPushRules()
+	polecat := PushIdentity{Role: "polecat", Rig: "gastown", Name: "Toast"}
+	refinery := PushIdentity{Role: "refinery", Rig: "gastown"}
+
+	tests := []struct {
+		id      PushIdentity
+		ref     string
+		op      PushOp
+		allowed bool
+	}{
+		{polecat, "polecat/Toast/gt-abc@mk1", PushUpdate, true},
+		{polecat, "refs/heads/polecat/Toast-mk1", PushForce, true},
+		{polecat, "polecat/Toast", PushDelete, true},
+		{polecat, "beads-sync", PushUpdate, true},
+		{polecat, "beads-sync", PushForce, false},
+		{polecat, "polecat/Nux/gt-abc", PushUpdate, false},
+		{polecat, "polecat/Toaster/gt-abc", PushUpdate, false},
+		{polecat, "main", PushUpdate, false},
+		{polecat, "refs/tags/v1.0.0", PushUpdate, true},
+		{refinery, "develop", PushUpdate, true},
+		{refinery, "main", PushUpdate, false},
+		{refinery, "integration/gt-epic", PushUpdate, true},
+		{refinery, "develop", PushForce, false},
+		{refinery, "polecat/Toast/gt-abc", PushDelete, true},
+		{refinery, "polecat/Toast/gt-abc", PushUpdate, false},
+	}
+	for _, tt := range tests {
+		err := CheckPushRules(tt.id, defaults[tt.id.Role], "develop", tt.ref, tt.op)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s %s %s: err = %v, want allowed = %v", tt.id, tt.op, tt.ref, err, tt.allowed)
+		}
+	}
+
+	// Roles without rules may push anywhere
+	if err := CheckPushRules(PushIdentity{Role: "crew"}, nil, "main", "main", PushForce); err != nil {
+		t.Errorf("crew force-push: %v", err)
+	}
+}
+
+func TestCheckPushRules_BranchTemplate(t *testing.T) {
+	polecat := PushIdentity{Role: "polecat", Rig: "gastown", Name: "Toast"}
+	defaults := config.DefaultPushRules()["polecat"]
+
+	tests := []struct {
+		template string
+		ref      string
+		allowed  bool
+	}{
+		{"", "polecat/Toast/gt-abc@mk1", true},
+		{"", "adam/26/01/fix-auth-bug", false},
+		{"adam/{year}/{month}/{description}", "adam/26/01/fix-auth-bug", true},
+		{"adam/{year}/{month}/{description}", "polecat/Toast-mk1", true},
+		{"adam/{year}/{month}/{description}", "main", false},
+		{"work/{name}/{issue}", "work/Toast/123", true},
+		{"work/{name}/{issue}", "work/Nux/123", false},
+	}
+	for _, tt := range tests {
+		rules := defaults.WithBranchTemplate(tt.template)
+		err := CheckPushRules(polecat, rules, "main", tt.ref, PushUpdate)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("template %q, push %s: err = %v, want allowed = %v", tt.template, tt.ref, err, tt.allowed)
+		}
+	}
+}
+
+func TestPushDeniedError(t *testing.T) {
+	id := PushIdentity{Role: "polecat", Rig: "gastown", Name: "Toast"}
+	err := CheckPushRules(id, &config.PushRules{Push: []string{"polecat/{name}/*"}}, "main", "main", PushForce)
+	var denied *PushDeniedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("err = %v, want *PushDeniedError", err)
+	}
+	want := "push policy: gastown/polecats/Toast may not force-push main (allowed: none)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestPushIdentityFromEnv(t *testing.T) {
+	tests := []struct {
+		role, rig, polecat string
+		want               PushIdentity
+	}{
+		{"gastown/polecats/Toast", "", "", PushIdentity{Role: "polecat", Rig: "gastown", Name: "Toast"}},
+		{"gastown/crew/max", "", "", PushIdentity{Role: "crew", Rig: "gastown", Name: "max"}},
+		{"gastown/refinery", "", "", PushIdentity{Role: "refinery", Rig: "gastown"}},
+		{"polecat", "gastown", "Nux", PushIdentity{Role: "polecat", Rig: "gastown", Name: "Nux"}},
+		{"mayor", "", "", PushIdentity{Role: "mayor"}},
+		{"", "", "", PushIdentity{}},
+	}
+	for _, tt := range tests {
+		t.Setenv("GT_ROLE", tt.role)
+		t.Setenv("GT_RIG", tt.rig)
+		t.Setenv("GT_POLECAT", tt.polecat)
+		t.Setenv("GT_ROOT", "")
+		if got := PushIdentityFromEnv(); got != tt.want {
+			t.Errorf("GT_ROLE=%q: got %+v, want %+v", tt.role, got, tt.want)
+		}
+	}
+}
+
+// pushPolicyRepo returns a clone with a bare origin and the environment of
+// polecat Toast in a town whose gastown rig targets main.
+func pushPolicyRepo(t *testing.T) *Git {
+	t.Helper()
+	town := t.TempDir()
+	remote := filepath.Join(town, "remote.git")
+	runGit(t, town, "init", "--bare", "-b", "main", remote)
+
+	seed := initTestRepo(t)
+	runGit(t, seed, "branch", "-M", "main")
+	runGit(t, seed, "push", "-q", remote, "main")
+
+	dir := filepath.Join(town, "gastown", "polecats", "Toast", "gastown")
+	runGit(t, town, "clone", "-q", remote, dir)
+	runGit(t, dir, "config", "user.email", "test@test.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+
+	t.Setenv("GT_ROOT", town)
+	t.Setenv("GT_ROLE", "gastown/polecats/Toast")
+	t.Setenv("GT_RIG", "")
+	t.Setenv("GT_POLECAT", "")
+	return NewGit(dir)
+}
+
+func TestPushEnforcesPolicy(t *testing.T) {
+	denied := noEscalations(t)
+	g := pushPolicyRepo(t)
+
+	runGit(t, g.WorkDir(), "checkout", "-q", "-b", "polecat/Toast/gt-abc")
+	if err := g.Push("origin", "polecat/Toast/gt-abc", false); err != nil {
+		t.Fatalf("push own branch: %v", err)
+	}
+	if err := g.Push("origin", "HEAD:main", false); err == nil {
+		t.Fatal("push to main: want denied")
+	}
+	if err := g.DeleteRemoteBranch("origin", "polecat/Toast/gt-abc"); err != nil {
+		t.Errorf("delete own branch: %v", err)
+	}
+	if len(*denied) != 1 || (*denied)[0].Branch != "main" {
+		t.Errorf("escalations = %v, want the push to main", *denied)
+	}
+	if runGit(t, g.WorkDir(), "ls-remote", "origin", "main") == "" {
+		t.Error("main was removed from origin")
+	}
+
+	// Repositories outside the town aren't covered
+	if err := NewGit(initTestRepo(t)).CheckPush("main", PushForce); err != nil {
+		t.Errorf("repo outside the town: %v", err)
+	}
+}
+
+func TestCheckPrePush(t *testing.T) {
+	denied := noEscalations(t)
+	g := pushPolicyRepo(t)
+	dir := g.WorkDir()
+
+	base := runGit(t, dir, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "a")
+	head := runGit(t, dir, "rev-parse", "HEAD")
+	runGit(t, dir, "reset", "-q", "--hard", base)
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "b")
+	rewritten := runGit(t, dir, "rev-parse", "HEAD")
+
+	line := func(remoteRef, localSHA, remoteSHA string) string {
+		return "refs/heads/x " + localSHA + " " + remoteRef + " " + remoteSHA + "\n"
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  PushOp // Op of the denial; empty when allowed
+	}{
+		{"new own branch", line("refs/heads/polecat/Toast/gt-abc", head, zeroSHA), ""},
+		{"fast-forward own branch", line("refs/heads/polecat/Toast/gt-abc", head, base), ""},
+		{"rewrite own branch", line("refs/heads/polecat/Toast/gt-abc", rewritten, head), ""},
+		{"fast-forward main", line("refs/heads/main", head, base), PushUpdate},
+		{"rewrite beads-sync", line("refs/heads/beads-sync", rewritten, head), PushForce},
+		{"delete main", line("refs/heads/main", zeroSHA, base), PushDelete},
+		{"tag", line("refs/tags/v1", head, zeroSHA), ""},
+		{"second ref denied", line("refs/heads/polecat/Toast", head, zeroSHA) + line("refs/heads/main", head, base), PushUpdate},
+	}
+	for _, tt := range tests {
+		*denied = nil
+		err := g.CheckPrePush(strings.NewReader(tt.input))
+		var got PushOp
+		var de *PushDeniedError
+		if errors.As(err, &de) {
+			got = de.Op
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: denied op = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInstallPrePushHook(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "#!/bin/sh\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := g.InstallPrePushHook()
+	if err != nil {
+		t.Fatalf("InstallPrePushHook: %v", err)
+	}
+	if want := filepath.Join(hooksDir, "pre-push"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), PrePushHookMarker) {
+		t.Error("hook not installed")
+	}
+	if local, _ := os.ReadFile(path + ".local"); string(local) != existing {
+		t.Errorf("pre-push.local = %q, want the existing hook", local)
+	}
+
+	// Reinstalling keeps the chained hook
+	if _, err := g.InstallPrePushHook(); err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+	if local, _ := os.ReadFile(path + ".local"); string(local) != existing {
+		t.Errorf("after reinstall pre-push.local = %q", local)
+	}
+
+	runGit(t, dir, "config", "core.hooksPath", ".githooks")
+	if _, err := g.InstallPrePushHook(); !errors.Is(err, ErrHooksPathSet) {
+		t.Errorf("with core.hooksPath: err = %v, want ErrHooksPathSet", err)
+	}
+}
+
+func TestCheckPushRigRules(t *testing.T) {
+	noEscalations(t)
+	g := pushPolicyRepo(t)
+	rigPath := filepath.Join(os.Getenv("GT_ROOT"), "gastown")
+
+	settings := config.NewRigSettings()
+	settings.MergeQueue.TargetBranch = "develop"
+	settings.PushPolicy = &config.PushPolicyConfig{Roles: map[string]*config.PushRules{
+		"polecat":  {Push: []string{"polecat/{name}/*", "{default}"}},
+		"refinery": {Push: []string{"{default}", "release/*"}},
+	}}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.CheckPush("develop", PushUpdate); err != nil {
+		t.Errorf("polecat push to the rig's target: %v", err)
+	}
+	if err := g.CheckPush("+HEAD:refs/heads/polecat/Toast/gt-abc", PushUpdate); err == nil {
+		t.Error("polecat force-push with no force_push rules: want denied")
+	}
+
+	t.Setenv("GT_ROLE", "gastown/refinery")
+	if err := g.CheckPush("release/1.0", PushUpdate); err != nil {
+		t.Errorf("refinery push to release branch: %v", err)
+	}
+	if err := g.CheckPush("main", PushUpdate); err == nil {
+		t.Error("refinery push to main when the target is develop: want denied")
+	}
+
+	// Roles the rig doesn't list keep no rules
+	t.Setenv("GT_ROLE", "gastown/crew/max")
+	if err := g.CheckPush("main", PushForce); err != nil {
+		t.Errorf("crew force-push: %v", err)
+	}
+}
+
+func TestCheckPushBranchTemplate(t *testing.T) {
+	noEscalations(t)
+	g := pushPolicyRepo(t)
+
+	if err := g.CheckPush("adam/2026/10/fix-login", PushUpdate); err == nil {
+		t.Error("push to a template branch with no template set: want denied")
+	}
+	g.SetBranchTemplate("adam/{year}/{month}/{description}")
+	if err := g.CheckPush("adam/2026/10/fix-login", PushUpdate); err != nil {
+		t.Errorf("push to a template branch: %v", err)
+	}
+	if err := g.CheckPush("polecat/Toast/gt-abc", PushUpdate); err != nil {
+		t.Errorf("push to the default layout with a template set: %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+package headless
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+	"golang.org/x/term"
+)
+
+var (
+	// ErrNotFound means no host is serving the session.
+	ErrNotFound = errors.New("session not found")
+
+	// ErrExists means a host is already serving the session.
+	ErrExists = errors.New("duplicate session")
+)
+
+// startTimeout is how long Start waits for a new host's socket.
+const startTimeout = 5 * time.Second
+
+// detachKey is Ctrl-\, which ends an Attach.
+const detachKey = 0x1c
+
+// Dir returns the directory holding session sockets: $GT_HEADLESS_DIR, or
+// a per-user directory under the system temp dir.
+func Dir() string {
+	if dir := os.Getenv("GT_HEADLESS_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "gt-headless-"+strconv.Itoa(os.Getuid()))
+}
+
+// checkDir refuses a socket dir another user could have planted sockets in
+// (see util.CheckPrivateDir): gt would type into their sessions.
+func checkDir() error {
+	if err := util.CheckPrivateDir(Dir()); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("unsafe session socket dir: %w", err)
+	}
+	return nil
+}
+
+// ensureDir creates the socket dir for a new session, or refuses it like
+// checkDir.
+func ensureDir() error {
+	if err := util.EnsurePrivateDir(Dir()); err != nil {
+		return fmt.Errorf("unsafe session socket dir: %w", err)
+	}
+	return nil
+}
+
+// SocketPath returns the socket a session's host listens on.
+func SocketPath(name string) string {
+	return filepath.Join(Dir(), name+".sock")
+}
+
+func logPath(name string) string {
+	return filepath.Join(Dir(), name+".log")
+}
+
+// paneID is the session's stand-in for a tmux pane ID. Targets accept it
+// wherever they accept the session name.
+func paneID(name string) string {
+	return "%" + name
+}
+
+func validName(name string) error {
+	if name == "" || strings.ContainsAny(name, "/:.%=") {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	return nil
+}
+
+// alive reports whether a host answers on sock.
+func alive(sock string) bool {
+	conn, err := net.DialTimeout("unix", sock, time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// Start runs a session's host in the background (gt session-host) and
+// waits until it is serving.
+func Start(opts Options) error {
+	if err := validName(opts.Name); err != nil {
+		return err
+	}
+	if err := ensureDir(); err != nil {
+		return err
+	}
+	if alive(SocketPath(opts.Name)) {
+		return ErrExists
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding gt executable: %w", err)
+	}
+
+	args := []string{"session-host", "--name", opts.Name}
+	if opts.WorkDir != "" {
+		args = append(args, "--dir", opts.WorkDir)
+	}
+	if opts.Width > 0 && opts.Height > 0 {
+		args = append(args, "--width", strconv.Itoa(opts.Width), "--height", strconv.Itoa(opts.Height))
+	}
+	if opts.Command != "" {
+		args = append(args, "--", opts.Command)
+	}
+
+	// The host's own errors go to a log beside its socket, so a host that
+	// dies on startup can say why.
+	logFile, err := os.Create(logPath(opts.Name))
+	if err != nil {
+		return fmt.Errorf("creating host log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, args...) //nolint:gosec // G204: re-executing gt itself
+	cmd.Env = append(os.Environ(), opts.Env...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting session host: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	deadline := time.After(startTimeout)
+	for {
+		if alive(SocketPath(opts.Name)) {
+			return nil
+		}
+		select {
+		case <-exited:
+			return fmt.Errorf("session host exited: %s", hostLog(opts.Name))
+		case <-deadline:
+			return fmt.Errorf("session host did not start within %s", startTimeout)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func hostLog(name string) string {
+	data, err := os.ReadFile(logPath(name))
+	if err != nil || len(strings.TrimSpace(string(data))) == 0 {
+		return "no output"
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Do sends one request to a session's host.
+func Do(name string, req Request) (*Response, error) {
+	conn, err := dial(name)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	resp, _, err := roundTrip(conn, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+func dial(name string) (net.Conn, error) {
+	if err := validName(name); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := checkDir(); err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", SocketPath(name), time.Second)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return conn, nil
+}
+
+// Exists reports whether a session is running.
+func Exists(name string) bool {
+	return validName(name) == nil && checkDir() == nil && alive(SocketPath(name))
+}
+
+// List describes the running sessions, sorted by name.
+func List() ([]*Info, error) {
+	if err := checkDir(); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	socks, err := filepath.Glob(filepath.Join(Dir(), "*.sock"))
+	if err != nil {
+		return nil, err
+	}
+	var infos []*Info
+	for _, sock := range socks {
+		name := strings.TrimSuffix(filepath.Base(sock), ".sock")
+		resp, err := Do(name, Request{Op: OpInfo})
+		if err != nil || resp.Info == nil {
+			continue
+		}
+		infos = append(infos, resp.Info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Reap removes the sockets and logs of hosts that died without cleaning
+// up, and returns how many it removed.
+func Reap() (int, error) {
+	if err := checkDir(); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	socks, err := filepath.Glob(filepath.Join(Dir(), "*.sock"))
+	if err != nil {
+		return 0, err
+	}
+	reaped := 0
+	for _, sock := range socks {
+		if alive(sock) {
+			continue
+		}
+		if err := os.Remove(sock); err != nil && !os.IsNotExist(err) {
+			return reaped, err
+		}
+		_ = os.Remove(strings.TrimSuffix(sock, ".sock") + ".log")
+		reaped++
+	}
+	return reaped, nil
+}
+
+// Attach connects the terminal on in and out to a session until the
+// session ends or the user presses Ctrl-\.
+func Attach(name string, in, out *os.File) error {
+	conn, err := dial(name)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := Request{Op: OpAttach}
+	fd := int(in.Fd())
+	if term.IsTerminal(fd) {
+		if w, h, err := term.GetSize(int(out.Fd())); err == nil {
+			req.Width, req.Height = w, h
+		}
+	}
+	resp, r, err := roundTrip(conn, req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+
+	if term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("setting raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+		stop := watchResize(func() {
+			if w, h, err := term.GetSize(int(out.Fd())); err == nil {
+				_, _ = Do(name, Request{Op: OpResize, Width: w, Height: h})
+			}
+		})
+		defer stop()
+	}
+
+	ended := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(out, r)
+		close(ended)
+	}()
+
+	detached := make(chan struct{})
+	go func() {
+		defer close(detached)
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				data := buf[:n]
+				if i := bytes.IndexByte(data, detachKey); i >= 0 {
+					_, _ = conn.Write(data[:i])
+					return
+				}
+				if _, err := conn.Write(data); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ended:
+	case <-detached:
+	}
+	_, _ = out.WriteString("\r\n")
+	return nil
+}
@@ -0,0 +1,546 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default terminal size for new sessions, matching a detached tmux session.
+const (
+	DefaultWidth  = 200
+	DefaultHeight = 50
+)
+
+// Options describes a headless session to start.
+type Options struct {
+	Name    string
+	WorkDir string
+	Command string   // Run with /bin/sh -c; empty for the user's shell
+	Env     []string // Added to the session program's environment
+	Width   int
+	Height  int
+}
+
+// host runs one session: the program on its PTY, the screen fed from it,
+// and the socket clients reach it through. It lives in its own process
+// (gt session-host) so sessions outlive the gt command that started them,
+// as tmux sessions outlive tmux clients.
+type host struct {
+	opts    Options
+	ln      net.Listener
+	screen  *screen
+	created time.Time
+
+	// outMu orders output with attaching, so a new client's redraw is never
+	// interleaved with output it already contains.
+	outMu sync.Mutex
+
+	mu         sync.Mutex
+	cmd        *exec.Cmd
+	exited     chan struct{} // Closed once cmd has been reaped
+	pty        *os.File
+	env        map[string]string
+	options    map[string]string
+	hooks      map[string]string
+	clients    map[net.Conn]bool
+	pipe       io.WriteCloser
+	activity   time.Time
+	dead       bool
+	deadStatus int
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// Serve runs a session until its program exits or it is killed. It is the
+// body of gt session-host; use Start to run it in the background.
+func Serve(opts Options) error {
+	if err := validName(opts.Name); err != nil {
+		return err
+	}
+	if opts.Width <= 0 {
+		opts.Width = DefaultWidth
+	}
+	if opts.Height <= 0 {
+		opts.Height = DefaultHeight
+	}
+	if err := ensureDir(); err != nil {
+		return err
+	}
+
+	sock := SocketPath(opts.Name)
+	if alive(sock) {
+		return ErrExists
+	}
+	_ = os.Remove(sock)
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", sock, err)
+	}
+
+	now := time.Now()
+	h := &host{
+		opts:     opts,
+		ln:       ln,
+		screen:   newScreen(opts.Width, opts.Height),
+		created:  now,
+		activity: now,
+		env:      make(map[string]string),
+		options:  make(map[string]string),
+		hooks:    make(map[string]string),
+		clients:  make(map[net.Conn]bool),
+		done:     make(chan struct{}),
+	}
+	if err := h.spawn(opts.Command, opts.WorkDir); err != nil {
+		_ = ln.Close()
+		_ = os.Remove(sock)
+		return err
+	}
+
+	go h.acceptLoop()
+	<-h.done
+
+	_ = ln.Close()
+	_ = os.Remove(sock)
+	_ = os.Remove(logPath(opts.Name))
+	h.mu.Lock()
+	for c := range h.clients {
+		_ = c.Close()
+	}
+	if h.pipe != nil {
+		_ = h.pipe.Close()
+	}
+	if h.pty != nil {
+		_ = h.pty.Close()
+	}
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *host) shutdown() {
+	h.doneOnce.Do(func() { close(h.done) })
+}
+
+// spawn starts command on a new PTY, replacing the current program.
+// Callers must not hold h.mu.
+func (h *host) spawn(command, workDir string) error {
+	var cmd *exec.Cmd
+	if command == "" {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		cmd = exec.Command(shell) //nolint:gosec // G204: the user's own shell
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", command) //nolint:gosec // G204: session command from gt
+	}
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), h.opts.Env...)
+	cmd.Env = append(cmd.Env,
+		"TERM=xterm-256color",
+		"TMUX_PANE="+paneID(h.opts.Name),
+	)
+
+	width, height := h.screen.Size()
+	pty, err := startPTY(cmd, width, height)
+	if err != nil {
+		return fmt.Errorf("starting %q: %w", command, err)
+	}
+
+	exited := make(chan struct{})
+	h.mu.Lock()
+	h.cmd, h.exited, h.pty = cmd, exited, pty
+	h.dead = false
+	h.mu.Unlock()
+
+	readDone := make(chan struct{})
+	go h.readLoop(pty, readDone)
+	go h.wait(cmd, exited, pty, readDone)
+	return nil
+}
+
+func (h *host) readLoop(pty *os.File, done chan<- struct{}) {
+	defer close(done)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := pty.Read(buf)
+		if n > 0 {
+			h.output(buf[:n])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wait reaps a program. When the session's current program exits it runs
+// the pane-died hook, and the session ends with it unless remain-on-exit
+// keeps it for a respawn.
+func (h *host) wait(cmd *exec.Cmd, exited chan<- struct{}, pty *os.File, readDone <-chan struct{}) {
+	err := cmd.Wait()
+	close(exited)
+	status := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		status = exitErr.ExitCode()
+	}
+
+	// Let the last of the output reach the screen.
+	select {
+	case <-readDone:
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	h.mu.Lock()
+	if h.cmd != cmd {
+		// Respawned; the new program owns the session now.
+		h.mu.Unlock()
+		_ = pty.Close()
+		return
+	}
+	hook := h.hooks[HookPaneDied]
+	remain := h.options["remain-on-exit"] == "on"
+	if remain {
+		h.dead, h.deadStatus = true, status
+	}
+	h.mu.Unlock()
+
+	if hook != "" {
+		h.runHook(strings.ReplaceAll(hook, "#{pane_dead_status}", strconv.Itoa(status)))
+	}
+	if !remain {
+		h.shutdown()
+	}
+}
+
+// runHook runs a hook command to completion, logging a failure to the
+// host's log.
+func (h *host) runHook(command string) {
+	cmd := exec.Command("/bin/sh", "-c", command) //nolint:gosec // G204: hook command from gt
+	cmd.Dir = h.opts.WorkDir
+	cmd.Env = append(os.Environ(), h.opts.Env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "hook %q: %v: %s\n", command, err, out)
+	}
+}
+
+func (h *host) output(p []byte) {
+	h.outMu.Lock()
+	defer h.outMu.Unlock()
+
+	_, _ = h.screen.Write(p)
+
+	h.mu.Lock()
+	h.activity = time.Now()
+	pipe := h.pipe
+	clients := make([]net.Conn, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	if pipe != nil {
+		if _, err := pipe.Write(p); err != nil {
+			h.closePipe(pipe)
+		}
+	}
+	for _, c := range clients {
+		_ = c.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err := c.Write(p); err != nil {
+			h.dropClient(c)
+		}
+	}
+}
+
+func (h *host) dropClient(c net.Conn) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	_ = c.Close()
+}
+
+func (h *host) closePipe(pipe io.WriteCloser) {
+	h.mu.Lock()
+	if h.pipe == pipe {
+		h.pipe = nil
+	}
+	h.mu.Unlock()
+	_ = pipe.Close()
+}
+
+// input writes to the program as if typed.
+func (h *host) input(p []byte) error {
+	h.mu.Lock()
+	pty := h.pty
+	dead := h.dead
+	h.mu.Unlock()
+	if pty == nil || dead {
+		return errors.New("pane is dead")
+	}
+	_, err := pty.Write(p)
+	return err
+}
+
+func (h *host) acceptLoop() {
+	for {
+		conn, err := h.ln.Accept()
+		if err != nil {
+			return
+		}
+		go h.handle(conn)
+	}
+}
+
+func (h *host) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		h.reply(conn, &Response{Error: fmt.Sprintf("bad request: %v", err)})
+		_ = conn.Close()
+		return
+	}
+
+	if req.Op == OpAttach {
+		h.attach(conn, r, req)
+		return
+	}
+	resp := h.do(req)
+	h.reply(conn, resp)
+	_ = conn.Close()
+	if req.Op == OpKill && resp.Error == "" {
+		h.shutdown()
+	}
+}
+
+func (h *host) reply(conn net.Conn, resp *Response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(&Response{Error: err.Error()})
+	}
+	_, _ = conn.Write(append(data, '\n'))
+}
+
+func (h *host) do(req Request) *Response {
+	switch req.Op {
+	case OpInfo:
+		return &Response{Info: h.info()}
+
+	case OpCapture:
+		return &Response{Lines: h.screen.Capture(req.Lines)}
+
+	case OpSend:
+		if err := h.input([]byte(req.Data)); err != nil {
+			return &Response{Error: err.Error()}
+		}
+		return &Response{}
+
+	case OpSetEnv:
+		h.mu.Lock()
+		h.env[req.Key] = req.Value
+		h.mu.Unlock()
+		return &Response{}
+
+	case OpGetEnv:
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if req.Key == "" {
+			env := make(map[string]string, len(h.env))
+			for k, v := range h.env {
+				env[k] = v
+			}
+			return &Response{Env: env}
+		}
+		v, ok := h.env[req.Key]
+		return &Response{Value: v, Found: ok}
+
+	case OpKill:
+		h.mu.Lock()
+		cmd, exited := h.cmd, h.exited
+		h.cmd = nil
+		h.mu.Unlock()
+		kill(cmd, exited)
+		// Stop answering before replying, so the session is gone by the
+		// time the caller hears back.
+		_ = h.ln.Close()
+		_ = os.Remove(SocketPath(h.opts.Name))
+		return &Response{}
+
+	case OpResize:
+		if req.Width <= 0 || req.Height <= 0 {
+			return &Response{Error: "resize needs a width and height"}
+		}
+		h.resize(req.Width, req.Height)
+		return &Response{}
+
+	case OpClear:
+		h.screen.ClearHistory()
+		return &Response{}
+
+	case OpRespawn:
+		h.mu.Lock()
+		old, exited := h.cmd, h.exited
+		h.cmd = nil
+		h.mu.Unlock()
+		kill(old, exited)
+		command, workDir := req.Command, req.WorkDir
+		if command == "" {
+			command = h.opts.Command
+		}
+		if workDir == "" {
+			workDir = h.opts.WorkDir
+		}
+		if err := h.spawn(command, workDir); err != nil {
+			return &Response{Error: err.Error()}
+		}
+		return &Response{}
+
+	case OpPipe:
+		return h.setPipe(req.Command)
+
+	case OpOption:
+		h.mu.Lock()
+		h.options[req.Key] = req.Value
+		h.mu.Unlock()
+		return &Response{}
+
+	case OpHook:
+		if req.Key != HookPaneDied {
+			return &Response{Error: fmt.Sprintf("unknown hook %q", req.Key)}
+		}
+		h.mu.Lock()
+		if req.Command == "" {
+			delete(h.hooks, req.Key)
+		} else {
+			h.hooks[req.Key] = req.Command
+		}
+		h.mu.Unlock()
+		return &Response{}
+	}
+	return &Response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
+
+func (h *host) resize(width, height int) {
+	h.screen.Resize(width, height)
+	h.mu.Lock()
+	pty := h.pty
+	h.mu.Unlock()
+	if pty != nil {
+		_ = resizePTY(pty, width, height)
+	}
+}
+
+// setPipe starts copying output to command, keeping an existing pipe, or
+// closes the pipe if command is empty.
+func (h *host) setPipe(command string) *Response {
+	h.mu.Lock()
+	existing := h.pipe
+	h.mu.Unlock()
+	if command == "" {
+		if existing != nil {
+			h.closePipe(existing)
+		}
+		return &Response{}
+	}
+	if existing != nil {
+		return &Response{}
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", command) //nolint:gosec // G204: pipe command from gt
+	cmd.Dir = h.opts.WorkDir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return &Response{Error: err.Error()}
+	}
+	if err := cmd.Start(); err != nil {
+		return &Response{Error: fmt.Sprintf("starting pipe: %v", err)}
+	}
+	go func() { _ = cmd.Wait() }()
+
+	h.mu.Lock()
+	h.pipe = stdin
+	h.mu.Unlock()
+	return &Response{}
+}
+
+func (h *host) info() *Info {
+	width, height := h.screen.Size()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	info := &Info{
+		Name:       h.opts.Name,
+		Created:    h.created,
+		Activity:   h.activity,
+		Attached:   len(h.clients),
+		Width:      width,
+		Height:     height,
+		Dead:       h.dead,
+		DeadStatus: h.deadStatus,
+		Path:       h.opts.WorkDir,
+	}
+	if h.cmd != nil && h.cmd.Process != nil {
+		info.PID = h.cmd.Process.Pid
+	}
+	fg := info.PID
+	if h.pty != nil && !h.dead {
+		if pgrp := foreground(h.pty); pgrp > 0 {
+			fg = pgrp
+		}
+	}
+	if fg > 0 {
+		info.Command = processName(fg)
+		if dir := processDir(fg); dir != "" {
+			info.Path = dir
+		}
+	}
+	return info
+}
+
+// attach turns conn into a terminal: it gets the screen, then all output,
+// and what it sends is typed into the session.
+func (h *host) attach(conn net.Conn, r *bufio.Reader, req Request) {
+	if req.Width > 0 && req.Height > 0 {
+		h.resize(req.Width, req.Height)
+	}
+	h.reply(conn, &Response{})
+
+	h.outMu.Lock()
+	_, err := conn.Write(h.screen.Redraw())
+	if err == nil {
+		h.mu.Lock()
+		h.clients[conn] = true
+		h.mu.Unlock()
+	}
+	h.outMu.Unlock()
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			_ = h.input(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	h.dropClient(conn)
+}
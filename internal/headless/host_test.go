@@ -0,0 +1,287 @@
+package headless
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve runs a session host in-process and returns a channel that receives
+// Serve's result once the session ends.
+func serve(t *testing.T, opts Options) <-chan error {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("headless sessions are only supported on Linux")
+	}
+	t.Setenv("GT_HEADLESS_DIR", filepath.Join(t.TempDir(), "sockets"))
+
+	done := make(chan error, 1)
+	go func() { done <- Serve(opts) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for !Exists(opts.Name) {
+		if time.Now().After(deadline) {
+			t.Fatal("session host did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Cleanup(func() { _, _ = Do(opts.Name, Request{Op: OpKill}) })
+	return done
+}
+
+// waitForScreen polls the session until its screen contains want.
+func waitForScreen(t *testing.T, name, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	var lines []string
+	for time.Now().Before(deadline) {
+		resp, err := Do(name, Request{Op: OpCapture})
+		if err != nil {
+			t.Fatalf("capture: %v", err)
+		}
+		lines = resp.Lines
+		if strings.Contains(strings.Join(lines, "\n"), want) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("screen never showed %q; got %q", want, lines)
+}
+
+func TestHostSendCaptureAndKill(t *testing.T) {
+	workDir := t.TempDir()
+	done := serve(t, Options{Name: "gt-test-host", WorkDir: workDir, Command: "exec cat"})
+
+	if _, err := Do("gt-test-host", Request{Op: OpSend, Data: "ping\r"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	waitForScreen(t, "gt-test-host", "ping")
+
+	resp, err := Do("gt-test-host", Request{Op: OpInfo})
+	if err != nil {
+		t.Fatalf("info: %v", err)
+	}
+	if resp.Info.Command != "cat" {
+		t.Errorf("Command = %q, want cat", resp.Info.Command)
+	}
+	if resp.Info.PID == 0 || resp.Info.Width != DefaultWidth || resp.Info.Height != DefaultHeight {
+		t.Errorf("Info = %+v", resp.Info)
+	}
+
+	if _, err := Do("gt-test-host", Request{Op: OpKill}); err != nil {
+		t.Fatalf("kill: %v", err)
+	}
+	if Exists("gt-test-host") {
+		t.Error("session still exists after kill")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after kill")
+	}
+}
+
+func TestHostEnvironment(t *testing.T) {
+	serve(t, Options{Name: "gt-test-env", Command: "cat"})
+
+	if _, err := Do("gt-test-env", Request{Op: OpSetEnv, Key: "GT_AGENT", Value: "codex"}); err != nil {
+		t.Fatalf("setenv: %v", err)
+	}
+	resp, err := Do("gt-test-env", Request{Op: OpGetEnv, Key: "GT_AGENT"})
+	if err != nil {
+		t.Fatalf("getenv: %v", err)
+	}
+	if !resp.Found || resp.Value != "codex" {
+		t.Errorf("getenv GT_AGENT = %q (found %v), want codex", resp.Value, resp.Found)
+	}
+	resp, err = Do("gt-test-env", Request{Op: OpGetEnv, Key: "MISSING"})
+	if err != nil {
+		t.Fatalf("getenv: %v", err)
+	}
+	if resp.Found {
+		t.Error("getenv MISSING found")
+	}
+	resp, err = Do("gt-test-env", Request{Op: OpGetEnv})
+	if err != nil {
+		t.Fatalf("getenv all: %v", err)
+	}
+	if resp.Env["GT_AGENT"] != "codex" {
+		t.Errorf("env = %v", resp.Env)
+	}
+}
+
+func TestHostEndsWithProgram(t *testing.T) {
+	done := serve(t, Options{Name: "gt-test-exit", Command: "sleep 0.2"})
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session outlived its program")
+	}
+	if _, err := Do("gt-test-exit", Request{Op: OpInfo}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Do after exit = %v, want ErrNotFound", err)
+	}
+}
+
+func TestHostRemainOnExitAndRespawn(t *testing.T) {
+	serve(t, Options{Name: "gt-test-respawn", Command: "echo first; sleep 0.5"})
+	if _, err := Do("gt-test-respawn", Request{Op: OpOption, Key: "remain-on-exit", Value: "on"}); err != nil {
+		t.Fatalf("option: %v", err)
+	}
+	waitForScreen(t, "gt-test-respawn", "first")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := Do("gt-test-respawn", Request{Op: OpInfo})
+		if err != nil {
+			t.Fatalf("session ended despite remain-on-exit: %v", err)
+		}
+		if resp.Info.Dead {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pane never died")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if _, err := Do("gt-test-respawn", Request{Op: OpRespawn, Command: "echo second; exec cat"}); err != nil {
+		t.Fatalf("respawn: %v", err)
+	}
+	waitForScreen(t, "gt-test-respawn", "second")
+	resp, err := Do("gt-test-respawn", Request{Op: OpInfo})
+	if err != nil {
+		t.Fatalf("info: %v", err)
+	}
+	if resp.Info.Dead {
+		t.Error("pane still dead after respawn")
+	}
+}
+
+func TestHostPaneDiedHook(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "died")
+	done := serve(t, Options{Name: "gt-test-hook", Command: "sleep 0.3; exit 3"})
+	req := Request{Op: OpHook, Key: HookPaneDied, Command: "echo #{pane_dead_status} > " + marker}
+	if _, err := Do("gt-test-hook", req); err != nil {
+		t.Fatalf("hook: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session outlived its program")
+	}
+	data, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("pane-died hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "3" {
+		t.Errorf("hook saw exit status %q, want 3", got)
+	}
+}
+
+func TestUnsafeSocketDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("headless sessions are only supported on Linux")
+	}
+	// A socket dir others can write to may hold their sockets, not ours
+	dir := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GT_HEADLESS_DIR", dir)
+
+	if err := Serve(Options{Name: "gt-test-unsafe", Command: "cat"}); err == nil {
+		t.Error("Serve in a world-writable socket dir succeeded")
+	}
+	if _, err := Do("gt-test-unsafe", Request{Op: OpInfo}); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Do in a world-writable socket dir = %v, want an unsafe dir error", err)
+	}
+	if Exists("gt-test-unsafe") {
+		t.Error("Exists in a world-writable socket dir")
+	}
+}
+
+func TestDuplicateSession(t *testing.T) {
+	serve(t, Options{Name: "gt-test-dup", Command: "cat"})
+
+	if err := Serve(Options{Name: "gt-test-dup", Command: "cat"}); !errors.Is(err, ErrExists) {
+		t.Errorf("second Serve = %v, want ErrExists", err)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	serve(t, Options{Name: "gt-test-attach", Command: "cat"})
+	waitForScreen(t, "gt-test-attach", "")
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	attached := make(chan error, 1)
+	go func() { attached <- Attach("gt-test-attach", inR, outW) }()
+
+	// Typed input reaches the program; Ctrl-\ detaches without killing it.
+	if _, err := inW.Write([]byte("typed\r")); err != nil {
+		t.Fatal(err)
+	}
+	waitForScreen(t, "gt-test-attach", "typed")
+	if _, err := inW.Write([]byte{detachKey}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-attached:
+		if err != nil {
+			t.Errorf("Attach: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach did not return on detach key")
+	}
+	_ = outW.Close()
+	out := make([]byte, 4096)
+	n, _ := outR.Read(out)
+	if !strings.Contains(string(out[:n]), "\x1b[2J") {
+		t.Errorf("attach output %q did not start with a redraw", out[:n])
+	}
+	if !Exists("gt-test-attach") {
+		t.Error("session ended on detach")
+	}
+}
+
+func TestReapRemovesStaleSockets(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sockets")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GT_HEADLESS_DIR", dir)
+	if err := os.WriteFile(SocketPath("gt-stale"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reaped, err := Reap()
+	if err != nil {
+		t.Fatalf("Reap: %v", err)
+	}
+	if reaped != 1 {
+		t.Errorf("Reap = %d, want 1", reaped)
+	}
+	if _, err := os.Stat(SocketPath("gt-stale")); !os.IsNotExist(err) {
+		t.Error("stale socket not removed")
+	}
+}
@@ -0,0 +1,95 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// A connection to a session host carries one JSON Request line and gets one
+// JSON Response line back. After a successful attach the connection turns
+// into a raw terminal stream instead.
+
+// Ops understood by a session host.
+const (
+	OpInfo    = "info"    // Describe the session
+	OpCapture = "capture" // Return screen and scrollback text
+	OpSend    = "send"    // Write Data to the terminal as if typed
+	OpSetEnv  = "setenv"  // Set a session environment variable
+	OpGetEnv  = "getenv"  // Read one (Key) or all session variables
+	OpKill    = "kill"    // Kill the session's processes and exit
+	OpResize  = "resize"  // Resize the terminal
+	OpClear   = "clear"   // Drop the scrollback
+	OpAttach  = "attach"  // Switch the connection to a terminal stream
+	OpRespawn = "respawn" // Kill the running program and start Command
+	OpPipe    = "pipe"    // Copy output to the stdin of Command
+	OpOption  = "option"  // Set a session option (Key, Value)
+	OpHook    = "hook"    // Run Command on an event (Key), "" to unset
+)
+
+// HookPaneDied runs when the session's program exits on its own, with
+// #{pane_dead_status} in the command replaced by its exit status.
+const HookPaneDied = "pane-died"
+
+// Request is a command sent to a session host.
+type Request struct {
+	Op      string `json:"op"`
+	Lines   int    `json:"lines,omitempty"` // capture: scrollback lines, < 0 for all
+	Data    string `json:"data,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Command string `json:"command,omitempty"`
+	WorkDir string `json:"work_dir,omitempty"`
+}
+
+// Response is a session host's answer to a Request.
+type Response struct {
+	Error string            `json:"error,omitempty"`
+	Lines []string          `json:"lines,omitempty"`
+	Value string            `json:"value,omitempty"`
+	Found bool              `json:"found,omitempty"`
+	Env   map[string]string `json:"env,omitempty"`
+	Info  *Info             `json:"info,omitempty"`
+}
+
+// Info describes a headless session, covering what tmux reports in its
+// session_* and pane_* format variables.
+type Info struct {
+	Name       string    `json:"name"`
+	PID        int       `json:"pid"`     // The program the session was started with
+	Command    string    `json:"command"` // Foreground program, like pane_current_command
+	Path       string    `json:"path"`    // Foreground program's working directory
+	Created    time.Time `json:"created"`
+	Activity   time.Time `json:"activity"` // Last output
+	Attached   int       `json:"attached"`
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	Dead       bool      `json:"dead"` // Program exited and remain-on-exit is on
+	DeadStatus int       `json:"dead_status"`
+}
+
+// roundTrip sends req over conn and reads the response. The reader is
+// returned so an attach can keep using what it buffered.
+func roundTrip(conn net.Conn, req Request) (*Response, *bufio.Reader, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, nil, fmt.Errorf("sending %s: %w", req.Op, err)
+	}
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s response: %w", req.Op, err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s response: %w", req.Op, err)
+	}
+	return &resp, r, nil
+}
@@ -0,0 +1,138 @@
+//go:build linux
+
+package headless
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// startPTY starts cmd on a new pseudo-terminal as a session leader with the
+// terminal as its controlling tty, and returns the master side.
+func startPTY(cmd *exec.Cmd, width, height int) (*os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening pty: %w", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		_ = master.Close()
+		return nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = master.Close()
+		return nil, fmt.Errorf("getting pty number: %w", err)
+	}
+	slave, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, fmt.Errorf("opening pty slave: %w", err)
+	}
+	defer slave.Close()
+
+	if err := resizePTY(master, width, height); err != nil {
+		_ = master.Close()
+		return nil, err
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		_ = master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// resizePTY sets the terminal size the session's programs see.
+func resizePTY(master *os.File, width, height int) error {
+	ws := &unix.Winsize{Col: uint16(width), Row: uint16(height)} //nolint:gosec // G115: sizes are small
+	if err := unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ, ws); err != nil {
+		return fmt.Errorf("resizing pty: %w", err)
+	}
+	return nil
+}
+
+// foreground returns the PID of the terminal's foreground process group
+// leader: the program the session is running right now.
+func foreground(master *os.File) int {
+	pgrp, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPGRP)
+	if err != nil {
+		return 0
+	}
+	return pgrp
+}
+
+// processName returns a process's command name, as tmux reports it in
+// #{pane_current_command}.
+func processName(pid int) string {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/comm")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// processDir returns a process's working directory.
+func processDir(pid int) string {
+	dir, err := os.Readlink("/proc/" + strconv.Itoa(pid) + "/cwd")
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// detach makes a spawned host outlive the command that started it.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// killGrace is how long a session's processes get to exit on SIGHUP before
+// they are killed.
+const killGrace = 2 * time.Second
+
+// kill hangs up on the program's process group, then kills the group if
+// the program hasn't exited in time. exited is closed once it has.
+func kill(cmd *exec.Cmd, exited <-chan struct{}) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	pgid := cmd.Process.Pid
+	_ = syscall.Kill(-pgid, syscall.SIGHUP)
+	select {
+	case <-exited:
+	case <-time.After(killGrace):
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	}
+}
+
+// watchResize calls f whenever the terminal is resized, until stop is
+// called.
+func watchResize(f func()) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				f()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
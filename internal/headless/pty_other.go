@@ -0,0 +1,33 @@
+//go:build !linux
+
+package headless
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errUnsupported = errors.New("headless sessions are only supported on Linux")
+
+func startPTY(cmd *exec.Cmd, width, height int) (*os.File, error) {
+	return nil, errUnsupported
+}
+
+func resizePTY(master *os.File, width, height int) error { return errUnsupported }
+
+func foreground(master *os.File) int { return 0 }
+
+func processName(pid int) string { return "" }
+
+func processDir(pid int) string { return "" }
+
+func detach(cmd *exec.Cmd) {}
+
+func kill(cmd *exec.Cmd, exited <-chan struct{}) {
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
+
+func watchResize(f func()) (stop func()) { return func() {} }
@@ -0,0 +1,456 @@
+package headless
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+)
+
+// maxScrollback is how many lines scrolled off the top of the screen a
+// session keeps, matching tmux's default history-limit.
+const maxScrollback = 2000
+
+// wideTail fills the cell after a double-width rune.
+const wideTail rune = 0
+
+// screen is a minimal VT100 emulator: enough cursor movement, erasing and
+// scrolling to turn an agent's output into the text tmux's capture-pane
+// would show. Colors and other attributes are dropped.
+type screen struct {
+	mu sync.Mutex
+
+	parser *ansi.Parser
+	width  int
+	height int
+
+	lines       [][]rune // visible rows, height of them
+	scrollback  [][]rune
+	x, y        int
+	savedX      int
+	savedY      int
+	pendingWrap bool
+
+	top, bottom int // scroll region, inclusive
+
+	// The alternate screen (full-screen programs) has no scrollback; the
+	// primary screen is kept here while it is up.
+	altSaved  [][]rune
+	altActive bool
+}
+
+func newScreen(width, height int) *screen {
+	s := &screen{width: width, height: height}
+	s.lines = blankRows(width, height)
+	s.bottom = height - 1
+	s.parser = ansi.NewParser()
+	s.parser.SetHandler(ansi.Handler{
+		Print:     s.print,
+		Execute:   s.execute,
+		HandleCsi: s.csi,
+		HandleEsc: s.esc,
+	})
+	return s
+}
+
+func blankRow(width int) []rune {
+	row := make([]rune, width)
+	for i := range row {
+		row[i] = ' '
+	}
+	return row
+}
+
+func blankRows(width, height int) [][]rune {
+	rows := make([][]rune, height)
+	for i := range rows {
+		rows[i] = blankRow(width)
+	}
+	return rows
+}
+
+// Write feeds program output to the emulator.
+func (s *screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Parse(p)
+	return len(p), nil
+}
+
+// Capture returns the visible screen preceded by up to history lines of
+// scrollback (all of it if history < 0), with trailing blank lines trimmed
+// like capture-pane.
+func (s *screen) Capture(history int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	back := s.scrollback
+	if history >= 0 && len(back) > history {
+		back = back[len(back)-history:]
+	}
+	all := make([]string, 0, len(back)+len(s.lines))
+	for _, row := range back {
+		all = append(all, rowString(row))
+	}
+	for _, row := range s.lines {
+		all = append(all, rowString(row))
+	}
+	for len(all) > 0 && all[len(all)-1] == "" {
+		all = all[:len(all)-1]
+	}
+	return all
+}
+
+// Redraw returns escape sequences that paint the current screen onto a
+// freshly attached terminal.
+func (s *screen) Redraw() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	for i, row := range s.lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(rowString(row))
+	}
+	b.WriteString(ansi.CursorPosition(s.x+1, s.y+1))
+	return []byte(b.String())
+}
+
+// Resize changes the screen size, keeping the bottom of the screen.
+func (s *screen) Resize(width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, row := range s.lines {
+		s.lines[i] = fitRow(row, width)
+	}
+	for len(s.lines) > height {
+		s.pushScrollback(s.lines[0])
+		s.lines = s.lines[1:]
+		s.y--
+	}
+	for len(s.lines) < height {
+		s.lines = append(s.lines, blankRow(width))
+	}
+	s.width, s.height = width, height
+	s.top, s.bottom = 0, height-1
+	s.x = clamp(s.x, 0, width-1)
+	s.y = clamp(s.y, 0, height-1)
+	s.pendingWrap = false
+}
+
+// ClearHistory drops the scrollback.
+func (s *screen) ClearHistory() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scrollback = nil
+}
+
+// Size returns the screen's width and height.
+func (s *screen) Size() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.width, s.height
+}
+
+func fitRow(row []rune, width int) []rune {
+	if len(row) >= width {
+		return row[:width]
+	}
+	for len(row) < width {
+		row = append(row, ' ')
+	}
+	return row
+}
+
+func rowString(row []rune) string {
+	var b strings.Builder
+	for _, r := range row {
+		if r != wideTail {
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func (s *screen) pushScrollback(row []rune) {
+	if s.altActive {
+		return
+	}
+	s.scrollback = append(s.scrollback, row)
+	if over := len(s.scrollback) - maxScrollback; over > 0 {
+		s.scrollback = s.scrollback[over:]
+	}
+}
+
+func (s *screen) print(r rune) {
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		return
+	}
+	if s.pendingWrap || s.x+w > s.width {
+		s.x = 0
+		s.lineFeed()
+		s.pendingWrap = false
+	}
+	row := s.lines[s.y]
+	row[s.x] = r
+	if w == 2 && s.x+1 < s.width {
+		row[s.x+1] = wideTail
+	}
+	if s.x+w >= s.width {
+		s.x = s.width - 1
+		s.pendingWrap = true
+	} else {
+		s.x += w
+	}
+}
+
+func (s *screen) execute(b byte) {
+	switch b {
+	case '\r':
+		s.x = 0
+		s.pendingWrap = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+		s.pendingWrap = false
+	case '\b':
+		if s.x > 0 {
+			s.x--
+		}
+		s.pendingWrap = false
+	case '\t':
+		s.x = min((s.x/8+1)*8, s.width-1)
+	}
+}
+
+// lineFeed moves the cursor down, scrolling the region at its bottom.
+func (s *screen) lineFeed() {
+	if s.y == s.bottom {
+		s.scrollUp(1)
+		return
+	}
+	if s.y < s.height-1 {
+		s.y++
+	}
+}
+
+func (s *screen) reverseLineFeed() {
+	if s.y == s.top {
+		s.scrollDown(1)
+		return
+	}
+	if s.y > 0 {
+		s.y--
+	}
+}
+
+// scrollUp scrolls the scroll region up n lines. Lines leaving the top of
+// the full screen go to the scrollback.
+func (s *screen) scrollUp(n int) {
+	for i := 0; i < n; i++ {
+		if s.top == 0 {
+			s.pushScrollback(s.lines[0])
+		}
+		copy(s.lines[s.top:s.bottom], s.lines[s.top+1:s.bottom+1])
+		s.lines[s.bottom] = blankRow(s.width)
+	}
+}
+
+func (s *screen) scrollDown(n int) {
+	for i := 0; i < n; i++ {
+		copy(s.lines[s.top+1:s.bottom+1], s.lines[s.top:s.bottom])
+		s.lines[s.top] = blankRow(s.width)
+	}
+}
+
+func (s *screen) eraseRow(y, from, to int) {
+	row := s.lines[y]
+	for x := max(from, 0); x < min(to, s.width); x++ {
+		row[x] = ' '
+	}
+}
+
+func (s *screen) csi(cmd ansi.Cmd, params ansi.Params) {
+	param := func(i, def int) int {
+		v, _, _ := params.Param(i, def)
+		if v == 0 && def > 0 {
+			return def
+		}
+		return v
+	}
+
+	if cmd.Prefix() == '?' {
+		switch cmd.Final() {
+		case 'h', 'l':
+			for i := range params {
+				switch param(i, 0) {
+				case 47, 1047, 1049:
+					s.setAltScreen(cmd.Final() == 'h')
+				}
+			}
+		}
+		return
+	}
+	if cmd.Prefix() != 0 || cmd.Intermediate() != 0 {
+		return
+	}
+
+	s.pendingWrap = false
+	switch cmd.Final() {
+	case 'A':
+		s.y = clamp(s.y-param(0, 1), 0, s.height-1)
+	case 'B':
+		s.y = clamp(s.y+param(0, 1), 0, s.height-1)
+	case 'C':
+		s.x = clamp(s.x+param(0, 1), 0, s.width-1)
+	case 'D':
+		s.x = clamp(s.x-param(0, 1), 0, s.width-1)
+	case 'E':
+		s.x, s.y = 0, clamp(s.y+param(0, 1), 0, s.height-1)
+	case 'F':
+		s.x, s.y = 0, clamp(s.y-param(0, 1), 0, s.height-1)
+	case 'G', '`':
+		s.x = clamp(param(0, 1)-1, 0, s.width-1)
+	case 'd':
+		s.y = clamp(param(0, 1)-1, 0, s.height-1)
+	case 'H', 'f':
+		s.y = clamp(param(0, 1)-1, 0, s.height-1)
+		s.x = clamp(param(1, 1)-1, 0, s.width-1)
+	case 'J':
+		switch param(0, 0) {
+		case 0:
+			s.eraseRow(s.y, s.x, s.width)
+			for y := s.y + 1; y < s.height; y++ {
+				s.eraseRow(y, 0, s.width)
+			}
+		case 1:
+			for y := 0; y < s.y; y++ {
+				s.eraseRow(y, 0, s.width)
+			}
+			s.eraseRow(s.y, 0, s.x+1)
+		case 2:
+			for y := 0; y < s.height; y++ {
+				s.eraseRow(y, 0, s.width)
+			}
+		case 3:
+			s.scrollback = nil
+		}
+	case 'K':
+		switch param(0, 0) {
+		case 0:
+			s.eraseRow(s.y, s.x, s.width)
+		case 1:
+			s.eraseRow(s.y, 0, s.x+1)
+		case 2:
+			s.eraseRow(s.y, 0, s.width)
+		}
+	case 'X':
+		s.eraseRow(s.y, s.x, s.x+param(0, 1))
+	case 'L', 'M':
+		if s.y < s.top || s.y > s.bottom {
+			return
+		}
+		top := s.top
+		s.top = s.y
+		if cmd.Final() == 'L' {
+			s.scrollDown(param(0, 1))
+		} else {
+			// Deleted lines don't go to the scrollback.
+			for i := 0; i < param(0, 1); i++ {
+				copy(s.lines[s.top:s.bottom], s.lines[s.top+1:s.bottom+1])
+				s.lines[s.bottom] = blankRow(s.width)
+			}
+		}
+		s.top = top
+		s.x = 0
+	case 'P':
+		row := s.lines[s.y]
+		n := min(param(0, 1), s.width-s.x)
+		copy(row[s.x:], row[s.x+n:])
+		s.eraseRow(s.y, s.width-n, s.width)
+	case '@':
+		row := s.lines[s.y]
+		n := min(param(0, 1), s.width-s.x)
+		copy(row[s.x+n:], row[s.x:s.width-n])
+		s.eraseRow(s.y, s.x, s.x+n)
+	case 'S':
+		s.scrollUp(param(0, 1))
+	case 'T':
+		s.scrollDown(param(0, 1))
+	case 'r':
+		top := param(0, 1) - 1
+		bottom := param(1, s.height) - 1
+		if top < bottom && bottom < s.height {
+			s.top, s.bottom = top, bottom
+			s.x, s.y = 0, 0
+		}
+	case 's':
+		s.savedX, s.savedY = s.x, s.y
+	case 'u':
+		s.x, s.y = s.savedX, s.savedY
+	}
+}
+
+func (s *screen) esc(cmd ansi.Cmd) {
+	if cmd.Intermediate() != 0 {
+		return
+	}
+	switch cmd.Final() {
+	case '7':
+		s.savedX, s.savedY = s.x, s.y
+	case '8':
+		s.x, s.y = s.savedX, s.savedY
+		s.pendingWrap = false
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.x = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseLineFeed()
+	case 'c':
+		s.lines = blankRows(s.width, s.height)
+		s.x, s.y = 0, 0
+		s.top, s.bottom = 0, s.height-1
+		s.pendingWrap = false
+	}
+}
+
+func (s *screen) setAltScreen(on bool) {
+	if on == s.altActive {
+		return
+	}
+	if on {
+		s.altSaved = s.lines
+		s.lines = blankRows(s.width, s.height)
+		s.savedX, s.savedY = s.x, s.y
+	} else {
+		s.lines = s.altSaved
+		for i, row := range s.lines {
+			s.lines[i] = fitRow(row, s.width)
+		}
+		for len(s.lines) < s.height {
+			s.lines = append(s.lines, blankRow(s.width))
+		}
+		s.lines = s.lines[len(s.lines)-s.height:]
+		s.altSaved = nil
+		s.x, s.y = s.savedX, s.savedY
+	}
+	s.altActive = on
+	s.top, s.bottom = 0, s.height-1
+}
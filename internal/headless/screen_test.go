@@ -0,0 +1,106 @@
+package headless
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScreenPrintAndNewlines(t *testing.T) {
+	s := newScreen(20, 5)
+	_, _ = s.Write([]byte("hello\r\nworld\r\n"))
+
+	got := s.Capture(0)
+	want := []string{"hello", "world"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture = %q, want %q", got, want)
+	}
+}
+
+func TestScreenWrapsLongLines(t *testing.T) {
+	s := newScreen(5, 3)
+	_, _ = s.Write([]byte("abcdefgh"))
+
+	got := s.Capture(0)
+	want := []string{"abcde", "fgh"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture = %q, want %q", got, want)
+	}
+}
+
+func TestScreenScrollback(t *testing.T) {
+	s := newScreen(10, 2)
+	_, _ = s.Write([]byte("one\r\ntwo\r\nthree\r\nfour"))
+
+	if got, want := s.Capture(0), []string{"three", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture(0) = %q, want %q", got, want)
+	}
+	if got, want := s.Capture(1), []string{"two", "three", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture(1) = %q, want %q", got, want)
+	}
+	if got, want := s.Capture(-1), []string{"one", "two", "three", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture(-1) = %q, want %q", got, want)
+	}
+
+	s.ClearHistory()
+	if got, want := s.Capture(-1), []string{"three", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after ClearHistory = %q, want %q", got, want)
+	}
+}
+
+func TestScreenCursorAndErase(t *testing.T) {
+	s := newScreen(20, 4)
+	// Draw a status line, then redraw it in place the way TUIs do.
+	_, _ = s.Write([]byte("line one\r\nWorking...\x1b[1G\x1b[2KDone\x1b[H\x1b[5CX"))
+
+	got := s.Capture(0)
+	want := []string{"line Xne", "Done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture = %q, want %q", got, want)
+	}
+
+	_, _ = s.Write([]byte("\x1b[2J"))
+	if got := s.Capture(0); len(got) != 0 {
+		t.Errorf("after clear screen = %q, want nothing", got)
+	}
+}
+
+func TestScreenAlternateScreen(t *testing.T) {
+	s := newScreen(20, 3)
+	_, _ = s.Write([]byte("shell prompt$ "))
+	_, _ = s.Write([]byte("\x1b[?1049h\x1b[Hfull screen app"))
+
+	if got := strings.Join(s.Capture(0), "\n"); got != "full screen app" {
+		t.Errorf("alternate screen = %q", got)
+	}
+
+	_, _ = s.Write([]byte("\x1b[?1049l"))
+	if got := strings.Join(s.Capture(0), "\n"); got != "shell prompt$" {
+		t.Errorf("after leaving alternate screen = %q", got)
+	}
+}
+
+func TestScreenWideRunes(t *testing.T) {
+	s := newScreen(10, 2)
+	_, _ = s.Write([]byte("日本語ok"))
+
+	if got := s.Capture(0); !reflect.DeepEqual(got, []string{"日本語ok"}) {
+		t.Errorf("Capture = %q", got)
+	}
+}
+
+func TestScreenResize(t *testing.T) {
+	s := newScreen(10, 3)
+	_, _ = s.Write([]byte("a\r\nb\r\nc"))
+	s.Resize(10, 2)
+
+	if w, h := s.Size(); w != 10 || h != 2 {
+		t.Errorf("Size = %dx%d, want 10x2", w, h)
+	}
+	if got, want := s.Capture(0), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture(0) = %q, want %q", got, want)
+	}
+	if got, want := s.Capture(-1), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Capture(-1) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package hostres
+
+import "syscall"
+
+// disk returns free (available to unprivileged users) and total space in GB
+// on the filesystem holding path.
+func disk(path string) (freeGB, totalGB float64) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return -1, -1
+	}
+	const gb = 1 << 30
+	bsize := float64(st.Bsize) //nolint:unconvert // Bsize type varies by platform
+	return float64(st.Bavail) * bsize / gb, float64(st.Blocks) * bsize / gb
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package hostres
+
+// disk is not measured on Windows; the disk check is skipped.
+func disk(path string) (freeGB, totalGB float64) {
+	return -1, -1
+}
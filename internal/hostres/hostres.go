@@ -0,0 +1,153 @@
+// Package hostres samples host resources (available memory, CPU load and
+// free disk) so polecat spawns can be deferred while the host is saturated,
+// and measures how much memory agent sessions use.
+package hostres
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrSaturated is returned by Check when the host has no room for another
+// agent. Spawns that hit it are queued, like spawns past max_polecats.
+var ErrSaturated = errors.New("host is saturated")
+
+// Snapshot is one sample of host resources. Values the platform cannot
+// report are negative and never count as pressure.
+type Snapshot struct {
+	MemAvailableMB int64   `json:"mem_available_mb"`
+	MemTotalMB     int64   `json:"mem_total_mb"`
+	Load1          float64 `json:"load1"` // 1-minute load average
+	CPUs           int     `json:"cpus"`
+	DiskPath       string  `json:"disk_path"`
+	DiskFreeGB     float64 `json:"disk_free_gb"`
+	DiskTotalGB    float64 `json:"disk_total_gb"`
+}
+
+// Sample reads current memory, load and the free space on the filesystem
+// holding diskPath.
+func Sample(diskPath string) *Snapshot {
+	s := &Snapshot{CPUs: runtime.NumCPU(), DiskPath: diskPath}
+	s.MemAvailableMB, s.MemTotalMB = memory()
+	s.Load1 = loadAverage()
+	s.DiskFreeGB, s.DiskTotalGB = disk(diskPath)
+	return s
+}
+
+// LoadPerCPU returns the 1-minute load average divided by the CPU count,
+// or -1 if the load is unknown.
+func (s *Snapshot) LoadPerCPU() float64 {
+	if s.Load1 < 0 || s.CPUs <= 0 {
+		return -1
+	}
+	return s.Load1 / float64(s.CPUs)
+}
+
+// Pressures describes each resource past its threshold in cfg. A nil cfg
+// has no thresholds.
+func (s *Snapshot) Pressures(cfg *config.ResourceConfig) []string {
+	if cfg == nil {
+		return nil
+	}
+	var out []string
+	if cfg.MinMemoryMB > 0 && s.MemAvailableMB >= 0 && s.MemAvailableMB < int64(cfg.MinMemoryMB) {
+		out = append(out, fmt.Sprintf("memory: %d MB available (min %d MB)", s.MemAvailableMB, cfg.MinMemoryMB))
+	}
+	if load := s.LoadPerCPU(); cfg.MaxLoadPerCPU > 0 && load > cfg.MaxLoadPerCPU {
+		out = append(out, fmt.Sprintf("cpu: load %.2f per CPU (max %.2f)", load, cfg.MaxLoadPerCPU))
+	}
+	if cfg.MinDiskGB > 0 && s.DiskFreeGB >= 0 && s.DiskFreeGB < cfg.MinDiskGB {
+		out = append(out, fmt.Sprintf("disk: %.1f GB free on %s (min %.1f GB)", s.DiskFreeGB, s.DiskPath, cfg.MinDiskGB))
+	}
+	return out
+}
+
+// Check samples the host and returns an error wrapping ErrSaturated if any
+// resource is past its threshold. diskPath should be on the filesystem new
+// worktrees are created in (the rig directory). A nil cfg (the town has no
+// "resources" section) always passes.
+func Check(diskPath string, cfg *config.ResourceConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if pressures := Sample(diskPath).Pressures(cfg); len(pressures) > 0 {
+		return fmt.Errorf("%w: %s", ErrSaturated, strings.Join(pressures, "; "))
+	}
+	return nil
+}
+
+// Processes is a snapshot of the process table, used to total the memory
+// of a session's process tree.
+type Processes struct {
+	children map[int][]int
+	rssKB    map[int]int64
+}
+
+// ListProcesses reads the process table with ps.
+func ListProcesses() (*Processes, error) {
+	out, err := exec.Command("ps", "-eo", "pid=,ppid=,rss=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing processes: %w", err)
+	}
+	return parseProcesses(string(out)), nil
+}
+
+// parseProcesses parses "pid ppid rss" lines.
+func parseProcesses(out string) *Processes {
+	p := &Processes{children: make(map[int][]int), rssKB: make(map[int]int64)}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		rss, err3 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		p.rssKB[pid] = rss
+		p.children[ppid] = append(p.children[ppid], pid)
+	}
+	return p
+}
+
+// TreeRSS returns the resident memory in KB of pid and all its descendants,
+// or 0 if pid is not running.
+func (p *Processes) TreeRSS(pid int) int64 {
+	if _, ok := p.rssKB[pid]; !ok {
+		return 0
+	}
+	var total int64
+	seen := make(map[int]bool)
+	stack := []int{pid}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		total += p.rssKB[cur]
+		stack = append(stack, p.children[cur]...)
+	}
+	return total
+}
+
+// FormatKB renders a memory size given in KB, e.g. "512 MB" or "1.4 GB".
+func FormatKB(kb int64) string {
+	switch {
+	case kb >= 1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(kb)/(1024*1024))
+	case kb >= 1024:
+		return fmt.Sprintf("%d MB", kb/1024)
+	default:
+		return fmt.Sprintf("%d KB", kb)
+	}
+}
@@ -0,0 +1,91 @@
+package hostres
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPressures(t *testing.T) {
+	cfg := &config.ResourceConfig{MinMemoryMB: 1024, MaxLoadPerCPU: 2, MinDiskGB: 5}
+	tests := []struct {
+		name string
+		snap Snapshot
+		want []string
+	}{
+		{"healthy", Snapshot{MemAvailableMB: 8192, Load1: 3, CPUs: 4, DiskFreeGB: 50}, nil},
+		{"low memory", Snapshot{MemAvailableMB: 512, Load1: 1, CPUs: 4, DiskFreeGB: 50}, []string{"memory"}},
+		{"overloaded", Snapshot{MemAvailableMB: 8192, Load1: 9, CPUs: 4, DiskFreeGB: 50}, []string{"cpu"}},
+		{"disk full", Snapshot{MemAvailableMB: 8192, Load1: 1, CPUs: 4, DiskFreeGB: 1}, []string{"disk"}},
+		{"unknown values never count", Snapshot{MemAvailableMB: -1, Load1: -1, CPUs: 4, DiskFreeGB: -1}, nil},
+		{"everything", Snapshot{MemAvailableMB: 10, Load1: 40, CPUs: 4, DiskFreeGB: 0.5}, []string{"memory", "cpu", "disk"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.snap.Pressures(cfg)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Pressures() = %v, want %v", got, tt.want)
+			}
+			for i, prefix := range tt.want {
+				if !strings.HasPrefix(got[i], prefix+":") {
+					t.Errorf("Pressures()[%d] = %q, want %s pressure", i, got[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestPressuresZeroDisablesCheck(t *testing.T) {
+	snap := Snapshot{MemAvailableMB: 1, Load1: 100, CPUs: 1, DiskFreeGB: 0}
+	if got := snap.Pressures(&config.ResourceConfig{}); len(got) != 0 {
+		t.Errorf("Pressures() with zero thresholds = %v, want none", got)
+	}
+	if got := snap.Pressures(nil); len(got) != 0 {
+		t.Errorf("Pressures(nil) = %v, want none: thresholds are opt-in", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(t.TempDir(), &config.ResourceConfig{}); err != nil {
+		t.Errorf("Check() with no thresholds = %v", err)
+	}
+	err := Check(t.TempDir(), &config.ResourceConfig{MinDiskGB: 1 << 30})
+	if !errors.Is(err, ErrSaturated) {
+		t.Errorf("Check() with impossible disk threshold = %v, want ErrSaturated", err)
+	}
+}
+
+func TestTreeRSS(t *testing.T) {
+	procs := parseProcesses(`
+    1     0   100
+   10     1  2048
+   11    10  1024
+   12    11   512
+   20     1  4096
+ junk line
+`)
+	if got := procs.TreeRSS(10); got != 3584 {
+		t.Errorf("TreeRSS(10) = %d, want 3584", got)
+	}
+	if got := procs.TreeRSS(20); got != 4096 {
+		t.Errorf("TreeRSS(20) = %d, want 4096", got)
+	}
+	if got := procs.TreeRSS(99); got != 0 {
+		t.Errorf("TreeRSS(99) = %d, want 0 for a missing process", got)
+	}
+}
+
+func TestFormatKB(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 KB",
+		300 * 1024:      "300 MB",
+		3 * 1024 * 1024: "3.0 GB",
+	}
+	for kb, want := range tests {
+		if got := FormatKB(kb); got != want {
+			t.Errorf("FormatKB(%d) = %q, want %q", kb, got, want)
+		}
+	}
+}
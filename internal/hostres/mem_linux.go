@@ -0,0 +1,50 @@
+package hostres
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// memory returns available and total memory in MB from /proc/meminfo.
+func memory() (availableMB, totalMB int64) {
+	availableMB, totalMB = -1, -1
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemAvailable:":
+			availableMB = kb / 1024
+		case "MemTotal:":
+			totalMB = kb / 1024
+		}
+	}
+	return
+}
+
+// loadAverage returns the 1-minute load average from /proc/loadavg.
+func loadAverage() float64 {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return -1
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return -1
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return -1
+	}
+	return load
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+
+package hostres
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// memory is only measured on Linux; elsewhere the memory check is skipped.
+func memory() (availableMB, totalMB int64) {
+	return -1, -1
+}
+
+// loadAverage returns the 1-minute load average via sysctl (macOS, BSD).
+func loadAverage() float64 {
+	out, err := exec.Command("sysctl", "-n", "vm.loadavg").Output()
+	if err != nil {
+		return -1
+	}
+	// Format: "{ 1.23 1.10 1.00 }"
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(string(out)), "{}"))
+	if len(fields) == 0 {
+		return -1
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return -1
+	}
+	return load
+}
@@ -11,6 +11,9 @@
 // - Configurable TTLs per event type (default: 7 days)
 // - Auto-pruning on daemon startup and periodic intervals
 // - Stats and visibility into ephemeral data lifecycle
+//
+// The compliance audit log (.audit.jsonl, see internal/auditlog) is not
+// Level 0 data: it is hash-chained and must never be pruned or rewritten.
 package krc
 
 import (
@@ -80,6 +83,12 @@ func DefaultConfig() *Config {
 
 			// Merge events - important for audit
 			"merge_*":       30 * 24 * time.Hour, // 30 days
+
+			// Step timing - feeds historical p50/p90 in gt mol stats
+			"step_*": 90 * 24 * time.Hour, // 90 days
+
+			// Pane recordings (not events) - pruned by file age
+			"session_recording": 14 * 24 * time.Hour, // 14 days
 		},
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -62,6 +64,12 @@ func TestGetTTL_GlobMatch(t *testing.T) {
 	if ttl != 30*24*time.Hour {
 		t.Errorf("expected merge_started TTL of 30 days (via merge_*), got %v", ttl)
 	}
+
+	// step_closed should match step_* (kept long for gt mol stats history)
+	ttl = config.GetTTL("step_closed")
+	if ttl != 90*24*time.Hour {
+		t.Errorf("expected step_closed TTL of 90 days (via step_*), got %v", ttl)
+	}
 }
 
 func TestGetTTL_DefaultFallback(t *testing.T) {
@@ -273,3 +281,27 @@ func TestGetStats(t *testing.T) {
 		t.Errorf("expected 3 events in 0-1d bucket, got %d", stats.ByAge["0-1d"])
 	}
 }
+
+func TestPruner_SkipsAuditLog(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Audit entries old enough to be pruned if KRC touched the file
+	old := time.Now().UTC().Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+	auditData := []byte(`{"seq":1,"ts":"` + old + `","type":"sling","actor":"mayor"}` + "\n")
+	auditPath := filepath.Join(tmpDir, auditlog.LogFile)
+	if err := os.WriteFile(auditPath, auditData, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewPruner(tmpDir, DefaultConfig()).Prune(); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	got, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("audit log missing after prune: %v", err)
+	}
+	if string(got) != string(auditData) {
+		t.Error("Prune modified the audit log")
+	}
+}
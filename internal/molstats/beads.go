@@ -0,0 +1,200 @@
+package molstats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// RunsFile holds step runs derived from bead timestamps, relative to the
+// town root. Molecules are often burned once done, so their runs are kept
+// here rather than re-derived.
+const RunsFile = constants.DirRuntime + "/step-runs.jsonl"
+
+// FromBeads derives a molecule's step runs from its step beads' timestamps,
+// which every way of working a step updates, including a plain bd close.
+// A step is taken to begin when it became ready: when the last step that
+// blocks it closed, or when the molecule was poured if none does. Steps
+// must carry their dependencies (bd show output). Steps still waiting on
+// an open blocker have no begin time.
+func FromBeads(root *beads.Issue, steps []*beads.Issue) []*StepRun {
+	fields := beads.ParseMoleculeFormulaFields(root)
+	if fields == nil {
+		fields = &beads.MoleculeFormulaFields{}
+	}
+	byID := make(map[string]*beads.Issue, len(steps))
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+
+	runs := make([]*StepRun, 0, len(steps))
+	for _, s := range steps {
+		run := &StepRun{
+			Step:        s.ID,
+			Molecule:    root.ID,
+			Formula:     fields.Formula,
+			FormulaStep: fields.Steps[s.ID],
+			Title:       s.Title,
+			Agent:       s.Assignee,
+		}
+		if run.Agent == "" {
+			run.Agent = root.Assignee
+		}
+		if s.Status == "closed" {
+			run.ClosedAt = parseBeadTime(s.ClosedAt)
+		}
+		run.ClaimedAt = readyAt(root, s, byID)
+		if run.Closed() && run.ClaimedAt.After(run.ClosedAt) {
+			run.ClaimedAt = parseBeadTime(s.CreatedAt)
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// readyAt returns when a step's last blocker in the molecule closed, the
+// molecule's creation if nothing blocks it, or zero while a blocker is open.
+func readyAt(root, step *beads.Issue, byID map[string]*beads.Issue) time.Time {
+	ready := parseBeadTime(root.CreatedAt)
+	for _, dep := range step.Dependencies {
+		blocker, ok := byID[dep.ID]
+		if dep.DependencyType != "blocks" || !ok {
+			continue
+		}
+		if blocker.Status != "closed" {
+			return time.Time{}
+		}
+		if closed := parseBeadTime(blocker.ClosedAt); closed.After(ready) {
+			ready = closed
+		}
+	}
+	return ready
+}
+
+func parseBeadTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Merge adds derived runs to runs, combining runs of the same step the way
+// Fold combines events: the earliest begin and the latest close win.
+func Merge(runs, derived []*StepRun) []*StepRun {
+	byStep := make(map[string]*StepRun, len(runs))
+	for _, r := range runs {
+		byStep[r.Step] = r
+	}
+	for _, d := range derived {
+		r, ok := byStep[d.Step]
+		if !ok {
+			copied := *d
+			runs = append(runs, &copied)
+			byStep[d.Step] = &copied
+			continue
+		}
+		if r.Molecule == "" {
+			r.Molecule = d.Molecule
+		}
+		if r.Formula == "" {
+			r.Formula = d.Formula
+		}
+		if r.FormulaStep == "" {
+			r.FormulaStep = d.FormulaStep
+		}
+		if r.Title == "" {
+			r.Title = d.Title
+		}
+		if r.Agent == "" {
+			r.Agent = d.Agent
+		}
+		if !d.ClaimedAt.IsZero() {
+			r.ClaimedAt = earliest(r.ClaimedAt, d.ClaimedAt)
+		}
+		if !d.StartedAt.IsZero() {
+			r.StartedAt = earliest(r.StartedAt, d.StartedAt)
+		}
+		if d.ClosedAt.After(r.ClosedAt) {
+			r.ClosedAt = d.ClosedAt
+		}
+		r.Breached = r.Breached || d.Breached
+	}
+	return runs
+}
+
+// Record appends closed derived runs to the town's runs file, skipping
+// steps already recorded as closed in known.
+func Record(townRoot string, known, derived []*StepRun) error {
+	var lines []byte
+	for _, d := range derived {
+		if !d.Closed() || d.Formula == "" {
+			continue
+		}
+		if r := Find(known, d.Step); r != nil && r.Closed() {
+			continue
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, data...), '\n')
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(townRoot, RunsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking step runs: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: runtime files are world-readable like the rest of the town
+	if err != nil {
+		return fmt.Errorf("opening step runs: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(lines); err != nil {
+		return fmt.Errorf("writing step runs: %w", err)
+	}
+	return nil
+}
+
+// loadRecorded reads the runs Record kept. A missing file yields no runs.
+func loadRecorded(townRoot string) ([]*StepRun, error) {
+	f, err := os.Open(filepath.Join(townRoot, RunsFile)) //nolint:gosec // G304: path is constructed from trusted town root
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening step runs: %w", err)
+	}
+	defer f.Close()
+
+	var runs []*StepRun
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r StepRun
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.Step == "" {
+			continue // Skip malformed lines
+		}
+		runs = append(runs, &r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading step runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,93 @@
+package molstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func blockedBy(ids ...string) []beads.IssueDep {
+	deps := []beads.IssueDep{{ID: "gt-mol", DependencyType: "parent-child"}}
+	for _, id := range ids {
+		deps = append(deps, beads.IssueDep{ID: id, DependencyType: "blocks"})
+	}
+	return deps
+}
+
+func TestFromBeads(t *testing.T) {
+	root := &beads.Issue{
+		ID:          "gt-mol",
+		CreatedAt:   "2026-01-01T10:00:00Z",
+		Assignee:    "gastown/polecats/Toast",
+		Description: "formula: mol-polecat-work\nformula_step: gt-mol.1=load-context\nformula_step: gt-mol.2=implement",
+	}
+	steps := []*beads.Issue{
+		{ID: "gt-mol.1", Title: "Load context", Status: "closed", ClosedAt: "2026-01-01T10:05:00Z", Dependencies: blockedBy()},
+		{ID: "gt-mol.2", Title: "Implement", Status: "open", Dependencies: blockedBy("gt-mol.1")},
+		{ID: "gt-mol.3", Title: "Submit", Status: "open", Dependencies: blockedBy("gt-mol.2")},
+	}
+
+	runs := FromBeads(root, steps)
+	if len(runs) != 3 {
+		t.Fatalf("FromBeads() returned %d runs, want 3", len(runs))
+	}
+
+	first := Find(runs, "gt-mol.1")
+	if got := first.Duration(); got != 5*time.Minute {
+		t.Errorf("first step Duration() = %v, want 5m (pour to close)", got)
+	}
+	if first.Formula != "mol-polecat-work" || first.FormulaStep != "load-context" || first.Agent != "gastown/polecats/Toast" {
+		t.Errorf("first step = %+v", first)
+	}
+
+	now := time.Date(2026, 1, 1, 10, 15, 0, 0, time.UTC)
+	if got := Find(runs, "gt-mol.2").Elapsed(now); got != 10*time.Minute {
+		t.Errorf("ready step Elapsed() = %v, want 10m (since its blocker closed)", got)
+	}
+	if got := Find(runs, "gt-mol.3").Elapsed(now); got != 0 {
+		t.Errorf("blocked step Elapsed() = %v, want 0 (not begun)", got)
+	}
+}
+
+func TestMergeAndRecord(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// gt mol step recorded the close; the claim came only from the bead
+	fromEvents := []*StepRun{{Step: "gt-mol.1", ClosedAt: base.Add(5 * time.Minute), Breached: true}}
+	derived := []*StepRun{
+		{Step: "gt-mol.1", Formula: "mol-polecat-work", FormulaStep: "load-context", ClaimedAt: base, ClosedAt: base.Add(5 * time.Minute)},
+		{Step: "gt-mol.2", Formula: "mol-polecat-work", FormulaStep: "implement", ClaimedAt: base.Add(5 * time.Minute)},
+	}
+
+	merged := Merge(fromEvents, derived)
+	if len(merged) != 2 {
+		t.Fatalf("Merge() returned %d runs, want 2", len(merged))
+	}
+	if r := Find(merged, "gt-mol.1"); r.Duration() != 5*time.Minute || r.FormulaStep != "load-context" || !r.Breached {
+		t.Errorf("merged run = %+v", r)
+	}
+
+	if err := Record(townRoot, nil, derived); err != nil {
+		t.Fatal(err)
+	}
+	// Recording again adds nothing for steps already recorded as closed
+	known, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Record(townRoot, known, derived); err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := loadRecorded(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 1 || recorded[0].Step != "gt-mol.1" {
+		t.Errorf("recorded runs = %+v, want only the closed step, once", recorded)
+	}
+	if stats := Summarize(known, "mol-polecat-work"); len(stats) != 1 || stats[0].P50 != 5*time.Minute {
+		t.Errorf("Summarize() of recorded runs = %+v", stats)
+	}
+}
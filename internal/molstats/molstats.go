@@ -0,0 +1,292 @@
+// Package molstats aggregates molecule step timing.
+//
+// Step lifecycle events (step_claimed, step_started, step_closed) are written
+// to ~/gt/.events.jsonl as agents work through molecules with gt mol step.
+// Steps closed some other way (such as bd close) are timed from their beads'
+// timestamps instead, recorded when the molecule's polecat finishes and by
+// the SLA patrol. This package folds both into per-step runs and computes
+// duration percentiles per formula step, which is how gt mol stats finds the
+// bottleneck steps in a formula.
+package molstats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// StepRun is the timing record for one execution of one molecule step.
+type StepRun struct {
+	Step        string    `json:"step"`
+	Molecule    string    `json:"molecule"`
+	Formula     string    `json:"formula,omitempty"`
+	FormulaStep string    `json:"formula_step,omitempty"`
+	Title       string    `json:"title"`
+	Agent       string    `json:"agent,omitempty"`
+	ClaimedAt   time.Time `json:"claimed_at,omitempty"`
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	ClosedAt    time.Time `json:"closed_at,omitempty"`
+	Breached    bool      `json:"sla_breached,omitempty"`
+}
+
+// Begin returns when work on the step began: the start time if recorded,
+// otherwise the claim time.
+func (r *StepRun) Begin() time.Time {
+	if !r.StartedAt.IsZero() {
+		return r.StartedAt
+	}
+	return r.ClaimedAt
+}
+
+// Closed reports whether the step has been closed.
+func (r *StepRun) Closed() bool {
+	return !r.ClosedAt.IsZero()
+}
+
+// Duration returns how long the step took, or 0 if it has not both begun
+// and closed.
+func (r *StepRun) Duration() time.Duration {
+	begin := r.Begin()
+	if begin.IsZero() || r.ClosedAt.IsZero() || r.ClosedAt.Before(begin) {
+		return 0
+	}
+	return r.ClosedAt.Sub(begin)
+}
+
+// Elapsed returns how long an open step has been running as of now.
+func (r *StepRun) Elapsed(now time.Time) time.Duration {
+	begin := r.Begin()
+	if begin.IsZero() || r.Closed() {
+		return 0
+	}
+	return now.Sub(begin)
+}
+
+// Load reads step timing events from the town's events log and the runs
+// derived from beads, and folds them into one StepRun per step bead, ordered
+// by begin time. Missing files yield no runs.
+func Load(townRoot string) ([]*StepRun, error) {
+	runs, err := loadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := loadRecorded(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return sortRuns(Merge(runs, recorded)), nil
+}
+
+// loadEvents folds the step timing events in the town's events log.
+func loadEvents(townRoot string) ([]*StepRun, error) {
+	path := filepath.Join(townRoot, events.EventsFile)
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted town root
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	var evts []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		switch e.Type {
+		case events.TypeStepClaimed, events.TypeStepStarted, events.TypeStepClosed, events.TypeStepSLABreached:
+			evts = append(evts, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+
+	return Fold(evts), nil
+}
+
+// Fold combines step events into runs keyed by step bead ID.
+// The earliest claim/start and the latest close win, so repeated events
+// (e.g. a step re-claimed after a crash) don't shorten the measured duration.
+func Fold(evts []events.Event) []*StepRun {
+	runs := make(map[string]*StepRun)
+	var order []string
+
+	for _, e := range evts {
+		stepID := payloadString(e.Payload, "step")
+		if stepID == "" {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+
+		run, ok := runs[stepID]
+		if !ok {
+			run = &StepRun{Step: stepID}
+			runs[stepID] = run
+			order = append(order, stepID)
+		}
+		if v := payloadString(e.Payload, "molecule"); v != "" {
+			run.Molecule = v
+		}
+		if v := payloadString(e.Payload, "formula"); v != "" {
+			run.Formula = v
+		}
+		if v := payloadString(e.Payload, "formula_step"); v != "" {
+			run.FormulaStep = v
+		}
+		if v := payloadString(e.Payload, "title"); v != "" {
+			run.Title = v
+		}
+
+		switch e.Type {
+		case events.TypeStepClaimed:
+			run.ClaimedAt = earliest(run.ClaimedAt, ts)
+			if e.Actor != "" {
+				run.Agent = e.Actor
+			}
+		case events.TypeStepStarted:
+			run.StartedAt = earliest(run.StartedAt, ts)
+			if run.Agent == "" {
+				run.Agent = e.Actor
+			}
+		case events.TypeStepClosed:
+			if ts.After(run.ClosedAt) {
+				run.ClosedAt = ts
+			}
+			if run.Agent == "" {
+				run.Agent = e.Actor
+			}
+		case events.TypeStepSLABreached:
+			run.Breached = true
+		}
+	}
+
+	result := make([]*StepRun, 0, len(order))
+	for _, id := range order {
+		result = append(result, runs[id])
+	}
+	return sortRuns(result)
+}
+
+// sortRuns orders runs by begin time.
+func sortRuns(runs []*StepRun) []*StepRun {
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Begin().Before(runs[j].Begin())
+	})
+	return runs
+}
+
+// Find returns the run for a step bead ID, or nil if none was recorded.
+func Find(runs []*StepRun, stepID string) *StepRun {
+	for _, r := range runs {
+		if r.Step == stepID {
+			return r
+		}
+	}
+	return nil
+}
+
+// StepStats summarizes the completed runs of one formula step.
+type StepStats struct {
+	Step     string        `json:"step,omitempty"` // Formula step ID; empty for runs recorded without one
+	Title    string        `json:"title"`
+	Runs     int           `json:"runs"`
+	P50      time.Duration `json:"p50"`
+	P90      time.Duration `json:"p90"`
+	Max      time.Duration `json:"max"`
+	Breaches int           `json:"sla_breaches,omitempty"`
+}
+
+// Summarize computes duration percentiles per formula step for the given
+// formula's completed runs. Runs recorded without a formula step ID are
+// grouped by title. Steps are returned in order of first appearance.
+func Summarize(runs []*StepRun, formula string) []StepStats {
+	durations := make(map[string][]time.Duration)
+	breaches := make(map[string]int)
+	first := make(map[string]*StepRun)
+	var order []string
+
+	for _, r := range runs {
+		if r.Formula != formula {
+			continue
+		}
+		d := r.Duration()
+		if d == 0 {
+			continue
+		}
+		key := "step:" + r.FormulaStep
+		if r.FormulaStep == "" {
+			key = "title:" + r.Title
+		}
+		if _, ok := durations[key]; !ok {
+			order = append(order, key)
+			first[key] = r
+		}
+		durations[key] = append(durations[key], d)
+		if r.Breached {
+			breaches[key]++
+		}
+	}
+
+	stats := make([]StepStats, 0, len(order))
+	for _, key := range order {
+		ds := durations[key]
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+		stats = append(stats, StepStats{
+			Step:     first[key].FormulaStep,
+			Title:    first[key].Title,
+			Runs:     len(ds),
+			P50:      Percentile(ds, 50),
+			P90:      Percentile(ds, 90),
+			Max:      ds[len(ds)-1],
+			Breaches: breaches[key],
+		})
+	}
+	return stats
+}
+
+// Percentile returns the nearest-rank percentile of sorted durations.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// earliest returns the earlier of two times, treating zero as unset.
+func earliest(current, candidate time.Time) time.Time {
+	if current.IsZero() || candidate.Before(current) {
+		return candidate
+	}
+	return current
+}
+
+// payloadString extracts a string field from an event payload.
+func payloadString(payload map[string]interface{}, key string) string {
+	if payload == nil {
+		return ""
+	}
+	s, _ := payload[key].(string)
+	return s
+}
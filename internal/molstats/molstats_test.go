@@ -0,0 +1,138 @@
+package molstats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func stepEvent(eventType, actor, ts, step, formulaStep, title string) events.Event {
+	return events.Event{
+		Timestamp: ts,
+		Type:      eventType,
+		Actor:     actor,
+		Payload:   events.StepPayload(step, "gt-mol", "mol-polecat-work", formulaStep, title),
+	}
+}
+
+func TestFold(t *testing.T) {
+	evts := []events.Event{
+		stepEvent(events.TypeStepClaimed, "gastown/polecats/Toast", "2026-01-01T10:00:00Z", "gt-mol.1", "load-context", "Load context"),
+		stepEvent(events.TypeStepStarted, "gastown/polecats/Toast", "2026-01-01T10:01:00Z", "gt-mol.1", "load-context", "Load context"),
+		stepEvent(events.TypeStepClosed, "gastown/polecats/Toast", "2026-01-01T10:05:00Z", "gt-mol.1", "load-context", "Load context"),
+		// Re-claim after crash must not move the claim time forward
+		stepEvent(events.TypeStepClaimed, "gastown/polecats/Toast", "2026-01-01T10:03:00Z", "gt-mol.1", "load-context", "Load context"),
+		stepEvent(events.TypeStepClaimed, "gastown/polecats/Toast", "2026-01-01T10:06:00Z", "gt-mol.2", "implement", "Implement"),
+	}
+
+	runs := Fold(evts)
+	if len(runs) != 2 {
+		t.Fatalf("Fold() returned %d runs, want 2", len(runs))
+	}
+
+	first := Find(runs, "gt-mol.1")
+	if first == nil {
+		t.Fatal("Find(gt-mol.1) = nil")
+	}
+	if got := first.Duration(); got != 4*time.Minute {
+		t.Errorf("Duration() = %v, want 4m (start to close)", got)
+	}
+	if first.Agent != "gastown/polecats/Toast" {
+		t.Errorf("Agent = %q", first.Agent)
+	}
+	if first.Formula != "mol-polecat-work" {
+		t.Errorf("Formula = %q", first.Formula)
+	}
+	if first.FormulaStep != "load-context" {
+		t.Errorf("FormulaStep = %q", first.FormulaStep)
+	}
+
+	second := Find(runs, "gt-mol.2")
+	if second.Closed() {
+		t.Error("gt-mol.2 should still be open")
+	}
+	now := time.Date(2026, 1, 1, 10, 16, 0, 0, time.UTC)
+	if got := second.Elapsed(now); got != 10*time.Minute {
+		t.Errorf("Elapsed() = %v, want 10m (from claim)", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var runs []*StepRun
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 10; i++ {
+		// Rendered titles vary with the vars a molecule was poured with
+		runs = append(runs, &StepRun{
+			Step:        fmt.Sprintf("gt-x%d.1", i),
+			Formula:     "mol-polecat-work",
+			FormulaStep: "implement",
+			Title:       fmt.Sprintf("Implement gt-%d", i),
+			StartedAt:   base,
+			ClosedAt:    base.Add(time.Duration(i) * time.Minute),
+			Breached:    i == 10,
+		})
+	}
+	runs = append(runs, &StepRun{Formula: "other", Title: "Implement", StartedAt: base, ClosedAt: base.Add(time.Hour)})
+	runs = append(runs, &StepRun{Formula: "mol-polecat-work", Title: "Open step", StartedAt: base})
+
+	stats := Summarize(runs, "mol-polecat-work")
+	if len(stats) != 1 {
+		t.Fatalf("Summarize() returned %d steps, want 1 (open runs and other formulas excluded)", len(stats))
+	}
+	s := stats[0]
+	if s.Step != "implement" {
+		t.Errorf("Step = %q, want implement", s.Step)
+	}
+	if s.Runs != 10 {
+		t.Errorf("Runs = %d, want 10", s.Runs)
+	}
+	if s.P50 != 5*time.Minute {
+		t.Errorf("P50 = %v, want 5m", s.P50)
+	}
+	if s.P90 != 9*time.Minute {
+		t.Errorf("P90 = %v, want 9m", s.P90)
+	}
+	if s.Max != 10*time.Minute {
+		t.Errorf("Max = %v, want 10m", s.Max)
+	}
+	if s.Breaches != 1 {
+		t.Errorf("Breaches = %d, want 1", s.Breaches)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	townRoot := t.TempDir()
+
+	runs, err := Load(townRoot)
+	if err != nil || runs != nil {
+		t.Fatalf("Load() with no events file = %v, %v; want nil, nil", runs, err)
+	}
+
+	var data []byte
+	for _, e := range []events.Event{
+		{Timestamp: "2026-01-01T10:00:00Z", Type: events.TypeSling, Payload: events.SlingPayload("gt-1", "gastown")},
+		stepEvent(events.TypeStepClaimed, "a", "2026-01-01T10:00:00Z", "gt-mol.1", "load-context", "Load context"),
+		stepEvent(events.TypeStepClosed, "a", "2026-01-01T10:02:00Z", "gt-mol.1", "load-context", "Load context"),
+	} {
+		line, _ := json.Marshal(e)
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	data = append(data, []byte("not json\n")...)
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err = Load(townRoot)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Duration() != 2*time.Minute {
+		t.Errorf("Load() = %+v, want one 2m run", runs)
+	}
+}
@@ -1,4 +1,5 @@
-// Gas Town OpenCode plugin: hooks SessionStart/Compaction via events.
+// Gas Town OpenCode plugin: hooks SessionStart/Compaction via events, and
+// checks each tool call against the Gas Town policy (gt policy check).
 export const GasTown = async ({ $, directory }) => {
   const role = (process.env.GT_ROLE || "").toLowerCase();
   const autonomousRoles = new Set(["polecat", "witness", "refinery", "deacon"]);
@@ -22,11 +23,30 @@ export const GasTown = async ({ $, directory }) => {
     await run("gt nudge deacon session-started");
   };
 
+  // OpenCode can't ask the user from a plugin, so "ask" blocks like "deny".
+  const checkPolicy = async (tool, args) => {
+    const input = JSON.stringify({ tool_name: tool, tool_input: args, cwd: directory });
+    const out = await $`gt policy check < ${new Response(input)}`.cwd(directory).quiet().nothrow().text();
+    if (!out.trim()) return;
+    let decision;
+    try {
+      decision = JSON.parse(out).hookSpecificOutput;
+    } catch {
+      return;
+    }
+    if (decision?.permissionDecision === "deny" || decision?.permissionDecision === "ask") {
+      throw new Error(decision.permissionDecisionReason || "Blocked by Gas Town policy");
+    }
+  };
+
   return {
     event: async ({ event }) => {
       if (event?.type === "session.created") {
         await onSessionCreated();
       }
     },
+    "tool.execute.before": async (input, output) => {
+      await checkPolicy(input.tool, output.args);
+    },
   };
 };
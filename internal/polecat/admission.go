@@ -0,0 +1,231 @@
+package polecat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ErrAtCapacity is returned when a rig already has max_polecats polecats.
+var ErrAtCapacity = errors.New("rig is at max_polecats capacity")
+
+// MaxPolecats returns the rig's max_polecats limit. Zero or less means unlimited.
+func (m *Manager) MaxPolecats() int {
+	return m.rig.GetIntConfig("max_polecats")
+}
+
+// Count returns the number of polecats that currently occupy a slot in the
+// rig. Every polecat directory counts, whether or not its session is running:
+// the worktree is the resource being limited.
+func (m *Manager) Count() int {
+	entries, err := os.ReadDir(filepath.Join(m.rig.Path, "polecats"))
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			count++
+		}
+	}
+	return count
+}
+
+// CheckCapacity returns ErrAtCapacity if adding another polecat would exceed
+// the rig's max_polecats limit.
+func (m *Manager) CheckCapacity() error {
+	limit := m.MaxPolecats()
+	if limit <= 0 {
+		return nil
+	}
+	if count := m.Count(); count >= limit {
+		return fmt.Errorf("%w (%d/%d)", ErrAtCapacity, count, limit)
+	}
+	return nil
+}
+
+// LockAdmission takes the rig's admission lock, which serializes the
+// capacity check and the creation of the polecat across processes, so two
+// slings can't both take the last slot. Returns the function releasing it.
+func (m *Manager) LockAdmission() (func(), error) {
+	path := filepath.Join(m.rig.Path, ".runtime", "admission.lock")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime dir: %w", err)
+	}
+	lock := flock.New(path)
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking admission: %w", err)
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+// QueuedSling is a sling waiting for a free polecat slot.
+type QueuedSling struct {
+	Bead     string    `json:"bead"`
+	Priority int       `json:"priority"`
+	Args     string    `json:"args,omitempty"`
+	Agent    string    `json:"agent,omitempty"`
+	Account  string    `json:"account,omitempty"`
+	NoMerge  bool      `json:"no_merge,omitempty"`
+	QueuedBy string    `json:"queued_by,omitempty"`
+	QueuedAt time.Time `json:"queued_at"`
+	Attempts int       `json:"attempts,omitempty"` // Failed replays so far
+}
+
+// AdmissionQueue is the per-rig queue of slings that arrived while the rig
+// was at max_polecats. Entries are kept in priority order (P0 first), FIFO
+// within a priority. The queue is persisted in .runtime/sling-queue.json
+// and every mutation holds a file lock, so concurrent slings and nukes
+// don't lose entries.
+type AdmissionQueue struct {
+	path string
+}
+
+// NewAdmissionQueue returns the admission queue for the rig at rigPath.
+func NewAdmissionQueue(rigPath string) *AdmissionQueue {
+	return &AdmissionQueue{path: filepath.Join(rigPath, ".runtime", "sling-queue.json")}
+}
+
+// List returns the queued slings in admission order.
+func (q *AdmissionQueue) List() ([]QueuedSling, error) {
+	return q.load()
+}
+
+// Enqueue adds a sling to the queue and returns its 1-based position.
+// A bead that is already queued keeps its original place.
+func (q *AdmissionQueue) Enqueue(entry QueuedSling) (int, error) {
+	if entry.QueuedAt.IsZero() {
+		entry.QueuedAt = time.Now().UTC()
+	}
+
+	var position int
+	err := q.update(func(entries []QueuedSling) []QueuedSling {
+		for i, e := range entries {
+			if e.Bead == entry.Bead {
+				position = i + 1
+				return entries
+			}
+		}
+		entries = append(entries, entry)
+		sortQueue(entries)
+		for i, e := range entries {
+			if e.Bead == entry.Bead {
+				position = i + 1
+			}
+		}
+		return entries
+	})
+	return position, err
+}
+
+// Peek returns the first queued sling without removing it, or nil if the
+// queue is empty.
+func (q *AdmissionQueue) Peek() (*QueuedSling, error) {
+	entries, err := q.load()
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// RecordFailure counts a failed replay of a queued bead. The entry keeps its
+// place until it has failed maxAttempts times, then it is dropped. Returns
+// true if it was dropped.
+func (q *AdmissionQueue) RecordFailure(beadID string, maxAttempts int) (bool, error) {
+	dropped := false
+	err := q.update(func(entries []QueuedSling) []QueuedSling {
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.Bead == beadID {
+				e.Attempts++
+				if e.Attempts >= maxAttempts {
+					dropped = true
+					continue
+				}
+			}
+			kept = append(kept, e)
+		}
+		return kept
+	})
+	return dropped, err
+}
+
+// Remove drops a bead from the queue. Returns true if it was queued.
+func (q *AdmissionQueue) Remove(beadID string) (bool, error) {
+	removed := false
+	err := q.update(func(entries []QueuedSling) []QueuedSling {
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.Bead == beadID {
+				removed = true
+				continue
+			}
+			kept = append(kept, e)
+		}
+		return kept
+	})
+	return removed, err
+}
+
+// update applies fn to the queue under the queue lock and saves the result.
+func (q *AdmissionQueue) update(fn func([]QueuedSling) []QueuedSling) error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	lock := flock.New(q.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking sling queue: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := q.load()
+	if err != nil {
+		return err
+	}
+	entries = fn(entries)
+	if len(entries) == 0 {
+		if err := os.Remove(q.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("clearing sling queue: %w", err)
+		}
+		return nil
+	}
+	if err := util.AtomicWriteJSON(q.path, entries); err != nil {
+		return fmt.Errorf("saving sling queue: %w", err)
+	}
+	return nil
+}
+
+func (q *AdmissionQueue) load() ([]QueuedSling, error) {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading sling queue: %w", err)
+	}
+	var entries []QueuedSling
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing sling queue: %w", err)
+	}
+	sortQueue(entries)
+	return entries, nil
+}
+
+// sortQueue orders entries by priority (lower number first), then by
+// queue time.
+func sortQueue(entries []QueuedSling) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Priority != entries[j].Priority {
+			return entries[i].Priority < entries[j].Priority
+		}
+		return entries[i].QueuedAt.Before(entries[j].QueuedAt)
+	})
+}
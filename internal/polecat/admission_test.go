@@ -0,0 +1,158 @@
+package polecat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+func TestCheckCapacity(t *testing.T) {
+	townRoot := t.TempDir()
+	r := &rig.Rig{Name: "testrig", Path: filepath.Join(townRoot, "testrig")}
+	if err := wisp.NewConfig(townRoot, r.Name).Set("max_polecats", 2); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(r, git.NewGit(r.Path), nil)
+
+	if got := m.MaxPolecats(); got != 2 {
+		t.Fatalf("MaxPolecats() = %d, want 2", got)
+	}
+	if err := m.CheckCapacity(); err != nil {
+		t.Fatalf("CheckCapacity() with no polecats = %v", err)
+	}
+
+	for _, name := range []string{"Toast", "Nux", ".hidden"} {
+		if err := os.MkdirAll(filepath.Join(r.Path, "polecats", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := m.Count(); got != 2 {
+		t.Errorf("Count() = %d, want 2 (dot dirs excluded)", got)
+	}
+	if err := m.CheckCapacity(); !errors.Is(err, ErrAtCapacity) {
+		t.Errorf("CheckCapacity() at limit = %v, want ErrAtCapacity", err)
+	}
+	if _, err := m.AddWithOptions("Slit", AddOptions{}); !errors.Is(err, ErrAtCapacity) {
+		t.Errorf("AddWithOptions() at limit = %v, want ErrAtCapacity", err)
+	}
+
+	if err := wisp.NewConfig(townRoot, r.Name).Set("max_polecats", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CheckCapacity(); err != nil {
+		t.Errorf("CheckCapacity() with max_polecats=0 = %v, want unlimited", err)
+	}
+}
+
+func TestLockAdmissionSerializes(t *testing.T) {
+	r := &rig.Rig{Name: "testrig", Path: filepath.Join(t.TempDir(), "testrig")}
+	m := NewManager(r, git.NewGit(r.Path), nil)
+
+	unlock, err := m.LockAdmission()
+	if err != nil {
+		t.Fatalf("LockAdmission() = %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock2, err := m.LockAdmission()
+		if err != nil {
+			t.Errorf("second LockAdmission() = %v", err)
+			close(acquired)
+			return
+		}
+		close(acquired)
+		unlock2()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second LockAdmission() returned while the lock was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second LockAdmission() did not return after unlock")
+	}
+}
+
+func TestAdmissionQueueOrder(t *testing.T) {
+	q := NewAdmissionQueue(t.TempDir())
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	enqueue := func(bead string, priority int, offset time.Duration) int {
+		t.Helper()
+		pos, err := q.Enqueue(QueuedSling{Bead: bead, Priority: priority, QueuedAt: base.Add(offset)})
+		if err != nil {
+			t.Fatalf("Enqueue(%s): %v", bead, err)
+		}
+		return pos
+	}
+
+	enqueue("gt-low", 3, 0)
+	enqueue("gt-mid-a", 2, time.Minute)
+	enqueue("gt-mid-b", 2, 2*time.Minute)
+	if pos := enqueue("gt-urgent", 0, 3*time.Minute); pos != 1 {
+		t.Errorf("P0 position = %d, want 1", pos)
+	}
+	if pos := enqueue("gt-mid-a", 2, 4*time.Minute); pos != 2 {
+		t.Errorf("re-queued bead position = %d, want original place 2", pos)
+	}
+
+	entries, err := q.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Bead)
+	}
+	want := []string{"gt-urgent", "gt-mid-a", "gt-mid-b", "gt-low"}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("List() = %v, want %v", got, want)
+		}
+	}
+
+	if removed, err := q.Remove("gt-mid-b"); err != nil || !removed {
+		t.Errorf("Remove(gt-mid-b) = %v, %v", removed, err)
+	}
+
+	// A failed replay keeps its place until it has failed maxAttempts times
+	if dropped, err := q.RecordFailure("gt-urgent", 2); err != nil || dropped {
+		t.Errorf("first RecordFailure(gt-urgent) = %v, %v; want kept", dropped, err)
+	}
+	if next, err := q.Peek(); err != nil || next == nil || next.Bead != "gt-urgent" || next.Attempts != 1 {
+		t.Errorf("Peek() after failure = %+v, %v; want gt-urgent with 1 attempt", next, err)
+	}
+	if dropped, err := q.RecordFailure("gt-urgent", 2); err != nil || !dropped {
+		t.Errorf("second RecordFailure(gt-urgent) = %v, %v; want dropped", dropped, err)
+	}
+
+	for _, wantBead := range []string{"gt-mid-a", "gt-low"} {
+		next, err := q.Peek()
+		if err != nil || next == nil || next.Bead != wantBead {
+			t.Fatalf("Peek() = %+v, %v; want %s", next, err, wantBead)
+		}
+		if _, err := q.Remove(next.Bead); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if next, err := q.Peek(); err != nil || next != nil {
+		t.Errorf("Peek() on empty queue = %+v, %v", next, err)
+	}
+	if _, err := os.Stat(q.path); !os.IsNotExist(err) {
+		t.Errorf("empty queue file should be removed, stat err = %v", err)
+	}
+}
@@ -222,7 +222,8 @@ func (m *Manager) exists(name string) bool {
 
 // AddOptions configures polecat creation.
 type AddOptions struct {
-	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	HookBead     string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	ResumeBranch string // Existing branch to start the polecat's branch from instead of the default branch (preempted work)
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -345,10 +346,14 @@ func (m *Manager) Add(name string) (*Polecat, error) {
 // AddWithOptions creates a new polecat with the specified options.
 // This allows setting hook_bead atomically at creation time, avoiding
 // cross-beads routing issues when slinging work to new polecats.
+// Returns ErrAtCapacity if the rig already has max_polecats polecats.
 func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error) {
 	if m.exists(name) {
 		return nil, ErrPolecatExists
 	}
+	if err := m.CheckCapacity(); err != nil {
+		return nil, err
+	}
 
 	// New structure: polecats/<name>/<rigname>/ for LLM ergonomics
 	// The polecat's home dir is polecats/<name>/, worktree is polecats/<name>/<rigname>/
@@ -375,21 +380,68 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		fmt.Printf("Warning: could not fetch origin: %v\n", err)
 	}
 
-	// Determine the start point for the new worktree
-	// Use origin/<default-branch> to ensure we start from the rig's configured branch
-	defaultBranch := "main"
-	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-		defaultBranch = rigCfg.DefaultBranch
+	// Start from origin/<default-branch> to ensure we start from the rig's configured branch
+	startPoint := m.startPoint()
+
+	// Resumed work continues from its existing branch, on a branch named
+	// for this polecat so its push rules cover it; the old branch is left
+	// for branch cleanup. Otherwise claim a pre-built worktree from the warm
+	// pool if one is ready (its setup hooks have already run), or build a
+	// fresh worktree.
+	if opts.ResumeBranch != "" {
+		if err := repoGit.WorktreeAddFromRef(clonePath, branchName, opts.ResumeBranch); err != nil {
+			return nil, fmt.Errorf("creating worktree from %s: %w", opts.ResumeBranch, err)
+		}
+		m.provisionWorktree(clonePath, true)
+	} else if !m.addFromWarmPool(repoGit, clonePath, branchName, startPoint) {
+		// Always create fresh branch - unique name guarantees no collision
+		// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
+		// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
+		if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+			return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+		}
+		m.provisionWorktree(clonePath, true)
+	}
+
+	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
+	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
+
+	// Create or reopen agent bead for ZFC compliance (self-report state).
+	// State starts as "spawning" - will be updated to "working" when Claude starts.
+	// HookBead is set atomically at creation time if provided (avoids cross-beads routing issues).
+	// Uses CreateOrReopenAgentBead to handle re-spawning with same name (GH #332).
+	agentID := m.agentBeadID(name)
+	_, err = m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
+		RoleType:   "polecat",
+		Rig:        m.rig.Name,
+		AgentState: "spawning",
+		HookBead:   opts.HookBead, // Set atomically at spawn time
+	})
+	if err != nil {
+		// Non-fatal - log warning but continue
+		fmt.Printf("Warning: could not create agent bead: %v\n", err)
 	}
-	startPoint := fmt.Sprintf("origin/%s", defaultBranch)
 
-	// Always create fresh branch - unique name guarantees no collision
-	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
-	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
-		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+	// Return polecat with working state (transient model: polecats are spawned with work)
+	// State is derived from beads, not stored in state.json
+	now := time.Now()
+	polecat := &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking, // Transient model: polecat spawns with work
+		ClonePath: clonePath,
+		Branch:    branchName,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
+	return polecat, nil
+}
+
+// provisionWorktree applies Gas Town setup to a polecat worktree: AGENTS.md,
+// shared beads, PRIME.md, overlay files and .gitignore patterns, then the
+// rig's setup hooks when runHooks is set. Every step is non-fatal.
+func (m *Manager) provisionWorktree(clonePath string, runHooks bool) {
 	// Ensure AGENTS.md exists - critical for polecats to "land the plane"
 	// Fall back to copy from mayor/rig if not in git (e.g., stale fetch, local-only file)
 	agentsMDPath := filepath.Join(clonePath, "AGENTS.md")
@@ -435,46 +487,47 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
 	}
 
+	// Check the polecat's own git pushes against the push policy
+	if _, err := git.NewGit(clonePath).InstallPrePushHook(); err != nil && !errors.Is(err, git.ErrHooksPathSet) {
+		fmt.Printf("Warning: could not install pre-push hook: %v\n", err)
+	}
+
 	// Run setup hooks from .runtime/setup-hooks/.
 	// These hooks can inject local git config, copy secrets, or perform other setup tasks.
+	if !runHooks {
+		return
+	}
 	if err := rig.RunSetupHooks(m.rig.Path, clonePath); err != nil {
 		// Non-fatal - log warning but continue
 		fmt.Printf("Warning: could not run setup hooks: %v\n", err)
 	}
+}
 
-	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
-	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
-
-	// Create or reopen agent bead for ZFC compliance (self-report state).
-	// State starts as "spawning" - will be updated to "working" when Claude starts.
-	// HookBead is set atomically at creation time if provided (avoids cross-beads routing issues).
-	// Uses CreateOrReopenAgentBead to handle re-spawning with same name (GH #332).
-	agentID := m.agentBeadID(name)
-	_, err = m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
-		RoleType:   "polecat",
-		Rig:        m.rig.Name,
-		AgentState: "spawning",
-		HookBead:   opts.HookBead, // Set atomically at spawn time
-	})
-	if err != nil {
-		// Non-fatal - log warning but continue
-		fmt.Printf("Warning: could not create agent bead: %v\n", err)
-	}
-
-	// Return polecat with working state (transient model: polecats are spawned with work)
-	// State is derived from beads, not stored in state.json
-	now := time.Now()
-	polecat := &Polecat{
-		Name:      name,
-		Rig:       m.rig.Name,
-		State:     StateWorking, // Transient model: polecat spawns with work
-		ClonePath: clonePath,
-		Branch:    branchName,
-		CreatedAt: now,
-		UpdatedAt: now,
+// addFromWarmPool moves a ready warm pool worktree to clonePath and checks
+// out a new branch at startPoint. Tracked files are reset; untracked files
+// such as installed dependencies are kept. Returns false if the pool had
+// nothing ready or the claim failed, leaving clonePath free.
+func (m *Manager) addFromWarmPool(repoGit *git.Git, clonePath, branchName, startPoint string) bool {
+	pool := m.WarmPool()
+	entry, err := pool.Claim()
+	if err != nil || entry == nil {
+		return false
+	}
+	if err := repoGit.WorktreeMove(entry.Path, clonePath); err != nil {
+		fmt.Printf("Warning: could not claim warm worktree %s: %v\n", entry.ID, err)
+		pool.Discard(entry)
+		return false
+	}
+	if err := git.NewGit(clonePath).ForceCheckoutNewBranch(branchName, startPoint); err != nil {
+		fmt.Printf("Warning: could not check out %s in warm worktree: %v\n", branchName, err)
+		if err := repoGit.WorktreeRemove(clonePath, true); err != nil {
+			_ = os.RemoveAll(clonePath)
+		}
+		_ = repoGit.WorktreePrune()
+		return false
 	}
-
-	return polecat, nil
+	m.provisionWorktree(clonePath, false)
+	return true
 }
 
 // Remove deletes a polecat worktree.
@@ -820,6 +873,11 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
 	}
 
+	// Check the polecat's own git pushes against the push policy
+	if _, err := git.NewGit(newClonePath).InstallPrePushHook(); err != nil && !errors.Is(err, git.ErrHooksPathSet) {
+		fmt.Printf("Warning: could not install pre-push hook: %v\n", err)
+	}
+
 	// NOTE: Slash commands inherited from town level - no per-workspace copies needed.
 
 	// Create or reopen agent bead for ZFC compliance
@@ -1178,6 +1236,7 @@ func (m *Manager) setupSharedBeads(clonePath string) error {
 // This includes:
 // - Branches for polecats that no longer exist
 // - Old timestamped branches (keeps only the most recent per polecat name)
+// Branches recorded in .runtime/preempted/ hold paused work and are kept.
 // Returns the number of branches deleted.
 func (m *Manager) CleanupStaleBranches() (int, error) {
 	repoGit, err := m.repoBase()
@@ -1201,8 +1260,9 @@ func (m *Manager) CleanupStaleBranches() (int, error) {
 		return 0, fmt.Errorf("listing polecats: %w", err)
 	}
 
-	// Build set of current polecat branches (from actual polecat objects)
-	currentBranches := make(map[string]bool)
+	// Build set of current polecat branches (from actual polecat objects),
+	// plus branches of preempted work waiting to be resumed
+	currentBranches := PreemptedBranches(m.rig.Path)
 	for _, p := range polecats {
 		currentBranches[p.Branch] = true
 	}
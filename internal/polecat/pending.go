@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -142,7 +143,7 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 		// Check if runtime is ready (non-blocking poll)
 		rigPath := filepath.Join(townRoot, ps.Rig)
 		runtimeConfig := config.LoadRuntimeConfig(rigPath)
-		err = t.WaitForRuntimeReady(ps.Session, runtimeConfig, timeout)
+		err = runtime.Driver(runtimeConfig).WaitReady(t, ps.Session, timeout)
 		if err != nil {
 			// Not ready yet - leave mail in inbox for next poll
 			continue
@@ -0,0 +1,214 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Preemption records work paused by gt polecat preempt so the next polecat
+// slung the same bead resumes it. The work itself lives on Branch (with any
+// uncommitted changes committed as WIP); Checkpoint is written into the new
+// worktree so gt prime shows where the preempted session left off.
+type Preemption struct {
+	Bead        string                 `json:"bead"`
+	Priority    int                    `json:"priority"`
+	Polecat     string                 `json:"polecat"`
+	Branch      string                 `json:"branch"`
+	Commit      string                 `json:"commit"`
+	For         string                 `json:"for,omitempty"` // bead the slot was freed for
+	PreemptedAt time.Time              `json:"preempted_at"`
+	Checkpoint  *checkpoint.Checkpoint `json:"checkpoint,omitempty"`
+}
+
+// PreemptCandidate is a working polecat that could be preempted.
+type PreemptCandidate struct {
+	Name     string
+	Bead     string
+	Priority int
+	Started  time.Time
+}
+
+// ChoosePreemptee picks the polecat to pause for work at priority forPriority:
+// the one on the lowest-priority work (highest P number), preferring the most
+// recently started on ties since it has the least work to lose. Only polecats
+// on strictly lower-priority work are eligible; forPriority < 0 makes every
+// candidate eligible. Returns nil if none is.
+func ChoosePreemptee(candidates []PreemptCandidate, forPriority int) *PreemptCandidate {
+	var best *PreemptCandidate
+	for i := range candidates {
+		c := &candidates[i]
+		if forPriority >= 0 && c.Priority <= forPriority {
+			continue
+		}
+		if best == nil || c.Priority > best.Priority ||
+			(c.Priority == best.Priority && c.Started.After(best.Started)) {
+			best = c
+		}
+	}
+	return best
+}
+
+// Preempt pauses a polecat's work: it captures a checkpoint, commits any
+// uncommitted changes to the polecat's branch as WIP, records the
+// preemption, and removes the worktree. The branch is kept so a later spawn
+// for rec.Bead continues from it on a branch of its own (see LoadPreemption). The caller stops the
+// session first and parks the hook afterwards.
+//
+// rec.Bead, rec.Priority and rec.For are set by the caller; the rest is
+// filled in here.
+func (m *Manager) Preempt(name string, rec *Preemption) error {
+	if !m.exists(name) {
+		return ErrPolecatNotFound
+	}
+	clonePath := m.clonePath(name)
+
+	cp, err := checkpoint.Capture(clonePath)
+	if err != nil {
+		return fmt.Errorf("capturing checkpoint: %w", err)
+	}
+	if cp.LastCommit == "" {
+		return fmt.Errorf("%s is not a git worktree", clonePath)
+	}
+	if cp.Branch == "" || cp.Branch == "HEAD" {
+		return fmt.Errorf("polecat %s is not on a branch; its work cannot be resumed", name)
+	}
+
+	// Keep the molecule context and notes the session recorded itself
+	if live, err := checkpoint.Read(clonePath); err == nil && live != nil {
+		cp.MoleculeID = live.MoleculeID
+		cp.CurrentStep = live.CurrentStep
+		cp.StepTitle = live.StepTitle
+		cp.PendingClosures = live.PendingClosures
+		cp.Notes = live.Notes
+	}
+	if err := checkpoint.Remove(clonePath); err != nil {
+		return err
+	}
+
+	reason := "preempted to free a polecat slot"
+	if rec.For != "" {
+		reason = fmt.Sprintf("preempted for %s", rec.For)
+	}
+	note := "Work " + reason
+	g := git.NewGit(clonePath)
+	if len(cp.ModifiedFiles) > 0 {
+		// Stage only the work, not checkpoint files or other debris that
+		// git status no longer lists
+		if err := g.Add(append([]string{"-A", "--"}, wipPaths(cp.ModifiedFiles)...)...); err != nil {
+			return fmt.Errorf("staging WIP: %w", err)
+		}
+		if status, err := g.Status(); err == nil && !status.Clean {
+			if err := g.Commit("WIP: " + reason); err != nil {
+				return fmt.Errorf("committing WIP: %w", err)
+			}
+			note += fmt.Sprintf("; uncommitted changes (%s) were committed as WIP", strings.Join(cp.ModifiedFiles, ", "))
+		}
+		head, err := g.Rev("HEAD")
+		if err != nil {
+			return fmt.Errorf("reading HEAD: %w", err)
+		}
+		cp.LastCommit = head
+		cp.ModifiedFiles = nil
+	}
+
+	if cp.Notes != "" {
+		note = cp.Notes + "\n" + note
+	}
+	cp.Notes = note
+	cp.HookedBead = rec.Bead
+	cp.Reason = checkpoint.ReasonPreempt
+	cp.SessionID = "preempt"
+
+	rec.Polecat = name
+	rec.Branch = cp.Branch
+	rec.Commit = cp.LastCommit
+	rec.PreemptedAt = time.Now().UTC()
+	rec.Checkpoint = cp
+	if err := SavePreemption(m.rig.Path, rec); err != nil {
+		return err
+	}
+
+	if err := m.RemoveWithOptions(name, true, true, false); err != nil {
+		return fmt.Errorf("removing worktree: %w", err)
+	}
+	return nil
+}
+
+// wipPaths returns the paths to stage for a checkpoint's modified files,
+// splitting git status's "old -> new" renames into both paths.
+func wipPaths(modified []string) []string {
+	var paths []string
+	for _, f := range modified {
+		if from, to, ok := strings.Cut(f, " -> "); ok {
+			paths = append(paths, from, to)
+			continue
+		}
+		paths = append(paths, f)
+	}
+	return paths
+}
+
+// preemptionPath is where the preemption record for a bead is kept.
+func preemptionPath(rigPath, beadID string) string {
+	return filepath.Join(rigPath, ".runtime", "preempted", beadID+".json")
+}
+
+// SavePreemption stores a preemption record under the rig's runtime dir.
+func SavePreemption(rigPath string, rec *Preemption) error {
+	path := preemptionPath(rigPath, rec.Bead)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating preempted dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, rec)
+}
+
+// LoadPreemption returns the preemption record for a bead, or nil if its
+// work was never preempted (or has already been resumed).
+func LoadPreemption(rigPath, beadID string) (*Preemption, error) {
+	data, err := os.ReadFile(preemptionPath(rigPath, beadID)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading preemption record: %w", err)
+	}
+	var rec Preemption
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing preemption record: %w", err)
+	}
+	return &rec, nil
+}
+
+// PreemptedBranches returns the branches holding preempted work waiting to
+// be resumed. They belong to no polecat, but must survive branch cleanup.
+func PreemptedBranches(rigPath string) map[string]bool {
+	branches := make(map[string]bool)
+	paths, _ := filepath.Glob(filepath.Join(rigPath, ".runtime", "preempted", "*.json"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		var rec Preemption
+		if json.Unmarshal(data, &rec) == nil && rec.Branch != "" {
+			branches[rec.Branch] = true
+		}
+	}
+	return branches
+}
+
+// ClearPreemption deletes a bead's preemption record once its work resumed.
+func ClearPreemption(rigPath, beadID string) error {
+	if err := os.Remove(preemptionPath(rigPath, beadID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing preemption record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestChoosePreemptee(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	candidates := []PreemptCandidate{
+		{Name: "a", Priority: 2, Started: t0},
+		{Name: "b", Priority: 3, Started: t0},
+		{Name: "c", Priority: 3, Started: t0.Add(time.Hour)},
+		{Name: "d", Priority: 1, Started: t0.Add(2 * time.Hour)},
+	}
+	tests := []struct {
+		name        string
+		forPriority int
+		want        string
+	}{
+		{"lowest priority, newest on ties", 0, "c"},
+		{"any priority", -1, "c"},
+		{"only strictly lower priority", 3, ""},
+		{"equal priority not eligible", 2, "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ChoosePreemptee(candidates, tt.forPriority)
+			name := ""
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("ChoosePreemptee(P%d) = %q, want %q", tt.forPriority, name, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreemptAndResume(t *testing.T) {
+	root := initTestRigRepo(t)
+	r := &rig.Rig{Name: "rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+
+	p, err := m.AddWithOptions("Toast", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	configureTestGitIdentity(t, p.ClonePath)
+	if err := os.WriteFile(filepath.Join(p.ClonePath, "wip.txt"), []byte("half done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A lock left in the worktree by an older gt is not work
+	if err := os.WriteFile(filepath.Join(p.ClonePath, checkpoint.Filename+".lock"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &Preemption{Bead: "gt-low", Priority: 3, For: "gt-urgent"}
+	if err := m.Preempt("Toast", rec); err != nil {
+		t.Fatalf("Preempt: %v", err)
+	}
+	if m.exists("Toast") {
+		t.Error("preempted polecat's worktree still exists")
+	}
+	if rec.Branch != p.Branch || rec.Commit == "" {
+		t.Errorf("record branch/commit = %q/%q, want branch %q", rec.Branch, rec.Commit, p.Branch)
+	}
+	if rec.Checkpoint == nil || rec.Checkpoint.Reason != checkpoint.ReasonPreempt ||
+		!strings.Contains(rec.Checkpoint.Notes, "wip.txt") {
+		t.Errorf("checkpoint = %+v, want preempt reason noting wip.txt", rec.Checkpoint)
+	}
+
+	saved, err := LoadPreemption(root, "gt-low")
+	if err != nil || saved == nil || saved.Commit != rec.Commit {
+		t.Fatalf("LoadPreemption() = %+v, %v", saved, err)
+	}
+
+	// A new polecat continues from the branch, WIP commit included, on a
+	// branch of its own
+	resumed, err := m.AddWithOptions("Nux", AddOptions{ResumeBranch: saved.Branch})
+	if err != nil {
+		t.Fatalf("AddWithOptions(ResumeBranch): %v", err)
+	}
+	if !strings.HasPrefix(resumed.Branch, "polecat/Nux") {
+		t.Errorf("resumed branch = %q, want a polecat/Nux branch", resumed.Branch)
+	}
+	if data, err := os.ReadFile(filepath.Join(resumed.ClonePath, "wip.txt")); err != nil || string(data) != "half done\n" {
+		t.Errorf("wip.txt in resumed worktree = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(resumed.ClonePath, checkpoint.Filename+".lock")); !os.IsNotExist(err) {
+		t.Error("checkpoint lock was committed with the WIP")
+	}
+
+	if err := ClearPreemption(root, "gt-low"); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := LoadPreemption(root, "gt-low"); saved != nil {
+		t.Error("preemption record survived ClearPreemption")
+	}
+}
+
+func TestPreemptGCResume(t *testing.T) {
+	root := initTestRigRepo(t)
+	r := &rig.Rig{Name: "rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+
+	p, err := m.AddWithOptions("Toast", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	configureTestGitIdentity(t, p.ClonePath)
+	if err := os.WriteFile(filepath.Join(p.ClonePath, "wip.txt"), []byte("half done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rec := &Preemption{Bead: "gt-low", Priority: 3}
+	if err := m.Preempt("Toast", rec); err != nil {
+		t.Fatalf("Preempt: %v", err)
+	}
+
+	// gt polecat gc runs between the preemption and the resume
+	deleted, err := m.CleanupStaleBranches()
+	if err != nil {
+		t.Fatalf("CleanupStaleBranches: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("CleanupStaleBranches() deleted %d branch(es), want the preempted branch kept", deleted)
+	}
+
+	resumed, err := m.AddWithOptions("Nux", AddOptions{ResumeBranch: rec.Branch})
+	if err != nil {
+		t.Fatalf("AddWithOptions(ResumeBranch) after gc: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(resumed.ClonePath, "wip.txt")); err != nil || string(data) != "half done\n" {
+		t.Errorf("wip.txt in resumed worktree = %q, %v", data, err)
+	}
+}
@@ -8,15 +8,23 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/agentdriver"
+	"github.com/steveyegge/gastown/internal/cgroup"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/container"
+	"github.com/steveyegge/gastown/internal/recording"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -55,9 +63,16 @@ type SessionStartOptions struct {
 	// Issue is an optional issue ID to work on.
 	Issue string
 
+	// HookBead is the bead already on the polecat's hook, if known. It is
+	// stored with the session recording so gt replay can find it by bead.
+	HookBead string
+
 	// Command overrides the default "claude" command.
 	Command string
 
+	// Agent overrides the rig's agent for this session (e.g. gt sling --agent).
+	Agent string
+
 	// Account specifies the account handle to use (overrides default).
 	Account string
 
@@ -91,6 +106,10 @@ type SessionInfo struct {
 
 	// LastActivity is when the session last had activity.
 	LastActivity time.Time `json:"last_activity,omitempty"`
+
+	// AgentStatus is what the agent in the session is doing, as its
+	// driver reads it.
+	AgentStatus agentdriver.Status `json:"agent_status,omitempty"`
 }
 
 // SessionName generates the tmux session name for a polecat.
@@ -171,7 +190,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		}
 	}
 
-	runtimeConfig := config.LoadRuntimeConfig(m.rig.Path)
+	startPrefix, runtimeConfig, err := config.ResolvePolecatStartup(m.rig.Name, polecat, m.rig.Path, opts.Agent, workDir)
+	if err != nil {
+		return fmt.Errorf("resolving agent: %w", err)
+	}
+	driver := runtime.Driver(runtimeConfig)
 
 	// Ensure runtime settings exist in polecat's home directory (polecats/<name>/).
 	// This keeps settings out of the git worktree while allowing runtime to find them
@@ -201,22 +224,58 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	command := opts.Command
 	if command == "" {
-		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon, workDir)
+		command = startPrefix + driver.StartCommand(beacon)
 	}
 	// Prepend runtime config dir env if needed
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
 
+	// Run the agent in a container, or confine its writes and resources to
+	// its own scope and cgroup, if the rig asks for it. A container enforces
+	// both itself.
+	townRoot := filepath.Dir(m.rig.Path)
+	limits := config.LoadCgroupConfig(townRoot, m.rig.Path)
+	var containerRuntime string
+	if containerCfg := config.LoadContainerConfig(m.rig.Path); container.Enabled(containerCfg) {
+		spec := container.Spec{
+			Name:    sessionID,
+			HomeDir: polecatHomeDir,
+			WorkDir: workDir,
+			Shared:  container.SharedDirs(townRoot, polecatHomeDir, workDir),
+			Tools:   container.Tools(),
+		}
+		wrapped, rt, err := container.Wrap(spec, command, containerCfg, limits)
+		if err != nil {
+			return fmt.Errorf("container isolation: %w", err)
+		}
+		command, containerRuntime = wrapped, rt
+	} else {
+		spec := sandbox.Spec{TownRoot: townRoot, RigPath: m.rig.Path, HomeDir: polecatHomeDir, WorkDir: workDir}
+		command = sandbox.Wrap(command, spec, config.LoadSandboxConfig(m.rig.Path))
+		command = cgroup.Wrap(sessionID, command, limits)
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Record the pane from the start if the rig records sessions (non-fatal)
+	bead := opts.HookBead
+	if bead == "" {
+		bead = opts.Issue
+	}
+	if bead == "" {
+		if cp, err := checkpoint.Read(workDir); err == nil && cp != nil {
+			bead = cp.HookedBead
+		}
+	}
+	debugSession("StartRecording", m.startRecording(sessionID, polecat, bead))
+
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
@@ -226,6 +285,12 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		BeadsNoDaemon:    true,
 		WorkDir:          workDir,
 	})
+	if opts.Agent != "" {
+		envVars["GT_AGENT"] = opts.Agent
+	}
+	if containerRuntime != "" {
+		envVars[container.EnvVar] = containerRuntime
+	}
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
 	}
@@ -249,22 +314,23 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Wait for Claude to start (non-fatal)
 	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
-	// Accept bypass permissions warning dialog if it appears
-	debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+	// Dismiss anything the runtime shows at startup (e.g. Claude's bypass
+	// permissions warning)
+	debugSession("AfterStart", driver.AfterStart(m.tmux, sessionID))
 
 	// Wait for runtime to be fully ready at the prompt (not just started)
-	runtime.SleepForReadyDelay(runtimeConfig)
+	debugSession("WaitReady", driver.WaitReady(m.tmux, sessionID, constants.ClaudeStartTimeout))
 
 	// Handle fallback nudges for non-hook agents.
 	// See StartupFallbackInfo in runtime package for the fallback matrix.
 	if fallbackInfo.SendBeaconNudge && fallbackInfo.SendStartupNudge && fallbackInfo.StartupNudgeDelayMs == 0 {
 		// Hooks + no prompt: Single combined nudge (hook already ran gt prime synchronously)
 		combined := beacon + "\n\n" + runtime.StartupNudgeContent()
-		debugSession("SendCombinedNudge", m.tmux.NudgeSession(sessionID, combined))
+		debugSession("SendCombinedNudge", driver.SendPrompt(m.tmux, sessionID, combined))
 	} else {
 		if fallbackInfo.SendBeaconNudge {
 			// Agent doesn't support CLI prompt - send beacon via nudge
-			debugSession("SendBeaconNudge", m.tmux.NudgeSession(sessionID, beacon))
+			debugSession("SendBeaconNudge", driver.SendPrompt(m.tmux, sessionID, beacon))
 		}
 
 		if fallbackInfo.StartupNudgeDelayMs > 0 {
@@ -274,7 +340,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 		if fallbackInfo.SendStartupNudge {
 			// Send work instructions via nudge
-			debugSession("SendStartupNudge", m.tmux.NudgeSession(sessionID, runtime.StartupNudgeContent()))
+			debugSession("SendStartupNudge", driver.SendPrompt(m.tmux, sessionID, runtime.StartupNudgeContent()))
 		}
 	}
 
@@ -294,6 +360,38 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	return nil
 }
 
+// startRecording pipes a session's pane output to gt record-pane when the
+// rig has recording enabled.
+func (m *SessionManager) startRecording(sessionID, polecat, bead string) error {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || settings.Recording == nil || !settings.Recording.Enabled {
+		return nil
+	}
+	cfg := settings.Recording
+
+	width, height, err := m.tmux.GetPaneSize(sessionID)
+	if err != nil {
+		width, height = 80, 24
+	}
+	args := []string{
+		"gt", "record-pane",
+		"--dir", recording.Dir(m.rig.Path, polecat),
+		"--session", sessionID,
+		"--agent", fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		"--width", strconv.Itoa(width),
+		"--height", strconv.Itoa(height),
+		"--max-file-bytes", strconv.FormatInt(cfg.MaxFileBytes(), 10),
+		"--max-files", strconv.Itoa(cfg.MaxFilesOrDefault()),
+	}
+	if bead != "" {
+		args = append(args, "--bead", bead)
+	}
+	for i, arg := range args {
+		args[i] = config.ShellQuote(arg)
+	}
+	return m.tmux.PipePane(sessionID, "exec "+strings.Join(args, " "))
+}
+
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
@@ -306,6 +404,9 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 		return ErrSessionNotFound
 	}
 
+	// Note what the transcript archive needs while the session still exists
+	src, entry := m.transcriptSource(sessionID, polecat)
+
 	// Try graceful shutdown first
 	if !force {
 		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
@@ -317,10 +418,44 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	cgroup.Release(sessionID, config.LoadCgroupConfig(filepath.Dir(m.rig.Path), m.rig.Path))
+	container.Release(sessionID, config.LoadContainerConfig(m.rig.Path))
+
+	// Archive the session's transcript for gt seance search (non-fatal)
+	_, err = transcript.ArchiveSession(filepath.Dir(m.rig.Path), src, entry)
+	debugSession("ArchiveTranscript", err)
 
 	return nil
 }
 
+// transcriptSource describes where a running polecat session's runtime
+// writes its transcript and how the archive should index it.
+func (m *SessionManager) transcriptSource(sessionID, polecat string) (transcript.Source, transcript.Entry) {
+	rc := config.LoadRuntimeConfig(m.rig.Path)
+	workDir := m.clonePath(polecat)
+	if dir, err := m.tmux.GetPaneWorkDir(sessionID); err == nil && dir != "" {
+		workDir = dir
+	}
+	configDir := ""
+	if rc.Session != nil && rc.Session.ConfigDirEnv != "" {
+		configDir, _ = m.tmux.GetEnvironment(sessionID, rc.Session.ConfigDirEnv)
+	}
+	src := runtime.Driver(rc).Transcript(workDir, configDir)
+	if created, err := m.tmux.GetSessionCreated(sessionID); err == nil {
+		src.Since = created
+	}
+
+	entry := transcript.Entry{
+		Agent:   fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		Rig:     m.rig.Name,
+		Session: sessionID,
+	}
+	if cp, err := checkpoint.Read(m.clonePath(polecat)); err == nil && cp != nil {
+		entry.Bead = cp.HookedBead
+	}
+	return src, entry
+}
+
 // IsRunning checks if a polecat session is active.
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
@@ -347,6 +482,8 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		return info, nil
 	}
 
+	info.AgentStatus = runtime.DriverForSession(m.tmux, sessionID).Status(m.tmux, sessionID)
+
 	tmuxInfo, err := m.tmux.GetSessionInfo(sessionID)
 	if err != nil {
 		return info, nil
@@ -0,0 +1,398 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// warmPoolDir is the pool directory under polecats/. The leading dot keeps
+// pool worktrees out of polecat listings and max_polecats counts.
+const warmPoolDir = ".warm"
+
+// warmBuildTimeout is how long a worktree may stay in the building state
+// before a later fill assumes its builder died and discards it.
+const warmBuildTimeout = time.Hour
+
+// warmFetchInterval is the least time between the origin fetches a fill
+// does before creating or refreshing worktrees. The daemon fills every
+// heartbeat; a pool with nothing to build does not fetch at all.
+const warmFetchInterval = 5 * time.Minute
+
+// WarmEntry is a pre-created worktree in a rig's warm pool.
+type WarmEntry struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Commit  string    `json:"commit"`
+	ReadyAt time.Time `json:"ready_at"`
+}
+
+// WarmFillResult summarizes one WarmPool.Fill.
+type WarmFillResult struct {
+	Created   int      `json:"created"`
+	Refreshed int      `json:"refreshed"`
+	Removed   int      `json:"removed"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// WarmPool is a rig's pool of worktrees created ahead of time, detached at
+// the default branch with setup hooks already run. Spawning a polecat claims
+// a ready worktree and moves it into place instead of building one.
+//
+// Each entry is a worktree at polecats/.warm/<id> plus a marker file beside
+// it: <id>.ready once provisioned, <id>.building while being created or
+// refreshed. Markers are only changed under the pool lock; the slow git and
+// hook work happens outside it.
+type WarmPool struct {
+	m   *Manager
+	dir string
+}
+
+// WarmPool returns the rig's warm pool.
+func (m *Manager) WarmPool() *WarmPool {
+	return &WarmPool{m: m, dir: filepath.Join(m.rig.Path, "polecats", warmPoolDir)}
+}
+
+// Config returns the rig's warm pool settings (defaults when unset).
+func (p *WarmPool) Config() *config.WarmPoolConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(p.m.rig.Path))
+	if err != nil || settings.WarmPool == nil {
+		return config.DefaultWarmPoolConfig()
+	}
+	return settings.WarmPool
+}
+
+// Size returns the configured pool size. Zero means the pool is disabled.
+func (p *WarmPool) Size() int {
+	return p.Config().PoolSize()
+}
+
+// List returns the ready entries, oldest first.
+func (p *WarmPool) List() ([]WarmEntry, error) {
+	ready, _, err := p.scan()
+	return ready, err
+}
+
+// Building returns the number of entries being created or refreshed.
+func (p *WarmPool) Building() int {
+	_, building, _ := p.scan()
+	return len(building)
+}
+
+// Claim takes the oldest ready entry out of the pool, or returns nil if
+// the pool is empty. The caller owns the worktree at entry.Path and must
+// move or remove it.
+func (p *WarmPool) Claim() (*WarmEntry, error) {
+	var claimed *WarmEntry
+	err := p.locked(func() error {
+		ready, _, err := p.scan()
+		if err != nil || len(ready) == 0 {
+			return err
+		}
+		claimed = &ready[0]
+		return os.Remove(p.marker(claimed.ID, "ready"))
+	})
+	return claimed, err
+}
+
+// Fill brings the pool to its configured size: it creates missing entries,
+// refreshes ready entries older than the refresh interval against the
+// latest default branch, and removes entries beyond the size and leftovers
+// from interrupted builds. A disabled pool is drained.
+func (p *WarmPool) Fill() (*WarmFillResult, error) {
+	cfg := p.Config()
+	size := cfg.PoolSize()
+	result := &WarmFillResult{}
+	if _, err := os.Stat(p.dir); size == 0 && os.IsNotExist(err) {
+		return result, nil
+	}
+
+	repoGit, err := p.m.repoBase()
+	if err != nil {
+		return nil, fmt.Errorf("finding repo base: %w", err)
+	}
+	cutoff := time.Now().Add(-cfg.RefreshDuration())
+	if size > 0 && p.needsBuild(size, cutoff) {
+		if err := p.fetchOrigin(repoGit); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("fetch origin: %v", err))
+		}
+	}
+	startPoint := p.m.startPoint()
+
+	var create, refresh, discard []string
+	err = p.locked(func() error {
+		if err := os.MkdirAll(p.dir, 0755); err != nil {
+			return fmt.Errorf("creating warm pool dir: %w", err)
+		}
+		ready, building, err := p.scan()
+		if err != nil {
+			return err
+		}
+		discard = p.orphans(ready, building)
+
+		// Trim the newest entries beyond size
+		for len(ready)+len(building) > size && len(ready) > 0 {
+			last := ready[len(ready)-1]
+			if err := os.Remove(p.marker(last.ID, "ready")); err != nil {
+				return err
+			}
+			discard = append(discard, last.ID)
+			ready = ready[:len(ready)-1]
+		}
+
+		for _, e := range ready {
+			if e.ReadyAt.Before(cutoff) {
+				if err := p.setBuilding(e.ID); err != nil {
+					return err
+				}
+				if err := os.Remove(p.marker(e.ID, "ready")); err != nil {
+					return err
+				}
+				refresh = append(refresh, e.ID)
+			}
+		}
+
+		base := time.Now().UnixNano()
+		for n := len(ready) + len(building); n < size; n++ {
+			id := strconv.FormatInt(base+int64(n), 36)
+			if err := p.setBuilding(id); err != nil {
+				return err
+			}
+			create = append(create, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for _, id := range discard {
+		p.remove(repoGit, id)
+		result.Removed++
+	}
+	for _, id := range create {
+		path := filepath.Join(p.dir, id)
+		if err := repoGit.WorktreeAddDetached(path, startPoint); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("creating %s: %v", id, err))
+			p.remove(repoGit, id)
+			continue
+		}
+		if err := p.ready(id, path); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Created++
+	}
+	for _, id := range refresh {
+		path := filepath.Join(p.dir, id)
+		if err := git.NewGit(path).ForceCheckoutDetached(startPoint); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("refreshing %s: %v", id, err))
+			p.remove(repoGit, id)
+			continue
+		}
+		if err := p.ready(id, path); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Refreshed++
+	}
+	return result, nil
+}
+
+// needsBuild reports whether a fill would create or refresh an entry.
+func (p *WarmPool) needsBuild(size int, cutoff time.Time) bool {
+	ready, building, err := p.scan()
+	if err != nil || len(ready)+len(building) < size {
+		return true
+	}
+	for _, e := range ready {
+		if e.ReadyAt.Before(cutoff) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchOrigin fetches origin unless a fill already did so within
+// warmFetchInterval.
+func (p *WarmPool) fetchOrigin(repoGit *git.Git) error {
+	stamp := filepath.Join(p.dir, ".fetched")
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < warmFetchInterval {
+		return nil
+	}
+	if err := repoGit.Fetch("origin"); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(stamp, nil, 0644) //nolint:gosec // G306: marker file
+}
+
+// Drain removes every entry that is not currently being built.
+func (p *WarmPool) Drain() (int, error) {
+	repoGit, err := p.m.repoBase()
+	if err != nil {
+		return 0, fmt.Errorf("finding repo base: %w", err)
+	}
+	var discard []string
+	err = p.locked(func() error {
+		ready, building, err := p.scan()
+		if err != nil {
+			return err
+		}
+		for _, e := range ready {
+			if err := os.Remove(p.marker(e.ID, "ready")); err != nil {
+				return err
+			}
+			discard = append(discard, e.ID)
+		}
+		discard = append(discard, p.orphans(ready, building)...)
+		return nil
+	})
+	for _, id := range discard {
+		p.remove(repoGit, id)
+	}
+	return len(discard), err
+}
+
+// Discard removes a claimed entry's worktree after a failed claim.
+func (p *WarmPool) Discard(entry *WarmEntry) {
+	if repoGit, err := p.m.repoBase(); err == nil {
+		p.remove(repoGit, entry.ID)
+	}
+}
+
+// ready runs the worktree setup (including setup hooks) and marks the entry
+// ready for claiming.
+func (p *WarmPool) ready(id, path string) error {
+	p.m.provisionWorktree(path, true)
+	commit, err := git.NewGit(path).Rev("HEAD")
+	if err != nil {
+		p.Discard(&WarmEntry{ID: id})
+		return fmt.Errorf("reading HEAD of %s: %w", id, err)
+	}
+	entry := WarmEntry{ID: id, Path: path, Commit: commit, ReadyAt: time.Now().UTC()}
+	return p.locked(func() error {
+		if err := util.AtomicWriteJSON(p.marker(id, "ready"), entry); err != nil {
+			return fmt.Errorf("marking %s ready: %w", id, err)
+		}
+		return os.Remove(p.marker(id, "building"))
+	})
+}
+
+// remove deletes an entry's worktree and markers.
+func (p *WarmPool) remove(repoGit *git.Git, id string) {
+	path := filepath.Join(p.dir, id)
+	if _, err := os.Stat(path); err == nil {
+		if err := repoGit.WorktreeRemove(path, true); err != nil {
+			_ = os.RemoveAll(path)
+		}
+	}
+	_ = repoGit.WorktreePrune()
+	_ = os.Remove(p.marker(id, "ready"))
+	_ = os.Remove(p.marker(id, "building"))
+}
+
+// orphans returns worktree dirs with no marker and builds that outlived
+// warmBuildTimeout, dropping the latter from building. Must be called under
+// the pool lock.
+func (p *WarmPool) orphans(ready []WarmEntry, building map[string]time.Time) []string {
+	known := make(map[string]bool)
+	for _, e := range ready {
+		known[e.ID] = true
+	}
+	var ids []string
+	for id, started := range building {
+		if time.Since(started) > warmBuildTimeout {
+			ids = append(ids, id)
+			_ = os.Remove(p.marker(id, "building"))
+			delete(building, id)
+		}
+		known[id] = true
+	}
+	entries, _ := os.ReadDir(p.dir)
+	for _, e := range entries {
+		if e.IsDir() && !known[e.Name()] {
+			ids = append(ids, e.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// scan reads the pool markers: ready entries oldest first, and the start
+// time of each build in progress.
+func (p *WarmPool) scan() ([]WarmEntry, map[string]time.Time, error) {
+	building := make(map[string]time.Time)
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, building, nil
+		}
+		return nil, nil, fmt.Errorf("reading warm pool: %w", err)
+	}
+
+	var ready []WarmEntry
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasSuffix(name, ".ready"):
+			data, err := os.ReadFile(filepath.Join(p.dir, name)) //nolint:gosec // G304: path is constructed internally
+			if err != nil {
+				continue
+			}
+			var entry WarmEntry
+			if json.Unmarshal(data, &entry) == nil && entry.ID != "" {
+				ready = append(ready, entry)
+			}
+		case strings.HasSuffix(name, ".building"):
+			if info, err := e.Info(); err == nil {
+				building[strings.TrimSuffix(name, ".building")] = info.ModTime()
+			}
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].ReadyAt.Before(ready[j].ReadyAt) })
+	return ready, building, nil
+}
+
+func (p *WarmPool) marker(id, state string) string {
+	return filepath.Join(p.dir, id+"."+state)
+}
+
+func (p *WarmPool) setBuilding(id string) error {
+	return os.WriteFile(p.marker(id, "building"), nil, 0644) //nolint:gosec // G306: marker file
+}
+
+// locked runs fn while holding the pool lock.
+func (p *WarmPool) locked(fn func() error) error {
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return fmt.Errorf("creating warm pool dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(p.dir, ".lock"))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking warm pool: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
+
+// startPoint returns origin/<default-branch> for the rig.
+func (m *Manager) startPoint() string {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	return fmt.Sprintf("origin/%s", defaultBranch)
+}
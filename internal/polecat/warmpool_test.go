@@ -0,0 +1,177 @@
+package polecat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestWarmPoolFillClaimDrain(t *testing.T) {
+	root := initTestRigRepo(t)
+
+	settings := config.NewRigSettings()
+	settings.WarmPool = &config.WarmPoolConfig{Size: 2}
+	if err := config.SaveRigSettings(config.RigSettingsPath(root), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &rig.Rig{Name: "rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+	pool := m.WarmPool()
+
+	result, err := pool.Fill()
+	if err != nil {
+		t.Fatalf("Fill: %v", err)
+	}
+	if result.Created != 2 || len(result.Errors) != 0 {
+		t.Fatalf("Fill() = %+v, want 2 created", result)
+	}
+	entries, err := pool.List()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("List() = %v, %v; want 2 entries", entries, err)
+	}
+	if m.Count() != 0 {
+		t.Errorf("Count() = %d, pool worktrees must not take polecat slots", m.Count())
+	}
+
+	// A refill of a full pool does nothing
+	if result, _ := pool.Fill(); result.Created != 0 || result.Removed != 0 {
+		t.Errorf("second Fill() = %+v, want no changes", result)
+	}
+
+	// Untracked files (installed dependencies) survive the claim
+	if err := os.WriteFile(filepath.Join(entries[0].Path, "deps.installed"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := m.AddWithOptions("Toast", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClonePath, "deps.installed")); err != nil {
+		t.Errorf("polecat did not get the warm worktree: %v", err)
+	}
+	branch, err := git.NewGit(p.ClonePath).CurrentBranch()
+	if err != nil || branch != p.Branch {
+		t.Errorf("CurrentBranch() = %q, %v; want %q", branch, err, p.Branch)
+	}
+	if _, err := os.Stat(entries[0].Path); !os.IsNotExist(err) {
+		t.Errorf("claimed entry still in pool: %v", err)
+	}
+	if left, _ := pool.List(); len(left) != 1 {
+		t.Errorf("pool has %d entries after claim, want 1", len(left))
+	}
+
+	removed, err := pool.Drain()
+	if err != nil || removed != 1 {
+		t.Errorf("Drain() = %d, %v; want 1", removed, err)
+	}
+	if left, _ := pool.List(); len(left) != 0 {
+		t.Errorf("pool has %d entries after drain", len(left))
+	}
+
+	// With the pool empty, spawning falls back to a fresh worktree
+	p2, err := m.AddWithOptions("Nux", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions without pool: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p2.ClonePath, "README.md")); err != nil {
+		t.Errorf("fresh worktree missing README.md: %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", filepath.Join(root, "mayor", "rig"), "worktree", "list").CombinedOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), ".warm") {
+		t.Errorf("drained pool worktrees still registered:\n%s", out)
+	}
+}
+
+// initTestRigRepo creates a rig at a temp dir whose mayor/rig clone has one
+// commit on main and an origin/main ref pointing at it.
+func initTestRigRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"init"}, {"checkout", "-b", "main"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	configureTestGitIdentity(t, mayorRig)
+	if err := os.WriteFile(filepath.Join(mayorRig, "README.md"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mayorGit := git.NewGit(mayorRig)
+	if err := mayorGit.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mayorGit.Commit("init"); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"remote", "add", "origin", mayorRig},
+		{"update-ref", "refs/remotes/origin/main", "HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return root
+}
+
+func TestWarmPoolFillFetchesOnlyToBuild(t *testing.T) {
+	root := initTestRigRepo(t)
+	settings := config.NewRigSettings()
+	settings.WarmPool = &config.WarmPoolConfig{Size: 1}
+	if err := config.SaveRigSettings(config.RigSettingsPath(root), settings); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(&rig.Rig{Name: "rig", Path: root}, git.NewGit(root), nil)
+	pool := m.WarmPool()
+	if result, err := pool.Fill(); err != nil || result.Created != 1 {
+		t.Fatalf("Fill() = %+v, %v; want 1 created", result, err)
+	}
+
+	// Break origin so that any fetch shows up as an error
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if out, err := exec.Command("git", "-C", mayorRig, "remote", "set-url", "origin", filepath.Join(root, "missing")).CombinedOutput(); err != nil {
+		t.Fatalf("set-url: %v\n%s", err, out)
+	}
+
+	// A full, fresh pool has nothing to build and does not fetch
+	if result, err := pool.Fill(); err != nil || len(result.Errors) != 0 {
+		t.Errorf("Fill() of a full pool = %+v, %v; want no fetch", result, err)
+	}
+
+	// A pool with work fetches, but at most once per interval
+	if _, err := pool.Claim(); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := pool.Fill(); err != nil || len(result.Errors) != 0 || result.Created != 1 {
+		t.Errorf("Fill() right after a fetch = %+v, %v; want 1 created without fetching", result, err)
+	}
+	if err := os.Remove(filepath.Join(root, "polecats", ".warm", ".fetched")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Claim(); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := pool.Fill(); len(result.Errors) == 0 || !strings.Contains(result.Errors[0], "fetch origin") {
+		t.Errorf("Fill() after the interval = %+v, want a fetch error", result)
+	}
+}
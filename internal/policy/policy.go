@@ -0,0 +1,286 @@
+// Package policy decides which tool calls agents may make.
+//
+// Policy files list rules that allow, deny, or ask about a tool call: for
+// shell commands, each command in a pipeline or list is matched on its own,
+// and for other tools the file or URL they act on. Rules can be limited to
+// roles. The rig's rules are consulted first, then the town's, then the
+// built-in defaults; the first matching rule decides. "gt policy check",
+// installed as each agent's pre-tool-use hook, applies the result.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Decision is what a rule says about a tool call.
+type Decision string
+
+const (
+	Allow Decision = "allow" // Run without asking
+	Ask   Decision = "ask"   // Ask the user first
+	Deny  Decision = "deny"  // Block the call
+)
+
+// strictness orders decisions so a command list gets its strictest one.
+func (d Decision) strictness() int {
+	switch d {
+	case Deny:
+		return 2
+	case Ask:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// FileName is the policy file in a town's or rig's settings directory.
+const FileName = "policy.json"
+
+// File is the on-disk form of a policy file.
+type File struct {
+	Type    string `json:"type"` // "policy"
+	Version int    `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Rule makes a decision about matching tool calls.
+type Rule struct {
+	Decision Decision `json:"decision"`
+
+	// Tool is the tool the rule applies to, compared without case ("Bash",
+	// "Write", "WebFetch"); "*" is any tool. Empty means Bash.
+	Tool string `json:"tool,omitempty"`
+
+	// Pattern is a regular expression searched for in each shell command
+	// (with leading variable assignments and sudo removed) or, for other
+	// tools, in the path or URL they act on.
+	Pattern string `json:"pattern"`
+
+	// Roles limits the rule to these roles (polecat, crew, witness,
+	// refinery, mayor, deacon). Empty applies it to every role.
+	Roles []string `json:"roles,omitempty"`
+
+	// Reason is shown to the agent when the rule denies or asks.
+	Reason string `json:"reason,omitempty"`
+
+	// Source is where the rule came from: "rig", "town" or "default".
+	Source string `json:"-"`
+
+	re *regexp.Regexp
+}
+
+// Policy is the ordered rules that apply to one role.
+type Policy struct {
+	Rules []*Rule
+}
+
+// Result is the outcome of checking a tool call.
+type Result struct {
+	Decision Decision
+	Rule     *Rule  // Rule that decided; nil when none matched
+	Subject  string // Command or target the rule matched
+}
+
+// Matched reports whether any rule matched. Calls no rule matches are left
+// to the runtime's own permission handling.
+func (r Result) Matched() bool {
+	return r.Rule != nil
+}
+
+// Path returns the policy file of a town or rig directory.
+func Path(dir string) string {
+	return filepath.Join(dir, "settings", FileName)
+}
+
+// Building blocks of the default force-push rule
+const (
+	gitPush   = `^git(\s+-C\s+\S+)?\s+push\b`
+	forceFlag = `(-f|--force|--force-with-lease)(=\S*)?`
+	mainRef   = `\+?(\S+:)?(refs/heads/)?(main|master)`
+)
+
+// defaultRules apply after the rig's and town's, so either can override them
+// with an allow rule.
+var defaultRules = []Rule{
+	{
+		Decision: Deny,
+		Pattern: gitPush + `(.*\s` + forceFlag + `(\s.*)?\s` + mainRef + `|.*\s` + mainRef + `\s(.*\s)?` + forceFlag +
+			`|.*\s\+(\S+:)?(refs/heads/)?(main|master))(\s|$)`,
+		Reason: "force-pushing main rewrites history every other agent has built on",
+	},
+	{
+		Decision: Deny,
+		Pattern:  `^rm\s+(.*\s)?(-\S*[rR]\S*|--recursive)\s(.*\s)?(/|~|\$HOME|\$\{HOME\})/?\*?(\s|$)`,
+		Reason:   "recursive delete of the root or home directory",
+	},
+}
+
+// Load returns the rules that apply to role: the rig's (if rigPath is set),
+// then the town's, then the defaults. Rules that are invalid are left out
+// and reported in the error; the rest are still returned.
+func Load(townRoot, rigPath, role string) (*Policy, error) {
+	var errs []error
+	p := &Policy{}
+	add := func(rules []Rule, source string) {
+		for i := range rules {
+			r := rules[i]
+			if !appliesTo(r.Roles, role) {
+				continue
+			}
+			if err := r.compile(); err != nil {
+				errs = append(errs, fmt.Errorf("%s rule %d: %w", source, i+1, err))
+				continue
+			}
+			r.Source = source
+			p.Rules = append(p.Rules, &r)
+		}
+	}
+
+	if rigPath != "" {
+		f, err := loadFile(Path(rigPath))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		add(f.Rules, "rig")
+	}
+	f, err := loadFile(Path(townRoot))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	add(f.Rules, "town")
+	add(defaultRules, "default")
+
+	return p, errors.Join(errs...)
+}
+
+// loadFile reads a policy file. A missing file has no rules.
+func loadFile(path string) (*File, error) {
+	f := &File{}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted town or rig root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return f, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return &File{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func (r *Rule) compile() error {
+	switch r.Decision {
+	case Allow, Ask, Deny:
+	default:
+		return fmt.Errorf("invalid decision %q (allow, ask or deny)", r.Decision)
+	}
+	if r.Pattern == "" {
+		return errors.New("no pattern")
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	r.re = re
+	return nil
+}
+
+func appliesTo(roles []string, role string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesTool(tool string) bool {
+	switch r.Tool {
+	case "*":
+		return true
+	case "":
+		return strings.EqualFold(tool, "Bash")
+	default:
+		return strings.EqualFold(tool, r.Tool)
+	}
+}
+
+// Check decides a tool call from its name and input, as runtimes pass them
+// to pre-tool-use hooks. A shell command list gets the strictest decision of
+// its commands, and is only allowed if every command in it is.
+func (p *Policy) Check(tool string, input map[string]any) Result {
+	var subjects []string
+	if strings.EqualFold(tool, "Bash") {
+		command, _ := input["command"].(string)
+		subjects = SplitCommand(command)
+	} else if target := Target(input); target != "" {
+		subjects = []string{target}
+	}
+
+	var result Result
+	unmatched := false
+	for _, subject := range subjects {
+		matched := false
+		for _, r := range p.Rules {
+			if !r.matchesTool(tool) || !r.re.MatchString(subject) {
+				continue
+			}
+			if result.Rule == nil || r.Decision.strictness() > result.Decision.strictness() {
+				result = Result{Decision: r.Decision, Rule: r, Subject: subject}
+			}
+			matched = true
+			break
+		}
+		unmatched = unmatched || !matched
+	}
+	// Allowing the list would skip the runtime's check of the commands no
+	// rule covers
+	if result.Decision == Allow && unmatched {
+		return Result{}
+	}
+	return result
+}
+
+// Target returns the path or URL a non-shell tool call acts on, from the
+// input field names Claude Code and OpenCode use.
+func Target(input map[string]any) string {
+	for _, key := range []string{"file_path", "filePath", "notebook_path", "path", "url"} {
+		if s, ok := input[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+var (
+	separatorRe  = regexp.MustCompile("&&|\\|\\||[;&|\n]|\\$\\(|[`()]")
+	spaceRe      = regexp.MustCompile(`\s+`)
+	assignmentRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*=\S*\s+)+`)
+)
+
+// SplitCommand splits a shell command line into its simple commands, with
+// whitespace collapsed and leading variable assignments and sudo removed.
+// Quoting is ignored, so a separator inside quotes splits too; that only
+// ever yields extra pieces to check.
+func SplitCommand(command string) []string {
+	var commands []string
+	for _, part := range separatorRe.Split(command, -1) {
+		part = strings.TrimSpace(spaceRe.ReplaceAllString(part, " "))
+		part = assignmentRe.ReplaceAllString(part, "")
+		part = strings.TrimPrefix(part, "sudo ")
+		if part != "" {
+			commands = append(commands, part)
+		}
+	}
+	return commands
+}
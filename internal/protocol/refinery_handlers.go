@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 )
 
@@ -85,7 +86,11 @@ func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetB
 // Called by the Refinery when a branch has conflicts.
 func (h *DefaultRefineryHandler) SendReworkRequest(polecat, branch, issue, targetBranch string, conflictFiles []string) error {
 	msg := NewReworkRequestMessage(h.Rig, polecat, branch, issue, targetBranch, conflictFiles)
-	return h.Router.Send(msg)
+	if err := h.Router.Send(msg); err != nil {
+		return err
+	}
+	_ = events.LogFeed(events.TypeReworkRequested, h.Rig+"/refinery", events.ReworkPayload(polecat, branch, issue))
+	return nil
 }
 
 // NotifyMergeOutcome is a convenience method that sends the appropriate message
@@ -0,0 +1,68 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// PlayOptions controls playback.
+type PlayOptions struct {
+	Speed   float64       // Playback speed multiplier; <= 0 means 1
+	MaxIdle time.Duration // Longest pause between events; 0 keeps real pauses
+
+	sleep func(time.Duration)
+}
+
+// Play writes a recording's output to w with its original timing.
+func Play(w io.Writer, path string, opts PlayOptions) error {
+	f, err := os.Open(path) //nolint:gosec // G304: recordings live under the town root
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	sleep := opts.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	if !scanner.Scan() {
+		return fmt.Errorf("%s: empty recording", path)
+	}
+	var last float64
+	for scanner.Scan() {
+		var event []json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			continue
+		}
+		var at float64
+		var kind, data string
+		if json.Unmarshal(event[0], &at) != nil || json.Unmarshal(event[1], &kind) != nil ||
+			json.Unmarshal(event[2], &data) != nil || kind != "o" {
+			continue
+		}
+
+		delay := time.Duration((at - last) / speed * float64(time.Second))
+		last = at
+		if opts.MaxIdle > 0 && delay > opts.MaxIdle {
+			delay = opts.MaxIdle
+		}
+		if delay > 0 {
+			sleep(delay)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,151 @@
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+)
+
+// Recorder writes pane output as asciicast v2, rotating to a new file when
+// the current one reaches MaxFileBytes (0 never rotates). Each part has its
+// own header, so any part plays on its own.
+type Recorder struct {
+	Dir          string
+	Session      string
+	Width        int
+	Height       int
+	Env          map[string]string
+	MaxFileBytes int64
+	MaxFiles     int // Recordings kept in Dir; the oldest go first (0 keeps all)
+
+	now     func() time.Time
+	file    *os.File
+	written int64
+	start   time.Time
+	part    int
+	pending []byte // Incomplete UTF-8 sequence held for the next write
+}
+
+// Record copies r into recordings until r is closed.
+func (rec *Recorder) Record(r io.Reader) error {
+	defer rec.Close()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if werr := rec.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Write records one chunk of output.
+func (rec *Recorder) Write(p []byte) error {
+	if rec.now == nil {
+		rec.now = time.Now
+	}
+	data := append(rec.pending, p...)
+	rec.pending = nil
+
+	// Don't split a multi-byte character across events
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	rec.pending = append([]byte(nil), data[cut:]...)
+	data = data[:cut]
+	if len(data) == 0 {
+		return nil
+	}
+
+	if rec.file == nil || (rec.MaxFileBytes > 0 && rec.written >= rec.MaxFileBytes) {
+		if err := rec.rotate(); err != nil {
+			return err
+		}
+	}
+
+	elapsed := rec.now().Sub(rec.start).Seconds()
+	event, err := json.Marshal([]interface{}{elapsed, "o", string(data)})
+	if err != nil {
+		return err
+	}
+	n, err := rec.file.Write(append(event, '\n'))
+	rec.written += int64(n)
+	return err
+}
+
+// rotate closes the current part and starts the next.
+func (rec *Recorder) rotate() error {
+	if err := rec.closeFile(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(rec.Dir, 0755); err != nil {
+		return fmt.Errorf("creating recording dir: %w", err)
+	}
+	rec.part++
+	rec.start = rec.now()
+	path := filepath.Join(rec.Dir, FileName(rec.Session, rec.start, rec.part))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("creating recording: %w", err)
+	}
+	header, err := json.Marshal(Header{
+		Version:   2,
+		Width:     rec.Width,
+		Height:    rec.Height,
+		Timestamp: rec.start.Unix(),
+		Title:     rec.Session,
+		Env:       rec.Env,
+	})
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	n, err := f.Write(append(header, '\n'))
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing recording header: %w", err)
+	}
+	rec.file = f
+	rec.written = int64(n)
+	return Trim(rec.Dir, rec.MaxFiles)
+}
+
+func (rec *Recorder) closeFile() error {
+	if rec.file == nil {
+		return nil
+	}
+	err := rec.file.Close()
+	rec.file = nil
+	return err
+}
+
+// Close flushes any held bytes and closes the current part.
+func (rec *Recorder) Close() error {
+	if len(rec.pending) > 0 && rec.file != nil {
+		pending := rec.pending
+		rec.pending = nil
+		// Flush as-is; JSON encoding replaces the invalid bytes
+		elapsed := rec.now().Sub(rec.start).Seconds()
+		if event, err := json.Marshal([]interface{}{elapsed, "o", string(pending)}); err == nil {
+			_, _ = rec.file.Write(append(event, '\n'))
+		}
+	}
+	return rec.closeFile()
+}
@@ -0,0 +1,214 @@
+// Package recording keeps asciicast v2 recordings of agent tmux panes so
+// what a polecat did can be audited after its session (and sandbox) is gone.
+//
+// tmux pipe-pane streams a session's output to gt record-pane, which writes
+// it with timings to <rig>/polecats/.recordings/<polecat>/. Files rotate by
+// size, each polecat keeps a bounded number of them, and the daemon prunes
+// recordings older than the KRC "session_recording" TTL.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirName is the directory under a rig's polecats/ holding recordings. The
+// leading dot keeps it out of polecat listings, like the warm pool.
+const DirName = ".recordings"
+
+// Ext is the recording file extension.
+const Ext = ".cast"
+
+// Header is the first line of an asciicast v2 file. The Gas Town session,
+// agent and bead are kept in Env so the file stays self-describing.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Env keys recorded in the header.
+const (
+	EnvSession = "GT_SESSION"
+	EnvAgent   = "GT_AGENT"
+	EnvBead    = "GT_BEAD"
+)
+
+// Recording describes one recording file.
+type Recording struct {
+	Path    string    `json:"path"`
+	Rig     string    `json:"rig"`
+	Polecat string    `json:"polecat"`
+	Session string    `json:"session"`
+	Agent   string    `json:"agent,omitempty"`
+	Bead    string    `json:"bead,omitempty"`
+	Started time.Time `json:"started"`
+	Size    int64     `json:"size"`
+}
+
+// Dir returns where a polecat's recordings are kept.
+func Dir(rigPath, polecat string) string {
+	return filepath.Join(rigPath, "polecats", DirName, polecat)
+}
+
+// FileName returns the name of a recording part started at t. Names sort
+// chronologically.
+func FileName(session string, t time.Time, part int) string {
+	return fmt.Sprintf("%s-%s-%d%s", t.UTC().Format("20060102T150405"), session, part, Ext)
+}
+
+// ReadHeader reads the header line of a recording.
+func ReadHeader(path string) (*Header, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: recordings live under the town root
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	var h Header
+	if err := json.Unmarshal(line, &h); err != nil {
+		return nil, fmt.Errorf("parsing header: %w", err)
+	}
+	if h.Version != 2 {
+		return nil, fmt.Errorf("unsupported asciicast version %d", h.Version)
+	}
+	return &h, nil
+}
+
+// List returns the town's recordings, oldest first.
+func List(townRoot string) ([]Recording, error) {
+	paths, err := filepath.Glob(filepath.Join(townRoot, "*", "polecats", DirName, "*", "*"+Ext))
+	if err != nil {
+		return nil, err
+	}
+	var recs []Recording
+	for _, path := range paths {
+		h, err := ReadHeader(path)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		polecatDir := filepath.Dir(path)
+		rec := Recording{
+			Path:    path,
+			Rig:     filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(polecatDir)))),
+			Polecat: filepath.Base(polecatDir),
+			Session: h.Env[EnvSession],
+			Agent:   h.Env[EnvAgent],
+			Bead:    h.Env[EnvBead],
+			Started: time.Unix(h.Timestamp, 0),
+			Size:    info.Size(),
+		}
+		if rec.Session == "" {
+			rec.Session = h.Title
+		}
+		recs = append(recs, rec)
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Started.Before(recs[j].Started) })
+	return recs, nil
+}
+
+// Find returns the recordings of a session, bead, or polecat (<rig>/<name>),
+// oldest first.
+func Find(townRoot, query string) ([]Recording, error) {
+	all, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var found []Recording
+	for _, rec := range all {
+		if rec.Session == query || rec.Bead == query || rec.Rig+"/"+rec.Polecat == query {
+			found = append(found, rec)
+		}
+	}
+	return found, nil
+}
+
+// Resolve validates that path (absolute, or relative to the town root) is a
+// recording inside the town and returns its absolute path.
+func Resolve(townRoot, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(townRoot, path)
+	}
+	path = filepath.Clean(path)
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the town", path)
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 5 || parts[1] != "polecats" || parts[2] != DirName || !strings.HasSuffix(path, Ext) {
+		return "", fmt.Errorf("%s is not a session recording", path)
+	}
+	return path, nil
+}
+
+// Trim deletes a polecat's oldest recordings so at most keep remain.
+func Trim(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	for len(paths) > keep {
+		if err := os.Remove(paths[0]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing old recording: %w", err)
+		}
+		paths = paths[1:]
+	}
+	return nil
+}
+
+// PruneResult reports what Prune removed.
+type PruneResult struct {
+	Removed int   `json:"removed"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Prune removes recordings not written to within ttl, and polecat
+// recording directories left empty.
+func Prune(townRoot string, ttl time.Duration, now time.Time) (*PruneResult, error) {
+	result := &PruneResult{}
+	dirs, err := filepath.Glob(filepath.Join(townRoot, "*", "polecats", DirName, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*"+Ext))
+		remaining := len(paths)
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil || now.Sub(info.ModTime()) <= ttl {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				return result, fmt.Errorf("removing %s: %w", path, err)
+			}
+			result.Removed++
+			result.Bytes += info.Size()
+			remaining--
+		}
+		if remaining == 0 {
+			_ = os.Remove(dir) // Only succeeds when empty
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,280 @@
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a now func advancing by step on each call.
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	t := start
+	return func() time.Time {
+		now := t
+		t = t.Add(step)
+		return now
+	}
+}
+
+func readEvents(t *testing.T, path string) (Header, []string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatalf("%s: no header", path)
+	}
+	var h Header
+	if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	var data []string
+	for scanner.Scan() {
+		var event []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("event %q: %v", scanner.Text(), err)
+		}
+		data = append(data, event[2].(string))
+	}
+	return h, data
+}
+
+func TestRecorderWritesAsciicast(t *testing.T) {
+	dir := t.TempDir()
+	rec := &Recorder{
+		Dir:     dir,
+		Session: "gt-gastown-Toast",
+		Width:   120,
+		Height:  40,
+		Env:     map[string]string{EnvSession: "gt-gastown-Toast", EnvBead: "gt-abc"},
+		now:     fakeClock(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), time.Second),
+	}
+	if err := rec.Record(strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if len(paths) != 1 {
+		t.Fatalf("got %d files, want 1", len(paths))
+	}
+	if got, want := filepath.Base(paths[0]), "20260102T030405-gt-gastown-Toast-1.cast"; got != want {
+		t.Errorf("file name = %q, want %q", got, want)
+	}
+	h, data := readEvents(t, paths[0])
+	if h.Version != 2 || h.Width != 120 || h.Height != 40 || h.Env[EnvBead] != "gt-abc" {
+		t.Errorf("header = %+v", h)
+	}
+	if len(data) != 1 || data[0] != "hello" {
+		t.Errorf("events = %q", data)
+	}
+}
+
+func TestRecorderKeepsMultiByteRunesWhole(t *testing.T) {
+	dir := t.TempDir()
+	rec := &Recorder{Dir: dir, Session: "s", now: fakeClock(time.Now(), time.Millisecond)}
+	check := []byte("✓")
+	if err := rec.Write(append([]byte("ok "), check[:1]...)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Write(check[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	_, data := readEvents(t, paths[0])
+	if strings.Join(data, "") != "ok ✓" || len(data) != 2 || data[1] != "✓" {
+		t.Errorf("events = %q, want [\"ok \" \"✓\"]", data)
+	}
+}
+
+func TestRecorderRotatesAndTrims(t *testing.T) {
+	dir := t.TempDir()
+	rec := &Recorder{
+		Dir:          dir,
+		Session:      "s",
+		MaxFileBytes: 200,
+		MaxFiles:     2,
+		now:          fakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Minute),
+	}
+	chunk := strings.Repeat("x", 150)
+	for i := 0; i < 5; i++ {
+		if err := rec.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if len(paths) != 2 {
+		t.Fatalf("got %d files, want 2 after trimming: %v", len(paths), paths)
+	}
+	if !strings.HasSuffix(paths[1], "-s-5.cast") {
+		t.Errorf("newest part = %s, want part 5", filepath.Base(paths[1]))
+	}
+	for _, path := range paths {
+		if _, err := ReadHeader(path); err != nil {
+			t.Errorf("%s: every part needs a header: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func writeRecording(t *testing.T, townRoot, rig, polecat, session, bead string, started time.Time) string {
+	t.Helper()
+	env := map[string]string{EnvSession: session}
+	if bead != "" {
+		env[EnvBead] = bead
+	}
+	rec := &Recorder{
+		Dir:     Dir(filepath.Join(townRoot, rig), polecat),
+		Session: session,
+		Env:     env,
+		now:     fakeClock(started, time.Second),
+	}
+	if err := rec.Write([]byte("out")); err != nil {
+		t.Fatal(err)
+	}
+	path := rec.file.Name()
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestListAndFind(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	writeRecording(t, town, "gastown", "Toast", "gt-gastown-Toast", "gt-abc", base.Add(time.Hour))
+	writeRecording(t, town, "gastown", "Toast", "gt-gastown-Toast", "gt-def", base)
+	writeRecording(t, town, "beads", "Nux", "bd-beads-Nux", "gt-abc", base.Add(2*time.Hour))
+
+	all, err := List(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("List = %d recordings, want 3", len(all))
+	}
+	if all[0].Bead != "gt-def" || all[0].Rig != "gastown" || all[0].Polecat != "Toast" {
+		t.Errorf("oldest = %+v", all[0])
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"gt-gastown-Toast", 2},
+		{"gt-abc", 2},
+		{"beads/Nux", 1},
+		{"gt-nope", 0},
+	}
+	for _, tt := range tests {
+		got, err := Find(town, tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("Find(%q) = %d recordings, want %d", tt.query, len(got), tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	town := t.TempDir()
+	good := "gastown/polecats/.recordings/Toast/20260101T000000-s-1.cast"
+
+	got, err := Resolve(town, good)
+	if err != nil {
+		t.Fatalf("Resolve(%q): %v", good, err)
+	}
+	if got != filepath.Join(town, good) {
+		t.Errorf("Resolve = %q", got)
+	}
+
+	for _, bad := range []string{
+		"../etc/passwd",
+		"gastown/polecats/.recordings/../../../../etc/passwd.cast",
+		"gastown/polecats/Toast/file.cast",
+		"gastown/polecats/.recordings/Toast/notes.txt",
+		"/etc/passwd",
+	} {
+		if _, err := Resolve(town, bad); err == nil {
+			t.Errorf("Resolve(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	old := writeRecording(t, town, "gastown", "Toast", "gt-gastown-Toast", "", now)
+	fresh := writeRecording(t, town, "gastown", "Nux", "gt-gastown-Nux", "", now)
+	if err := os.Chtimes(old, now.Add(-20*24*time.Hour), now.Add(-20*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Prune(town, 14*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Removed != 1 || result.Bytes == 0 {
+		t.Errorf("Prune = %+v, want 1 removed", result)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("expired recording still exists")
+	}
+	if _, err := os.Stat(filepath.Dir(old)); !os.IsNotExist(err) {
+		t.Error("empty polecat recording dir not removed")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("fresh recording removed: %v", err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r.cast")
+	cast := `{"version":2,"width":80,"height":24,"timestamp":0}
+[0.5,"o","a"]
+[1.5,"i","ignored"]
+[10.5,"o","b"]
+[11.0,"o","c"]
+`
+	if err := os.WriteFile(path, []byte(cast), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var slept []time.Duration
+	var out bytes.Buffer
+	err := Play(&out, path, PlayOptions{
+		Speed:   2,
+		MaxIdle: 2 * time.Second,
+		sleep:   func(d time.Duration) { slept = append(slept, d) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "abc" {
+		t.Errorf("output = %q, want %q", out.String(), "abc")
+	}
+	want := []time.Duration{250 * time.Millisecond, 2 * time.Second, 250 * time.Millisecond}
+	if len(slept) != len(want) {
+		t.Fatalf("slept %v, want %v", slept, want)
+	}
+	for i := range want {
+		if slept[i] != want[i] {
+			t.Errorf("sleep %d = %v, want %v", i, slept[i], want[i])
+		}
+	}
+}
@@ -11,10 +11,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
@@ -147,7 +150,14 @@ func (e *Engineer) SetOutput(w io.Writer) {
 }
 
 // LoadConfig loads merge queue configuration from the rig's config.json.
+// merge_queue.max_concurrent may also come from the rig settings
+// (settings/config.json); config.json wins when both set it.
 func (e *Engineer) LoadConfig() error {
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path)); err == nil &&
+		settings.MergeQueue != nil && settings.MergeQueue.MaxConcurrent > 0 {
+		e.config.MaxConcurrent = settings.MergeQueue.MaxConcurrent
+	}
+
 	configPath := filepath.Join(e.rig.Path, "config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -243,7 +253,10 @@ type ProcessResult struct {
 	TestsFailed bool
 }
 
-// ProcessMR processes a single merge request from a beads issue.
+// ProcessMR processes a single merge request from a beads issue: it merges
+// the MR's branch onto the latest origin/<target>, tests the merged result
+// and pushes it to <target> (see doMerge). The clone is left on the target
+// branch, fast-forwarded to origin/<target>, whether or not the merge landed.
 func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult {
 	// Parse MR fields from description
 	mrFields := beads.ParseMRFields(mr)
@@ -260,15 +273,47 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
-	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
+	defer e.returnToTarget(mrFields.Target)
+	return e.doMerge(ctx, e.git, mrFields.Branch, mrFields.Target, mrFields.SourceIssue, nil)
 }
 
-// doMerge performs the actual git merge operation.
-// This is the core merge logic shared by ProcessMR and ProcessMRFromQueue.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+// returnToTarget puts the clone back on the target branch between MRs, as
+// doMerge leaves it detached. The local branch is first fast-forwarded to
+// origin/<target>, which includes anything doMerge pushed, so it doesn't
+// fall behind the merges landed from it.
+func (e *Engineer) returnToTarget(target string) {
+	remoteTarget := "origin/" + target
+	if ok, err := e.git.IsAncestor(target, remoteTarget); err == nil && ok {
+		_ = e.git.ResetBranch(target, remoteTarget)
+	}
+	_ = e.git.Checkout(target)
+}
+
+// maxPushAttempts bounds how often doMerge rebases and re-tests after its
+// push is rejected because the target moved.
+const maxPushAttempts = 3
+
+// doMerge performs the actual git merge operation in the clone or worktree
+// g: it squash-merges branch onto the latest origin/target (leaving g
+// detached there), runs the tests against the result, and pushes it to
+// target. If the push is rejected because the target moved, it rebases onto
+// the new target and re-runs the tests before pushing again. pushMu, when
+// set, is held around each push so that pool workers land one at a time.
+// This is the core merge logic shared by ProcessMR, ProcessMRInfo and the
+// merge pool's workers.
+func (e *Engineer) doMerge(ctx context.Context, g *git.Git, branch, target, sourceIssue string, pushMu sync.Locker) ProcessResult {
+	if pushMu == nil {
+		pushMu = &sync.Mutex{}
+	}
+	remoteTarget := "origin/" + target
+
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
+	if err := g.Fetch("origin"); err != nil {
+		// Fetch might fail transiently; merge against what we have
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin: %v (continuing)\n", err)
+	}
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
-	exists, err := e.git.BranchExists(branch)
+	exists, err := g.BranchExists(branch)
 	if err != nil {
 		return ProcessResult{
 			Success: false,
@@ -282,24 +327,10 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 2: Checkout the target branch
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
-	if err := e.git.Checkout(target); err != nil {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("failed to checkout target %s: %v", target, err),
-		}
-	}
-
-	// Make sure target is up to date with origin
-	if err := e.git.Pull("origin", target); err != nil {
-		// Pull might fail if nothing to pull, that's ok
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
-	}
-
-	// Step 3: Check for merge conflicts (using local branch)
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
-	conflicts, err := e.git.CheckConflicts(branch, target)
+	// Step 2: Check for merge conflicts (using local branch). CheckConflicts
+	// leaves g detached at the remote target, which the merge builds on.
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts against %s...\n", remoteTarget)
+	conflicts, err := g.CheckConflicts(branch, remoteTarget)
 	if err != nil {
 		return ProcessResult{
 			Success:  false,
@@ -315,24 +346,10 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 4: Run tests if configured
-	if e.config.RunTests && e.config.TestCommand != "" {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
-			}
-		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
-	}
-
-	// Step 5: Perform the actual merge using squash merge
+	// Step 3: Perform the actual merge using squash merge
 	// Get the original commit message from the polecat branch to preserve the
 	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
+	originalMsg, err := g.GetBranchCommitMessage(branch)
 	if err != nil {
 		// Fallback to a descriptive message if we can't get the original
 		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
@@ -342,12 +359,12 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
 	}
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
+	if err := g.MergeSquash(branch, originalMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
-		conflicts, conflictErr := e.git.GetConflictingFiles()
+		conflicts, conflictErr := g.GetConflictingFiles()
 		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+			_ = g.AbortMerge()
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
@@ -360,21 +377,50 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 6: Get the merge commit SHA
-	mergeCommit, err := e.git.Rev("HEAD")
-	if err != nil {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
+	// Step 4: Test and push, rebasing and re-testing whenever the target
+	// moved under us
+	for attempt := 1; ; attempt++ {
+		if result := e.testMerged(ctx, g); !result.Success {
+			return result
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
+		pushMu.Lock()
+		pushErr := g.Push("origin", "HEAD:"+target, false)
+		pushMu.Unlock()
+		if pushErr == nil {
+			break
+		}
+		if attempt == maxPushAttempts {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to push to origin: %v", pushErr),
+			}
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Push rejected, rebasing onto %s: %v\n", remoteTarget, pushErr)
+		if err := g.FetchBranch("origin", target); err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to fetch %s: %v", remoteTarget, err),
+			}
+		}
+		if err := g.Rebase(remoteTarget); err != nil {
+			_ = g.AbortRebase()
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("rebase onto %s failed: %v", remoteTarget, err),
+			}
 		}
 	}
 
-	// Step 7: Push to origin
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
-	if err := e.git.Push("origin", target, false); err != nil {
+	// Step 5: Get the merge commit SHA
+	mergeCommit, err := g.Rev("HEAD")
+	if err != nil {
 		return ProcessResult{
 			Success: false,
-			Error:   fmt.Sprintf("failed to push to origin: %v", err),
+			Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
 		}
 	}
 
@@ -385,8 +431,26 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 }
 
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
+// testMerged runs the configured tests, if any, in g's working tree.
+func (e *Engineer) testMerged(ctx context.Context, g *git.Git) ProcessResult {
+	if !e.config.RunTests || e.config.TestCommand == "" {
+		return ProcessResult{Success: true}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
+	result := e.runTests(ctx, g.WorkDir())
+	if !result.Success {
+		return ProcessResult{
+			Success:     false,
+			TestsFailed: true,
+			Error:       result.Error,
+		}
+	}
+	_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+	return result
+}
+
+// runTests runs the configured test command in dir and returns the result.
+func (e *Engineer) runTests(ctx context.Context, dir string) ProcessResult {
 	if e.config.TestCommand == "" {
 		return ProcessResult{Success: true}
 	}
@@ -406,7 +470,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		// Note: TestCommand comes from rig's config.json (trusted infrastructure config),
 		// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
 		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
+		cmd.Dir = dir
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
@@ -502,6 +566,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 
 	// 5. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mrFields.Worker, mrFields.Branch, ""))
 }
 
 // handleFailure handles a failed merge request.
@@ -515,6 +580,9 @@ func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
 
 	// Log the failure
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
+	if mrFields := beads.ParseMRFields(mr); mrFields != nil {
+		_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mrFields.Worker, mrFields.Branch, result.Error))
+	}
 }
 
 // ProcessMRInfo processes a merge request from MRInfo.
@@ -527,11 +595,14 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	defer e.returnToTarget(mr.Target)
+	return e.doMerge(ctx, e.git, mr.Branch, mr.Target, mr.SourceIssue, nil)
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+
 	// Release merge slot if this was a conflict resolution
 	// The slot is held while conflict resolution is in progress
 	holder := e.rig.Name + "/refinery"
@@ -633,6 +704,7 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, failureType))
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
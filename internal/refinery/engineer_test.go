@@ -1,12 +1,18 @@
 package refinery
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -215,3 +221,120 @@ func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 		t.Error("expected DeleteMergedBranches to be true by default")
 	}
 }
+
+func TestEngineer_LoadConfig_MaxConcurrentFromSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	settings := config.NewRigSettings()
+	settings.MergeQueue = config.DefaultMergeQueueConfig()
+	settings.MergeQueue.MaxConcurrent = 4
+	if err := config.SaveRigSettings(config.RigSettingsPath(tmpDir), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+	if e.config.MaxConcurrent != 4 {
+		t.Errorf("expected MaxConcurrent 4 from rig settings, got %d", e.config.MaxConcurrent)
+	}
+	if got := NewMergePool(e).Size(); got != 4 {
+		t.Errorf("NewMergePool().Size() = %d, want 4", got)
+	}
+}
+
+// TestEngineer_ProcessMR merges an MR whose target moved on origin since the
+// refinery's clone last pulled, and checks the merge is built on
+// origin/main, tested as merged and pushed, leaving the clone on main. A
+// failing test run must push nothing.
+func TestEngineer_ProcessMR(t *testing.T) {
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+
+	repo := filepath.Join(root, "refinery", "rig")
+	runGit(t, root, "clone", origin, repo)
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "initial")
+	runGit(t, repo, "push", "-u", "origin", "main")
+
+	for _, name := range []string{"alpha", "beta"} {
+		runGit(t, repo, "checkout", "-b", "polecat/"+name, "main")
+		if err := os.WriteFile(filepath.Join(repo, name+".txt"), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, repo, "add", ".")
+		runGit(t, repo, "commit", "-m", "feat: add "+name)
+	}
+	runGit(t, repo, "checkout", "main")
+
+	// main moves on origin without the refinery's clone pulling it
+	other := filepath.Join(root, "other")
+	runGit(t, root, "clone", origin, other)
+	runGit(t, other, "config", "user.email", "test@test.com")
+	runGit(t, other, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(other, "other.txt"), []byte("other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, other, "add", ".")
+	runGit(t, other, "commit", "-m", "feat: add other")
+	runGit(t, other, "push", "origin", "main")
+
+	cfg := DefaultMergeQueueConfig()
+	cfg.RunTests = true
+	cfg.RetryFlakyTests = 1
+	// Passes only on top of origin's main, and never with beta merged
+	cfg.TestCommand = "test -e other.txt && test ! -e beta.txt"
+	var out bytes.Buffer
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "testrig", Path: root},
+		git:     git.NewGit(repo),
+		config:  cfg,
+		workDir: repo,
+		output:  &out,
+	}
+	mr := func(branch string) *beads.Issue {
+		return &beads.Issue{ID: "mr-" + branch, Description: "branch: " + branch + "\ntarget: main\nworker: " + branch}
+	}
+
+	result := e.ProcessMR(context.Background(), mr("polecat/alpha"))
+	if !result.Success {
+		t.Fatalf("ProcessMR(alpha) = %+v\n%s", result, out.String())
+	}
+	runGit(t, repo, "fetch", "origin")
+	if head := runGit(t, repo, "rev-parse", "origin/main"); head != result.MergeCommit {
+		t.Errorf("origin/main = %s, want the merge commit %s", head, result.MergeCommit)
+	}
+	files := runGit(t, repo, "ls-tree", "--name-only", "origin/main")
+	for _, want := range []string{"alpha.txt", "other.txt"} {
+		if !strings.Contains(files, want) {
+			t.Errorf("origin/main missing %s; tree:\n%s", want, files)
+		}
+	}
+	if branch := runGit(t, repo, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("clone left on %q, want main", branch)
+	}
+	if local := runGit(t, repo, "rev-parse", "main"); local != result.MergeCommit {
+		t.Errorf("local main = %s, want it fast-forwarded to the merge commit %s", local, result.MergeCommit)
+	}
+
+	before := runGit(t, repo, "rev-parse", "origin/main")
+	result = e.ProcessMR(context.Background(), mr("polecat/beta"))
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("ProcessMR(beta) = %+v, want TestsFailed\n%s", result, out.String())
+	}
+	runGit(t, repo, "fetch", "origin")
+	if after := runGit(t, repo, "rev-parse", "origin/main"); after != before {
+		t.Errorf("origin/main moved to %s although beta's tests failed", after)
+	}
+	if branch := runGit(t, repo, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("clone left on %q after a failed merge, want main", branch)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
 )
 
 // GitOperations defines the interface for git operations.
@@ -234,10 +236,15 @@ func (g *RealGitOps) Fetch(remote string) error {
 }
 
 func (g *RealGitOps) Push(remote, branch string, force bool) error {
+	op := git.PushUpdate
 	args := []string{"push", remote, branch}
 	if force {
+		op = git.PushForce
 		args = []string{"push", "--force-with-lease", remote, branch}
 	}
+	if err := git.NewGit(g.WorkDir).CheckPush(branch, op); err != nil {
+		return err
+	}
 	_, err := g.run(args...)
 	return err
 }
@@ -0,0 +1,246 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// MergePool processes independent MRs in parallel, up to MaxConcurrent at a
+// time. Each worker merges and tests in its own detached worktree under
+// refinery/workers/, so a slow test suite no longer serializes the queue.
+// Pushes to the target branch (and the bead bookkeeping that follows) are
+// serialized: a worker whose push is rejected because another worker landed
+// first rebases onto the new target, re-runs the tests and pushes again.
+type MergePool struct {
+	e    *Engineer
+	size int
+
+	pushMu sync.Mutex // serializes pushes and post-merge bookkeeping
+	outMu  sync.Mutex // serializes writes to e.output
+}
+
+// NewMergePool creates a pool sized by the engineer's MaxConcurrent setting.
+func NewMergePool(e *Engineer) *MergePool {
+	size := e.config.MaxConcurrent
+	if size < 1 {
+		size = 1
+	}
+	return &MergePool{e: e, size: size}
+}
+
+// Size returns the number of workers in the pool.
+func (p *MergePool) Size() int {
+	return p.size
+}
+
+// WorktreePath returns the merge worktree for worker i.
+func (p *MergePool) WorktreePath(i int) string {
+	return filepath.Join(p.e.rig.Path, "refinery", "workers", fmt.Sprintf("worker-%d", i))
+}
+
+// WorkerID returns the claim identity for worker i.
+func (p *MergePool) WorkerID(i int) string {
+	return fmt.Sprintf("%s/refinery/worker-%d", p.e.rig.Name, i)
+}
+
+// ChangedFiles returns the files an MR's branch touches relative to its target.
+func (p *MergePool) ChangedFiles(mr *MRInfo) ([]string, error) {
+	return p.e.git.ChangedFiles("origin/"+mr.Target, mr.Branch)
+}
+
+// SelectIndependent picks up to max MRs, in queue order, that can be merged
+// in parallel: each selected MR either targets a different branch than every
+// other selected MR, or touches no file that another selected MR on the same
+// target touches. MRs whose file set is unknown (nil entry in files) are only
+// selected when nothing else shares their target.
+func SelectIndependent(mrs []*MRInfo, files map[string][]string, max int) []*MRInfo {
+	var selected []*MRInfo
+	claimed := make(map[string]map[string]bool) // target -> files claimed by selected MRs
+	exclusive := make(map[string]bool)          // targets held by an MR with unknown files
+
+	for _, mr := range mrs {
+		if len(selected) >= max {
+			break
+		}
+		if exclusive[mr.Target] {
+			continue
+		}
+		changed, known := files[mr.ID]
+		if !known || changed == nil {
+			if _, busy := claimed[mr.Target]; busy {
+				continue
+			}
+			exclusive[mr.Target] = true
+			claimed[mr.Target] = map[string]bool{}
+			selected = append(selected, mr)
+			continue
+		}
+
+		targetFiles := claimed[mr.Target]
+		overlap := false
+		for _, f := range changed {
+			if targetFiles[f] {
+				overlap = true
+				break
+			}
+		}
+		if overlap {
+			continue
+		}
+		if targetFiles == nil {
+			targetFiles = make(map[string]bool)
+			claimed[mr.Target] = targetFiles
+		}
+		for _, f := range changed {
+			targetFiles[f] = true
+		}
+		selected = append(selected, mr)
+	}
+	return selected
+}
+
+// BatchResult is the outcome of one MR processed by the pool.
+type BatchResult struct {
+	MR     *MRInfo
+	Worker string
+	Result ProcessResult
+}
+
+// ProcessReady selects a batch of independent MRs from ready and processes
+// it. MRs left out of the batch stay in the queue for the next call.
+func (p *MergePool) ProcessReady(ctx context.Context, ready []*MRInfo) []BatchResult {
+	files := make(map[string][]string, len(ready))
+	for _, mr := range ready {
+		changed, err := p.ChangedFiles(mr)
+		if err != nil {
+			p.logf("Warning: could not list changed files for %s: %v\n", mr.ID, err)
+			continue
+		}
+		if changed == nil {
+			changed = []string{}
+		}
+		files[mr.ID] = changed
+	}
+	return p.ProcessBatch(ctx, SelectIndependent(ready, files, p.size))
+}
+
+// ProcessBatch merges the given MRs concurrently, one per worker. Each MR is
+// claimed with ClaimMR before work starts; failed MRs are released with
+// ReleaseMR so they return to the queue.
+func (p *MergePool) ProcessBatch(ctx context.Context, mrs []*MRInfo) []BatchResult {
+	results := make([]BatchResult, len(mrs))
+	var wg sync.WaitGroup
+
+	for i, mr := range mrs {
+		if i >= p.size {
+			break
+		}
+		workerID := p.WorkerID(i)
+		results[i] = BatchResult{MR: mr, Worker: workerID}
+
+		if err := p.e.ClaimMR(mr.ID, workerID); err != nil {
+			results[i].Result = ProcessResult{Error: fmt.Sprintf("claim failed: %v", err)}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, mr *MRInfo) {
+			defer wg.Done()
+			results[i].Result = p.process(ctx, i, mr)
+		}(i, mr)
+	}
+	wg.Wait()
+	return results[:min(len(mrs), p.size)]
+}
+
+// process runs one MR on worker i and records the outcome on its beads.
+func (p *MergePool) process(ctx context.Context, i int, mr *MRInfo) ProcessResult {
+	w := p.worker(i)
+	var result ProcessResult
+	if path, err := p.ensureWorktree(i); err != nil {
+		result = ProcessResult{Error: fmt.Sprintf("preparing worktree: %v", err)}
+	} else {
+		w.git = git.NewGit(path)
+		w.workDir = path
+		_, _ = fmt.Fprintf(w.output, "[Engineer] Processing %s: %s → %s\n", mr.ID, mr.Branch, mr.Target)
+		result = w.doMerge(ctx, w.git, mr.Branch, mr.Target, mr.SourceIssue, &p.pushMu)
+	}
+
+	// Bead updates and branch cleanup touch shared state; do them one at a time
+	p.pushMu.Lock()
+	defer p.pushMu.Unlock()
+	if result.Success {
+		w.HandleMRInfoSuccess(mr, result)
+		return result
+	}
+	w.HandleMRInfoFailure(mr, result)
+	if err := w.ReleaseMR(mr.ID); err != nil {
+		_, _ = fmt.Fprintf(w.output, "[Engineer] Warning: failed to release %s: %v\n", mr.ID, err)
+	}
+	return result
+}
+
+// worker returns a copy of the engineer whose output is tagged with the
+// worker number.
+func (p *MergePool) worker(i int) *Engineer {
+	w := *p.e
+	w.output = &prefixWriter{prefix: fmt.Sprintf("[worker-%d] ", i), w: p.e.output, mu: &p.outMu}
+	return &w
+}
+
+// ensureWorktree returns worker i's merge worktree, creating it on first use.
+func (p *MergePool) ensureWorktree(i int) (string, error) {
+	path := p.WorktreePath(i)
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	p.pushMu.Lock() // worktree add takes the shared repo's lock
+	defer p.pushMu.Unlock()
+	if err := p.e.git.WorktreeAddDetached(path, "HEAD"); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// logf writes a pool-level message to the engineer's output.
+func (p *MergePool) logf(format string, args ...interface{}) {
+	p.outMu.Lock()
+	defer p.outMu.Unlock()
+	_, _ = fmt.Fprintf(p.e.output, "[Engineer] "+format, args...)
+}
+
+// prefixWriter tags each line from a worker and keeps concurrent workers
+// from interleaving partial lines.
+type prefixWriter struct {
+	prefix string
+	w      io.Writer
+	mu     *sync.Mutex
+}
+
+func (pw *prefixWriter) Write(b []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(string(b), "\n") {
+		if line == "" {
+			continue
+		}
+		buf.WriteString(pw.prefix)
+		buf.WriteString(line)
+	}
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
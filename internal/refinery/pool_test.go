@@ -0,0 +1,222 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestSelectIndependent(t *testing.T) {
+	mrs := []*MRInfo{
+		{ID: "mr-1", Target: "main"},
+		{ID: "mr-2", Target: "main"},    // overlaps mr-1
+		{ID: "mr-3", Target: "main"},    // disjoint from mr-1
+		{ID: "mr-4", Target: "release"}, // same file as mr-1, other target
+		{ID: "mr-5", Target: "main"},    // disjoint, but over max
+	}
+	files := map[string][]string{
+		"mr-1": {"a.go", "b.go"},
+		"mr-2": {"b.go"},
+		"mr-3": {"c.go"},
+		"mr-4": {"a.go"},
+		"mr-5": {"d.go"},
+	}
+
+	got := SelectIndependent(mrs, files, 3)
+	var ids []string
+	for _, mr := range got {
+		ids = append(ids, mr.ID)
+	}
+	if strings.Join(ids, ",") != "mr-1,mr-3,mr-4" {
+		t.Errorf("SelectIndependent() = %v, want [mr-1 mr-3 mr-4]", ids)
+	}
+
+	// An MR with unknown files gets its target to itself
+	delete(files, "mr-1")
+	got = SelectIndependent(mrs, files, 5)
+	ids = nil
+	for _, mr := range got {
+		ids = append(ids, mr.ID)
+	}
+	if strings.Join(ids, ",") != "mr-1,mr-4" {
+		t.Errorf("SelectIndependent() with unknown files = %v, want [mr-1 mr-4]", ids)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	w := &prefixWriter{prefix: "[worker-1] ", w: &buf, mu: &mu}
+	_, _ = w.Write([]byte("one\ntwo\n"))
+	if got := buf.String(); got != "[worker-1] one\n[worker-1] two\n" {
+		t.Errorf("prefixWriter output = %q", got)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// TestMergePoolParallelMerge merges two independent branches concurrently
+// and checks both land on origin/main despite racing pushes.
+func TestMergePoolParallelMerge(t *testing.T) {
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+
+	repo := filepath.Join(root, "refinery", "rig")
+	runGit(t, root, "clone", origin, repo)
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "initial")
+	runGit(t, repo, "push", "-u", "origin", "main")
+
+	for _, name := range []string{"alpha", "beta"} {
+		runGit(t, repo, "checkout", "-b", "polecat/"+name, "main")
+		if err := os.WriteFile(filepath.Join(repo, name+".txt"), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, repo, "add", ".")
+		runGit(t, repo, "commit", "-m", "feat: add "+name)
+	}
+	runGit(t, repo, "checkout", "main")
+
+	cfg := DefaultMergeQueueConfig()
+	cfg.MaxConcurrent = 2
+	cfg.RunTests = false
+	var out bytes.Buffer
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "testrig", Path: root},
+		git:     git.NewGit(repo),
+		config:  cfg,
+		workDir: repo,
+		output:  &out,
+	}
+	pool := NewMergePool(e)
+
+	mrs := []*MRInfo{
+		{ID: "mr-alpha", Branch: "polecat/alpha", Target: "main"},
+		{ID: "mr-beta", Branch: "polecat/beta", Target: "main"},
+	}
+	results := make([]ProcessResult, len(mrs))
+	var wg sync.WaitGroup
+	for i, mr := range mrs {
+		path, err := pool.ensureWorktree(i)
+		if err != nil {
+			t.Fatalf("ensureWorktree(%d): %v", i, err)
+		}
+		w := pool.worker(i)
+		w.git = git.NewGit(path)
+		w.workDir = path
+
+		wg.Add(1)
+		go func(i int, mr *MRInfo) {
+			defer wg.Done()
+			results[i] = w.doMerge(context.Background(), w.git, mr.Branch, mr.Target, mr.SourceIssue, &pool.pushMu)
+		}(i, mr)
+	}
+	wg.Wait()
+
+	for i, r := range results {
+		if !r.Success {
+			t.Errorf("merge %s failed: %s\n%s", mrs[i].ID, r.Error, out.String())
+		}
+	}
+
+	runGit(t, repo, "fetch", "origin")
+	files := runGit(t, repo, "ls-tree", "--name-only", "origin/main")
+	for _, want := range []string{"alpha.txt", "beta.txt"} {
+		if !strings.Contains(files, want) {
+			t.Errorf("origin/main missing %s; tree:\n%s", want, files)
+		}
+	}
+}
+
+// TestDoMergeRetestsAfterRebase lands a commit that breaks the tests while
+// an MR is being tested, and checks the rebased MR is re-tested and not
+// pushed.
+func TestDoMergeRetestsAfterRebase(t *testing.T) {
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+
+	repo := filepath.Join(root, "refinery", "rig")
+	runGit(t, root, "clone", origin, repo)
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "initial")
+	runGit(t, repo, "push", "-u", "origin", "main")
+
+	runGit(t, repo, "checkout", "-b", "polecat/alpha", "main")
+	if err := os.WriteFile(filepath.Join(repo, "alpha.txt"), []byte("alpha\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "feat: add alpha")
+	runGit(t, repo, "checkout", "main")
+
+	// Another clone holds a commit that breaks the tests, pushed by the
+	// first test run so that it lands between testing and pushing
+	other := filepath.Join(root, "other")
+	runGit(t, root, "clone", origin, other)
+	runGit(t, other, "config", "user.email", "test@test.com")
+	runGit(t, other, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(other, "broken.txt"), []byte("broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, other, "add", ".")
+	runGit(t, other, "commit", "-m", "break the tests")
+
+	marker := filepath.Join(root, "landed")
+	cfg := DefaultMergeQueueConfig()
+	cfg.RunTests = true
+	cfg.RetryFlakyTests = 1
+	cfg.TestCommand = "test ! -e broken.txt && { test -e '" + marker + "' || { touch '" + marker +
+		"' && git -C '" + other + "' push -q origin HEAD:main; }; }"
+	var out bytes.Buffer
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "testrig", Path: root},
+		git:     git.NewGit(repo),
+		config:  cfg,
+		workDir: repo,
+		output:  &out,
+	}
+
+	result := e.doMerge(context.Background(), e.git, "polecat/alpha", "main", "", nil)
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("doMerge() = %+v, want TestsFailed after rebase\n%s", result, out.String())
+	}
+	if !strings.Contains(out.String(), "Push rejected") {
+		t.Errorf("expected a rejected push before the re-test:\n%s", out.String())
+	}
+
+	runGit(t, repo, "fetch", "origin")
+	if files := runGit(t, repo, "ls-tree", "--name-only", "origin/main"); strings.Contains(files, "alpha.txt") {
+		t.Errorf("origin/main has alpha.txt although its rebased tests failed; tree:\n%s", files)
+	}
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"github.com/steveyegge/gastown/internal/agentdriver"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+
+	// Each runtime's agent driver registers itself on import.
+	_ "github.com/steveyegge/gastown/internal/agentdriver/claude"
+	_ "github.com/steveyegge/gastown/internal/agentdriver/codex"
+	_ "github.com/steveyegge/gastown/internal/agentdriver/gemini"
+	_ "github.com/steveyegge/gastown/internal/agentdriver/opencode"
+)
+
+// Driver returns the agent driver for a resolved runtime config.
+func Driver(rc *config.RuntimeConfig) agentdriver.AgentDriver {
+	return agentdriver.For(rc)
+}
+
+// DriverForAgent returns the agent driver for an agent name (GT_AGENT).
+func DriverForAgent(name string) agentdriver.AgentDriver {
+	return agentdriver.ForAgent(name)
+}
+
+// DriverForSession returns the agent driver for the agent running in a
+// tmux session, for callers that don't have its runtime config.
+func DriverForSession(t *tmux.Tmux, session string) agentdriver.AgentDriver {
+	return agentdriver.ForSession(t, session)
+}
+
+// DriverWithOverride returns the driver for an agent override (--agent),
+// or for rc when there is none.
+func DriverWithOverride(rc *config.RuntimeConfig, agentOverride string) agentdriver.AgentDriver {
+	if agentOverride != "" {
+		return agentdriver.ForAgent(agentOverride)
+	}
+	return agentdriver.For(rc)
+}
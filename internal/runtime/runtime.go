@@ -6,9 +6,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/agentdriver"
 	"github.com/steveyegge/gastown/internal/config"
-	"github.com/steveyegge/gastown/internal/opencode"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -22,14 +21,13 @@ func EnsureSettingsForRole(workDir, role string, rc *config.RuntimeConfig) error
 		return nil
 	}
 
-	switch rc.Hooks.Provider {
-	case "claude":
-		return claude.EnsureSettingsForRoleAt(workDir, role, rc.Hooks.Dir, rc.Hooks.SettingsFile)
-	case "opencode":
-		return opencode.EnsurePluginAt(workDir, rc.Hooks.Dir, rc.Hooks.SettingsFile)
-	default:
+	// The hooks provider can differ from the runtime (a custom agent may
+	// use Claude's hooks), so its driver does the setup.
+	driver, ok := agentdriver.Lookup(rc.Hooks.Provider, rc)
+	if !ok {
 		return nil
 	}
+	return driver.Setup(workDir, role)
 }
 
 // SessionIDFromEnv returns the runtime session ID, if present.
@@ -73,11 +71,12 @@ func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 	return []string{command}
 }
 
-// RunStartupFallback sends the startup fallback commands via tmux.
+// RunStartupFallback sends the startup fallback commands to the runtime.
 func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.RuntimeConfig) error {
 	commands := StartupFallbackCommands(role, rc)
+	driver := Driver(rc)
 	for _, cmd := range commands {
-		if err := t.NudgeSession(sessionID, cmd); err != nil {
+		if err := driver.SendPrompt(t, sessionID, cmd); err != nil {
 			return err
 		}
 	}
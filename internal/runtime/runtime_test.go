@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -410,3 +411,32 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDriverForEveryPreset(t *testing.T) {
+	for _, name := range []string{"claude", "gemini", "codex", "cursor", "auggie", "amp", "opencode"} {
+		if got := DriverForAgent(name).Name(); got != name {
+			t.Errorf("DriverForAgent(%q).Name() = %q", name, got)
+		}
+	}
+	if got := DriverForAgent("").Name(); got != "claude" {
+		t.Errorf("DriverForAgent(\"\") = %q, want the default agent", got)
+	}
+}
+
+func TestEnsureSettingsForRole_DelegatesToHooksDriver(t *testing.T) {
+	workDir := t.TempDir()
+	rc := &config.RuntimeConfig{
+		Provider: "custom",
+		Hooks: &config.RuntimeHooksConfig{
+			Provider:     "opencode",
+			Dir:          ".opencode/plugin",
+			SettingsFile: "gastown.js",
+		},
+	}
+	if err := EnsureSettingsForRole(workDir, "polecat", rc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, ".opencode", "plugin", "gastown.js")); err != nil {
+		t.Errorf("opencode plugin not installed: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package sandbox
+
+// Available reports whether the kernel supports Landlock. It is Linux-only.
+func Available() bool {
+	return false
+}
+
+// Restrict is not supported off Linux.
+func Restrict(paths []string) error {
+	return ErrUnsupported
+}
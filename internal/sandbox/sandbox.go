@@ -0,0 +1,133 @@
+// Package sandbox confines an agent's writes to its own scope.
+//
+// On Linux, "gt sandbox exec" applies a Landlock ruleset to itself and then
+// execs the agent, so the agent and every process it starts can write only
+// beneath the polecat's directory and the shared paths an agent needs
+// (beads, the rig's git objects, temp and tool caches). Reads are not
+// restricted. Landlock needs no privileges; on kernels without it (before
+// 5.13) or on other systems the agent runs unconfined with a warning.
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// EnvVar is set in the environment of a confined agent, so gt run from it
+// knows not to do work that writes outside its scope.
+const EnvVar = "GT_SANDBOX"
+
+// ErrUnsupported means the system can't confine processes (no Landlock).
+var ErrUnsupported = errors.New("landlock is not supported on this system")
+
+// Spec describes the write scope of one polecat.
+type Spec struct {
+	TownRoot string
+	RigPath  string
+	HomeDir  string // Polecat directory (worktree plus its settings)
+	WorkDir  string // The worktree
+}
+
+// Enabled reports whether cfg asks for write confinement.
+func Enabled(cfg *config.SandboxConfig) bool {
+	return cfg != nil && cfg.Enabled
+}
+
+// Confined reports whether this process runs under gt sandbox exec.
+func Confined() bool {
+	return os.Getenv(EnvVar) != ""
+}
+
+// Wrap returns command wrapped to run confined to spec's write scope plus
+// cfg's extra paths. Off Linux it returns command unchanged.
+func Wrap(command string, spec Spec, cfg *config.SandboxConfig) string {
+	if !Enabled(cfg) || runtime.GOOS != "linux" {
+		return command
+	}
+	createTownFiles(spec.TownRoot)
+	args := []string{"exec", "gt", "sandbox", "exec"}
+	for _, path := range WritablePaths(spec, cfg) {
+		args = append(args, "--write", config.ShellQuote(path))
+	}
+	return strings.Join(append(args, "--", "sh", "-c", config.ShellQuote(command)), " ")
+}
+
+// WritablePaths returns the existing paths a polecat may write beneath.
+func WritablePaths(spec Spec, cfg *config.SandboxConfig) []string {
+	home, _ := os.UserHomeDir()
+	candidates := []string{
+		spec.HomeDir,
+		beads.ResolveBeadsDir(spec.WorkDir),
+		filepath.Join(spec.TownRoot, ".beads"),
+		git.CommonDir(spec.WorkDir),
+
+		// Town and rig state that gt writes on an agent's behalf (mail,
+		// events, nudge queues, audit log)
+		filepath.Join(spec.TownRoot, ".runtime"),
+		filepath.Join(spec.RigPath, ".runtime"),
+		filepath.Join(spec.TownRoot, "logs"),
+	}
+	for _, name := range townFiles {
+		candidates = append(candidates, filepath.Join(spec.TownRoot, name))
+	}
+	candidates = append(candidates,
+		// Scratch space, devices and per-user tool state
+		os.TempDir(),
+		os.Getenv("XDG_RUNTIME_DIR"),
+		"/dev",
+		filepath.Join(home, ".claude"),
+		filepath.Join(home, ".claude.json"),
+		filepath.Join(home, ".cache"),
+		filepath.Join(home, ".config"),
+		filepath.Join(home, ".local"),
+		os.Getenv("CLAUDE_CONFIG_DIR"),
+	)
+	if cfg != nil {
+		for _, p := range cfg.Writable {
+			if strings.HasPrefix(p, "~/") {
+				p = filepath.Join(home, p[2:])
+			}
+			candidates = append(candidates, p)
+		}
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, p := range candidates {
+		if p == "" || !filepath.IsAbs(p) {
+			continue
+		}
+		p = filepath.Clean(p)
+		if seen[p] {
+			continue
+		}
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// townFiles are the files in the town root that agents append to.
+var townFiles = []string{".events.jsonl", ".feed.jsonl", ".audit.jsonl", ".audit.jsonl.lock"}
+
+// createTownFiles creates any missing town files so they can be allowed:
+// a confined agent can write to an existing file but not create one in the
+// town root.
+func createTownFiles(townRoot string) {
+	for _, name := range townFiles {
+		f, err := os.OpenFile(filepath.Join(townRoot, name), os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: town files are world-readable like the rest of the town
+		if err == nil {
+			_ = f.Close()
+		}
+	}
+}
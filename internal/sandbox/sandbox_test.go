@@ -0,0 +1,84 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestWritablePaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "rig")
+	polecat := filepath.Join(rigPath, "polecats", "Toast")
+	workDir := filepath.Join(polecat, "rig")
+	for _, dir := range []string{workDir, filepath.Join(town, ".beads"), filepath.Join(home, ".cache"), filepath.Join(home, "go")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spec := Spec{TownRoot: town, RigPath: rigPath, HomeDir: polecat, WorkDir: workDir}
+	cfg := &config.SandboxConfig{Enabled: true, Writable: []string{"~/go", "/nonexistent/cache", "relative/dir", polecat}}
+	paths := WritablePaths(spec, cfg)
+
+	for _, want := range []string{polecat, filepath.Join(town, ".beads"), filepath.Join(home, ".cache"), filepath.Join(home, "go"), "/dev"} {
+		if !slices.Contains(paths, want) {
+			t.Errorf("WritablePaths() = %v, missing %s", paths, want)
+		}
+	}
+	for _, unwanted := range []string{town, rigPath, workDir, "/nonexistent/cache", "relative/dir", filepath.Join(home, ".local")} {
+		if slices.Contains(paths, unwanted) {
+			t.Errorf("WritablePaths() = %v, should not contain %s", paths, unwanted)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if seen[p] {
+			t.Errorf("WritablePaths() lists %s twice", p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestWrap(t *testing.T) {
+	town := t.TempDir()
+	polecat := filepath.Join(town, "rig", "polecats", "Toast")
+	if err := os.MkdirAll(polecat, 0755); err != nil {
+		t.Fatal(err)
+	}
+	spec := Spec{TownRoot: town, RigPath: filepath.Join(town, "rig"), HomeDir: polecat, WorkDir: polecat}
+	command := "exec env GT_ROLE=polecat claude 'hi there'"
+
+	if got := Wrap(command, spec, nil); got != command {
+		t.Errorf("Wrap() with no config = %q, want command unchanged", got)
+	}
+	if got := Wrap(command, spec, &config.SandboxConfig{}); got != command {
+		t.Errorf("Wrap() when disabled = %q, want command unchanged", got)
+	}
+
+	got := Wrap(command, spec, &config.SandboxConfig{Enabled: true})
+	if runtime.GOOS != "linux" {
+		if got != command {
+			t.Errorf("Wrap() off Linux = %q, want command unchanged", got)
+		}
+		return
+	}
+	if !strings.HasPrefix(got, "exec gt sandbox exec --write "+polecat+" ") {
+		t.Errorf("Wrap() = %q, want it to start with the polecat dir", got)
+	}
+	if !strings.HasSuffix(got, " -- sh -c "+config.ShellQuote(command)) {
+		t.Errorf("Wrap() = %q, want it to end with the quoted command", got)
+	}
+	for _, name := range townFiles {
+		if !strings.Contains(got, "--write "+filepath.Join(town, name)) {
+			t.Errorf("Wrap() = %q, want %s created and writable", got, name)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package scheduler places ready work from every rig onto free polecat
+// capacity. It is pure policy: callers gather candidates and rig state
+// (gt ready, polecat counts, park/dock status) and act on the plan.
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Candidate is a ready bead that could be slung to its rig.
+type Candidate struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Rig      string    `json:"rig"`
+	Priority int       `json:"priority"`
+	Created  time.Time `json:"created"`
+
+	// Convoy is the open convoy tracking the bead, if any.
+	Convoy        string     `json:"convoy,omitempty"`
+	ConvoyCreated *time.Time `json:"convoy_created,omitempty"`
+
+	// Score is filled in by Plan.
+	Score float64 `json:"score"`
+}
+
+// RigState is the capacity of one rig at planning time.
+type RigState struct {
+	Name        string `json:"name"`
+	Polecats    int    `json:"polecats"`
+	MaxPolecats int    `json:"max_polecats"` // <= 0 means unlimited
+
+	// Blocked explains why the rig takes no work (e.g. "parked").
+	// Empty means the rig is schedulable.
+	Blocked string `json:"blocked,omitempty"`
+}
+
+// Dispatch is one planned sling.
+type Dispatch struct {
+	Candidate
+	Share float64 `json:"share"` // rig's weighted usage when chosen (fair_share)
+}
+
+// Score returns a bead's priority score. Higher scores dispatch first.
+//
+//	score = PriorityWeight * (4 - priority)
+//	      + AgeWeight * hours since the bead was created
+//	      + ConvoyAgeWeight * hours since its convoy was created
+func Score(c Candidate, cfg *config.SchedulerConfig, now time.Time) float64 {
+	priority := c.Priority
+	if priority < 0 {
+		priority = 0
+	}
+	if priority > 4 {
+		priority = 4
+	}
+	score := cfg.PriorityWeight * float64(4-priority)
+	if !c.Created.IsZero() {
+		if age := now.Sub(c.Created).Hours(); age > 0 {
+			score += cfg.AgeWeight * age
+		}
+	}
+	if c.ConvoyCreated != nil {
+		if age := now.Sub(*c.ConvoyCreated).Hours(); age > 0 {
+			score += cfg.ConvoyAgeWeight * age
+		}
+	}
+	return score
+}
+
+// free returns how many more polecats the scheduler may add to a rig,
+// or -1 for no limit.
+func free(r RigState, cfg *config.SchedulerConfig) int {
+	limit := r.MaxPolecats
+	if q, ok := cfg.RigQuotas[r.Name]; ok && q > 0 && (limit <= 0 || q < limit) {
+		limit = q
+	}
+	if limit <= 0 {
+		return -1
+	}
+	if n := limit - r.Polecats; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Plan picks up to cfg.MaxDispatch candidates to sling, in dispatch order.
+// Candidates for blocked or unknown rigs, rigs at capacity, and rigs with
+// weight 0 are never chosen.
+//
+// Under fair_share, each step serves the rig with the lowest weighted usage
+// ((polecats + planned) / weight) that still has capacity and work, taking
+// its highest-scoring bead; ties go to the better bead. Under priority, each
+// step takes the highest-scoring bead in town.
+func Plan(cfg *config.SchedulerConfig, rigs []RigState, candidates []Candidate, now time.Time) []Dispatch {
+	if cfg == nil {
+		cfg = config.DefaultSchedulerConfig()
+	}
+	maxDispatch := cfg.MaxDispatch
+	if maxDispatch <= 0 {
+		maxDispatch = config.DefaultSchedulerConfig().MaxDispatch
+	}
+
+	type rigQueue struct {
+		state   RigState
+		weight  float64
+		free    int // -1 = unlimited
+		planned int
+		queue   []Candidate
+	}
+	byName := make(map[string]*rigQueue, len(rigs))
+	for _, r := range rigs {
+		w := cfg.RigWeight(r.Name)
+		if r.Blocked != "" || w <= 0 {
+			continue
+		}
+		byName[r.Name] = &rigQueue{state: r, weight: w, free: free(r, cfg)}
+	}
+	for _, c := range candidates {
+		rq := byName[c.Rig]
+		if rq == nil {
+			continue
+		}
+		c.Score = Score(c, cfg, now)
+		rq.queue = append(rq.queue, c)
+	}
+	for _, rq := range byName {
+		sort.SliceStable(rq.queue, func(i, j int) bool {
+			if rq.queue[i].Score != rq.queue[j].Score {
+				return rq.queue[i].Score > rq.queue[j].Score
+			}
+			return rq.queue[i].ID < rq.queue[j].ID
+		})
+	}
+
+	usage := func(rq *rigQueue) float64 {
+		return float64(rq.state.Polecats+rq.planned) / rq.weight
+	}
+
+	var plan []Dispatch
+	for len(plan) < maxDispatch {
+		var best *rigQueue
+		for _, rq := range byName {
+			if len(rq.queue) == 0 || rq.free == 0 {
+				continue
+			}
+			if best == nil || better(cfg.Policy, rq.queue[0], usage(rq), best.queue[0], usage(best)) {
+				best = rq
+			}
+		}
+		if best == nil {
+			break
+		}
+		plan = append(plan, Dispatch{Candidate: best.queue[0], Share: usage(best)})
+		best.queue = best.queue[1:]
+		best.planned++
+		if best.free > 0 {
+			best.free--
+		}
+	}
+	return plan
+}
+
+// better reports whether candidate a (from a rig at usage ua) should be
+// dispatched before candidate b (from a rig at usage ub).
+func better(policy string, a Candidate, ua float64, b Candidate, ub float64) bool {
+	if policy != config.SchedulerPolicyPriority && ua != ub {
+		return ua < ub
+	}
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Rig != b.Rig {
+		return a.Rig < b.Rig
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func planIDs(plan []Dispatch) string {
+	var ids []string
+	for _, d := range plan {
+		ids = append(ids, d.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestScore(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	cfg := config.DefaultSchedulerConfig()
+	convoy := now.Add(-10 * time.Hour)
+
+	c := Candidate{Priority: 1, Created: now.Add(-2 * time.Hour), ConvoyCreated: &convoy}
+	// 100*3 + 1*2 + 10*10
+	if got := Score(c, cfg, now); got != 402 {
+		t.Errorf("Score() = %v, want 402", got)
+	}
+
+	// Out-of-range priorities are clamped
+	if got := Score(Candidate{Priority: 9}, cfg, now); got != 0 {
+		t.Errorf("Score(P9) = %v, want 0", got)
+	}
+}
+
+func TestPlanFairShare(t *testing.T) {
+	now := time.Now()
+	cfg := config.DefaultSchedulerConfig()
+	cfg.MaxDispatch = 4
+
+	rigs := []RigState{
+		{Name: "alpha", Polecats: 2, MaxPolecats: 10},
+		{Name: "beta", Polecats: 0, MaxPolecats: 10},
+		{Name: "parked", Polecats: 0, MaxPolecats: 10, Blocked: "parked"},
+	}
+	cands := []Candidate{
+		{ID: "a-1", Rig: "alpha", Priority: 0},
+		{ID: "a-2", Rig: "alpha", Priority: 0},
+		{ID: "b-1", Rig: "beta", Priority: 3},
+		{ID: "b-2", Rig: "beta", Priority: 2},
+		{ID: "p-1", Rig: "parked", Priority: 0},
+	}
+
+	// beta is idle, so it catches up to alpha before alpha's P0s run.
+	// Ties at equal usage go to the higher score.
+	if got := planIDs(Plan(cfg, rigs, cands, now)); got != "b-2,b-1,a-1,a-2" {
+		t.Errorf("fair_share plan = %s, want b-2,b-1,a-1,a-2", got)
+	}
+
+	cfg.Policy = config.SchedulerPolicyPriority
+	if got := planIDs(Plan(cfg, rigs, cands, now)); got != "a-1,a-2,b-2,b-1" {
+		t.Errorf("priority plan = %s, want a-1,a-2,b-2,b-1", got)
+	}
+}
+
+func TestPlanCapacityWeightsAndQuotas(t *testing.T) {
+	now := time.Now()
+	cfg := config.DefaultSchedulerConfig()
+	cfg.RigWeights = map[string]float64{"big": 3, "off": 0}
+	cfg.RigQuotas = map[string]int{"small": 1}
+	cfg.MaxDispatch = 10
+
+	rigs := []RigState{
+		{Name: "big", Polecats: 0, MaxPolecats: 3},
+		{Name: "small", Polecats: 0, MaxPolecats: 5},
+		{Name: "full", Polecats: 2, MaxPolecats: 2},
+		{Name: "off", Polecats: 0, MaxPolecats: 5},
+	}
+	var cands []Candidate
+	for _, r := range []string{"big", "small", "full", "off"} {
+		for i := 0; i < 4; i++ {
+			cands = append(cands, Candidate{ID: r + "-" + string(rune('a'+i)), Rig: r, Priority: 2})
+		}
+	}
+
+	plan := Plan(cfg, rigs, cands, now)
+	count := map[string]int{}
+	for _, d := range plan {
+		count[d.Rig]++
+	}
+	if count["big"] != 3 {
+		t.Errorf("big got %d dispatches, want 3 (max_polecats)", count["big"])
+	}
+	if count["small"] != 1 {
+		t.Errorf("small got %d dispatches, want 1 (quota)", count["small"])
+	}
+	if count["full"] != 0 || count["off"] != 0 {
+		t.Errorf("full/off should get nothing, got %v", count)
+	}
+
+	cfg.MaxDispatch = 2
+	if got := len(Plan(cfg, rigs, cands, now)); got != 2 {
+		t.Errorf("plan length with MaxDispatch=2 = %d", got)
+	}
+}
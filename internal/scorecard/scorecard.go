@@ -0,0 +1,324 @@
+// Package scorecard measures how well agent presets, formulas, and rigs
+// perform, from signals Gas Town already records: slings, gt done, merge
+// outcomes, rework requests and escalations in the events log, and session
+// costs from the costs log.
+//
+// Each sling to a polecat starts a run. Later events for the same
+// <rig>/polecats/<name> are credited to that run until the next sling or
+// spawn reuses the name.
+package scorecard
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Run is one polecat working one hooked bead.
+type Run struct {
+	Rig     string    `json:"rig"`
+	Polecat string    `json:"polecat"`
+	Bead    string    `json:"bead"`
+	Agent   string    `json:"agent,omitempty"`
+	Formula string    `json:"formula,omitempty"`
+	Hooked  time.Time `json:"hooked"`
+	Done    time.Time `json:"done,omitempty"`
+
+	Merged      int     `json:"merged"`
+	MergeFailed int     `json:"merge_failed"`
+	Rework      int     `json:"rework"`
+	Escalations int     `json:"escalations"`
+	CostUSD     float64 `json:"cost_usd"`
+}
+
+// CostRecord is the subset of a ~/.gt/costs.jsonl entry used for scoring.
+type CostRecord struct {
+	Role    string    `json:"role"`
+	Rig     string    `json:"rig"`
+	Worker  string    `json:"worker"`
+	CostUSD float64   `json:"cost_usd"`
+	EndedAt time.Time `json:"ended_at"`
+}
+
+// Card is the aggregated scorecard for one preset, formula, or rig.
+type Card struct {
+	Key         string        `json:"key"`
+	Runs        int           `json:"runs"`
+	Completed   int           `json:"completed"`
+	Merged      int           `json:"merged"`
+	MergeFailed int           `json:"merge_failed"`
+	Rework      int           `json:"rework"`
+	Escalations int           `json:"escalations"`
+	AvgToDone   time.Duration `json:"avg_to_done_ns"`
+	CostUSD     float64       `json:"cost_usd"`
+}
+
+// MergeRate is the fraction of merge attempts that landed, or -1 with no attempts.
+func (c Card) MergeRate() float64 {
+	attempts := c.Merged + c.MergeFailed
+	if attempts == 0 {
+		return -1
+	}
+	return float64(c.Merged) / float64(attempts)
+}
+
+// CostPerMerge is the total cost divided by merges, or 0 with no merges.
+func (c Card) CostPerMerge() float64 {
+	if c.Merged == 0 {
+		return 0
+	}
+	return c.CostUSD / float64(c.Merged)
+}
+
+// Group keys for Aggregate.
+const (
+	ByAgent   = "agent"
+	ByFormula = "formula"
+	ByRig     = "rig"
+)
+
+// polecatKey parses a "<rig>/polecats/<name>" address.
+func polecatKey(addr string) (string, bool) {
+	parts := strings.Split(strings.TrimSuffix(addr, "/"), "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		return parts[0] + "/" + parts[2], true
+	}
+	return "", false
+}
+
+// workerName reduces an MR worker field ("nux" or "gastown/polecats/nux")
+// to the polecat name.
+func workerName(worker string) string {
+	worker = strings.TrimSuffix(worker, "/")
+	if i := strings.LastIndex(worker, "/"); i >= 0 {
+		return worker[i+1:]
+	}
+	return worker
+}
+
+func payloadString(p map[string]interface{}, key string) string {
+	if v, ok := p[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Build reconstructs runs from events (in any order) and attributes costs to
+// them. Events older than since are ignored (zero since keeps everything).
+func Build(evts []events.Event, costs []CostRecord, since time.Time) []*Run {
+	type stamped struct {
+		ts time.Time
+		e  events.Event
+	}
+	var ordered []stamped
+	for _, e := range evts {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || ts.Before(since) {
+			continue
+		}
+		ordered = append(ordered, stamped{ts, e})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].ts.Before(ordered[j].ts) })
+
+	var runs []*Run
+	byKey := make(map[string][]*Run)
+	agents := make(map[string]string) // polecat key -> agent from its latest spawn
+	current := func(key string) *Run {
+		if rs := byKey[key]; len(rs) > 0 {
+			return rs[len(rs)-1]
+		}
+		return nil
+	}
+
+	for _, s := range ordered {
+		e := s.e
+		switch e.Type {
+		case events.TypeSpawn:
+			key := payloadString(e.Payload, "rig") + "/" + payloadString(e.Payload, "polecat")
+			agents[key] = payloadString(e.Payload, "agent")
+
+		case events.TypeSling:
+			key, ok := polecatKey(payloadString(e.Payload, "target"))
+			if !ok {
+				continue
+			}
+			parts := strings.SplitN(key, "/", 2)
+			r := &Run{
+				Rig:     parts[0],
+				Polecat: parts[1],
+				Bead:    payloadString(e.Payload, "bead"),
+				Agent:   agents[key],
+				Formula: payloadString(e.Payload, "formula"),
+				Hooked:  s.ts,
+			}
+			runs = append(runs, r)
+			byKey[key] = append(byKey[key], r)
+
+		case events.TypeDone:
+			if key, ok := polecatKey(e.Actor); ok {
+				if r := current(key); r != nil && r.Done.IsZero() {
+					r.Done = s.ts
+				}
+			}
+
+		case events.TypeEscalationSent:
+			if key, ok := polecatKey(e.Actor); ok {
+				if r := current(key); r != nil {
+					r.Escalations++
+				}
+			}
+
+		case events.TypeMerged, events.TypeMergeFailed, events.TypeReworkRequested:
+			// Logged by the refinery engineer: actor <rig>/refinery
+			rigName := strings.SplitN(e.Actor, "/", 2)[0]
+			r := current(rigName + "/" + workerName(payloadString(e.Payload, "worker")))
+			if r == nil {
+				continue
+			}
+			switch e.Type {
+			case events.TypeMerged:
+				r.Merged++
+			case events.TypeMergeFailed:
+				r.MergeFailed++
+			default:
+				r.Rework++
+			}
+
+		case events.TypeMail:
+			// Protocol messages sent by the refinery agent with gt mail send,
+			// e.g. "MERGED nux" to gastown/witness
+			to := payloadString(e.Payload, "to")
+			fields := strings.Fields(payloadString(e.Payload, "subject"))
+			if len(fields) < 2 || !strings.HasSuffix(to, "/witness") {
+				continue
+			}
+			r := current(strings.TrimSuffix(to, "/witness") + "/" + fields[1])
+			if r == nil {
+				continue
+			}
+			switch fields[0] {
+			case "MERGED":
+				r.Merged++
+			case "MERGE_FAILED":
+				r.MergeFailed++
+			case "REWORK_REQUEST":
+				r.Rework++
+			}
+		}
+	}
+
+	// Credit each polecat session's cost to the run it ended in
+	for _, c := range costs {
+		if c.Role != "polecat" || c.EndedAt.Before(since) {
+			continue
+		}
+		rs := byKey[c.Rig+"/"+c.Worker]
+		for i := len(rs) - 1; i >= 0; i-- {
+			if !rs[i].Hooked.After(c.EndedAt) {
+				rs[i].CostUSD += c.CostUSD
+				break
+			}
+		}
+	}
+	return runs
+}
+
+// Aggregate groups runs into cards by ByAgent, ByFormula, or ByRig,
+// sorted by key. Runs with no value for the key are grouped as "(default)".
+func Aggregate(runs []*Run, by string) []Card {
+	cards := make(map[string]*Card)
+	durations := make(map[string]time.Duration)
+	for _, r := range runs {
+		var key string
+		switch by {
+		case ByFormula:
+			key = r.Formula
+		case ByRig:
+			key = r.Rig
+		default:
+			key = r.Agent
+		}
+		if key == "" {
+			key = "(default)"
+		}
+		c := cards[key]
+		if c == nil {
+			c = &Card{Key: key}
+			cards[key] = c
+		}
+		c.Runs++
+		c.Merged += r.Merged
+		c.MergeFailed += r.MergeFailed
+		c.Rework += r.Rework
+		c.Escalations += r.Escalations
+		c.CostUSD += r.CostUSD
+		if !r.Done.IsZero() {
+			c.Completed++
+			durations[key] += r.Done.Sub(r.Hooked)
+		}
+	}
+
+	out := make([]Card, 0, len(cards))
+	for key, c := range cards {
+		if c.Completed > 0 {
+			c.AvgToDone = durations[key] / time.Duration(c.Completed)
+		}
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// Load reads the town's events log and the costs log at costsPath and
+// builds runs since the given time. Missing files are treated as empty.
+func Load(townRoot, costsPath string, since time.Time) ([]*Run, error) {
+	var evts []events.Event
+	if err := readJSONL(filepath.Join(townRoot, events.EventsFile), func(line []byte) {
+		var e events.Event
+		if json.Unmarshal(line, &e) == nil {
+			evts = append(evts, e)
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	var costs []CostRecord
+	if costsPath != "" {
+		if err := readJSONL(costsPath, func(line []byte) {
+			var c CostRecord
+			if json.Unmarshal(line, &c) == nil {
+				costs = append(costs, c)
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return Build(evts, costs, since), nil
+}
+
+// readJSONL calls fn for each line of a JSONL file. A missing file is not an error.
+func readJSONL(path string, fn func([]byte)) error {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			fn(line)
+		}
+	}
+	return scanner.Err()
+}
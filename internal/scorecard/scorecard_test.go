@@ -0,0 +1,82 @@
+package scorecard
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func ev(ts time.Time, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: ts.Format(time.RFC3339), Type: typ, Actor: actor, Payload: payload}
+}
+
+func TestBuildAndAggregate(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+
+	evts := []events.Event{
+		// nux runs opus on a merged bead, then is reused for a haiku bead
+		ev(at(0), events.TypeSpawn, "gt", map[string]interface{}{"rig": "gastown", "polecat": "nux", "agent": "opus"}),
+		ev(at(1), events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-1", "target": "gastown/polecats/nux", "formula": "mol-polecat-work"}),
+		ev(at(5), events.TypeEscalationSent, "gastown/polecats/nux", nil),
+		ev(at(31), events.TypeDone, "gastown/polecats/nux", map[string]interface{}{"bead": "gt-1"}),
+		ev(at(35), events.TypeMail, "gastown/refinery", map[string]interface{}{"to": "gastown/witness", "subject": "REWORK_REQUEST nux"}),
+		ev(at(40), events.TypeMerged, "gastown/refinery", map[string]interface{}{"mr": "gt-mr1", "worker": "gastown/polecats/nux"}),
+
+		ev(at(50), events.TypeSpawn, "gt", map[string]interface{}{"rig": "gastown", "polecat": "nux", "agent": "haiku"}),
+		ev(at(51), events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-2", "target": "gastown/polecats/nux", "formula": "mol-polecat-work"}),
+		ev(at(60), events.TypeMail, "gastown/refinery", map[string]interface{}{"to": "gastown/witness", "subject": "MERGE_FAILED nux"}),
+
+		// Slings to non-polecat targets are not runs
+		ev(at(2), events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-3", "target": "gastown/crew/max"}),
+		// Before the since cutoff
+		ev(t0.Add(-48*time.Hour), events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-old", "target": "gastown/polecats/toast"}),
+	}
+	costs := []CostRecord{
+		{Role: "polecat", Rig: "gastown", Worker: "nux", CostUSD: 3.0, EndedAt: at(32)},
+		{Role: "polecat", Rig: "gastown", Worker: "nux", CostUSD: 0.5, EndedAt: at(61)},
+		{Role: "witness", Rig: "gastown", CostUSD: 9.0, EndedAt: at(32)},
+	}
+
+	runs := Build(evts, costs, t0.Add(-time.Hour))
+	if len(runs) != 2 {
+		t.Fatalf("Build() = %d runs, want 2", len(runs))
+	}
+
+	first := runs[0]
+	if first.Agent != "opus" || first.Bead != "gt-1" || first.Merged != 1 || first.Rework != 1 ||
+		first.Escalations != 1 || first.CostUSD != 3.0 || first.Done.Sub(first.Hooked) != 30*time.Minute {
+		t.Errorf("first run = %+v", first)
+	}
+	second := runs[1]
+	if second.Agent != "haiku" || second.MergeFailed != 1 || !second.Done.IsZero() || second.CostUSD != 0.5 {
+		t.Errorf("second run = %+v", second)
+	}
+
+	cards := Aggregate(runs, ByAgent)
+	if len(cards) != 2 || cards[0].Key != "haiku" || cards[1].Key != "opus" {
+		t.Fatalf("Aggregate(ByAgent) = %+v", cards)
+	}
+	if cards[1].AvgToDone != 30*time.Minute || cards[1].MergeRate() != 1 || cards[1].CostPerMerge() != 3.0 {
+		t.Errorf("opus card = %+v", cards[1])
+	}
+	if cards[0].MergeRate() != 0 || cards[0].Completed != 0 {
+		t.Errorf("haiku card = %+v", cards[0])
+	}
+
+	byFormula := Aggregate(runs, ByFormula)
+	if len(byFormula) != 1 || byFormula[0].Runs != 2 || byFormula[0].MergeRate() != 0.5 {
+		t.Errorf("Aggregate(ByFormula) = %+v", byFormula)
+	}
+	if math.Abs(byFormula[0].CostUSD-3.5) > 1e-9 {
+		t.Errorf("formula cost = %v, want 3.5", byFormula[0].CostUSD)
+	}
+}
+
+func TestMergeRateNoAttempts(t *testing.T) {
+	if got := (Card{}).MergeRate(); got != -1 {
+		t.Errorf("MergeRate() with no attempts = %v, want -1", got)
+	}
+}
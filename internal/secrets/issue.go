@@ -0,0 +1,228 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// issuedFile records what was issued in an agent's secrets directory.
+const issuedFile = "issued.json"
+
+// Issued describes the secrets issued to one agent process. Its files live
+// in RuntimeDir()/<pid> until the process exits and Reap removes them.
+type Issued struct {
+	PID      int            `json:"pid"`
+	Agent    string         `json:"agent"` // GT_ROLE of the session, e.g. "gastown/polecats/Toast"
+	Town     string         `json:"town"`
+	IssuedAt time.Time      `json:"issued_at"`
+	Files    map[string]int `json:"files,omitempty"`  // Secrets issued as files, by version
+	Inline   map[string]int `json:"inline,omitempty"` // Secrets issued as variables, by version
+
+	dir string
+}
+
+// RuntimeDir returns the directory holding issued secret files: under
+// $XDG_RUNTIME_DIR (usually a per-user tmpfs) when set, else a per-user
+// directory under the system temp dir.
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gastown-secrets")
+	}
+	return filepath.Join(os.TempDir(), "gt-secrets-"+strconv.Itoa(os.Getuid()))
+}
+
+// Issue materializes an agent's granted secrets for process pid and returns
+// the variables that expose them. Each issue is recorded in the audit log.
+// Secrets that can't be issued are skipped and reported in the error; the
+// rest are still returned.
+func Issue(townRoot, agent string, pid int, grants []config.SecretGrant) (map[string]string, error) {
+	env := make(map[string]string)
+	if len(grants) == 0 {
+		return env, nil
+	}
+	store, err := Load(townRoot)
+	if err != nil {
+		return env, err
+	}
+
+	// The fallback under the system temp dir is a predictable path another
+	// user could create first
+	if err := util.EnsurePrivateDir(RuntimeDir()); err != nil {
+		return env, fmt.Errorf("secrets dir: %w", err)
+	}
+	dir := filepath.Join(RuntimeDir(), strconv.Itoa(pid))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return env, fmt.Errorf("creating secrets dir: %w", err)
+	}
+	issued := &Issued{
+		PID:      pid,
+		Agent:    agent,
+		Town:     townRoot,
+		IssuedAt: time.Now().UTC(),
+		Files:    make(map[string]int),
+		Inline:   make(map[string]int),
+		dir:      dir,
+	}
+	// Record the issue before writing any file, so Reap never finds the
+	// directory without its record while this process is running
+	if err := issued.save(); err != nil {
+		return env, err
+	}
+
+	var errs []error
+	for _, g := range grants {
+		if g.Env == "" {
+			errs = append(errs, fmt.Errorf("grant of %s has no env", g.Secret))
+			continue
+		}
+		value, version, err := store.Get(g.Secret)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if g.Inline {
+			env[g.Env] = value
+			issued.Inline[g.Secret] = version
+		} else {
+			path := filepath.Join(dir, g.Secret)
+			if err := util.AtomicWriteFile(path, []byte(value), 0600); err != nil {
+				errs = append(errs, fmt.Errorf("writing %s: %w", g.Secret, err))
+				continue
+			}
+			env[g.Env] = path
+			issued.Files[g.Secret] = version
+		}
+		_ = events.LogAudit(events.TypeSecretIssued, agent, events.SecretPayload(g.Secret, version, agent))
+	}
+
+	if err := issued.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return env, errors.Join(errs...)
+}
+
+func (i *Issued) save() error {
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(filepath.Join(i.dir, issuedFile), data, 0600)
+}
+
+// Active returns the issues to agent processes that are still running,
+// sorted by agent.
+func Active() ([]*Issued, error) {
+	all, err := readIssued()
+	if err != nil {
+		return nil, err
+	}
+	var active []*Issued
+	for _, i := range all {
+		if util.ProcessExists(i.PID) {
+			active = append(active, i)
+		}
+	}
+	sort.Slice(active, func(a, b int) bool { return active[a].Agent < active[b].Agent })
+	return active, nil
+}
+
+// Refresh rewrites the live file copies of a rotated secret in a town, so
+// running agents read the new value without restarting. It returns the
+// agents it refreshed and those holding the old value in a variable, which
+// only a restart replaces.
+func Refresh(townRoot, name string) (refreshed, stale []string, err error) {
+	store, err := Load(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, version, err := store.Get(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := util.CheckPrivateDir(RuntimeDir()); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("secrets dir: %w", err)
+	}
+	active, err := Active()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, i := range active {
+		if i.Town != townRoot {
+			continue
+		}
+		if _, ok := i.Inline[name]; ok {
+			stale = append(stale, i.Agent)
+		}
+		if _, ok := i.Files[name]; ok {
+			if err := util.AtomicWriteFile(filepath.Join(i.dir, name), []byte(value), 0600); err != nil {
+				return refreshed, stale, fmt.Errorf("refreshing %s for %s: %w", name, i.Agent, err)
+			}
+			i.Files[name] = version
+			_ = i.save()
+			refreshed = append(refreshed, i.Agent)
+			_ = events.LogAudit(events.TypeSecretIssued, i.Agent, events.SecretPayload(name, version, i.Agent))
+		}
+	}
+	return refreshed, stale, nil
+}
+
+// Reap removes the secret files of agent processes that have exited and
+// returns how many directories it removed.
+func Reap() (int, error) {
+	all, err := readIssued()
+	if err != nil {
+		return 0, err
+	}
+	reaped := 0
+	for _, i := range all {
+		if util.ProcessExists(i.PID) {
+			continue
+		}
+		if err := os.RemoveAll(i.dir); err != nil {
+			return reaped, err
+		}
+		reaped++
+	}
+	return reaped, nil
+}
+
+// readIssued reads every issue record under RuntimeDir. Directories
+// without a readable record (e.g., cut short by a crash) are reported as
+// issues to PID 0, which is never running.
+func readIssued() ([]*Issued, error) {
+	entries, err := os.ReadDir(RuntimeDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading secrets dir: %w", err)
+	}
+	var all []*Issued
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(RuntimeDir(), e.Name())
+		i := &Issued{dir: dir}
+		data, err := os.ReadFile(filepath.Join(dir, issuedFile)) //nolint:gosec // G304: path is under our runtime dir
+		if err != nil || json.Unmarshal(data, i) != nil || i.PID != pid {
+			i = &Issued{dir: dir}
+		}
+		all = append(all, i)
+	}
+	return all, nil
+}
@@ -0,0 +1,289 @@
+// Package secrets is the town's secrets broker.
+//
+// Secrets are stored in the town at mayor/secrets.json, each value sealed
+// with AES-256-GCM under a key kept outside the town in the user's config
+// directory (~/.config/gastown/secrets.key), so a copy of the town directory
+// alone doesn't expose them. Town and rig settings grant secrets to agent
+// roles; "gt secrets exec", which the startup command of a granted role
+// runs in front of the agent, issues them to that one session as short-lived
+// files (or variables) and records each issue in the audit log.
+//
+// Grants are advisory: gt secrets exec takes the role from GT_ROLE, which
+// any agent can set, and every agent running as the user can read the key.
+// They keep secrets out of sessions that don't need them, but only agents
+// that can't reach the key, such as container-isolated polecats, are
+// actually denied the rest.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// StoreFile is the encrypted store, relative to the town root.
+const StoreFile = "mayor/secrets.json"
+
+// KeyEnv overrides the path of the store key.
+const KeyEnv = "GT_SECRETS_KEY"
+
+// ErrNotFound means the store has no secret by that name.
+var ErrNotFound = errors.New("secret not found")
+
+var validNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Entry is one sealed secret.
+type Entry struct {
+	Version   int       `json:"version"` // Bumped by every set or rotate
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy string    `json:"updated_by"`
+	Nonce     []byte    `json:"nonce"`
+	Data      []byte    `json:"data"` // AES-GCM ciphertext, sealed with the name as associated data
+}
+
+// Store is a town's secrets store.
+type Store struct {
+	KeyID   string            `json:"key_id"` // Fingerprint of the key the entries are sealed with
+	Secrets map[string]*Entry `json:"secrets"`
+
+	path string
+}
+
+// Info describes a stored secret without its value.
+type Info struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy string    `json:"updated_by"`
+}
+
+// StorePath returns the store path for a town.
+func StorePath(townRoot string) string {
+	return filepath.Join(townRoot, StoreFile)
+}
+
+// KeyPath returns the path of the key that seals store entries.
+func KeyPath() string {
+	if path := os.Getenv(KeyEnv); path != "" {
+		return path
+	}
+	return filepath.Join(state.ConfigDir(), "secrets.key")
+}
+
+// ValidName reports whether name can be used for a secret.
+func ValidName(name string) error {
+	if !validNameRe.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q (letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// Load reads a town's store. A town without one has an empty store.
+// Listing needs no key; reading or writing values does.
+func Load(townRoot string) (*Store, error) {
+	s := &Store{Secrets: make(map[string]*Entry), path: StorePath(townRoot)}
+	data, err := os.ReadFile(s.path) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading secrets store: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing secrets store: %w", err)
+	}
+	if s.Secrets == nil {
+		s.Secrets = make(map[string]*Entry)
+	}
+	return s, nil
+}
+
+// List describes the stored secrets, sorted by name.
+func (s *Store) List() []Info {
+	infos := make([]Info, 0, len(s.Secrets))
+	for name, e := range s.Secrets {
+		infos = append(infos, Info{Name: name, Version: e.Version, UpdatedAt: e.UpdatedAt, UpdatedBy: e.UpdatedBy})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Get decrypts a secret and returns its value and version.
+func (s *Store) Get(name string) (string, int, error) {
+	e, ok := s.Secrets[name]
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	key, err := loadKey(false)
+	if err != nil {
+		return "", 0, err
+	}
+	if key == nil {
+		return "", 0, fmt.Errorf("no secrets key at %s", KeyPath())
+	}
+	if err := s.checkKey(key); err != nil {
+		return "", 0, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", 0, err
+	}
+	plain, err := gcm.Open(nil, e.Nonce, e.Data, []byte(name))
+	if err != nil {
+		return "", 0, fmt.Errorf("decrypting secret %s: %w", name, err)
+	}
+	return string(plain), e.Version, nil
+}
+
+// Set stores a secret's value, creating the key on first use, and returns
+// the new version. The store is written immediately.
+func Set(townRoot, name, value, actor string) (int, error) {
+	return update(townRoot, name, value, actor, false)
+}
+
+// Rotate replaces an existing secret's value and returns the new version.
+func Rotate(townRoot, name, value, actor string) (int, error) {
+	return update(townRoot, name, value, actor, true)
+}
+
+// update seals value under name, serialized across processes with a lock
+// beside the store.
+func update(townRoot, name, value, actor string, mustExist bool) (int, error) {
+	if err := ValidName(name); err != nil {
+		return 0, err
+	}
+	key, err := loadKey(true)
+	if err != nil {
+		return 0, err
+	}
+
+	path := StorePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("creating store directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return 0, fmt.Errorf("locking secrets store: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := Load(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	if len(s.Secrets) == 0 {
+		s.KeyID = keyID(key)
+	} else if err := s.checkKey(key); err != nil {
+		return 0, err
+	}
+	prev, ok := s.Secrets[name]
+	if mustExist && !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return 0, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, fmt.Errorf("generating nonce: %w", err)
+	}
+	e := &Entry{
+		Version:   1,
+		UpdatedAt: time.Now().UTC(),
+		UpdatedBy: actor,
+		Nonce:     nonce,
+		Data:      gcm.Seal(nil, nonce, []byte(value), []byte(name)),
+	}
+	if ok {
+		e.Version = prev.Version + 1
+	}
+	s.Secrets[name] = e
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := util.AtomicWriteFile(path, append(data, '\n'), 0600); err != nil {
+		return 0, fmt.Errorf("writing secrets store: %w", err)
+	}
+	return e.Version, nil
+}
+
+func (s *Store) checkKey(key []byte) error {
+	if s.KeyID != "" && s.KeyID != keyID(key) {
+		return fmt.Errorf("secrets store is sealed with key %s, but %s is key %s", s.KeyID, KeyPath(), keyID(key))
+	}
+	return nil
+}
+
+// loadKey reads the store key, generating it first if create is set.
+// Returns nil, nil if there is no key and create is not set.
+func loadKey(create bool) ([]byte, error) {
+	path := KeyPath()
+	data, err := os.ReadFile(path) //nolint:gosec // G304: key path is from the user's config dir or GT_SECRETS_KEY
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid secrets key in %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading secrets key: %w", err)
+	}
+	if !create {
+		return nil, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating secrets key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	// O_EXCL so two processes racing to create the key don't clobber each other
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // G304: see above
+	if err != nil {
+		if os.IsExist(err) {
+			return loadKey(false)
+		}
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	return key, nil
+}
+
+// keyID returns a short fingerprint identifying a key.
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
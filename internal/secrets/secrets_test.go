@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// setup points the key and runtime dir at temp dirs and returns a town root.
+// It also leaves the source tree, which issues would otherwise audit-log to.
+func setup(t *testing.T) string {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv(KeyEnv, filepath.Join(t.TempDir(), "secrets.key"))
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	return t.TempDir()
+}
+
+func TestSetGetRotate(t *testing.T) {
+	town := setup(t)
+
+	if v, err := Set(town, "github-token", "ghp_one", "overseer"); err != nil || v != 1 {
+		t.Fatalf("Set() = %d, %v; want 1, nil", v, err)
+	}
+	data, err := os.ReadFile(StorePath(town))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ghp_one") {
+		t.Error("store contains the plaintext value")
+	}
+
+	if v, err := Rotate(town, "github-token", "ghp_two", "overseer"); err != nil || v != 2 {
+		t.Fatalf("Rotate() = %d, %v; want 2, nil", v, err)
+	}
+	store, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, version, err := store.Get("github-token")
+	if err != nil || value != "ghp_two" || version != 2 {
+		t.Errorf("Get() = %q, %d, %v; want ghp_two, 2, nil", value, version, err)
+	}
+	if list := store.List(); len(list) != 1 || list[0].Name != "github-token" || list[0].UpdatedBy != "overseer" {
+		t.Errorf("List() = %+v", list)
+	}
+
+	if _, err := Rotate(town, "missing", "x", "overseer"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Rotate(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := Set(town, "../escape", "x", "overseer"); err == nil {
+		t.Error("Set() accepted an invalid name")
+	}
+}
+
+func TestWrongKey(t *testing.T) {
+	town := setup(t)
+	if _, err := Set(town, "token", "secret", "overseer"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A different key must not decrypt, or reseal, the store
+	t.Setenv(KeyEnv, filepath.Join(t.TempDir(), "other.key"))
+	if _, err := Set(town, "other", "x", "overseer"); err == nil {
+		t.Error("Set() with a different key succeeded")
+	}
+	store, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Get("token"); err == nil {
+		t.Error("Get() with a different key succeeded")
+	}
+}
+
+func TestIssueRefreshReap(t *testing.T) {
+	town := setup(t)
+	if _, err := Set(town, "github-token", "ghp_one", "overseer"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Set(town, "npm-token", "npm_one", "overseer"); err != nil {
+		t.Fatal(err)
+	}
+
+	grants := []config.SecretGrant{
+		{Secret: "github-token", Env: "GITHUB_TOKEN_FILE"},
+		{Secret: "npm-token", Env: "NPM_TOKEN", Inline: true},
+		{Secret: "unset", Env: "UNSET"},
+	}
+	env, err := Issue(town, "gastown/refinery", os.Getpid(), grants)
+	if err == nil || !strings.Contains(err.Error(), "unset") {
+		t.Errorf("Issue() error = %v, want one about the unset secret", err)
+	}
+	if env["NPM_TOKEN"] != "npm_one" {
+		t.Errorf("NPM_TOKEN = %q, want inline value", env["NPM_TOKEN"])
+	}
+	path := env["GITHUB_TOKEN_FILE"]
+	if data, err := os.ReadFile(path); err != nil || string(data) != "ghp_one" {
+		t.Fatalf("GITHUB_TOKEN_FILE contents = %q, %v", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("secret file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	if _, err := Rotate(town, "github-token", "ghp_two", "overseer"); err != nil {
+		t.Fatal(err)
+	}
+	refreshed, stale, err := Refresh(town, "github-token")
+	if err != nil || len(refreshed) != 1 || len(stale) != 0 {
+		t.Errorf("Refresh(github-token) = %v, %v, %v", refreshed, stale, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "ghp_two" {
+		t.Errorf("refreshed file = %q, want ghp_two", data)
+	}
+	if _, stale, _ := Refresh(town, "npm-token"); len(stale) != 1 || stale[0] != "gastown/refinery" {
+		t.Errorf("Refresh(npm-token) stale = %v, want the refinery", stale)
+	}
+
+	// Issue to a process that has exited: only its files are reaped
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run true: %v", err)
+	}
+	deadEnv, _ := Issue(town, "gastown/polecats/Toast", cmd.Process.Pid, grants[:1])
+	if n, err := Reap(); err != nil || n != 1 {
+		t.Errorf("Reap() = %d, %v; want 1, nil", n, err)
+	}
+	if _, err := os.Stat(deadEnv["GITHUB_TOKEN_FILE"]); !os.IsNotExist(err) {
+		t.Error("exited agent's secret file survived Reap")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("running agent's secret file was reaped: %v", err)
+	}
+}
+
+func TestIssueRefusesSharedDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix permissions on Windows")
+	}
+	town := setup(t)
+	if _, err := Set(town, "github-token", "ghp_one", "overseer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(RuntimeDir(), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(RuntimeDir(), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	grants := []config.SecretGrant{{Secret: "github-token", Env: "GITHUB_TOKEN_FILE"}}
+	if env, err := Issue(town, "gastown/refinery", os.Getpid(), grants); err == nil || len(env) != 0 {
+		t.Errorf("Issue() into a world-writable dir = %v, %v; want an error", env, err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
 )
 
 // Integration branch errors
@@ -52,7 +54,7 @@ func (m *Manager) CreateIntegrationBranch(swarmID string) error {
 	}
 
 	// Push to origin (non-fatal: may not have remote)
-	_ = m.gitRun("push", "-u", "origin", branchName)
+	_ = m.gitPush(branchName, git.PushUpdate, "-u", "origin", branchName)
 
 	return nil
 }
@@ -131,7 +133,7 @@ func (m *Manager) LandToMain(swarmID string) error {
 	}
 
 	// Push
-	if err := m.gitRun("push", "origin", swarm.TargetBranch); err != nil {
+	if err := m.gitPush(swarm.TargetBranch, git.PushUpdate, "origin", swarm.TargetBranch); err != nil {
 		return fmt.Errorf("pushing: %w", err)
 	}
 
@@ -153,7 +155,7 @@ func (m *Manager) CleanupBranches(swarmID string) error {
 	}
 
 	// Delete integration branch remotely (best-effort cleanup)
-	_ = m.gitRun("push", "origin", "--delete", swarm.Integration)
+	_ = m.gitPush(swarm.Integration, git.PushDelete, "origin", "--delete", swarm.Integration)
 
 	// Delete worker branches (best-effort cleanup)
 	for _, task := range swarm.Tasks {
@@ -161,7 +163,7 @@ func (m *Manager) CleanupBranches(swarmID string) error {
 			// Local delete
 			_ = m.gitRun("branch", "-D", task.Branch)
 			// Remote delete
-			_ = m.gitRun("push", "origin", "--delete", task.Branch)
+			_ = m.gitPush(task.Branch, git.PushDelete, "origin", "--delete", task.Branch)
 		}
 	}
 
@@ -231,6 +233,15 @@ func (m *Manager) getConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// gitPush runs git push with args, if the push policy allows the calling
+// agent to make the op to branch.
+func (m *Manager) gitPush(branch string, op git.PushOp, args ...string) error {
+	if err := git.NewGit(m.gitDir).CheckPush(branch, op); err != nil {
+		return err
+	}
+	return m.gitRun(append([]string{"push"}, args...)...)
+}
+
 // gitRun executes a git command.
 // ZFC: Returns SwarmGitError with raw output for agent observation.
 func (m *Manager) gitRun(args ...string) error {
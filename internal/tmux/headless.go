@@ -0,0 +1,477 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/headless"
+)
+
+// Session backends.
+const (
+	BackendTmux     = "tmux"     // Sessions are tmux sessions
+	BackendHeadless = "headless" // Sessions are PTYs held by gt session-host processes
+
+	// BackendEnv selects the backend: "tmux", "headless", or "auto" (the
+	// default), which uses tmux when it is installed.
+	BackendEnv = "GT_SESSION_BACKEND"
+)
+
+// Backend returns the session backend this process uses.
+func Backend() string {
+	switch os.Getenv(BackendEnv) {
+	case BackendTmux:
+		return BackendTmux
+	case BackendHeadless:
+		return BackendHeadless
+	}
+	if _, err := exec.LookPath("tmux"); err != nil && runtime.GOOS == "linux" {
+		return BackendHeadless
+	}
+	return BackendTmux
+}
+
+// IsHeadless reports whether t drives headless sessions instead of tmux.
+func (t *Tmux) IsHeadless() bool {
+	return t.headless
+}
+
+// errHeadlessUnsupported is returned for tmux commands that only make sense
+// with a tmux client, such as switching the client to another session.
+func errHeadlessUnsupported(cmd string) error {
+	return fmt.Errorf("tmux %s: not supported by the headless session backend", cmd)
+}
+
+// valueFlags lists, per tmux command, the flags that take a value. Every
+// other flag is a switch.
+var valueFlags = map[string]string{
+	"new-session":      "cesxyFn",
+	"has-session":      "t",
+	"kill-session":     "t",
+	"list-sessions":    "Ff",
+	"list-panes":       "Fft",
+	"display-message":  "cdFt",
+	"capture-pane":     "bEFSt",
+	"send-keys":        "Nt",
+	"set-environment":  "t",
+	"show-environment": "t",
+	"resize-pane":      "txy",
+	"clear-history":    "t",
+	"respawn-pane":     "cet",
+	"pipe-pane":        "t",
+	"set-option":       "t",
+	"set-hook":         "t",
+	"select-window":    "t",
+}
+
+// parsedArgs is a tmux command line split into flags and arguments.
+type parsedArgs struct {
+	flags map[byte]string // Switches map to ""
+	args  []string
+}
+
+func (p parsedArgs) has(flag byte) bool {
+	_, ok := p.flags[flag]
+	return ok
+}
+
+func parseArgs(cmd string, args []string) parsedArgs {
+	p := parsedArgs{flags: make(map[byte]string)}
+	takesValue := valueFlags[cmd]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			p.args = append(p.args, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			p.args = append(p.args, args[i:]...)
+			break
+		}
+		for j := 1; j < len(arg); j++ {
+			flag := arg[j]
+			if !strings.ContainsRune(takesValue, rune(flag)) {
+				p.flags[flag] = ""
+				continue
+			}
+			if rest := arg[j+1:]; rest != "" {
+				p.flags[flag] = rest
+			} else if i+1 < len(args) {
+				i++
+				p.flags[flag] = args[i]
+			}
+			break
+		}
+	}
+	return p
+}
+
+// targetSession reduces a tmux target (=name, name:window.pane, %pane) to
+// the session name. Headless sessions have one pane, whose ID is the
+// session name after a %.
+func targetSession(target string) string {
+	target = strings.TrimPrefix(target, "=")
+	target = strings.TrimPrefix(target, "%")
+	if i := strings.IndexByte(target, ':'); i >= 0 {
+		target = target[:i]
+	}
+	return target
+}
+
+// listHeadless formats every session (each has a single pane) for
+// list-sessions and list-panes -a.
+func listHeadless(p parsedArgs, defaultFormat string) (string, error) {
+	infos, err := headless.List()
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, info := range infos {
+		if filter := p.flags['f']; filter != "" && !formatTrue(expandFormat(filter, info)) {
+			continue
+		}
+		lines = append(lines, expandFormat(formatOr(p.flags['F'], defaultFormat), info))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// runHeadless carries out a tmux command against headless sessions,
+// producing the output tmux would.
+func runHeadless(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("tmux: no command")
+	}
+	cmd := args[0]
+	p := parseArgs(cmd, args[1:])
+	target := targetSession(p.flags['t'])
+
+	switch cmd {
+	case "new-session":
+		name := p.flags['s']
+		if headless.Exists(name) {
+			return "", ErrSessionExists
+		}
+		opts := headless.Options{
+			Name:    name,
+			WorkDir: p.flags['c'],
+			Command: strings.Join(p.args, " "),
+			Env:     []string{BackendEnv + "=" + BackendHeadless},
+		}
+		opts.Width, _ = strconv.Atoi(p.flags['x'])
+		opts.Height, _ = strconv.Atoi(p.flags['y'])
+		return "", wrapHeadlessError(headless.Start(opts))
+
+	case "has-session":
+		if !headless.Exists(target) {
+			return "", ErrSessionNotFound
+		}
+		return "", nil
+
+	case "kill-session":
+		_, err := headless.Do(target, headless.Request{Op: headless.OpKill})
+		return "", wrapHeadlessError(err)
+
+	case "kill-server":
+		infos, err := headless.List()
+		if err != nil {
+			return "", err
+		}
+		for _, info := range infos {
+			_, _ = headless.Do(info.Name, headless.Request{Op: headless.OpKill})
+		}
+		return "", nil
+
+	case "list-sessions":
+		return listHeadless(p, "#{session_name}")
+
+	case "list-panes", "display-message":
+		if cmd == "list-panes" && p.has('a') {
+			return listHeadless(p, "#{pane_id}")
+		}
+		if cmd == "display-message" && !p.has('p') {
+			// Messages shown in a client's status line: there is no client.
+			return "", nil
+		}
+		format := p.flags['F']
+		if len(p.args) > 0 {
+			format = p.args[0]
+		}
+		info, err := sessionInfo(target)
+		if err != nil {
+			return "", err
+		}
+		return expandFormat(formatOr(format, "#{pane_id}"), info), nil
+
+	case "capture-pane":
+		history := 0
+		switch start := p.flags['S']; {
+		case start == "-":
+			history = -1
+		case strings.HasPrefix(start, "-"):
+			history, _ = strconv.Atoi(start[1:])
+		}
+		resp, err := headless.Do(target, headless.Request{Op: headless.OpCapture, Lines: history})
+		if err != nil {
+			return "", wrapHeadlessError(err)
+		}
+		return strings.TrimSpace(strings.Join(resp.Lines, "\n")), nil
+
+	case "send-keys":
+		var data strings.Builder
+		for _, arg := range p.args {
+			if p.has('l') {
+				data.WriteString(arg)
+			} else {
+				data.WriteString(keyBytes(arg))
+			}
+		}
+		_, err := headless.Do(target, headless.Request{Op: headless.OpSend, Data: data.String()})
+		return "", wrapHeadlessError(err)
+
+	case "set-environment":
+		if len(p.args) < 2 {
+			return "", errors.New("tmux set-environment: need a name and value")
+		}
+		_, err := headless.Do(target, headless.Request{Op: headless.OpSetEnv, Key: p.args[0], Value: p.args[1]})
+		return "", wrapHeadlessError(err)
+
+	case "show-environment":
+		req := headless.Request{Op: headless.OpGetEnv}
+		if len(p.args) > 0 {
+			req.Key = p.args[0]
+		}
+		resp, err := headless.Do(target, req)
+		if err != nil {
+			return "", wrapHeadlessError(err)
+		}
+		if req.Key != "" {
+			if !resp.Found {
+				return "", fmt.Errorf("tmux show-environment: unknown variable: %s", req.Key)
+			}
+			return req.Key + "=" + resp.Value, nil
+		}
+		var lines []string
+		for k, v := range resp.Env {
+			lines = append(lines, k+"="+v)
+		}
+		return strings.Join(lines, "\n"), nil
+
+	case "resize-pane":
+		info, err := sessionInfo(target)
+		if err != nil {
+			return "", err
+		}
+		width := resizeValue(p.flags['x'], info.Width)
+		height := resizeValue(p.flags['y'], info.Height)
+		_, err = headless.Do(target, headless.Request{Op: headless.OpResize, Width: width, Height: height})
+		return "", wrapHeadlessError(err)
+
+	case "clear-history":
+		_, err := headless.Do(target, headless.Request{Op: headless.OpClear})
+		return "", wrapHeadlessError(err)
+
+	case "respawn-pane":
+		req := headless.Request{
+			Op:      headless.OpRespawn,
+			Command: strings.Join(p.args, " "),
+			WorkDir: p.flags['c'],
+		}
+		_, err := headless.Do(target, req)
+		return "", wrapHeadlessError(err)
+
+	case "pipe-pane":
+		_, err := headless.Do(target, headless.Request{Op: headless.OpPipe, Command: strings.Join(p.args, " ")})
+		return "", wrapHeadlessError(err)
+
+	case "set-option":
+		if target == "" || len(p.args) < 2 {
+			// Server-wide and window options don't apply.
+			return "", nil
+		}
+		_, err := headless.Do(target, headless.Request{Op: headless.OpOption, Key: p.args[0], Value: p.args[1]})
+		return "", wrapHeadlessError(err)
+
+	case "set-hook":
+		if target == "" || len(p.args) == 0 || p.args[0] != headless.HookPaneDied {
+			// Other hooks are about clients and windows headless lacks.
+			return "", nil
+		}
+		req := headless.Request{Op: headless.OpHook, Key: p.args[0]}
+		if !p.has('u') && len(p.args) > 1 {
+			command, ok := runShellCommand(p.args[1])
+			if !ok {
+				return "", fmt.Errorf("tmux set-hook: headless hooks must be run-shell commands, got %q", p.args[1])
+			}
+			req.Command = command
+		}
+		_, err := headless.Do(target, req)
+		return "", wrapHeadlessError(err)
+
+	case "bind-key", "select-window":
+		// Key bindings and windows belong to tmux; a headless session has
+		// one pane and no client to bind keys in.
+		return "", nil
+	}
+	return "", errHeadlessUnsupported(cmd)
+}
+
+// runShellCommand extracts the shell command from a tmux run-shell hook
+// command, e.g. `run-shell "gt log crash ..."`.
+func runShellCommand(hook string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(hook), "run-shell ")
+	if !ok {
+		return "", false
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), "-b "))
+	if len(rest) >= 2 && (rest[0] == '"' || rest[0] == '\'') && rest[len(rest)-1] == rest[0] {
+		rest = rest[1 : len(rest)-1]
+	}
+	return rest, rest != ""
+}
+
+func wrapHeadlessError(err error) error {
+	switch {
+	case errors.Is(err, headless.ErrNotFound):
+		return ErrSessionNotFound
+	case errors.Is(err, headless.ErrExists):
+		return ErrSessionExists
+	}
+	return err
+}
+
+func sessionInfo(name string) (*headless.Info, error) {
+	resp, err := headless.Do(name, headless.Request{Op: headless.OpInfo})
+	if err != nil {
+		return nil, wrapHeadlessError(err)
+	}
+	return resp.Info, nil
+}
+
+func formatOr(format, def string) string {
+	if format == "" {
+		return def
+	}
+	return format
+}
+
+// resizeValue applies a resize-pane size, which may be relative (+1, -1).
+func resizeValue(v string, current int) int {
+	n, err := strconv.Atoi(v)
+	switch {
+	case err != nil:
+		return current
+	case strings.HasPrefix(v, "+") || strings.HasPrefix(v, "-"):
+		return max(current+n, 1)
+	}
+	return n
+}
+
+// keyBytes turns a tmux key name into what the terminal sends for it. Text
+// that isn't a key name is sent as typed, as tmux does.
+func keyBytes(key string) string {
+	switch key {
+	case "Enter", "C-m":
+		return "\r"
+	case "Escape", "C-[":
+		return "\x1b"
+	case "Tab", "C-i":
+		return "\t"
+	case "BSpace":
+		return "\x7f"
+	case "Space":
+		return " "
+	case "Up":
+		return "\x1b[A"
+	case "Down":
+		return "\x1b[B"
+	case "Right":
+		return "\x1b[C"
+	case "Left":
+		return "\x1b[D"
+	case "Home":
+		return "\x1b[H"
+	case "End":
+		return "\x1b[F"
+	}
+	if len(key) == 3 && key[:2] == "C-" {
+		c := key[2]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c >= 'a' && c <= 'z' {
+			return string([]byte{c & 0x1f})
+		}
+	}
+	return key
+}
+
+var (
+	formatVarRe   = regexp.MustCompile(`#\{([a-z_]+)\}`)
+	formatEqualRe = regexp.MustCompile(`#\{==:([^,}]*),([^}]*)\}`)
+)
+
+// expandFormat expands the tmux format variables gastown uses for a
+// headless session.
+func expandFormat(format string, info *headless.Info) string {
+	out := formatVarRe.ReplaceAllStringFunc(format, func(m string) string {
+		return formatVar(m[2:len(m)-1], info)
+	})
+	return formatEqualRe.ReplaceAllStringFunc(out, func(m string) string {
+		parts := formatEqualRe.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return "1"
+		}
+		return "0"
+	})
+}
+
+func formatTrue(s string) bool {
+	return s != "" && s != "0"
+}
+
+func formatVar(name string, info *headless.Info) string {
+	unix := func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }
+	switch name {
+	case "session_name", "window_name":
+		return info.Name
+	case "session_id":
+		return "$" + info.Name
+	case "pane_id":
+		return "%" + info.Name
+	case "session_windows":
+		return "1"
+	case "session_created":
+		return unix(info.Created)
+	case "session_created_string":
+		return info.Created.Format("Mon Jan _2 15:04:05 2006")
+	case "session_activity", "window_activity":
+		return unix(info.Activity)
+	case "session_attached":
+		return strconv.Itoa(info.Attached)
+	case "pane_pid":
+		return strconv.Itoa(info.PID)
+	case "pane_current_command":
+		return info.Command
+	case "pane_current_path":
+		return info.Path
+	case "pane_width":
+		return strconv.Itoa(info.Width)
+	case "pane_height":
+		return strconv.Itoa(info.Height)
+	case "pane_dead":
+		if info.Dead {
+			return "1"
+		}
+		return "0"
+	case "pane_dead_status":
+		return strconv.Itoa(info.DeadStatus)
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/headless"
+)
+
+func TestBackendFromEnv(t *testing.T) {
+	t.Setenv(BackendEnv, BackendHeadless)
+	if got := Backend(); got != BackendHeadless {
+		t.Errorf("Backend() = %q, want %q", got, BackendHeadless)
+	}
+	if !NewTmux().IsHeadless() {
+		t.Error("NewTmux() is not headless with GT_SESSION_BACKEND=headless")
+	}
+
+	t.Setenv(BackendEnv, BackendTmux)
+	if got := Backend(); got != BackendTmux {
+		t.Errorf("Backend() = %q, want %q", got, BackendTmux)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		cmd   string
+		args  []string
+		flags map[byte]string
+		rest  []string
+	}{
+		{
+			cmd:   "new-session",
+			args:  []string{"-d", "-s", "gt-x", "-c", "/tmp", "claude --resume"},
+			flags: map[byte]string{'d': "", 's': "gt-x", 'c': "/tmp"},
+			rest:  []string{"claude --resume"},
+		},
+		{
+			cmd:   "capture-pane",
+			args:  []string{"-p", "-t", "gt-x", "-S", "-100"},
+			flags: map[byte]string{'p': "", 't': "gt-x", 'S': "-100"},
+		},
+		{
+			cmd:   "resize-pane",
+			args:  []string{"-t", "gt-x", "-y", "-1"},
+			flags: map[byte]string{'t': "gt-x", 'y': "-1"},
+		},
+		{
+			cmd:   "send-keys",
+			args:  []string{"-t", "gt-x", "-l", "hello -world"},
+			flags: map[byte]string{'t': "gt-x", 'l': ""},
+			rest:  []string{"hello -world"},
+		},
+		{
+			cmd:   "display-message",
+			args:  []string{"-p", "-t", "gt-x", "#{pane_width} #{pane_height}"},
+			flags: map[byte]string{'p': "", 't': "gt-x"},
+			rest:  []string{"#{pane_width} #{pane_height}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			p := parseArgs(tt.cmd, tt.args)
+			if !reflect.DeepEqual(p.flags, tt.flags) {
+				t.Errorf("flags = %q, want %q", p.flags, tt.flags)
+			}
+			if !reflect.DeepEqual(p.args, tt.rest) {
+				t.Errorf("args = %q, want %q", p.args, tt.rest)
+			}
+		})
+	}
+}
+
+func TestTargetSession(t *testing.T) {
+	for target, want := range map[string]string{
+		"gt-x":        "gt-x",
+		"=gt-x":       "gt-x",
+		"gt-x:0":      "gt-x",
+		"gt-x:0.1":    "gt-x",
+		"%gt-x":       "gt-x",
+		"=hq-mayor:1": "hq-mayor",
+	} {
+		if got := targetSession(target); got != want {
+			t.Errorf("targetSession(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestExpandFormat(t *testing.T) {
+	info := &headless.Info{
+		Name:     "gt-x",
+		PID:      42,
+		Command:  "claude",
+		Path:     "/work",
+		Created:  time.Unix(1700000000, 0),
+		Activity: time.Unix(1700000100, 0),
+		Attached: 1,
+		Width:    200,
+		Height:   50,
+	}
+	tests := map[string]string{
+		"#{session_name}":                        "gt-x",
+		"#{session_name}:#{session_id}":          "gt-x:$gt-x",
+		"#{pane_id}":                             "%gt-x",
+		"#{pane_pid}|#{pane_current_command}":    "42|claude",
+		"#{pane_current_path}":                   "/work",
+		"#{session_created} #{session_activity}": "1700000000 1700000100",
+		"#{pane_width} #{pane_height}":           "200 50",
+		"#{session_attached}":                    "1",
+		"#{==:#{session_name},gt-x}":             "1",
+		"#{==:#{session_name},gt-y}":             "0",
+	}
+	for format, want := range tests {
+		if got := expandFormat(format, info); got != want {
+			t.Errorf("expandFormat(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	for key, want := range map[string]string{
+		"Enter":  "\r",
+		"Escape": "\x1b",
+		"Down":   "\x1b[B",
+		"C-c":    "\x03",
+		"C-u":    "\x15",
+		"hello":  "hello",
+	} {
+		if got := keyBytes(key); got != want {
+			t.Errorf("keyBytes(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestResizeValue(t *testing.T) {
+	if got := resizeValue("-1", 50); got != 49 {
+		t.Errorf("resizeValue(-1, 50) = %d, want 49", got)
+	}
+	if got := resizeValue("+1", 49); got != 50 {
+		t.Errorf("resizeValue(+1, 49) = %d, want 50", got)
+	}
+	if got := resizeValue("80", 50); got != 80 {
+		t.Errorf("resizeValue(80, 50) = %d, want 80", got)
+	}
+	if got := resizeValue("", 50); got != 50 {
+		t.Errorf("resizeValue(\"\", 50) = %d, want 50", got)
+	}
+}
+
+func TestRunShellCommand(t *testing.T) {
+	tests := []struct {
+		hook string
+		want string
+		ok   bool
+	}{
+		{`run-shell "gt log crash --agent 'gastown/Toast' --exit-code #{pane_dead_status}"`, `gt log crash --agent 'gastown/Toast' --exit-code #{pane_dead_status}`, true},
+		{`run-shell -b 'echo died'`, `echo died`, true},
+		{`run-shell echo died`, `echo died`, true},
+		{`kill-session -t gt-x`, ``, false},
+	}
+	for _, tt := range tests {
+		got, ok := runShellCommand(tt.hook)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("runShellCommand(%q) = %q, %v; want %q, %v", tt.hook, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHeadlessUnsupportedCommands(t *testing.T) {
+	for _, cmd := range []string{"switch-client", "rename-session"} {
+		if _, err := runHeadless([]string{cmd, "-t", "gt-x"}); err == nil {
+			t.Errorf("%s: expected an error", cmd)
+		}
+	}
+	// Cosmetic commands have nothing to do and succeed.
+	for _, args := range [][]string{
+		{"bind-key", "-T", "prefix", "n", "run-shell", "gt cycle"},
+		{"set-option", "-g", "exit-empty", "off"},
+		{"display-message", "-t", "gt-x", "-d", "5000", "hello"},
+	} {
+		if _, err := runHeadless(args); err != nil {
+			t.Errorf("%v: %v", args, err)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/headless"
 )
 
 // sessionNudgeLocks serializes nudges to the same session.
@@ -33,15 +35,21 @@ var (
 )
 
 // Tmux wraps tmux operations.
-type Tmux struct{}
+type Tmux struct {
+	// headless routes commands to headless sessions instead of tmux.
+	headless bool
+}
 
-// NewTmux creates a new Tmux wrapper.
+// NewTmux creates a new Tmux wrapper for the configured session backend.
 func NewTmux() *Tmux {
-	return &Tmux{}
+	return &Tmux{headless: Backend() == BackendHeadless}
 }
 
 // run executes a tmux command and returns stdout.
 func (t *Tmux) run(args ...string) (string, error) {
+	if t.headless {
+		return runHeadless(args)
+	}
 	cmd := exec.Command("tmux", args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -504,8 +512,12 @@ func (t *Tmux) SetExitEmpty(on bool) error {
 	return err
 }
 
-// IsAvailable checks if tmux is installed and can be invoked.
+// IsAvailable checks if tmux is installed and can be invoked. The headless
+// backend needs no tmux, so it is always available.
 func (t *Tmux) IsAvailable() bool {
+	if t.headless {
+		return true
+	}
 	cmd := exec.Command("tmux", "-V")
 	return cmd.Run() == nil
 }
@@ -643,6 +655,26 @@ func (t *Tmux) ListSessionIDs() (map[string]string, error) {
 	return result, nil
 }
 
+// ListPaneSessions returns the session of every pane, keyed by pane ID
+// (e.g., "%55", the TMUX_PANE an agent sees).
+func (t *Tmux) ListPaneSessions() (map[string]string, error) {
+	out, err := t.run("list-panes", "-a", "-F", "#{pane_id}:#{session_name}")
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return nil, nil // No server = no panes
+		}
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if pane, session, ok := strings.Cut(line, ":"); ok && pane != "" {
+			result[pane] = session
+		}
+	}
+	return result, nil
+}
+
 // SendKeys sends keystrokes to a session and presses Enter.
 // Always sends Enter as a separate command for reliability.
 // Uses a debounce delay between paste and Enter to ensure paste completes.
@@ -1015,6 +1047,9 @@ func (t *Tmux) CapturePaneLines(session string, lines int) ([]string, error) {
 // AttachSession attaches to an existing session.
 // Note: This replaces the current process with tmux attach.
 func (t *Tmux) AttachSession(session string) error {
+	if t.headless {
+		return wrapHeadlessError(headless.Attach(targetSession(session), os.Stdin, os.Stdout))
+	}
 	_, err := t.run("attach-session", "-t", session)
 	return err
 }
@@ -1176,6 +1211,11 @@ func (t *Tmux) IsRuntimeRunning(session string, processNames []string) bool {
 			break
 		}
 	}
+	// A containerized agent is a process of the container, not the pane. The
+	// runtime's client in the pane lives exactly as long as the container.
+	if rt, _ := t.GetEnvironment(session, "GT_CONTAINER"); rt != "" && !slices.Contains(processNames, rt) {
+		return t.IsRuntimeRunning(session, []string{rt})
+	}
 	return false
 }
 
@@ -1335,6 +1375,19 @@ func (t *Tmux) GetSessionInfo(name string) (*SessionInfo, error) {
 	return info, nil
 }
 
+// GetSessionCreated returns when a session was created.
+func (t *Tmux) GetSessionCreated(session string) (time.Time, error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{session_created}")
+	if err != nil {
+		return time.Time{}, err
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing session_created %q: %w", out, err)
+	}
+	return time.Unix(secs, 0), nil
+}
+
 // ApplyTheme sets the status bar style for a session.
 func (t *Tmux) ApplyTheme(session string, theme Theme) error {
 	_, err := t.run("set-option", "-t", session, "status-style", theme.Style())
@@ -1616,6 +1669,25 @@ func (t *Tmux) CleanupOrphanedSessions() (cleaned int, err error) {
 	return cleaned, nil
 }
 
+// PipePane streams a session's pane output to a shell command (tmux
+// pipe-pane). It does nothing if the pane is already piped.
+func (t *Tmux) PipePane(session, command string) error {
+	_, err := t.run("pipe-pane", "-o", "-t", session, command)
+	return err
+}
+
+// GetPaneSize returns the width and height of a session's pane.
+func (t *Tmux) GetPaneSize(session string) (width, height int, err error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_width} #{pane_height}")
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(out, "%d %d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("parsing pane size %q: %w", out, err)
+	}
+	return width, height, nil
+}
+
 // SetPaneDiedHook sets a pane-died hook on a session to detect crashes.
 // When the pane exits, tmux runs the hook command with exit status info.
 // The agentID is used to identify the agent in crash logs (e.g., "gastown/Toast").
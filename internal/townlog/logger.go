@@ -40,6 +40,9 @@ const (
 	// Session death events (for crash investigation)
 	EventSessionDeath EventType = "session_death" // Session terminated (with reason)
 	EventMassDeath    EventType = "mass_death"    // Multiple sessions died in short window
+
+	// EventAutoscale records a polecat autoscaling decision for a rig.
+	EventAutoscale EventType = "autoscale"
 )
 
 // Event represents a single agent lifecycle event.
@@ -204,6 +207,12 @@ func formatLogLine(e Event) string {
 		} else {
 			detail = "MASS SESSION DEATH"
 		}
+	case EventAutoscale:
+		if e.Context != "" {
+			detail = fmt.Sprintf("autoscale: %s", e.Context)
+		} else {
+			detail = "autoscale decision"
+		}
 	default:
 		detail = string(e.Type)
 		if e.Context != "" {
@@ -66,6 +66,16 @@ func TestFormatLogLine(t *testing.T) {
 			},
 			contains: []string{"[kill]", "killed", "gt stop"},
 		},
+		{
+			name: "autoscale event",
+			event: Event{
+				Timestamp: ts,
+				Type:      EventAutoscale,
+				Agent:     "gastown/autoscaler",
+				Context:   "scale_up +2: 5 ready",
+			},
+			contains: []string{"[autoscale]", "gastown/autoscaler", "autoscale: scale_up +2"},
+		},
 	}
 
 	for _, tt := range tests {
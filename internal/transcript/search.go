@@ -0,0 +1,169 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Filter narrows a search to some of the archive.
+type Filter struct {
+	Bead  string    // Exact bead ID
+	Agent string    // Substring of the agent address (case-insensitive)
+	Rig   string    // Exact rig name
+	Since time.Time // Sessions that ended at or after this
+	Limit int       // Most matches to return (0 for all)
+}
+
+// Match is an archived transcript that matched a search.
+type Match struct {
+	Entry
+	Hits     int      `json:"hits"`
+	Snippets []string `json:"snippets,omitempty"`
+}
+
+// maxSnippets is how many excerpts a match keeps.
+const maxSnippets = 3
+
+// snippetRadius is how much text to keep either side of a hit.
+const snippetRadius = 60
+
+// Search returns archived transcripts passing f whose text contains query
+// (case-insensitive), newest first. An empty query matches every transcript
+// passing f without reading it.
+func Search(townRoot, query string, f Filter) ([]Match, error) {
+	entries, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(query)
+
+	var matches []Match
+	for _, e := range entries {
+		if !f.accepts(e) {
+			continue
+		}
+		m := Match{Entry: e}
+		if needle != "" {
+			if err := scan(townRoot, e, needle, &m); err != nil || m.Hits == 0 {
+				continue
+			}
+		}
+		matches = append(matches, m)
+		if f.Limit > 0 && len(matches) >= f.Limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+func (f Filter) accepts(e Entry) bool {
+	if f.Bead != "" && e.Bead != f.Bead {
+		return false
+	}
+	if f.Rig != "" && e.Rig != f.Rig {
+		return false
+	}
+	if f.Agent != "" && !strings.Contains(strings.ToLower(e.Agent), strings.ToLower(f.Agent)) {
+		return false
+	}
+	if !f.Since.IsZero() && e.Ended.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// scan counts lines of a transcript containing needle and keeps excerpts.
+func scan(townRoot string, e Entry, needle string, m *Match) error {
+	r, err := Open(townRoot, e)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Cheap check on the raw line before decoding it
+		if !strings.Contains(strings.ToLower(string(line)), needle) {
+			continue
+		}
+		text := lineText(line)
+		idx := strings.Index(strings.ToLower(text), needle)
+		if idx < 0 {
+			continue
+		}
+		m.Hits++
+		if len(m.Snippets) < maxSnippets {
+			m.Snippets = append(m.Snippets, snippet(text, idx, len(needle)))
+		}
+	}
+	return scanner.Err()
+}
+
+// metadataKeys are transcript fields that identify or time a message rather
+// than say anything; they are left out of the searchable text.
+var metadataKeys = map[string]bool{
+	"uuid": true, "parentUuid": true, "sessionId": true, "session_id": true,
+	"requestId": true, "id": true, "tool_use_id": true, "signature": true,
+	"timestamp": true, "type": true, "role": true, "model": true,
+	"cwd": true, "gitBranch": true, "version": true, "userType": true,
+}
+
+// lineText returns the searchable text of one transcript line: the string
+// values of a JSON record joined by spaces, or the line itself if it is not
+// JSON.
+func lineText(line []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(line, &v); err != nil {
+		return string(line)
+	}
+	var parts []string
+	var walk func(key string, v interface{})
+	walk = func(key string, v interface{}) {
+		switch v := v.(type) {
+		case string:
+			if !metadataKeys[key] && v != "" {
+				parts = append(parts, v)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(key, item)
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(k, v[k])
+			}
+		}
+	}
+	walk("", v)
+	return strings.Join(parts, " ")
+}
+
+// snippet returns text around [idx, idx+n) on one line.
+func snippet(text string, idx, n int) string {
+	start, end := idx-snippetRadius, idx+n+snippetRadius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(text) {
+		end, suffix = len(text), ""
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return prefix + strings.Join(strings.Fields(text[start:end]), " ") + suffix
+}
@@ -0,0 +1,291 @@
+// Package transcript archives runtime session transcripts so past sessions
+// stay searchable after the runtime's own files are rotated, the account is
+// switched, or the polecat is gone.
+//
+// When a session ends its transcript, found where the runtime's agent driver
+// says it writes them, is gzipped into <town>/.transcripts/ and
+// indexed by runtime session ID, agent, rig, bead and time in index.jsonl.
+// gt seance search reads the archive.
+package transcript
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// DirName is the archive directory under the town root.
+const DirName = ".transcripts"
+
+// IndexFile is the archive index, one Entry per line. Re-archiving a
+// session appends a new line; the last one wins.
+const IndexFile = "index.jsonl"
+
+// Entry describes one archived transcript.
+type Entry struct {
+	ID      string    `json:"id"`                // Runtime session ID (the transcript's file name)
+	Runtime string    `json:"runtime"`           // Runtime provider (claude, codex, ...)
+	Agent   string    `json:"agent,omitempty"`   // Agent address (gastown/polecats/Toast)
+	Rig     string    `json:"rig,omitempty"`     // Rig name, empty for town-level agents
+	Session string    `json:"session,omitempty"` // Tmux session name
+	Bead    string    `json:"bead,omitempty"`    // Bead on the agent's hook
+	Account string    `json:"account,omitempty"` // Runtime config dir the session ran under
+	Source  string    `json:"source"`            // Where the transcript was copied from
+	Path    string    `json:"path"`              // Archive file, relative to the archive dir
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"`
+	Size    int64     `json:"size"` // Uncompressed bytes
+}
+
+// Source says where a runtime keeps a session's transcript. Agent drivers
+// (internal/agentdriver) know where each runtime writes; Locate only
+// searches.
+type Source struct {
+	Provider  string                 // Runtime provider
+	Dirs      []string               // Directories the runtime writes transcripts to
+	Recursive bool                   // Also search subdirectories of Dirs
+	Match     func(path string) bool // Accepts only this session's transcripts (nil accepts all)
+	ConfigDir string                 // Runtime account/config dir the session ran under
+	Since     time.Time              // Ignore transcripts last written before this
+}
+
+// ArchiveDir returns the town's transcript archive directory.
+func ArchiveDir(townRoot string) string {
+	return filepath.Join(townRoot, DirName)
+}
+
+// Locate finds the transcript of the session src describes: the most
+// recently written one, as a session that ends is the last to write.
+func Locate(src Source) (string, error) {
+	if len(src.Dirs) == 0 {
+		return "", fmt.Errorf("no transcript location known for runtime %q (set session.transcript_dir)", src.Provider)
+	}
+	dirs := make([]string, len(src.Dirs))
+	for i, dir := range src.Dirs {
+		dirs[i] = expandHome(dir)
+	}
+	return newest(dirs, src.Recursive, src.Since, src.Match)
+}
+
+// newest returns the most recently modified *.jsonl file in dirs.
+func newest(dirs []string, recursive bool, since time.Time, accept func(string) bool) (string, error) {
+	var best string
+	var bestTime time.Time
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != dir && !recursive {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".jsonl") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil || info.ModTime().Before(since) || !info.ModTime().After(bestTime) {
+				return nil
+			}
+			if accept != nil && !accept(path) {
+				return nil
+			}
+			best, bestTime = path, info.ModTime()
+			return nil
+		})
+	}
+	if best == "" {
+		return "", fmt.Errorf("no transcript found in %s", strings.Join(dirs, ", "))
+	}
+	return best, nil
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// ArchiveSession locates the transcript of a session that just ended and
+// archives it.
+func ArchiveSession(townRoot string, src Source, e Entry) (*Entry, error) {
+	path, err := Locate(src)
+	if err != nil {
+		return nil, err
+	}
+	if e.Runtime == "" {
+		e.Runtime = src.Provider
+	}
+	if e.Account == "" {
+		e.Account = src.ConfigDir
+	}
+	if e.Started.IsZero() {
+		e.Started = src.Since
+	}
+	return Archive(townRoot, path, e)
+}
+
+// Archive compresses the transcript at src into the town's archive and
+// indexes it. Unset ID, Source, Ended and Size are filled in from src.
+func Archive(townRoot, src string, e Entry) (*Entry, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	if e.ID == "" {
+		e.ID = strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	}
+	if e.Ended.IsZero() {
+		e.Ended = info.ModTime()
+	}
+	e.Source = src
+	e.Size = info.Size()
+	e.Path = filepath.Join(e.Ended.UTC().Format("2006"), e.Ended.UTC().Format("01"), e.ID+".jsonl.gz")
+
+	dir := ArchiveDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating archive dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(dir, IndexFile+".lock"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return nil, fmt.Errorf("locking transcript index: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("timeout waiting for transcript index lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if err := compress(src, filepath.Join(dir, e.Path)); err != nil {
+		return nil, err
+	}
+
+	// A session archived again (e.g. resumed) may land in another month
+	if prev, _ := Load(townRoot); prev != nil {
+		for _, p := range prev {
+			if p.ID == e.ID && p.Path != e.Path {
+				_ = os.Remove(filepath.Join(dir, p.Path))
+			}
+		}
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, IndexFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: index is not secret
+	if err != nil {
+		return nil, fmt.Errorf("opening transcript index: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("writing transcript index: %w", err)
+	}
+	return &e, nil
+}
+
+// compress gzips src to dst via a temp file, so readers never see a
+// partial archive.
+func compress(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // G304: transcript path located by gt
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("creating archive dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	if _, err := io.Copy(zw, in); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("compressing transcript: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("compressing transcript: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Load returns the archive's entries, newest first.
+func Load(townRoot string) ([]Entry, error) {
+	f, err := os.Open(filepath.Join(ArchiveDir(townRoot), IndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	byID := make(map[string]int)
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ID == "" {
+			continue
+		}
+		if i, ok := byID[e.ID]; ok {
+			entries[i] = e
+			continue
+		}
+		byID[e.ID] = len(entries)
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Ended.After(entries[j].Ended) })
+	return entries, scanner.Err()
+}
+
+// Open returns a reader for an archived transcript's original content.
+func Open(townRoot string, e Entry) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(ArchiveDir(townRoot), e.Path)) //nolint:gosec // G304: path from the archive index
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("reading %s: %w", e.Path, err)
+	}
+	return &gzipFile{Reader: zr, file: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	_ = g.Reader.Close()
+	return g.file.Close()
+}
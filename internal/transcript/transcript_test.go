@@ -0,0 +1,200 @@
+package transcript
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeFile(t, filepath.Join(dir, "old.jsonl"), "{}\n", now.Add(-time.Hour))
+	writeFile(t, filepath.Join(dir, "new.jsonl"), "{}\n", now)
+	writeFile(t, filepath.Join(dir, "sub", "newer.jsonl"), "{}\n", now.Add(time.Minute))
+	writeFile(t, filepath.Join(dir, "newest.txt"), "", now.Add(2*time.Minute))
+
+	tests := []struct {
+		name string
+		src  Source
+		want string
+	}{
+		{"top level only", Source{Dirs: []string{dir}}, "new.jsonl"},
+		{"recursive", Source{Dirs: []string{dir}, Recursive: true}, "newer.jsonl"},
+		{"missing dirs skipped", Source{Dirs: []string{filepath.Join(dir, "nope"), dir}}, "new.jsonl"},
+		{"match", Source{Dirs: []string{dir}, Recursive: true, Match: func(path string) bool {
+			return filepath.Base(path) == "old.jsonl"
+		}}, "old.jsonl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Locate(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if filepath.Base(got) != tt.want {
+				t.Errorf("Locate = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Locate(Source{Dirs: []string{dir}, Since: now.Add(time.Hour)}); err == nil {
+		t.Error("Locate found a transcript older than Since")
+	}
+	if _, err := Locate(Source{Provider: "gemini"}); err == nil {
+		t.Error("Locate without dirs should fail")
+	}
+}
+
+func readArchived(t *testing.T, townRoot string, e Entry) string {
+	t.Helper()
+	r, err := Open(townRoot, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestArchiveAndLoad(t *testing.T) {
+	town := t.TempDir()
+	src := filepath.Join(t.TempDir(), "abc-123.jsonl")
+	ended := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	writeFile(t, src, "line one\n", ended)
+
+	e, err := Archive(town, src, Entry{Runtime: "claude", Agent: "gastown/polecats/Toast", Rig: "gastown", Bead: "gt-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "abc-123" || e.Path != filepath.Join("2026", "03", "abc-123.jsonl.gz") || e.Size != int64(len("line one\n")) {
+		t.Errorf("entry = %+v", e)
+	}
+	if got := readArchived(t, town, *e); got != "line one\n" {
+		t.Errorf("archived content = %q", got)
+	}
+
+	// Re-archiving the same session (resumed next month) replaces it
+	writeFile(t, src, "line one\nline two\n", ended.Add(2*time.Hour))
+	e2, err := Archive(town, src, Entry{Runtime: "claude", Agent: "gastown/polecats/Toast", Bead: "gt-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != e2.Path {
+		t.Fatalf("Load = %+v, want the one re-archived entry", entries)
+	}
+	if _, err := os.Stat(filepath.Join(ArchiveDir(town), e.Path)); !os.IsNotExist(err) {
+		t.Error("the superseded archive file was left behind")
+	}
+	if got := readArchived(t, town, entries[0]); !strings.Contains(got, "line two") {
+		t.Errorf("archived content = %q", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	town := t.TempDir()
+	srcDir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+
+	claude := `{"type":"user","uuid":"u1","message":{"role":"user","content":"Fix the migration for gt-1"}}
+{"type":"assistant","uuid":"u2","message":{"content":[{"type":"text","text":"The Migration failed with a lock timeout, retrying."}]}}
+{"type":"assistant","uuid":"u3","message":{"content":[{"type":"tool_use","input":{"command":"go test ./..."}}]}}
+`
+	writeFile(t, filepath.Join(srcDir, "s1.jsonl"), claude, base)
+	writeFile(t, filepath.Join(srcDir, "s2.jsonl"), `{"message":{"content":"migration done"}}`+"\n", base.Add(time.Minute))
+	writeFile(t, filepath.Join(srcDir, "s3.jsonl"), `{"message":{"content":"unrelated"}}`+"\n", base.Add(2*time.Minute))
+
+	for _, a := range []struct {
+		file string
+		e    Entry
+	}{
+		{"s1.jsonl", Entry{Agent: "gastown/polecats/Toast", Rig: "gastown", Bead: "gt-1"}},
+		{"s2.jsonl", Entry{Agent: "beads/polecats/Nux", Rig: "beads", Bead: "gt-2"}},
+		{"s3.jsonl", Entry{Agent: "gastown/crew/max", Rig: "gastown"}},
+	} {
+		if _, err := Archive(town, filepath.Join(srcDir, a.file), a.e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := Search(town, "MIGRATION", Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 || matches[0].ID != "s2" || matches[1].ID != "s1" {
+		t.Fatalf("Search = %+v, want s2 then s1", matches)
+	}
+	if matches[1].Hits != 2 {
+		t.Errorf("s1 hits = %d, want 2", matches[1].Hits)
+	}
+	if !strings.Contains(matches[1].Snippets[1], "lock timeout") {
+		t.Errorf("snippet = %q", matches[1].Snippets[1])
+	}
+
+	// Metadata fields aren't searchable
+	if matches, _ := Search(town, "u2", Filter{}); len(matches) != 0 {
+		t.Errorf("Search matched a uuid: %+v", matches)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		filter Filter
+		want   []string
+	}{
+		{"by bead", "migration", Filter{Bead: "gt-1"}, []string{"s1"}},
+		{"by rig", "", Filter{Rig: "gastown"}, []string{"s3", "s1"}},
+		{"by agent", "", Filter{Agent: "POLECATS"}, []string{"s2", "s1"}},
+		{"since", "", Filter{Since: base.Add(90 * time.Second)}, []string{"s3"}},
+		{"limit", "", Filter{Limit: 1}, []string{"s3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Search(town, tt.query, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, m := range matches {
+				ids = append(ids, m.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+	got := snippet(text, 101, len("needle"))
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "needle") {
+		t.Errorf("snippet = %q", got)
+	}
+	if got := snippet("short needle", 6, 6); got != "short needle" {
+		t.Errorf("snippet = %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// EnsurePrivateDir creates dir (mode 0700) if it is missing and checks it
+// with CheckPrivateDir.
+func EnsurePrivateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return CheckPrivateDir(dir)
+}
+
+// CheckPrivateDir checks that dir is safe to keep sockets or secrets in: a
+// real directory, not a symlink, owned by the current user with mode 0700.
+// A predictable path in a shared temp dir can be created by another user
+// first; this refuses such a directory rather than trust what is in it.
+func CheckPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%s is a symlink", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is owned by uid %d, not %d", dir, st.Uid, os.Getuid())
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		return fmt.Errorf("%s has mode %#o, want 0700", dir, perm)
+	}
+	return nil
+}
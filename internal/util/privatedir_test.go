@@ -0,0 +1,40 @@
+//go:build !windows
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsurePrivateDir(t *testing.T) {
+	base := t.TempDir()
+
+	dir := filepath.Join(base, "private")
+	if err := EnsurePrivateDir(dir); err != nil {
+		t.Fatalf("EnsurePrivateDir(new) = %v", err)
+	}
+	if err := EnsurePrivateDir(dir); err != nil {
+		t.Errorf("EnsurePrivateDir(existing) = %v", err)
+	}
+
+	open := filepath.Join(base, "open")
+	if err := os.Mkdir(open, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(open, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsurePrivateDir(open); err == nil {
+		t.Error("EnsurePrivateDir accepted a 0755 dir")
+	}
+
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsurePrivateDir(link); err == nil {
+		t.Error("EnsurePrivateDir accepted a symlink")
+	}
+}
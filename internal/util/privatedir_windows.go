@@ -0,0 +1,26 @@
+//go:build windows
+
+package util
+
+import (
+	"fmt"
+	"os"
+)
+
+// EnsurePrivateDir creates dir if it is missing. Windows keeps per-user
+// temp dirs, so there is no shared path to check.
+func EnsurePrivateDir(dir string) error {
+	return os.MkdirAll(dir, 0700)
+}
+
+// CheckPrivateDir checks that dir is a directory.
+func CheckPrivateDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
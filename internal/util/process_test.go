@@ -0,0 +1,27 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessExists(t *testing.T) {
+	// Current process exists
+	if !ProcessExists(os.Getpid()) {
+		t.Error("ProcessExists(current PID) = false, want true")
+	}
+
+	// Note: PID 1 (init/launchd) cannot be signaled without permission on macOS,
+	// so we only test our own process and invalid PIDs.
+
+	// Invalid PIDs
+	if ProcessExists(0) {
+		t.Error("ProcessExists(0) = true, want false")
+	}
+	if ProcessExists(-1) {
+		t.Error("ProcessExists(-1) = true, want false")
+	}
+	if ProcessExists(999999999) {
+		t.Error("ProcessExists(999999999) = true, want false")
+	}
+}
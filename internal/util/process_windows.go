@@ -1,11 +1,11 @@
 //go:build windows
 
-package lock
+package util
 
 import "golang.org/x/sys/windows"
 
-// processExists checks if a process with the given PID exists and is alive.
-func processExists(pid int) bool {
+// ProcessExists checks if a process with the given PID exists and is alive.
+func ProcessExists(pid int) bool {
 	if pid <= 0 {
 		return false
 	}
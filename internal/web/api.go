@@ -8,9 +8,13 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 const (
@@ -96,6 +100,10 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleIssueShow(w, r)
 	case path == "/pr/show" && r.Method == http.MethodGet:
 		h.handlePRShow(w, r)
+	case path == "/recordings" && r.Method == http.MethodGet:
+		h.handleRecordings(w, r)
+	case path == "/recordings/cast" && r.Method == http.MethodGet:
+		h.handleRecordingCast(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -764,6 +772,60 @@ func (h *APIHandler) handleIssueShow(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// handleRecordings lists session recordings, optionally only those of a
+// session, bead or rig/polecat given as ?q=. Paths are relative to the town.
+func (h *APIHandler) handleRecordings(w http.ResponseWriter, r *http.Request) {
+	townRoot, err := workspace.FindOrError(h.workDir)
+	if err != nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusInternalServerError)
+		return
+	}
+
+	var recs []recording.Recording
+	if q := r.URL.Query().Get("q"); q != "" {
+		recs, err = recording.Find(townRoot, q)
+	} else {
+		recs, err = recording.List(townRoot)
+	}
+	if err != nil {
+		h.sendError(w, "Failed to list recordings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range recs {
+		if rel, err := filepath.Rel(townRoot, recs[i].Path); err == nil {
+			recs[i].Path = filepath.ToSlash(rel)
+		}
+	}
+	if recs == nil {
+		recs = []recording.Recording{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"recordings": recs})
+}
+
+// handleRecordingCast serves one recording (?path= from /recordings) for the
+// dashboard's player.
+func (h *APIHandler) handleRecordingCast(w http.ResponseWriter, r *http.Request) {
+	townRoot, err := workspace.FindOrError(h.workDir)
+	if err != nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusInternalServerError)
+		return
+	}
+	path, err := recording.Resolve(townRoot, r.URL.Query().Get("path"))
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		h.sendError(w, "Recording not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	http.ServeFile(w, r, path)
+}
+
 // runBdCommand executes a bd command with the given args.
 func (h *APIHandler) runBdCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -376,3 +379,55 @@ func TestGetCommandList(t *testing.T) {
 		}
 	}
 }
+
+func TestAPIHandler_Recordings(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	recDir := filepath.Join(town, "gastown", "polecats", ".recordings", "Toast")
+	if err := os.MkdirAll(recDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1767225600,"env":{"GT_SESSION":"gt-gastown-Toast","GT_BEAD":"gt-abc"}}` + "\n" + `[0.1,"o","hi"]` + "\n"
+	if err := os.WriteFile(filepath.Join(recDir, "20260101T000000-gt-gastown-Toast-1.cast"), []byte(cast), 0644); err != nil {
+		t.Fatal(err)
+	}
+	handler := &APIHandler{workDir: town}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recordings?q=gt-abc", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/recordings status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Recordings []struct {
+			Path    string `json:"path"`
+			Session string `json:"session"`
+		} `json:"recordings"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recordings) != 1 || resp.Recordings[0].Session != "gt-gastown-Toast" {
+		t.Fatalf("recordings = %+v", resp.Recordings)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/recordings/cast?path="+url.QueryEscape(resp.Recordings[0].Path), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != cast {
+		t.Errorf("GET /api/recordings/cast status = %d, body = %q", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/recordings/cast?path="+url.QueryEscape("../../etc/passwd"), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("path traversal status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -886,6 +886,9 @@
         document.getElementById('issue-detail-blocks').innerHTML = '';
         document.getElementById('issue-detail-deps').style.display = 'none';
         document.getElementById('issue-detail-blocks-section').style.display = 'none';
+        document.getElementById('issue-detail-recordings').innerHTML = '';
+        document.getElementById('issue-detail-player').innerHTML = '';
+        document.getElementById('issue-detail-recordings-section').style.display = 'none';
 
         // Show detail view
         issuesList.style.display = 'none';
@@ -953,8 +956,72 @@
                 document.getElementById('issue-detail-title-text').textContent = 'Error';
                 document.getElementById('issue-detail-description').textContent = 'Failed to load issue: ' + err.message;
             });
+
+        loadIssueRecordings(issueId);
+    }
+
+    // Recordings of the polecat sessions that worked an issue
+    function loadIssueRecordings(issueId) {
+        fetch('/api/recordings?q=' + encodeURIComponent(issueId))
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                if (issueId !== currentIssueId || !data.recordings || data.recordings.length === 0) {
+                    return;
+                }
+                document.getElementById('issue-detail-recordings-section').style.display = 'block';
+                document.getElementById('issue-detail-recordings').innerHTML = data.recordings.map(function(rec) {
+                    var started = new Date(rec.started).toLocaleString();
+                    return '<span class="issue-dep-item recording-item" data-recording-path="' + escapeHtml(rec.path) + '">▶ ' +
+                        escapeHtml(rec.session) + ' · ' + escapeHtml(started) + '</span>';
+                }).join(' ');
+            })
+            .catch(function() {
+                // Recordings are optional; leave the section hidden
+            });
+    }
+
+    // The asciinema player is only fetched the first time a recording is played
+    var asciinemaLoading = null;
+    function loadAsciinemaPlayer() {
+        if (asciinemaLoading) {
+            return asciinemaLoading;
+        }
+        asciinemaLoading = new Promise(function(resolve, reject) {
+            var css = document.createElement('link');
+            css.rel = 'stylesheet';
+            css.href = 'https://unpkg.com/asciinema-player@3.8.0/dist/bundle/asciinema-player.css';
+            document.head.appendChild(css);
+
+            var script = document.createElement('script');
+            script.src = 'https://unpkg.com/asciinema-player@3.8.0/dist/bundle/asciinema-player.min.js';
+            script.onload = function() { resolve(window.AsciinemaPlayer); };
+            script.onerror = function() {
+                asciinemaLoading = null;
+                reject(new Error('could not load the asciinema player'));
+            };
+            document.head.appendChild(script);
+        });
+        return asciinemaLoading;
     }
 
+    document.addEventListener('click', function(e) {
+        var item = e.target.closest('.recording-item');
+        if (!item) {
+            return;
+        }
+        e.preventDefault();
+        var playerEl = document.getElementById('issue-detail-player');
+        playerEl.innerHTML = '';
+        var src = '/api/recordings/cast?path=' + encodeURIComponent(item.getAttribute('data-recording-path'));
+        loadAsciinemaPlayer()
+            .then(function(player) {
+                player.create(src, playerEl, { idleTimeLimit: 2, fit: 'width', autoPlay: true });
+            })
+            .catch(function(err) {
+                playerEl.textContent = err.message;
+            });
+    });
+
     // Back button from issue detail
     var issueBackBtn = document.getElementById('issue-back-btn');
     if (issueBackBtn) {
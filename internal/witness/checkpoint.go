@@ -0,0 +1,95 @@
+package witness
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// CheckpointTarget identifies a working polecat for the witness to checkpoint.
+type CheckpointTarget struct {
+	// Name is the polecat name.
+	Name string
+
+	// HomeDir is the polecat's home (polecats/<name>/), where the live
+	// checkpoint and history live.
+	HomeDir string
+
+	// WorkDir is the polecat's git worktree.
+	WorkDir string
+
+	// HookBead is the polecat's assigned work, if known.
+	HookBead string
+
+	// LastStepClose is when the polecat last closed a molecule step.
+	LastStepClose time.Time
+}
+
+// CheckpointResult describes what the witness did for one polecat.
+type CheckpointResult struct {
+	Polecat    string `json:"polecat"`
+	Reason     string `json:"reason,omitempty"` // empty when no snapshot was due
+	SnapshotID string `json:"snapshot_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CheckpointPolecat captures a polecat's current state and, if a snapshot is
+// due (cadence elapsed, new commit, or step closed), appends it to the
+// polecat's bounded history and refreshes the git state in the live
+// checkpoint used by gt mol resume. Molecule context, session and pending
+// closures in the live checkpoint belong to the polecat: they are carried
+// over, and the update holds the checkpoint lock so the polecat's own
+// changes aren't lost.
+func CheckpointPolecat(target CheckpointTarget, cfg *config.CheckpointConfig, now time.Time) CheckpointResult {
+	result := CheckpointResult{Polecat: target.Name}
+
+	cp, err := checkpoint.Capture(target.WorkDir)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if cp.LastCommit == "" {
+		result.Error = fmt.Sprintf("%s is not a git worktree", target.WorkDir)
+		return result
+	}
+
+	historyDir := checkpoint.HistoryPath(target.HomeDir)
+	err = checkpoint.Update(target.WorkDir, func(live *checkpoint.Checkpoint) (*checkpoint.Checkpoint, error) {
+		if live != nil {
+			cp.MoleculeID = live.MoleculeID
+			cp.CurrentStep = live.CurrentStep
+			cp.StepTitle = live.StepTitle
+			cp.HookedBead = live.HookedBead
+			cp.SessionID = live.SessionID
+			cp.Notes = live.Notes
+			cp.PendingClosures = live.PendingClosures
+		}
+		if target.HookBead != "" {
+			cp.HookedBead = target.HookBead
+		}
+
+		var last *checkpoint.Checkpoint
+		if latest, err := checkpoint.Latest(historyDir); err == nil && latest != nil {
+			last = latest.Checkpoint
+		}
+		result.Reason = checkpoint.Due(last, cp, cfg.IntervalDuration(), target.LastStepClose, now)
+		if result.Reason == "" {
+			return nil, nil
+		}
+
+		cp.Reason = result.Reason
+		cp.Timestamp = now
+		id, err := checkpoint.SaveSnapshot(historyDir, cp, cfg.KeepCount())
+		if err != nil {
+			return nil, err
+		}
+		result.SnapshotID = id
+		return cp, nil
+	})
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package witness
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("git %v: %v: %s", args, err, out)
+	}
+}
+
+func TestCheckpointPolecat(t *testing.T) {
+	home := t.TempDir()
+	workDir := filepath.Join(home, "gastown")
+	gitRun(t, home, "init", "-q", "-b", "main", workDir)
+	gitRun(t, workDir, "config", "user.email", "test@example.com")
+	gitRun(t, workDir, "config", "user.name", "Test")
+	gitRun(t, workDir, "commit", "-q", "--allow-empty", "-m", "initial")
+
+	// The polecat's own checkpoint carries molecule context
+	if err := checkpoint.Write(workDir, &checkpoint.Checkpoint{
+		MoleculeID:  "gt-mol",
+		CurrentStep: "gt-mol.2",
+		SessionID:   "sess-1",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.CheckpointConfig{Interval: "5m", Keep: 2}
+	target := CheckpointTarget{Name: "Toast", HomeDir: home, WorkDir: workDir, HookBead: "gt-123"}
+	now := time.Now()
+
+	res := CheckpointPolecat(target, cfg, now)
+	if res.Error != "" || res.Reason != checkpoint.ReasonInitial {
+		t.Fatalf("first snapshot = %+v, want initial", res)
+	}
+
+	// Nothing changed and interval not elapsed
+	res = CheckpointPolecat(target, cfg, now.Add(time.Minute))
+	if res.Reason != "" {
+		t.Errorf("unchanged snapshot reason = %q, want none", res.Reason)
+	}
+
+	gitRun(t, workDir, "commit", "-q", "--allow-empty", "-m", "work")
+	res = CheckpointPolecat(target, cfg, now.Add(2*time.Minute))
+	if res.Reason != checkpoint.ReasonCommit {
+		t.Errorf("after commit reason = %q, want %q", res.Reason, checkpoint.ReasonCommit)
+	}
+
+	res = CheckpointPolecat(target, cfg, now.Add(10*time.Minute))
+	if res.Reason != checkpoint.ReasonInterval {
+		t.Errorf("after interval reason = %q, want %q", res.Reason, checkpoint.ReasonInterval)
+	}
+
+	snapshots, err := checkpoint.History(checkpoint.HistoryPath(home))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("history has %d snapshots, want 2 (bounded by keep)", len(snapshots))
+	}
+
+	live, err := checkpoint.Read(workDir)
+	if err != nil || live == nil {
+		t.Fatalf("live checkpoint = %v, %v", live, err)
+	}
+	if live.CurrentStep != "gt-mol.2" || live.SessionID != "sess-1" || live.HookedBead != "gt-123" {
+		t.Errorf("live checkpoint lost context: %+v", live)
+	}
+}
+
+func TestCheckpointPolecatWithoutLiveCheckpoint(t *testing.T) {
+	home := t.TempDir()
+	workDir := filepath.Join(home, "gastown")
+	gitRun(t, home, "init", "-q", "-b", "main", workDir)
+	gitRun(t, workDir, "config", "user.email", "test@example.com")
+	gitRun(t, workDir, "config", "user.name", "Test")
+	gitRun(t, workDir, "commit", "-q", "--allow-empty", "-m", "initial")
+
+	target := CheckpointTarget{Name: "Toast", HomeDir: home, WorkDir: workDir}
+	res := CheckpointPolecat(target, &config.CheckpointConfig{}, time.Now())
+	if res.Error != "" || res.SnapshotID == "" {
+		t.Fatalf("CheckpointPolecat() = %+v", res)
+	}
+
+	// The witness must not pass itself off as the polecat's session
+	live, err := checkpoint.Read(workDir)
+	if err != nil || live == nil {
+		t.Fatalf("live checkpoint = %v, %v", live, err)
+	}
+	if live.SessionID != "" {
+		t.Errorf("SessionID = %q, want empty", live.SessionID)
+	}
+}
@@ -177,8 +177,9 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		return fmt.Errorf("waiting for witness to start: %w", err)
 	}
 
-	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+	// Dismiss anything the runtime shows at startup (e.g. Claude's bypass
+	// permissions warning).
+	_ = runtime.DriverWithOverride(runtimeConfig, agentOverride).AfterStart(t, sessionID)
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 